@@ -18,8 +18,15 @@ func main() {
 
 	containerConf := container.InitContainer()
 	defer mysql.CloseDatabaseConnection(containerConf.Mysqldb)
+	defer containerConf.Messaging.Stop()
+	defer containerConf.Jadwal.Stop()
 
-	app := fiber.New()
+	// request body limit, bulk import with zip of foto need more than fiber default
+	fiberConf := fiber.Config{}
+	if containerConf.Apps.MaxBody > 0 {
+		fiberConf.BodyLimit = containerConf.Apps.MaxBody << 20
+	}
+	app := fiber.New(fiberConf)
 	app.Use(logger.New())
 	http.RouteInit(app, containerConf)
 	port := fmt.Sprintf("%s:%d", containerConf.Apps.Host, containerConf.Apps.HttpPort)
@@ -1,6 +1,9 @@
 package daos
 
-import "time"
+import (
+	"gorm.io/gorm"
+	"time"
+)
 
 type Alamat struct {
 	ID           uint
@@ -11,6 +14,7 @@ type Alamat struct {
 	DetailAlamat string `gorm:"type:varchar(255)"`
 	UpdatedAt    time.Time
 	CreatedAt    time.Time
+	DeletedAt    gorm.DeletedAt `gorm:"index"`
 	TRX          []TRX
 }
 
@@ -0,0 +1,122 @@
+package daos
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	AtributTeks    = "string"
+	AtributAngka   = "number"
+	AtributPilihan = "enum"
+	AtributBoolean = "boolean"
+)
+
+// AtributCategory attribute that produk of the category can be described with, e.g. merek, bahan or ukuran RAM.
+// Kode is the key used by seller and search filter, it can not be changed after created.
+type AtributCategory struct {
+	ID         uint
+	CategoryID uint   `gorm:"not null;uniqueIndex:idx_atribut_category_kode"`
+	Kode       string `gorm:"type:varchar(50);not null;uniqueIndex:idx_atribut_category_kode"`
+	Nama       string `gorm:"type:varchar(100);not null"`
+	Tipe       string `gorm:"type:varchar(20);not null"`
+	// Opsi json array of value allowed for enum attribute
+	Opsi      string `gorm:"type:text"`
+	Satuan    string `gorm:"type:varchar(20)"`
+	Wajib     bool   `gorm:"not null;default:false"`
+	Urutan    uint
+	UpdatedAt time.Time
+	CreatedAt time.Time
+}
+
+// AtributProduk value of category attribute on produk, value of every type is kept as text
+// and number is also kept in NilaiAngka so it can be filtered by range
+type AtributProduk struct {
+	ID                uint
+	ProdukID          uint `gorm:"not null;uniqueIndex:idx_atribut_produk"`
+	AtributCategoryID uint `gorm:"not null;uniqueIndex:idx_atribut_produk;index:idx_atribut_nilai_teks,priority:1;index:idx_atribut_nilai_angka,priority:1"`
+	AtributCategory   AtributCategory
+	NilaiTeks         string   `gorm:"type:varchar(255);index:idx_atribut_nilai_teks,priority:2"`
+	NilaiAngka        *float64 `gorm:"index:idx_atribut_nilai_angka,priority:2"`
+	UpdatedAt         time.Time
+	CreatedAt         time.Time
+}
+
+// FilterAtribut produk whose attribute Kode is one of Nilai, number attribute can also be filtered between Min and Max
+type FilterAtribut struct {
+	Kode  string
+	Nilai []string
+	Min   *float64
+	Max   *float64
+}
+
+// ListOpsi value allowed for enum attribute
+func (a AtributCategory) ListOpsi() (listOpsi []string) {
+	_ = json.Unmarshal([]byte(a.Opsi), &listOpsi)
+	return listOpsi
+}
+
+// NilaiProduk check value sent by seller against type of the attribute, kosong is true when no value is sent.
+// Value of enum attribute is saved as written in Opsi.
+func (a AtributCategory) NilaiProduk(nilai interface{}) (atribut AtributProduk, kosong bool, err error) {
+	atribut.AtributCategoryID = a.ID
+	if teks, ok := nilai.(string); ok && strings.TrimSpace(teks) == "" {
+		nilai = nil
+	}
+	if nilai == nil {
+		return atribut, true, nil
+	}
+	switch a.Tipe {
+	case AtributTeks:
+		teks, ok := nilai.(string)
+		if !ok {
+			return atribut, false, fmt.Errorf("%s must be text", a.Kode)
+		}
+		atribut.NilaiTeks = strings.TrimSpace(teks)
+		if utf8.RuneCountInString(atribut.NilaiTeks) > 255 {
+			return atribut, false, fmt.Errorf("%s is longer than 255 character", a.Kode)
+		}
+	case AtributAngka:
+		angka, ok := nilai.(float64)
+		if !ok {
+			return atribut, false, fmt.Errorf("%s must be number", a.Kode)
+		}
+		atribut.NilaiTeks = strconv.FormatFloat(angka, 'f', -1, 64)
+		atribut.NilaiAngka = &angka
+	case AtributPilihan:
+		teks, _ := nilai.(string)
+		for _, v := range a.ListOpsi() {
+			if strings.EqualFold(v, strings.TrimSpace(teks)) {
+				atribut.NilaiTeks = v
+				return atribut, false, nil
+			}
+		}
+		return atribut, false, fmt.Errorf("%s must be one of %s", a.Kode, strings.Join(a.ListOpsi(), ", "))
+	case AtributBoolean:
+		benar, ok := nilai.(bool)
+		if !ok {
+			return atribut, false, fmt.Errorf("%s must be true or false", a.Kode)
+		}
+		atribut.NilaiTeks = strconv.FormatBool(benar)
+	default:
+		return atribut, false, fmt.Errorf("unknown type of %s", a.Kode)
+	}
+	return atribut, false, nil
+}
+
+// Nilai value of the attribute in its type, AtributCategory must be loaded
+func (a AtributProduk) Nilai() interface{} {
+	switch a.AtributCategory.Tipe {
+	case AtributAngka:
+		if a.NilaiAngka != nil {
+			return *a.NilaiAngka
+		}
+	case AtributBoolean:
+		return a.NilaiTeks == "true"
+	}
+	return a.NilaiTeks
+}
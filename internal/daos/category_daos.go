@@ -1,6 +1,9 @@
 package daos
 
-import "time"
+import (
+	"gorm.io/gorm"
+	"time"
+)
 
 type Category struct {
 	ID           uint
@@ -8,5 +11,6 @@ type Category struct {
 	Produk       []Produk
 	CreatedAt    time.Time
 	UpdatedAt    time.Time
+	DeletedAt    gorm.DeletedAt `gorm:"index"`
 	LogProduk    []LogProduk
 }
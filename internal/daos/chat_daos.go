@@ -0,0 +1,41 @@
+package daos
+
+import "time"
+
+type Percakapan struct {
+	ID              uint
+	UserID          uint `gorm:"not null;index"`
+	User            User
+	TokoID          uint `gorm:"not null;index"`
+	Toko            Toko
+	ProdukID        *uint
+	TRXID           *uint
+	PesanTerakhir   string `gorm:"type:varchar(255)"`
+	PesanTerakhirAt *time.Time
+	UnreadUser      uint `gorm:"not null;default:0"`
+	UnreadToko      uint `gorm:"not null;default:0"`
+	Pesan           []Pesan
+	UpdatedAt       time.Time
+	CreatedAt       time.Time
+}
+
+type Pesan struct {
+	ID           uint
+	PercakapanID uint `gorm:"not null;index"`
+	PengirimID   uint `gorm:"not null"`
+	DariToko     bool
+	IsiPesan     string `gorm:"type:text"`
+	ReadAt       *time.Time
+	UpdatedAt    time.Time
+	CreatedAt    time.Time
+}
+
+type FilterPercakapan struct {
+	Limit  int
+	Offset int
+}
+
+type FilterPesan struct {
+	Limit    int
+	BeforeID uint
+}
@@ -9,6 +9,9 @@ type DetailTRX struct {
 	TokoID      uint
 	Kuantitas   uint
 	HargaTotal  uint
+	// NoResi filled when toko ship the trx, every detail trx of the same toko is shipped together
+	NoResi      string `gorm:"type:varchar(100)"`
+	DikirimPada *time.Time
 	UpdatedAt   time.Time
 	CreatedAt   time.Time
 }
@@ -21,6 +24,7 @@ type DetailTRXResponse struct {
 	Toko        Toko
 	Kuantitas   uint
 	HargaTotal  uint
+	NoResi      string
 	UpdatedAt   time.Time
 	CreatedAt   time.Time
 	LogProduk   LogProduk
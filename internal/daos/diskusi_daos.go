@@ -0,0 +1,57 @@
+package daos
+
+import "time"
+
+const (
+	TanggapanMembantu = "helpful"
+	TanggapanLaporan  = "report"
+)
+
+// DiskusiProduk question asked by user on produk, answer of the question is also DiskusiProduk with PertanyaanID.
+// Question and answer are shown right away, admin hide it by rejecting it when it is reported.
+type DiskusiProduk struct {
+	ID           uint
+	ProdukID     uint  `gorm:"not null;index"`
+	PertanyaanID *uint `gorm:"index"`
+	UserID       uint  `gorm:"not null;index"`
+	User         User
+	// DariToko answer is given by owner of the produk
+	DariToko       bool
+	Isi            string `gorm:"type:text"`
+	StatusModerasi string `gorm:"type:varchar(20);not null;default:approved;index"`
+	AlasanModerasi string `gorm:"type:varchar(255)"`
+	// JumlahJawaban count approved answer of the question
+	JumlahJawaban  uint            `gorm:"not null;default:0"`
+	JumlahMembantu uint            `gorm:"not null;default:0"`
+	JumlahLaporan  uint            `gorm:"not null;default:0;index"`
+	Jawaban        []DiskusiProduk `gorm:"foreignKey:PertanyaanID"`
+	UpdatedAt      time.Time
+	CreatedAt      time.Time
+}
+
+// TanggapanDiskusi helpful vote or report given by user, each user can give each Jenis once
+type TanggapanDiskusi struct {
+	ID        uint
+	DiskusiID uint   `gorm:"not null;uniqueIndex:idx_tanggapan_diskusi"`
+	UserID    uint   `gorm:"not null;uniqueIndex:idx_tanggapan_diskusi"`
+	Jenis     string `gorm:"type:varchar(20);not null;uniqueIndex:idx_tanggapan_diskusi"`
+	CreatedAt time.Time
+}
+
+type FilterDiskusi struct {
+	Limit  int
+	Offset int
+}
+
+// FilterModerasiDiskusi Dilaporkan only list question and answer that is reported, the most reported first
+type FilterModerasiDiskusi struct {
+	Limit      int
+	Offset     int
+	Status     string
+	Dilaporkan bool
+}
+
+// Pertanyaan diskusi without PertanyaanID is a question
+func (d DiskusiProduk) Pertanyaan() bool {
+	return d.PertanyaanID == nil
+}
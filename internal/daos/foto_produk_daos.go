@@ -1,11 +1,35 @@
 package daos
 
-import "time"
+import (
+	"gorm.io/gorm"
+	"time"
+)
 
 type FotoProduk struct {
-	ID        uint
-	ProdukID  uint
-	URL       string `gorm:"type:varchar(255)"`
+	ID       uint
+	ProdukID uint
+	SKUID    *uint  `gorm:"column:sku_id;index"`
+	URL      string `gorm:"type:varchar(255)"`
+	// rendition of the same photo, URL is the large one
+	URLThumbnail string `gorm:"type:varchar(255)"`
+	URLMedium    string `gorm:"type:varchar(255)"`
+	URLLarge     string `gorm:"type:varchar(255)"`
+	// position of the photo in produk gallery, one photo per produk is the primary photo
+	Urutan    int  `gorm:"default:0"`
+	Utama     bool `gorm:"default:false"`
 	UpdatedAt time.Time
 	CreatedAt time.Time
+	DeletedAt gorm.DeletedAt `gorm:"index"`
+}
+
+// ListURL every stored file of the photo without duplicate, photo uploaded before rendition only have URL
+func (f FotoProduk) ListURL() (listURL []string) {
+	ada := map[string]bool{}
+	for _, v := range []string{f.URL, f.URLThumbnail, f.URLMedium, f.URLLarge} {
+		if v != "" && !ada[v] {
+			ada[v] = true
+			listURL = append(listURL, v)
+		}
+	}
+	return listURL
 }
@@ -0,0 +1,43 @@
+package daos
+
+import "time"
+
+const (
+	StatusImporMenunggu = "menunggu"
+	StatusImporDiproses = "diproses"
+	StatusImporSelesai  = "selesai"
+	StatusImporGagal    = "gagal"
+)
+
+// ImporProduk bulk import job of one uploaded file, row is processed in background
+type ImporProduk struct {
+	ID       uint
+	TokoID   uint   `gorm:"not null;index"`
+	NamaFile string `gorm:"type:varchar(255)"`
+	Format   string `gorm:"type:varchar(10)"`
+	Status   string `gorm:"type:varchar(20);not null;index"`
+	// Header first row of the file encoded as json array, used as header of rejected row report
+	Header string `gorm:"type:text"`
+	// TotalBaris number of data row without header, BarisDiproses is the progress
+	TotalBaris    int
+	BarisDiproses int
+	Berhasil      int
+	Gagal         int
+	// Pesan reason when the whole job failed
+	Pesan      string `gorm:"type:varchar(255)"`
+	BarisGagal []BarisImporGagal
+	SelesaiAt  *time.Time
+	UpdatedAt  time.Time
+	CreatedAt  time.Time
+}
+
+// BarisImporGagal rejected row of import file with its original cell so it can be fixed and imported again
+type BarisImporGagal struct {
+	ID            uint
+	ImporProdukID uint `gorm:"not null;index"`
+	NomorBaris    int
+	// Data original cell of the row encoded as json array
+	Data      string `gorm:"type:text"`
+	Pesan     string `gorm:"type:text"`
+	CreatedAt time.Time
+}
@@ -10,6 +10,9 @@ type LogProduk struct {
 	HargaReseller uint
 	HargaKonsumen uint
 	Deskripsi     string `gorm:"type:text"`
+	SKUID         *uint  `gorm:"column:sku_id"`
+	KodeSKU       string `gorm:"type:varchar(100)"`
+	Varian        string `gorm:"type:text"`
 	TokoID        uint   `gorm:"not null"`
 	Toko          Toko
 	CategoryID    uint `gorm:"not null"`
@@ -0,0 +1,27 @@
+package daos
+
+import "time"
+
+const (
+	ModerasiMenunggu  = "pending"
+	ModerasiDisetujui = "approved"
+	ModerasiDitolak   = "rejected"
+)
+
+// RiwayatModerasi every change of moderation status of a produk, AdminID is nil when it is changed by the system
+// (new produk of untrusted toko or produk of trusted toko that is approved automatically)
+type RiwayatModerasi struct {
+	ID        uint
+	ProdukID  uint   `gorm:"not null;index"`
+	Status    string `gorm:"type:varchar(20);not null"`
+	Alasan    string `gorm:"type:varchar(255)"`
+	AdminID   *uint
+	CreatedAt time.Time
+}
+
+type FilterModerasi struct {
+	Limit  int
+	Offset int
+	Status string
+	TokoID uint
+}
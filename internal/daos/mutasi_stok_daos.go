@@ -0,0 +1,36 @@
+package daos
+
+import "time"
+
+const (
+	MutasiStokAwal        = "initial"
+	MutasiStokPenjualan   = "sale"
+	MutasiStokPembatalan  = "cancellation"
+	MutasiStokRestock     = "restock"
+	MutasiStokPenyesuaian = "adjustment"
+	MutasiStokRetur       = "return"
+)
+
+// MutasiStok one change of stok produk or sku, Saldo is stok after the change.
+// Stok of produk with variant is the sum of its sku, so movement of sku also write resulting stok produk in SaldoProduk.
+type MutasiStok struct {
+	ID          uint
+	ProdukID    uint   `gorm:"not null;index:idx_mutasi_stok_produk"`
+	SKUID       *uint  `gorm:"column:sku_id;index"`
+	Tipe        string `gorm:"type:varchar(20);not null"`
+	Perubahan   int
+	Saldo       uint
+	SaldoProduk uint
+	// PelakuID user who made the change, buyer for sale and seller for change made by toko
+	PelakuID  *uint
+	Alasan    string    `gorm:"type:varchar(255)"`
+	TRXID     *uint     `gorm:"column:trx_id;index"`
+	CreatedAt time.Time `gorm:"index:idx_mutasi_stok_produk"`
+}
+
+type FilterMutasiStok struct {
+	Limit  int
+	Offset int
+	SKUID  uint
+	Tipe   string
+}
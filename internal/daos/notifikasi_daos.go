@@ -0,0 +1,88 @@
+package daos
+
+import "time"
+
+const (
+	NotifikasiTipePesananDibuat         = "order_created"
+	NotifikasiTipePesananMasuk          = "order_received"
+	NotifikasiTipePembayaran            = "payment"
+	NotifikasiTipePengiriman            = "shipment"
+	NotifikasiTipeStokRendah            = "low_stock"
+	NotifikasiTipeImporSelesai          = "import_finished"
+	NotifikasiTipeModerasiProduk        = "product_moderation"
+	NotifikasiTipePertanyaanProduk      = "product_question"
+	NotifikasiTipeJawabanProduk         = "product_answer"
+	NotifikasiRefTRX                    = "trx"
+	NotifikasiRefProduk                 = "produk"
+	NotifikasiRefImpor                  = "impor"
+	BatasStokRendahDefault         uint = 5
+)
+
+type Notifikasi struct {
+	ID        uint
+	UserID    uint   `gorm:"not null;index"`
+	User      User   `gorm:"foreignKey:UserID"`
+	Tipe      string `gorm:"type:varchar(50);not null;index"`
+	Judul     string `gorm:"type:varchar(255)"`
+	Pesan     string `gorm:"type:text"`
+	Payload   string `gorm:"type:text"`
+	RefTipe   string `gorm:"type:varchar(50)"`
+	RefID     uint
+	IsRead    bool `gorm:"not null;default:false;index"`
+	ReadAt    *time.Time
+	UpdatedAt time.Time
+	CreatedAt time.Time
+}
+
+// PayloadPesananDibuat payload of notifikasi for buyer of new trx
+type PayloadPesananDibuat struct {
+	TRXID       uint   `json:"trx_id"`
+	KodeInvoice string `json:"kode_invoice"`
+	HargaTotal  uint   `json:"harga_total"`
+}
+
+// PayloadPesananMasuk payload of notifikasi for seller of new trx, summarize detail trx of the toko
+type PayloadPesananMasuk struct {
+	TRXID       uint   `json:"trx_id"`
+	KodeInvoice string `json:"kode_invoice"`
+	TokoID      uint   `json:"toko_id"`
+	Kuantitas   uint   `json:"kuantitas"`
+	HargaTotal  uint   `json:"harga_total"`
+}
+
+// PayloadPembayaran payload of notifikasi for buyer when payment of trx is confirmed
+type PayloadPembayaran struct {
+	TRXID       uint   `json:"trx_id"`
+	KodeInvoice string `json:"kode_invoice"`
+	HargaTotal  uint   `json:"harga_total"`
+}
+
+// PayloadPengiriman payload of notifikasi for buyer when a toko ship its part of trx
+type PayloadPengiriman struct {
+	TRXID       uint   `json:"trx_id"`
+	KodeInvoice string `json:"kode_invoice"`
+	TokoID      uint   `json:"toko_id"`
+	NamaToko    string `json:"nama_toko"`
+	NoResi      string `json:"no_resi"`
+}
+
+// PayloadStokRendah payload of notifikasi for seller of produk reaching low stock threshold
+type PayloadStokRendah struct {
+	TRXID           uint   `json:"trx_id"`
+	ProdukID        uint   `json:"produk_id"`
+	NamaProduk      string `json:"nama_produk"`
+	Stok            uint   `json:"stok"`
+	BatasStokRendah uint   `json:"batas_stok_rendah"`
+}
+
+type FilterNotifikasi struct {
+	Limit      int
+	Offset     int
+	Tipe       string
+	UnreadOnly bool
+}
+
+type NotifikasiUnread struct {
+	Tipe   string
+	Jumlah uint
+}
@@ -1,6 +1,9 @@
 package daos
 
-import "time"
+import (
+	"gorm.io/gorm"
+	"time"
+)
 
 type Produk struct {
 	ID            uint
@@ -10,14 +13,78 @@ type Produk struct {
 	HargaKonsumen uint
 	Stok          uint
 	Deskripsi     string `gorm:"type:text"`
-	TokoID        uint   `gorm:"not null"`
-	Toko          Toko
-	CategoryID    uint `gorm:"not null"`
-	Category      Category
-	FotoProduk    []FotoProduk
-	UpdatedAt     time.Time
-	CreatedAt     time.Time
-	LogProduk     []LogProduk
+	// BatasStokRendah low stock threshold of the produk, nil use threshold of the toko
+	BatasStokRendah *uint
+	// StatusModerasi only approved produk is shown to buyer and can be bought
+	StatusModerasi string `gorm:"type:varchar(20);not null;default:approved;index"`
+	// AlasanModerasi reason given by admin when produk is rejected
+	AlasanModerasi string `gorm:"type:varchar(255)"`
+	// StatusPublikasi draft and archived produk is only seen by its toko
+	StatusPublikasi string `gorm:"type:varchar(20);not null;default:published;index"`
+	// JadwalTerbit published produk is hidden until this time, nil is published right away
+	JadwalTerbit *time.Time `gorm:"index"`
+	TokoID       uint       `gorm:"not null"`
+	Toko         Toko
+	CategoryID   uint `gorm:"not null"`
+	Category     Category
+	FotoProduk   []FotoProduk
+	OpsiVarian   []OpsiVarian
+	SKU          []SKU
+	// Atribut value of attribute defined by the category
+	Atribut   []AtributProduk
+	UpdatedAt time.Time
+	CreatedAt time.Time
+	DeletedAt gorm.DeletedAt `gorm:"index"`
+	LogProduk []LogProduk
+	// Versi increased on every update of the produk by its toko, sent as ETag so conflicting update can be rejected
+	Versi uint `gorm:"not null;default:1"`
+	// Terjual total kuantitas sold, only filled when sorted by terlaris
+	Terjual uint `gorm:"->;-:migration"`
+	// PromoAktif item of promo running now with the lowest price, filled by repository that read produk for buyer
+	PromoAktif *ItemPromo `gorm:"-"`
+	// Statistik only filled in popular produk listing
+	Statistik *StatistikProduk `gorm:"-"`
+}
+
+const (
+	PublikasiDraf   = "draft"
+	PublikasiTerbit = "published"
+	PublikasiArsip  = "archived"
+)
+
+const (
+	SortProdukRelevansi = "relevansi"
+	SortProdukHargaAsc  = "harga_asc"
+	SortProdukHargaDesc = "harga_desc"
+	SortProdukTerbaru   = "terbaru"
+	SortProdukTerlaris  = "terlaris"
+)
+
+// BatasHargaFacet upper bound of every harga_konsumen bucket, the last bucket has no upper bound
+var BatasHargaFacet = []uint{50000, 100000, 250000, 500000, 1000000}
+
+// Tampil report whether produk is shown to buyer and can be bought at waktu
+func (p Produk) Tampil(waktu time.Time) bool {
+	return p.StatusModerasi == ModerasiDisetujui && p.StatusPublikasi == PublikasiTerbit &&
+		(p.JadwalTerbit == nil || !p.JadwalTerbit.After(waktu))
+}
+
+// BatasStokRendahEfektif low stock threshold of the produk, Toko must be loaded to use threshold of the toko
+func (p Produk) BatasStokRendahEfektif() uint {
+	if p.BatasStokRendah != nil {
+		return *p.BatasStokRendah
+	}
+	if p.Toko.BatasStokRendah != nil {
+		return *p.Toko.BatasStokRendah
+	}
+	return BatasStokRendahDefault
+}
+
+// MelewatiBatasStokRendah report whether stok going from sebelum to sesudah reach the low stock threshold,
+// stok already below the threshold does not cross it again
+func (p Produk) MelewatiBatasStokRendah(sebelum, sesudah uint) bool {
+	batas := p.BatasStokRendahEfektif()
+	return sebelum > batas && sesudah <= batas
 }
 
 type FilterProduk struct {
@@ -28,4 +95,43 @@ type FilterProduk struct {
 	TokoID     uint
 	MaxHarga   uint
 	MinHarga   uint
+	Tersedia   bool
+	ListID     []uint
+	Sort       string
+	Atribut    []FilterAtribut
+	// Setelah last produk of previous page, keyset pagination continue after it instead of using Offset
+	Setelah *Produk
+}
+
+// FilterProdukToko filter of every produk owned by a toko whatever its state
+type FilterProdukToko struct {
+	Limit           int
+	Offset          int
+	NamaProduk      string
+	StatusPublikasi string
+	StatusModerasi  string
+}
+
+// FilterBatchProduk produk walked by GetProdukBatch to fill search index, zero value walk every produk.
+// BerubahSejak also return deleted produk so it can be removed from the index.
+type FilterBatchProduk struct {
+	TokoID       uint
+	CategoryID   uint
+	BerubahSejak *time.Time
+}
+
+type FacetCategory struct {
+	CategoryID   uint
+	NamaCategory string
+	Jumlah       uint
+}
+
+type FacetHarga struct {
+	Bucket int
+	Jumlah uint
+}
+
+type FacetProduk struct {
+	Category []FacetCategory
+	Harga    []FacetHarga
 }
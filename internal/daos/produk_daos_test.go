@@ -0,0 +1,56 @@
+package daos
+
+import (
+	"testing"
+	"time"
+)
+
+func TestProdukTampil(t *testing.T) {
+	sekarang := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	sebelum := sekarang.Add(-time.Second)
+	sesudah := sekarang.Add(time.Second)
+	listKasus := []struct {
+		nama   string
+		produk Produk
+		harap  bool
+	}{
+		{"terbit", Produk{StatusModerasi: ModerasiDisetujui, StatusPublikasi: PublikasiTerbit}, true},
+		{"draf", Produk{StatusModerasi: ModerasiDisetujui, StatusPublikasi: PublikasiDraf}, false},
+		{"arsip", Produk{StatusModerasi: ModerasiDisetujui, StatusPublikasi: PublikasiArsip}, false},
+		{"belum disetujui", Produk{StatusModerasi: ModerasiMenunggu, StatusPublikasi: PublikasiTerbit}, false},
+		{"jadwal sudah lewat", Produk{StatusModerasi: ModerasiDisetujui, StatusPublikasi: PublikasiTerbit, JadwalTerbit: &sebelum}, true},
+		{"jadwal tepat sekarang", Produk{StatusModerasi: ModerasiDisetujui, StatusPublikasi: PublikasiTerbit, JadwalTerbit: &sekarang}, true},
+		{"jadwal belum tiba", Produk{StatusModerasi: ModerasiDisetujui, StatusPublikasi: PublikasiTerbit, JadwalTerbit: &sesudah}, false},
+	}
+	for _, kasus := range listKasus {
+		if kasus.produk.Tampil(sekarang) != kasus.harap {
+			t.Errorf("%s: expected %v", kasus.nama, kasus.harap)
+		}
+	}
+}
+
+func TestMelewatiBatasStokRendah(t *testing.T) {
+	batasProduk := uint(3)
+	batasToko := uint(10)
+	listKasus := []struct {
+		nama    string
+		produk  Produk
+		sebelum uint
+		sesudah uint
+		harap   bool
+	}{
+		{"turun ke batas default", Produk{}, 6, BatasStokRendahDefault, true},
+		{"turun melewati batas default", Produk{}, 8, 2, true},
+		{"masih di atas batas", Produk{}, 9, BatasStokRendahDefault + 1, false},
+		{"sudah di bawah batas", Produk{}, BatasStokRendahDefault, 1, false},
+		{"stok bertambah", Produk{}, 2, 20, false},
+		{"batas produk", Produk{BatasStokRendah: &batasProduk, Toko: Toko{BatasStokRendah: &batasToko}}, 4, 3, true},
+		{"batas produk belum tercapai", Produk{BatasStokRendah: &batasProduk, Toko: Toko{BatasStokRendah: &batasToko}}, 12, 4, false},
+		{"batas toko", Produk{Toko: Toko{BatasStokRendah: &batasToko}}, 12, 10, true},
+	}
+	for _, kasus := range listKasus {
+		if kasus.produk.MelewatiBatasStokRendah(kasus.sebelum, kasus.sesudah) != kasus.harap {
+			t.Errorf("%s: stok %d to %d expected %v", kasus.nama, kasus.sebelum, kasus.sesudah, kasus.harap)
+		}
+	}
+}
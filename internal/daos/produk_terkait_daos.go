@@ -0,0 +1,16 @@
+package daos
+
+import "time"
+
+// BatasProdukTerkait number of related produk kept for every produk
+const BatasProdukTerkait = 20
+
+// ProdukTerkait produk bought in the same trx as ProdukID, Skor is the number of those trx.
+// The table is rebuilt from trx history by batch job.
+type ProdukTerkait struct {
+	ID        uint
+	ProdukID  uint `gorm:"not null;uniqueIndex:idx_produk_terkait"`
+	TerkaitID uint `gorm:"not null;uniqueIndex:idx_produk_terkait"`
+	Skor      uint
+	CreatedAt time.Time
+}
@@ -2,5 +2,6 @@ package daos
 
 type ProdukIDKuantitas struct {
 	ProdukID  uint
+	SKUID     uint
 	Kuantitas uint
 }
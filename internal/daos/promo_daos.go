@@ -0,0 +1,95 @@
+package daos
+
+import (
+	"gorm.io/gorm"
+	"time"
+)
+
+const (
+	PromoTipeSale      = "sale"
+	PromoTipeFlashSale = "flash_sale"
+
+	RiwayatHargaManual = "manual"
+)
+
+// Promo time boxed sale price for produk of a toko, flash sale also limit the quota of every produk and how many a user can buy.
+// Promo is active when Mulai <= now < Selesai, canceled promo that already started ends at the time it is canceled.
+type Promo struct {
+	ID        uint
+	TokoID    uint      `gorm:"not null;index"`
+	Tipe      string    `gorm:"type:varchar(20);not null"`
+	Nama      string    `gorm:"type:varchar(255)"`
+	Mulai     time.Time `gorm:"index:idx_promo_waktu"`
+	Selesai   time.Time `gorm:"index:idx_promo_waktu"`
+	ItemPromo []ItemPromo
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt gorm.DeletedAt `gorm:"index"`
+}
+
+// ItemPromo sale price of one produk in a promo, it also applies to every sku of the produk.
+// HargaReseller 0 keep normal harga reseller, Kuota 0 and BatasPerUser 0 mean unlimited.
+type ItemPromo struct {
+	ID            uint
+	PromoID       uint `gorm:"not null;uniqueIndex:idx_item_promo_produk"`
+	Promo         Promo
+	ProdukID      uint `gorm:"not null;uniqueIndex:idx_item_promo_produk;index"`
+	HargaReseller uint
+	HargaKonsumen uint
+	Kuota         uint
+	Terjual       uint
+	BatasPerUser  uint
+}
+
+// Tersedia report whether quota of the item is not sold out yet
+func (i ItemPromo) Tersedia() bool {
+	return i.Kuota == 0 || i.Terjual < i.Kuota
+}
+
+// Harga sale price from normal price of produk or sku
+func (i ItemPromo) Harga(hargaReseller, hargaKonsumen uint) (uint, uint) {
+	if i.HargaReseller != 0 && i.HargaReseller < hargaReseller {
+		hargaReseller = i.HargaReseller
+	}
+	if i.HargaKonsumen < hargaKonsumen {
+		hargaKonsumen = i.HargaKonsumen
+	}
+	return hargaReseller, hargaKonsumen
+}
+
+// PembelianPromo kuantitas bought by a user from item promo in one trx, used to limit purchase per user
+type PembelianPromo struct {
+	ID          uint
+	ItemPromoID uint `gorm:"not null;index:idx_pembelian_promo_user"`
+	UserID      uint `gorm:"not null;index:idx_pembelian_promo_user"`
+	TRXID       uint `gorm:"column:trx_id;index"`
+	Kuantitas   uint
+	CreatedAt   time.Time
+}
+
+// RiwayatHarga price of produk from BerlakuMulai, manual price apply until the next manual price
+// and price from promo apply until BerlakuSampai
+type RiwayatHarga struct {
+	ID            uint
+	ProdukID      uint `gorm:"not null;index:idx_riwayat_harga_produk"`
+	HargaReseller uint
+	HargaKonsumen uint
+	Sumber        string    `gorm:"type:varchar(20);not null"`
+	PromoID       *uint     `gorm:"index"`
+	BerlakuMulai  time.Time `gorm:"index:idx_riwayat_harga_produk"`
+	BerlakuSampai *time.Time
+	CreatedAt     time.Time
+}
+
+type FilterPromo struct {
+	Limit  int
+	Offset int
+	Tipe   string
+	// Aktif only promo running at this time
+	Aktif bool
+}
+
+type FilterRiwayatHarga struct {
+	Limit  int
+	Offset int
+}
@@ -0,0 +1,42 @@
+package daos
+
+import "testing"
+
+func TestItemPromoHarga(t *testing.T) {
+	listKasus := []struct {
+		nama          string
+		item          ItemPromo
+		hargaReseller uint
+		hargaKonsumen uint
+		harapReseller uint
+		harapKonsumen uint
+	}{
+		{"promo lebih murah", ItemPromo{HargaReseller: 8000, HargaKonsumen: 9000}, 10000, 12000, 8000, 9000},
+		{"harga reseller promo kosong", ItemPromo{HargaKonsumen: 9000}, 10000, 12000, 10000, 9000},
+		{"promo lebih mahal dari harga sku", ItemPromo{HargaReseller: 15000, HargaKonsumen: 20000}, 10000, 12000, 10000, 12000},
+		{"promo sama dengan harga normal", ItemPromo{HargaReseller: 10000, HargaKonsumen: 12000}, 10000, 12000, 10000, 12000},
+		{"harga konsumen promo gratis", ItemPromo{HargaKonsumen: 0}, 10000, 12000, 10000, 0},
+	}
+	for _, kasus := range listKasus {
+		reseller, konsumen := kasus.item.Harga(kasus.hargaReseller, kasus.hargaKonsumen)
+		if reseller != kasus.harapReseller || konsumen != kasus.harapKonsumen {
+			t.Errorf("%s: expected %d %d, got %d %d", kasus.nama, kasus.harapReseller, kasus.harapKonsumen, reseller, konsumen)
+		}
+	}
+}
+
+func TestItemPromoTersedia(t *testing.T) {
+	listKasus := []struct {
+		item  ItemPromo
+		harap bool
+	}{
+		{ItemPromo{Kuota: 0, Terjual: 100}, true},
+		{ItemPromo{Kuota: 10, Terjual: 9}, true},
+		{ItemPromo{Kuota: 10, Terjual: 10}, false},
+	}
+	for _, kasus := range listKasus {
+		if kasus.item.Tersedia() != kasus.harap {
+			t.Errorf("kuota %d terjual %d: expected %v", kasus.item.Kuota, kasus.item.Terjual, kasus.harap)
+		}
+	}
+}
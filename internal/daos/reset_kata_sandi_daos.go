@@ -0,0 +1,15 @@
+package daos
+
+import "time"
+
+// ResetKataSandi request to reset kata sandi of user, only sha256 of the token is saved
+type ResetKataSandi struct {
+	ID            uint
+	UserID        uint   `gorm:"not null;index"`
+	User          User   `gorm:"foreignKey:UserID"`
+	TokenHash     string `gorm:"type:varchar(64);not null;uniqueIndex"`
+	BerlakuHingga time.Time
+	// DipakaiPada set when token is used or replaced by newer request, token can only be used once
+	DipakaiPada *time.Time
+	CreatedAt   time.Time
+}
@@ -0,0 +1,19 @@
+package daos
+
+import "time"
+
+const (
+	SlugTipeProduk = "produk"
+	SlugTipeToko   = "toko"
+)
+
+// Slug every slug ever given to produk or toko, slug replaced by rename is kept so its url redirect to the current one
+// and is never given to other produk or toko. The current slug is also written in slug column of produk or toko.
+type Slug struct {
+	ID        uint
+	Tipe      string `gorm:"type:varchar(20);not null;uniqueIndex:idx_slug_tipe_slug;index:idx_slug_tipe_ref"`
+	Slug      string `gorm:"type:varchar(255);not null;uniqueIndex:idx_slug_tipe_slug"`
+	RefID     uint   `gorm:"not null;index:idx_slug_tipe_ref"`
+	UpdatedAt time.Time
+	CreatedAt time.Time
+}
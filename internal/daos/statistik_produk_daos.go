@@ -0,0 +1,34 @@
+package daos
+
+import "time"
+
+const (
+	// BobotTerjualTrending one sold item weighs as much as this many view in trending score
+	BobotTerjualTrending = 10
+	// HariTrendingDefault trending is counted from statistik of the last days
+	HariTrendingDefault = 7
+)
+
+// StatistikProduk total view and sold kuantitas of produk, aggregated by background task
+type StatistikProduk struct {
+	ProdukID  uint `gorm:"primaryKey;autoIncrement:false"`
+	Dilihat   uint `gorm:"not null;default:0"`
+	Terjual   uint `gorm:"not null;default:0;index"`
+	UpdatedAt time.Time
+}
+
+// StatistikHarian view and sold kuantitas of produk in one day, used for trending
+type StatistikHarian struct {
+	ProdukID uint      `gorm:"primaryKey;autoIncrement:false"`
+	Tanggal  time.Time `gorm:"primaryKey;type:date;index"`
+	Dilihat  uint      `gorm:"not null;default:0"`
+	Terjual  uint      `gorm:"not null;default:0"`
+}
+
+type FilterPopuler struct {
+	Limit      int
+	Offset     int
+	CategoryID uint
+	// Hari only for trending, number of last days counted
+	Hari int
+}
@@ -1,21 +1,33 @@
 package daos
 
-import "time"
+import (
+	"gorm.io/gorm"
+	"time"
+)
 
 type Toko struct {
-	ID        uint
-	UserID    uint   `gorm:"not null;unique"`
-	NamaToko  string `gorm:"type:varchar(255);not null"`
-	UrlFoto   string `gorm:"type:varchar(255)"`
-	Produk    []Produk
-	UpdatedAt time.Time
-	CreatedAt time.Time
-	LogProduk []LogProduk
-	DetailTRX []DetailTRX
+	ID       uint
+	UserID   uint   `gorm:"not null;unique"`
+	NamaToko string `gorm:"type:varchar(255);not null"`
+	Slug     string `gorm:"type:varchar(255)"`
+	UrlFoto  string `gorm:"type:varchar(255)"`
+	// BatasStokRendah default low stock threshold of produk in the toko, nil use BatasStokRendahDefault
+	BatasStokRendah *uint
+	// Terpercaya produk of trusted toko is approved without waiting for admin review
+	Terpercaya bool `gorm:"not null;default:false"`
+	Produk     []Produk
+	UpdatedAt  time.Time
+	CreatedAt  time.Time
+	DeletedAt  gorm.DeletedAt `gorm:"index"`
+	LogProduk  []LogProduk
+	DetailTRX  []DetailTRX
+	// Versi increased on every update of the toko by its owner, sent as ETag so conflicting update can be rejected
+	Versi uint `gorm:"not null;default:1"`
 }
 
 type FilterToko struct {
-	Limit  int
-	Offset int
-	Nama   string
+	Limit     int
+	Offset    int
+	Nama      string
+	SetelahID uint
 }
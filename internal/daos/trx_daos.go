@@ -9,11 +9,19 @@ type TRX struct {
 	HargaTotal  uint
 	KodeInvoice string `gorm:"type:varchar(255)"`
 	MethodBayar string `gorm:"type:varchar(255)"`
-	UpdatedAt   time.Time
-	CreatedAt   time.Time
-	DetailTRX   []DetailTRX
+	// StatusPembayaran pending until payment is confirmed by admin, toko can only ship paid trx
+	StatusPembayaran string `gorm:"type:varchar(20);not null;default:pending;index"`
+	DibayarPada      *time.Time
+	UpdatedAt        time.Time
+	CreatedAt        time.Time
+	DetailTRX        []DetailTRX
 }
 
+const (
+	PembayaranMenunggu = "pending"
+	PembayaranLunas    = "paid"
+)
+
 type TRXResponse struct {
 	ID          uint
 	UserID      uint
@@ -22,12 +30,15 @@ type TRXResponse struct {
 	HargaTotal  uint
 	KodeInvoice string `gorm:"type:varchar(255)"`
 	MethodBayar string `gorm:"type:varchar(255)"`
-	UpdatedAt   time.Time
-	CreatedAt   time.Time
-	DetailTRX   []DetailTRXResponse
+	// StatusPembayaran pending or paid
+	StatusPembayaran string
+	UpdatedAt        time.Time
+	CreatedAt        time.Time
+	DetailTRX        []DetailTRXResponse
 }
 
 type FilterTRX struct {
-	Limit  int
-	Offset int
+	Limit     int
+	Offset    int
+	SetelahID uint
 }
@@ -0,0 +1,40 @@
+package daos
+
+import "time"
+
+// OpsiVarian option of produk variant, e.g. ukuran with nilai ["S","M","L"]
+type OpsiVarian struct {
+	ID        uint
+	ProdukID  uint   `gorm:"not null;index"`
+	Nama      string `gorm:"type:varchar(50);not null"`
+	Nilai     string `gorm:"type:text"`
+	Urutan    uint
+	UpdatedAt time.Time
+	CreatedAt time.Time
+}
+
+// SKU sellable combination of opsi varian, zero harga means using harga of produk
+type SKU struct {
+	ID            uint
+	ProdukID      uint   `gorm:"not null;index"`
+	KodeSKU       string `gorm:"type:varchar(100);not null;unique"`
+	Varian        string `gorm:"type:text"`
+	HargaReseller uint
+	HargaKonsumen uint
+	Stok          uint
+	FotoProduk    []FotoProduk `gorm:"foreignKey:SKUID"`
+	UpdatedAt     time.Time
+	CreatedAt     time.Time
+}
+
+// Harga return harga of sku, harga of produk used when sku does not override it
+func (s SKU) Harga(produk Produk) (hargaReseller, hargaKonsumen uint) {
+	hargaReseller, hargaKonsumen = produk.HargaReseller, produk.HargaKonsumen
+	if s.HargaReseller != 0 {
+		hargaReseller = s.HargaReseller
+	}
+	if s.HargaKonsumen != 0 {
+		hargaKonsumen = s.HargaKonsumen
+	}
+	return hargaReseller, hargaKonsumen
+}
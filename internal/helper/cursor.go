@@ -0,0 +1,33 @@
+package helper
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+)
+
+// Cursor sort key and id of the last item of a page for keyset pagination, client only see it as opaque string
+type Cursor struct {
+	Sort  string `json:"s,omitempty"`
+	Nilai string `json:"n,omitempty"`
+	ID    uint   `json:"i"`
+}
+
+var ErrCursorInvalid = errors.New("invalid cursor")
+
+func EncodeCursor(cursor Cursor) string {
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor parse cursor from client, cursor created for another sort order is rejected
+func DecodeCursor(text string, sort string) (cursor Cursor, err error) {
+	data, err := base64.RawURLEncoding.DecodeString(text)
+	if err != nil {
+		return cursor, ErrCursorInvalid
+	}
+	if err = json.Unmarshal(data, &cursor); err != nil || cursor.ID == 0 || cursor.Sort != sort {
+		return Cursor{}, ErrCursorInvalid
+	}
+	return cursor, nil
+}
@@ -0,0 +1,40 @@
+package helper
+
+import (
+	"encoding/base64"
+	"testing"
+)
+
+func TestCursor(t *testing.T) {
+	listCursor := []Cursor{
+		{ID: 1},
+		{Sort: "harga_asc", Nilai: "15000", ID: 42},
+		{Sort: "terbaru", Nilai: "2024-01-01T10:00:00Z", ID: 7},
+	}
+	for _, cursor := range listCursor {
+		hasil, err := DecodeCursor(EncodeCursor(cursor), cursor.Sort)
+		if err != nil || hasil != cursor {
+			t.Errorf("expected %+v, got %+v %v", cursor, hasil, err)
+		}
+	}
+}
+
+func TestDecodeCursorInvalid(t *testing.T) {
+	listKasus := []struct {
+		nama string
+		teks string
+		sort string
+	}{
+		{"bukan base64", "%%%", ""},
+		{"bukan json", base64.RawURLEncoding.EncodeToString([]byte("bukan json")), ""},
+		{"tanpa id", EncodeCursor(Cursor{Sort: "harga_asc", Nilai: "1"}), "harga_asc"},
+		{"urutan lain", EncodeCursor(Cursor{Sort: "harga_asc", Nilai: "1", ID: 3}), "harga_desc"},
+		{"padding base64 standar", base64.URLEncoding.EncodeToString([]byte(`{"i":3}`)), ""},
+		{"kosong", "", ""},
+	}
+	for _, kasus := range listKasus {
+		if _, err := DecodeCursor(kasus.teks, kasus.sort); err != ErrCursorInvalid {
+			t.Errorf("%s: expected ErrCursorInvalid, got %v", kasus.nama, err)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package helper
+
+import (
+	"strconv"
+	"strings"
+)
+
+// PanjangMaksSlug maximum length of slug including number added on collision
+const PanjangMaksSlug = 100
+
+// hurufSlug latin letter with accent written without it
+var hurufSlug = map[rune]string{
+	'à': "a", 'á': "a", 'â': "a", 'ã': "a", 'ä': "a", 'å': "a", 'ā': "a", 'æ': "ae",
+	'ç': "c", 'č': "c", 'đ': "d", 'è': "e", 'é': "e", 'ê': "e", 'ë': "e", 'ē': "e",
+	'ì': "i", 'í': "i", 'î': "i", 'ï': "i", 'ī': "i", 'ł': "l", 'ñ': "n",
+	'ò': "o", 'ó': "o", 'ô': "o", 'õ': "o", 'ö': "o", 'ø': "o", 'ō': "o", 'œ': "oe",
+	'š': "s", 'ß': "ss", 'ù': "u", 'ú': "u", 'û': "u", 'ü': "u", 'ū': "u", 'ý': "y", 'ÿ': "y", 'ž': "z",
+}
+
+// simbolSlug symbol that has meaning, written as Indonesian word
+var simbolSlug = map[rune]string{'&': "dan", '@': "di", '%': "persen", '+': "plus"}
+
+// BuatSlug url safe slug of text, only a-z, 0-9 and dash is kept. Apostrophe is dropped so "Jum'at" become "jumat",
+// empty is returned when text has no latin letter or digit.
+func BuatSlug(teks string) string {
+	var slug strings.Builder
+	pisah := false
+	tulis := func(s string) {
+		if pisah && slug.Len() > 0 {
+			slug.WriteByte('-')
+		}
+		pisah = false
+		slug.WriteString(s)
+	}
+	for _, r := range strings.ToLower(teks) {
+		switch {
+		case r >= 'a' && r <= 'z' || r >= '0' && r <= '9':
+			tulis(string(r))
+		case r == '\'' || r == '’' || r == '`':
+		case hurufSlug[r] != "":
+			tulis(hurufSlug[r])
+		case simbolSlug[r] != "":
+			pisah = true
+			tulis(simbolSlug[r])
+			pisah = true
+		default:
+			pisah = true
+		}
+	}
+	return potongSlug(slug.String(), PanjangMaksSlug)
+}
+
+// potongSlug cut slug at dash so the last word is not cut in half when possible
+func potongSlug(slug string, panjang int) string {
+	if len(slug) <= panjang {
+		return slug
+	}
+	slug = slug[:panjang]
+	if i := strings.LastIndexByte(slug, '-'); i > 0 {
+		slug = slug[:i]
+	}
+	return strings.Trim(slug, "-")
+}
+
+// SlugBernomor slug used when dasar is already taken, the first slug has no number and the next one start from 2
+func SlugBernomor(dasar string, nomor int) string {
+	if nomor <= 1 {
+		return dasar
+	}
+	akhiran := "-" + strconv.Itoa(nomor)
+	return potongSlug(dasar, PanjangMaksSlug-len(akhiran)) + akhiran
+}
+
+// SlugDariDasar report whether slug is made from dasar, with or without number
+func SlugDariDasar(slug, dasar string) bool {
+	if slug == dasar {
+		return true
+	}
+	i := strings.LastIndexByte(slug, '-')
+	if i < 0 {
+		return false
+	}
+	nomor, err := strconv.Atoi(slug[i+1:])
+	return err == nil && nomor > 1 && SlugBernomor(dasar, nomor) == slug
+}
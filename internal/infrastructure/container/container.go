@@ -8,7 +8,13 @@ import (
 
 	"github.com/spf13/viper"
 	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/helper"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/infrastructure/gambar"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/infrastructure/jadwal"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/infrastructure/messaging"
 	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/infrastructure/mysql"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/infrastructure/search"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/infrastructure/storage"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/infrastructure/tayangan"
 	"gorm.io/gorm"
 )
 
@@ -18,8 +24,14 @@ const currentfilepath = "internal/infrastructure/container/container.go"
 
 type (
 	Container struct {
-		Mysqldb *gorm.DB
-		Apps    *Apps
+		Mysqldb   *gorm.DB
+		Apps      *Apps
+		Messaging *messaging.Queue
+		Search    search.Index
+		Gambar    *gambar.Pipeline
+		Storage   *storage.MediaStorage
+		Jadwal    *jadwal.Penjadwal
+		Tayangan  *tayangan.Pencatat
 	}
 	Apps struct {
 		Name             string `mapstructure:"name"`
@@ -29,6 +41,9 @@ type (
 		HttpPort         int    `mapstructure:"http_port"`
 		SecretJwt        string `mapstructure:"secretjwt"`
 		URLPrvovinceCity string `mapstructure:"url_province_city"`
+		RetensiSampah    int    `mapstructure:"retensi_sampah_hari"`
+		MaxBody          int    `mapstructure:"max_body_mb"`
+		URLResetSandi    string `mapstructure:"url_reset_kata_sandi"`
 	}
 )
 
@@ -78,9 +93,21 @@ func AppsInit(v *viper.Viper) (apps Apps) {
 func InitContainer() (cont *Container) {
 	apps := AppsInit(v)
 	mysqldb := mysql.DatabaseInit(v)
+	messagingQueue := messaging.MessagingInit(v)
+	searchIndex := search.SearchInit(v)
+	gambarPipeline := gambar.GambarInit(v)
+	blobStorage := storage.StorageInit(v)
+	penjadwal := jadwal.JadwalInit(v)
+	pencatatTayangan := tayangan.TayanganInit(v)
 
 	return &Container{
-		Apps:    &apps,
-		Mysqldb: mysqldb,
+		Apps:      &apps,
+		Mysqldb:   mysqldb,
+		Messaging: messagingQueue,
+		Search:    searchIndex,
+		Gambar:    gambarPipeline,
+		Storage:   blobStorage,
+		Jadwal:    penjadwal,
+		Tayangan:  pencatatTayangan,
 	}
 }
@@ -0,0 +1,29 @@
+package gambar
+
+import (
+	"fmt"
+
+	"github.com/spf13/viper"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/helper"
+)
+
+const currentfilepath = "internal/infrastructure/gambar/gambar.go"
+
+type GambarConf struct {
+	MaxUkuran  int64 `mapstructure:"gambar_max_ukuran"`
+	MaxDimensi int   `mapstructure:"gambar_max_dimensi"`
+	MinDimensi int   `mapstructure:"gambar_min_dimensi"`
+	Kualitas   int   `mapstructure:"gambar_kualitas"`
+}
+
+// GambarInit setup image pipeline from configuration, missing value fall back to default
+func GambarInit(v *viper.Viper) *Pipeline {
+	var gambarConf GambarConf
+	if err := v.Unmarshal(&gambarConf); err != nil {
+		helper.Logger(currentfilepath, helper.LoggerLevelPanic, fmt.Sprintf("failed init image pipeline : %s", err.Error()))
+	}
+	pipeline := NewPipeline(gambarConf)
+
+	helper.Logger(currentfilepath, helper.LoggerLevelInfo, "⇨ Image pipeline ready")
+	return pipeline
+}
@@ -0,0 +1,145 @@
+package gambar
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func buatGambar(lebar, tinggi int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, lebar, tinggi))
+	for y := 0; y < tinggi; y++ {
+		for x := 0; x < lebar; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 100, A: 255})
+		}
+	}
+	return img
+}
+
+// jpegDenganExif encode image as jpeg with APP1 EXIF segment containing orientation tag
+func jpegDenganExif(t *testing.T, img image.Image, orientasi byte) []byte {
+	var buffer bytes.Buffer
+	if err := jpeg.Encode(&buffer, img, nil); err != nil {
+		t.Fatal(err)
+	}
+	tiff := []byte{'M', 'M', 0, 42, 0, 0, 0, 8, 0, 1, 0x01, 0x12, 0, 3, 0, 0, 0, 1, 0, orientasi, 0, 0, 0, 0, 0, 0}
+	segmen := append([]byte("Exif\x00\x00"), tiff...)
+	app1 := append([]byte{0xFF, 0xE1, byte((len(segmen) + 2) >> 8), byte(len(segmen) + 2)}, segmen...)
+	data := buffer.Bytes()
+	return append(append([]byte{0xFF, 0xD8}, app1...), data[2:]...)
+}
+
+func TestProsesRendisi(t *testing.T) {
+	pipeline := NewPipeline(GambarConf{})
+	var buffer bytes.Buffer
+	if err := png.Encode(&buffer, buatGambar(1600, 800)); err != nil {
+		t.Fatal(err)
+	}
+	listRendisi, err := pipeline.Proses(&buffer)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	harapan := map[string][2]int{RendisiThumbnail: {200, 100}, RendisiMedium: {600, 300}, RendisiLarge: {1200, 600}}
+	if len(listRendisi) != len(harapan) {
+		t.Fatalf("expected %d rendition, got %d", len(harapan), len(listRendisi))
+	}
+	for _, v := range listRendisi {
+		if v.Lebar != harapan[v.Nama][0] || v.Tinggi != harapan[v.Nama][1] {
+			t.Errorf("%s: unexpected size %dx%d", v.Nama, v.Lebar, v.Tinggi)
+		}
+		if _, format, err := image.DecodeConfig(bytes.NewReader(v.Data)); err != nil || format != "jpeg" {
+			t.Errorf("%s: rendition must be jpeg, got %q %v", v.Nama, format, err)
+		}
+	}
+}
+
+func TestProsesExif(t *testing.T) {
+	pipeline := NewPipeline(GambarConf{})
+	data := jpegDenganExif(t, buatGambar(300, 150), 6)
+	if orientasiExif(data) != 6 {
+		t.Fatalf("orientation tag is not read")
+	}
+	listRendisi, err := pipeline.Proses(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	for _, v := range listRendisi {
+		if bytes.Contains(v.Data, []byte("Exif")) {
+			t.Errorf("%s: EXIF metadata must be stripped", v.Nama)
+		}
+	}
+	// rotated 90 degree so portrait
+	if large := listRendisi[len(listRendisi)-1]; large.Lebar != 150 || large.Tinggi != 300 {
+		t.Errorf("orientation not applied, got %dx%d", large.Lebar, large.Tinggi)
+	}
+}
+
+func TestProsesDitolak(t *testing.T) {
+	pipeline := NewPipeline(GambarConf{MaxUkuran: 1 << 20, MaxDimensi: 1000})
+	var besar bytes.Buffer
+	if err := png.Encode(&besar, buatGambar(1200, 200)); err != nil {
+		t.Fatal(err)
+	}
+	var kecil bytes.Buffer
+	if err := png.Encode(&kecil, buatGambar(50, 50)); err != nil {
+		t.Fatal(err)
+	}
+	listKasus := []struct {
+		Nama string
+		Data []byte
+		Err  error
+	}{
+		{"text", []byte("<html><body>bukan gambar</body></html>"), ErrBukanGambar},
+		{"too large file", append([]byte("\x89PNG\r\n\x1a\n"), make([]byte, 1<<20)...), ErrUkuranBesar},
+		{"too large dimension", besar.Bytes(), ErrDimensiBesar},
+		{"too small dimension", kecil.Bytes(), ErrDimensiKecil},
+		{"corrupted", []byte("\x89PNG\r\n\x1a\nrusak"), ErrGambarRusak},
+	}
+	for _, v := range listKasus {
+		if _, err := pipeline.Proses(bytes.NewReader(v.Data)); !errors.Is(err, v.Err) {
+			t.Errorf("%s: expected %v, got %v", v.Nama, v.Err, err)
+		}
+	}
+}
+
+func TestUnduh(t *testing.T) {
+	var buffer bytes.Buffer
+	if err := png.Encode(&buffer, buatGambar(300, 300)); err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/foto.png" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(buffer.Bytes())
+	}))
+	defer server.Close()
+
+	// test server listen on loopback which is refused by default
+	pipeline := NewPipeline(GambarConf{})
+	if _, err := pipeline.Unduh(context.Background(), server.URL+"/foto.png"); !errors.Is(err, ErrURLDitolak) {
+		t.Errorf("loopback address must be refused, got %v", err)
+	}
+	for _, URL := range []string{"ftp://example.com/foto.png", "file:///etc/passwd", "foto.png"} {
+		if _, err := pipeline.Unduh(context.Background(), URL); !errors.Is(err, ErrURLDitolak) {
+			t.Errorf("%s: expected ErrURLDitolak, got %v", URL, err)
+		}
+	}
+
+	pipeline.klien = klienUnduh(true)
+	listRendisi, err := pipeline.Unduh(context.Background(), server.URL+"/foto.png")
+	if err != nil || len(listRendisi) != len(ListRendisi) {
+		t.Fatalf("unexpected result %d rendition %v", len(listRendisi), err)
+	}
+	if _, err := pipeline.Unduh(context.Background(), server.URL+"/hilang.png"); !errors.Is(err, ErrUnduhGagal) {
+		t.Errorf("expected ErrUnduhGagal, got %v", err)
+	}
+}
@@ -0,0 +1,168 @@
+package gambar
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+	"io"
+	"net/http"
+
+	// register decoder of accepted format
+	_ "image/gif"
+	_ "image/png"
+)
+
+const (
+	RendisiThumbnail = "thumbnail"
+	RendisiMedium    = "medium"
+	RendisiLarge     = "large"
+
+	// Ekstensi every rendition is re-encoded to jpeg
+	Ekstensi    = ".jpg"
+	ContentType = "image/jpeg"
+
+	defaultMaxUkuran  = 4 << 20
+	defaultMaxDimensi = 6000
+	defaultMinDimensi = 100
+	defaultKualitas   = 85
+)
+
+// ListRendisi name and longest side of every generated rendition, from the smallest
+var ListRendisi = []struct {
+	Nama    string
+	Panjang int
+}{
+	{RendisiThumbnail, 200},
+	{RendisiMedium, 600},
+	{RendisiLarge, 1200},
+}
+
+// contentTypeDiterima sniffed content type that can be decoded
+var contentTypeDiterima = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/gif":  true,
+}
+
+var (
+	ErrBukanGambar    = errors.New("file is not a supported image, only jpeg, png and gif are accepted")
+	ErrUkuranBesar    = errors.New("image file is too large")
+	ErrDimensiBesar   = errors.New("image dimension is too large")
+	ErrDimensiKecil   = errors.New("image dimension is too small")
+	ErrGambarRusak    = errors.New("image file is corrupted")
+	ErrGambarDiproses = errors.New("failed to encode image")
+)
+
+// Rendisi one re-encoded rendition of uploaded image
+type Rendisi struct {
+	Nama   string
+	Lebar  int
+	Tinggi int
+	Data   []byte
+}
+
+type Pipeline struct {
+	conf  GambarConf
+	klien *http.Client
+}
+
+func NewPipeline(conf GambarConf) *Pipeline {
+	if conf.MaxUkuran <= 0 {
+		conf.MaxUkuran = defaultMaxUkuran
+	}
+	if conf.MaxDimensi <= 0 {
+		conf.MaxDimensi = defaultMaxDimensi
+	}
+	if conf.MinDimensi <= 0 {
+		conf.MinDimensi = defaultMinDimensi
+	}
+	if conf.Kualitas <= 0 || conf.Kualitas > 100 {
+		conf.Kualitas = defaultKualitas
+	}
+	return &Pipeline{conf: conf, klien: klienUnduh(false)}
+}
+
+// Proses validate uploaded image and return every rendition ordered like ListRendisi.
+// Metadata such as EXIF is dropped by re-encoding, orientation tag is applied to pixel first.
+func (p *Pipeline) Proses(r io.Reader) (listRendisi []Rendisi, err error) {
+	data, err := io.ReadAll(io.LimitReader(r, p.conf.MaxUkuran+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > p.conf.MaxUkuran {
+		return nil, fmt.Errorf("%w, maximum is %d bytes", ErrUkuranBesar, p.conf.MaxUkuran)
+	}
+
+	// content type is sniffed from content, client filename and header are not trusted
+	if !contentTypeDiterima[http.DetectContentType(data)] {
+		return nil, ErrBukanGambar
+	}
+
+	// check dimension from header before decoding whole pixel
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrGambarRusak
+	}
+	if config.Width > p.conf.MaxDimensi || config.Height > p.conf.MaxDimensi {
+		return nil, fmt.Errorf("%w, maximum is %dx%d pixel", ErrDimensiBesar, p.conf.MaxDimensi, p.conf.MaxDimensi)
+	}
+	if config.Width < p.conf.MinDimensi || config.Height < p.conf.MinDimensi {
+		return nil, fmt.Errorf("%w, minimum is %dx%d pixel", ErrDimensiKecil, p.conf.MinDimensi, p.conf.MinDimensi)
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrGambarRusak
+	}
+	gambar := putar(ratakan(src), orientasiExif(data))
+
+	for _, v := range ListRendisi {
+		hasil := gambar
+		if lebar, tinggi := ukuranMuat(gambar.Bounds().Dx(), gambar.Bounds().Dy(), v.Panjang); lebar != gambar.Bounds().Dx() {
+			hasil = perkecil(gambar, lebar, tinggi)
+		}
+		var buffer bytes.Buffer
+		if err := jpeg.Encode(&buffer, hasil, &jpeg.Options{Quality: p.conf.Kualitas}); err != nil {
+			return nil, ErrGambarDiproses
+		}
+		listRendisi = append(listRendisi, Rendisi{
+			Nama:   v.Nama,
+			Lebar:  hasil.Bounds().Dx(),
+			Tinggi: hasil.Bounds().Dy(),
+			Data:   buffer.Bytes(),
+		})
+	}
+	return listRendisi, nil
+}
+
+// ratakan draw image on white background, transparent pixel can not be stored as jpeg
+func ratakan(src image.Image) *image.RGBA {
+	bounds := src.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(dst, dst.Bounds(), &image.Uniform{C: color.White}, image.Point{}, draw.Src)
+	draw.Draw(dst, dst.Bounds(), src, bounds.Min, draw.Over)
+	return dst
+}
+
+// ukuranMuat size that fit inside panjang x panjang box keeping aspect ratio, image is never enlarged
+func ukuranMuat(lebar, tinggi, panjang int) (int, int) {
+	if lebar <= panjang && tinggi <= panjang {
+		return lebar, tinggi
+	}
+	if lebar >= tinggi {
+		tinggi = tinggi * panjang / lebar
+		if tinggi < 1 {
+			tinggi = 1
+		}
+		return panjang, tinggi
+	}
+	lebar = lebar * panjang / tinggi
+	if lebar < 1 {
+		lebar = 1
+	}
+	return lebar, panjang
+}
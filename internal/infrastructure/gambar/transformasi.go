@@ -0,0 +1,144 @@
+package gambar
+
+import (
+	"encoding/binary"
+	"image"
+)
+
+// orientasiExif read orientation tag (1-8) of jpeg EXIF metadata, 1 when there is none
+func orientasiExif(data []byte) int {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return 1
+	}
+	i := 2
+	for i+4 <= len(data) {
+		if data[i] != 0xFF {
+			return 1
+		}
+		marker := data[i+1]
+		// metadata segment always come before start of scan
+		if marker == 0xDA || marker == 0xD9 {
+			return 1
+		}
+		panjang := int(binary.BigEndian.Uint16(data[i+2:]))
+		if panjang < 2 || i+2+panjang > len(data) {
+			return 1
+		}
+		segmen := data[i+4 : i+2+panjang]
+		if marker == 0xE1 && len(segmen) > 6 && string(segmen[:6]) == "Exif\x00\x00" {
+			return orientasiTIFF(segmen[6:])
+		}
+		i += 2 + panjang
+	}
+	return 1
+}
+
+// orientasiTIFF find orientation tag in the first IFD of EXIF TIFF structure
+func orientasiTIFF(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 1
+	}
+	var urutan binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		urutan = binary.LittleEndian
+	case "MM":
+		urutan = binary.BigEndian
+	default:
+		return 1
+	}
+	ifd := int(urutan.Uint32(tiff[4:8]))
+	if ifd < 8 || ifd+2 > len(tiff) {
+		return 1
+	}
+	jumlah := int(urutan.Uint16(tiff[ifd:]))
+	for k := 0; k < jumlah; k++ {
+		entri := ifd + 2 + k*12
+		if entri+12 > len(tiff) {
+			return 1
+		}
+		if urutan.Uint16(tiff[entri:]) == 0x0112 {
+			if orientasi := int(urutan.Uint16(tiff[entri+8:])); orientasi >= 1 && orientasi <= 8 {
+				return orientasi
+			}
+			return 1
+		}
+	}
+	return 1
+}
+
+// putar flip and rotate image so it is displayed upright without EXIF orientation
+func putar(src *image.RGBA, orientasi int) *image.RGBA {
+	if orientasi <= 1 || orientasi > 8 {
+		return src
+	}
+	lebar, tinggi := src.Bounds().Dx(), src.Bounds().Dy()
+	dstLebar, dstTinggi := lebar, tinggi
+	if orientasi >= 5 {
+		dstLebar, dstTinggi = tinggi, lebar
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, dstLebar, dstTinggi))
+	for y := 0; y < dstTinggi; y++ {
+		for x := 0; x < dstLebar; x++ {
+			var sx, sy int
+			switch orientasi {
+			case 2:
+				sx, sy = lebar-1-x, y
+			case 3:
+				sx, sy = lebar-1-x, tinggi-1-y
+			case 4:
+				sx, sy = x, tinggi-1-y
+			case 5:
+				sx, sy = y, x
+			case 6:
+				sx, sy = y, tinggi-1-x
+			case 7:
+				sx, sy = lebar-1-y, tinggi-1-x
+			case 8:
+				sx, sy = lebar-1-y, x
+			}
+			s := src.PixOffset(sx, sy)
+			d := dst.PixOffset(x, y)
+			copy(dst.Pix[d:d+4], src.Pix[s:s+4])
+		}
+	}
+	return dst
+}
+
+// perkecil downscale image with area averaging, every source pixel covered by destination pixel is averaged
+func perkecil(src *image.RGBA, lebar, tinggi int) *image.RGBA {
+	srcLebar, srcTinggi := src.Bounds().Dx(), src.Bounds().Dy()
+	dst := image.NewRGBA(image.Rect(0, 0, lebar, tinggi))
+	for y := 0; y < tinggi; y++ {
+		y0 := y * srcTinggi / tinggi
+		y1 := (y + 1) * srcTinggi / tinggi
+		if y1 <= y0 {
+			y1 = y0 + 1
+		}
+		for x := 0; x < lebar; x++ {
+			x0 := x * srcLebar / lebar
+			x1 := (x + 1) * srcLebar / lebar
+			if x1 <= x0 {
+				x1 = x0 + 1
+			}
+			var r, g, b, a, n uint32
+			for sy := y0; sy < y1; sy++ {
+				s := src.PixOffset(x0, sy)
+				for sx := x0; sx < x1; sx++ {
+					r += uint32(src.Pix[s])
+					g += uint32(src.Pix[s+1])
+					b += uint32(src.Pix[s+2])
+					a += uint32(src.Pix[s+3])
+					s += 4
+					n++
+				}
+			}
+			d := dst.PixOffset(x, y)
+			dst.Pix[d] = uint8(r / n)
+			dst.Pix[d+1] = uint8(g / n)
+			dst.Pix[d+2] = uint8(b / n)
+			dst.Pix[d+3] = uint8(a / n)
+		}
+	}
+	return dst
+}
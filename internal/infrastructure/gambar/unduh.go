@@ -0,0 +1,71 @@
+package gambar
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"syscall"
+	"time"
+)
+
+const defaultTimeoutUnduh = 15 * time.Second
+
+var (
+	ErrURLDitolak = errors.New("image url must be a public http or https address")
+	ErrUnduhGagal = errors.New("failed to download image")
+)
+
+// klienUnduh http client that only dial public address, so image url given by user can not reach internal service.
+// Redirect use the same dialer so it is checked too.
+func klienUnduh(izinLokal bool) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: defaultTimeoutUnduh,
+		Control: func(network, address string, c syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if !izinLokal && !alamatPublik(net.ParseIP(host)) {
+				return ErrURLDitolak
+			}
+			return nil
+		},
+	}
+	return &http.Client{
+		Timeout:   defaultTimeoutUnduh,
+		Transport: &http.Transport{DialContext: dialer.DialContext, Proxy: nil},
+	}
+}
+
+func alamatPublik(ip net.IP) bool {
+	return ip != nil && !ip.IsLoopback() && !ip.IsPrivate() && !ip.IsUnspecified() &&
+		!ip.IsLinkLocalUnicast() && !ip.IsLinkLocalMulticast() && !ip.IsMulticast()
+}
+
+// Unduh download image from url then process it like uploaded file
+func (p *Pipeline) Unduh(ctx context.Context, URL string) (listRendisi []Rendisi, err error) {
+	alamat, err := url.Parse(URL)
+	if err != nil || (alamat.Scheme != "http" && alamat.Scheme != "https") || alamat.Host == "" {
+		return nil, ErrURLDitolak
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, alamat.String(), nil)
+	if err != nil {
+		return nil, ErrURLDitolak
+	}
+	response, err := p.klien.Do(request)
+	if err != nil {
+		if errors.Is(err, ErrURLDitolak) {
+			return nil, ErrURLDitolak
+		}
+		return nil, fmt.Errorf("%w: %s", ErrUnduhGagal, err.Error())
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: status %d", ErrUnduhGagal, response.StatusCode)
+	}
+	return p.Proses(response.Body)
+}
@@ -0,0 +1,29 @@
+package jadwal
+
+import (
+	"fmt"
+
+	"github.com/spf13/viper"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/helper"
+)
+
+const currentfilepath = "internal/infrastructure/jadwal/jadwal.go"
+
+type JadwalConf struct {
+	// Nonaktif turn off background task, used when several instance share one database and only one should run them
+	Nonaktif bool `mapstructure:"jadwal_nonaktif"`
+}
+
+// JadwalInit setup scheduler for background task, task is added by handler
+func JadwalInit(v *viper.Viper) *Penjadwal {
+	var jadwalConf JadwalConf
+	if err := v.Unmarshal(&jadwalConf); err != nil {
+		helper.Logger(currentfilepath, helper.LoggerLevelPanic, fmt.Sprintf("failed init jadwal : %s", err.Error()))
+	}
+
+	penjadwal := NewPenjadwal(!jadwalConf.Nonaktif)
+	if jadwalConf.Nonaktif {
+		helper.Logger(currentfilepath, helper.LoggerLevelInfo, "⇨ Background task is turned off")
+	}
+	return penjadwal
+}
@@ -0,0 +1,93 @@
+package jadwal
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestPenjadwal(t *testing.T) {
+	penjadwal := NewPenjadwal(true)
+
+	var jalan, panik int32
+	penjadwal.Tambah("hitung", 10*time.Millisecond, func(ctx context.Context) {
+		atomic.AddInt32(&jalan, 1)
+	})
+	penjadwal.Tambah("panik", 10*time.Millisecond, func(ctx context.Context) {
+		atomic.AddInt32(&panik, 1)
+		panic("gagal")
+	})
+	time.Sleep(55 * time.Millisecond)
+	penjadwal.Stop()
+
+	// first run is right away, panic does not stop the next run
+	if n := atomic.LoadInt32(&jalan); n < 3 {
+		t.Errorf("task run %d times, want at least 3", n)
+	}
+	if n := atomic.LoadInt32(&panik); n < 3 {
+		t.Errorf("panicking task run %d times, want at least 3", n)
+	}
+
+	// no task run after stop
+	sebelum := atomic.LoadInt32(&jalan)
+	penjadwal.Tambah("setelah stop", 10*time.Millisecond, func(ctx context.Context) {
+		atomic.AddInt32(&jalan, 1)
+	})
+	time.Sleep(30 * time.Millisecond)
+	if n := atomic.LoadInt32(&jalan); n != sebelum {
+		t.Errorf("task run after stop")
+	}
+}
+
+func TestPenjadwalNonaktif(t *testing.T) {
+	penjadwal := NewPenjadwal(false)
+
+	var jalan int32
+	penjadwal.Tambah("hitung", 10*time.Millisecond, func(ctx context.Context) {
+		atomic.AddInt32(&jalan, 1)
+	})
+	time.Sleep(30 * time.Millisecond)
+	penjadwal.Stop()
+	if n := atomic.LoadInt32(&jalan); n != 0 {
+		t.Errorf("turned off scheduler run task %d times", n)
+	}
+}
+
+func TestPenjadwalTambahLokal(t *testing.T) {
+	// task of every instance still run when periodic task is turned off
+	penjadwal := NewPenjadwal(false)
+
+	var jalan int32
+	penjadwal.TambahLokal("lokal", 10*time.Millisecond, func(ctx context.Context) {
+		atomic.AddInt32(&jalan, 1)
+	})
+	time.Sleep(25 * time.Millisecond)
+	penjadwal.Stop()
+	if n := atomic.LoadInt32(&jalan); n < 2 {
+		t.Errorf("local task run %d times, want at least 2", n)
+	}
+}
+
+func TestPenjadwalSekali(t *testing.T) {
+	// one-off task still run when periodic task is turned off
+	penjadwal := NewPenjadwal(false)
+
+	var jalan int32
+	if !penjadwal.Sekali("sekali", func(ctx context.Context) {
+		atomic.AddInt32(&jalan, 1)
+		<-ctx.Done()
+	}) {
+		t.Fatal("task refused before stop")
+	}
+	time.Sleep(10 * time.Millisecond)
+
+	// stop cancel the running task and wait for it
+	penjadwal.Stop()
+	if n := atomic.LoadInt32(&jalan); n != 1 {
+		t.Errorf("task run %d times, want 1", n)
+	}
+	if penjadwal.Sekali("setelah stop", func(ctx context.Context) {}) {
+		t.Errorf("task accepted after stop")
+	}
+}
@@ -0,0 +1,89 @@
+package jadwal
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/helper"
+)
+
+// Penjadwal run background task periodically until stopped
+type Penjadwal struct {
+	aktif  bool
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewPenjadwal(aktif bool) *Penjadwal {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Penjadwal{aktif: aktif, ctx: ctx, cancel: cancel}
+}
+
+// Tambah run tugas right away then every interval. Tugas receive context that is cancelled on Stop,
+// panic is logged so the next run and other task keep going.
+func (p *Penjadwal) Tambah(nama string, interval time.Duration, tugas func(ctx context.Context)) {
+	if !p.aktif {
+		return
+	}
+	p.jadwalkan(nama, interval, tugas)
+}
+
+// TambahLokal same as Tambah for task that every instance must run such as writing data kept in its memory,
+// it is not affected by jadwal_nonaktif
+func (p *Penjadwal) TambahLokal(nama string, interval time.Duration, tugas func(ctx context.Context)) {
+	p.jadwalkan(nama, interval, tugas)
+}
+
+func (p *Penjadwal) jadwalkan(nama string, interval time.Duration, tugas func(ctx context.Context)) {
+	if interval <= 0 || p.ctx.Err() != nil {
+		return
+	}
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			p.jalankan(nama, tugas)
+			select {
+			case <-p.ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	helper.Logger(currentfilepath, helper.LoggerLevelInfo, fmt.Sprintf("⇨ Task %s scheduled every %s", nama, interval))
+}
+
+func (p *Penjadwal) jalankan(nama string, tugas func(ctx context.Context)) {
+	defer func() {
+		if r := recover(); r != nil {
+			helper.Logger(currentfilepath, helper.LoggerLevelError, fmt.Sprintf("task %s panic : %v", nama, r))
+		}
+	}()
+	tugas(p.ctx)
+}
+
+// Stop cancel running task and wait until every task return
+func (p *Penjadwal) Stop() {
+	p.cancel()
+	p.wg.Wait()
+}
+
+// Sekali run tugas once in background, used for job requested by user such as import.
+// It is not affected by jadwal_nonaktif because the instance that accept the request must run it,
+// tugas should stop when its context is cancelled on Stop. Return false when scheduler is already stopped.
+func (p *Penjadwal) Sekali(nama string, tugas func(ctx context.Context)) bool {
+	if p.ctx.Err() != nil {
+		return false
+	}
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		p.jalankan(nama, tugas)
+	}()
+	return true
+}
@@ -0,0 +1,68 @@
+package messaging
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/helper"
+)
+
+// LogSender stand-in sender for development and test, write every message to a file or to the logger
+type LogSender struct {
+	Path string
+	mu   sync.Mutex
+}
+
+type logEntry struct {
+	Channel string      `json:"channel"`
+	SentAt  string      `json:"sent_at"`
+	Message interface{} `json:"message"`
+}
+
+func NewLogSender(path string) *LogSender {
+	return &LogSender{Path: path}
+}
+
+func (ls *LogSender) SendEmail(ctx context.Context, email Email) error {
+	return ls.write("email", email)
+}
+
+func (ls *LogSender) SendSMS(ctx context.Context, sms SMS) error {
+	return ls.write("sms", sms)
+}
+
+func (ls *LogSender) write(channel string, message interface{}) error {
+	entry, err := json.Marshal(logEntry{
+		Channel: channel,
+		SentAt:  time.Now().Format(time.RFC3339),
+		Message: message,
+	})
+	if err != nil {
+		return err
+	}
+
+	// no file configured, print to logger
+	if ls.Path == "" {
+		helper.Logger(currentfilepath, helper.LoggerLevelInfo, fmt.Sprintf("%s sent : %s", channel, entry))
+		return nil
+	}
+
+	// append message as json line
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+	if err = os.MkdirAll(filepath.Dir(ls.Path), 0755); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(ls.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	_, err = file.Write(append(entry, '\n'))
+	return err
+}
@@ -0,0 +1,63 @@
+package messaging
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/helper"
+)
+
+const (
+	currentfilepath = "internal/infrastructure/messaging/messaging.go"
+	DriverSMTP      = "smtp"
+	DriverLog       = "log"
+)
+
+type MessagingConf struct {
+	Driver       string `mapstructure:"messaging_driver"`
+	LogPath      string `mapstructure:"messaging_log_path"`
+	DefaultLang  string `mapstructure:"messaging_default_lang"`
+	Workers      int    `mapstructure:"messaging_workers"`
+	QueueSize    int    `mapstructure:"messaging_queue_size"`
+	MaxRetry     int    `mapstructure:"messaging_max_retry"`
+	Backoff      int    `mapstructure:"messaging_backoff_second"`
+	SMTPHost     string `mapstructure:"smtp_host"`
+	SMTPPort     int    `mapstructure:"smtp_port"`
+	SMTPUsername string `mapstructure:"smtp_username"`
+	SMTPPassword string `mapstructure:"smtp_password"`
+	SMTPFrom     string `mapstructure:"smtp_from"`
+}
+
+// MessagingInit setup senders from configuration and start the send queue
+func MessagingInit(v *viper.Viper) *Queue {
+	var messagingConf MessagingConf
+	if err := v.Unmarshal(&messagingConf); err != nil {
+		helper.Logger(currentfilepath, helper.LoggerLevelPanic, fmt.Sprintf("failed init messaging : %s", err.Error()))
+	}
+
+	templates, err := NewTemplates(messagingConf.DefaultLang)
+	if err != nil {
+		helper.Logger(currentfilepath, helper.LoggerLevelPanic, fmt.Sprintf("failed parse messaging template : %s", err.Error()))
+	}
+
+	// sms only has log stand-in sender, email use smtp when configured
+	logSender := NewLogSender(messagingConf.LogPath)
+	var emailSender EmailSender = logSender
+	if messagingConf.Driver == DriverSMTP {
+		emailSender = NewSMTPSender(messagingConf.SMTPHost, messagingConf.SMTPPort, messagingConf.SMTPUsername, messagingConf.SMTPPassword, messagingConf.SMTPFrom)
+	}
+
+	queue := NewQueue(emailSender, logSender, templates, messagingConf.QueueSize, messagingConf.MaxRetry, time.Duration(messagingConf.Backoff)*time.Second)
+	queue.Start(messagingConf.Workers)
+
+	helper.Logger(currentfilepath, helper.LoggerLevelInfo, fmt.Sprintf("⇨ Messaging queue started with %s email driver", emailSenderName(messagingConf.Driver)))
+	return queue
+}
+
+func emailSenderName(driver string) string {
+	if driver == DriverSMTP {
+		return DriverSMTP
+	}
+	return DriverLog
+}
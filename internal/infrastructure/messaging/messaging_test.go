@@ -0,0 +1,174 @@
+package messaging
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type flakySender struct {
+	mu     sync.Mutex
+	fail   int
+	called int
+	sent   []Email
+}
+
+func (fs *flakySender) SendEmail(ctx context.Context, email Email) error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	fs.called++
+	if fs.called <= fs.fail {
+		return errors.New("smtp unavailable")
+	}
+	fs.sent = append(fs.sent, email)
+	return nil
+}
+
+func (fs *flakySender) SendSMS(ctx context.Context, sms SMS) error {
+	return nil
+}
+
+func TestTemplatesRender(t *testing.T) {
+	templates, err := NewTemplates(LangIndonesia)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := DataNotifikasi{Nama: "Budi", Payload: struct {
+		KodeInvoice string
+		HargaTotal  uint
+	}{"INV-1", 15000}}
+
+	message, err := templates.Render(TemplatePesananDibuat, LangEnglish, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if message.Subject != "Order INV-1 has been placed" {
+		t.Errorf("unexpected subject %q", message.Subject)
+	}
+
+	// unknown language fallback to default language
+	message, err = templates.Render(TemplatePesananDibuat, "fr", data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(message.Email, "Halo Budi") || !strings.Contains(message.SMS, "Rp15000") {
+		t.Errorf("unexpected message %+v", message)
+	}
+
+	if _, err = templates.Render("unknown", LangIndonesia, data); err == nil {
+		t.Error("expected error for unknown template")
+	}
+}
+
+func TestQueueRetry(t *testing.T) {
+	templates, err := NewTemplates(LangIndonesia)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sender := &flakySender{fail: 2}
+	queue := NewQueue(sender, sender, templates, 10, 3, time.Millisecond)
+	queue.Start(1)
+
+	if err = queue.KirimEmail("budi@example.com", TemplateRegistrasi, "", map[string]interface{}{"Nama": "Budi", "NamaToko": "Toko Budi"}); err != nil {
+		t.Fatal(err)
+	}
+	queue.Stop()
+
+	if sender.called != 3 || len(sender.sent) != 1 {
+		t.Fatalf("expected delivered on third attempt, called %d sent %d", sender.called, len(sender.sent))
+	}
+	if sender.sent[0].Subject != "Selamat datang, Budi" {
+		t.Errorf("unexpected subject %q", sender.sent[0].Subject)
+	}
+}
+
+func TestQueueGiveUp(t *testing.T) {
+	templates, err := NewTemplates(LangIndonesia)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sender := &flakySender{fail: 10}
+	queue := NewQueue(sender, sender, templates, 10, 2, time.Millisecond)
+	queue.Start(1)
+
+	if err = queue.KirimEmail("budi@example.com", TemplateRegistrasi, "", map[string]interface{}{"Nama": "Budi"}); err != nil {
+		t.Fatal(err)
+	}
+	queue.Stop()
+
+	if sender.called != 3 || len(sender.sent) != 0 {
+		t.Fatalf("expected 3 failed attempt, called %d sent %d", sender.called, len(sender.sent))
+	}
+	if err = queue.KirimEmail("budi@example.com", TemplateRegistrasi, "", nil); err == nil {
+		t.Error("expected error when queue is stopped")
+	}
+}
+
+func TestLogSender(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "messaging.log")
+	sender := NewLogSender(path)
+
+	if err := sender.SendEmail(context.Background(), Email{To: "budi@example.com", Subject: "Halo"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := sender.SendSMS(context.Background(), SMS{To: "0812", Body: "Halo"}); err != nil {
+		t.Fatal(err)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	if len(lines) != 2 || !strings.Contains(lines[0], `"channel":"email"`) || !strings.Contains(lines[1], `"channel":"sms"`) {
+		t.Errorf("unexpected log content %s", content)
+	}
+}
+
+func TestSMTPSenderHeader(t *testing.T) {
+	sender := NewSMTPSender("127.0.0.1", 1, "", "", "no-reply@example.com")
+
+	for _, email := range []Email{
+		{To: "budi@example.com\r\nBcc: lain@example.com", Subject: "Halo"},
+		{To: "budi@example.com", Subject: "Halo\r\nBcc: lain@example.com"},
+	} {
+		if err := sender.SendEmail(context.Background(), email); err != errHeaderTidakValid {
+			t.Errorf("expected header error for %q, got %v", email, err)
+		}
+	}
+}
+
+func TestSMTPSenderDeadline(t *testing.T) {
+	// server accept connection but never greet
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	go func() {
+		conn, err := listener.Accept()
+		if err == nil {
+			defer conn.Close()
+			time.Sleep(time.Second)
+		}
+	}()
+
+	port := listener.Addr().(*net.TCPAddr).Port
+	sender := NewSMTPSender("127.0.0.1", port, "", "", "no-reply@example.com")
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	mulai := time.Now()
+	if err = sender.SendEmail(ctx, Email{To: "budi@example.com", Subject: "Halo"}); err == nil {
+		t.Fatal("expected error from silent server")
+	}
+	if time.Since(mulai) > 500*time.Millisecond {
+		t.Errorf("send did not stop at deadline of ctx, took %s", time.Since(mulai))
+	}
+}
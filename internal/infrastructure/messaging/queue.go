@@ -0,0 +1,161 @@
+package messaging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/helper"
+)
+
+var ErrQueueFull = errors.New("messaging queue is full")
+
+type job struct {
+	email   *Email
+	sms     *SMS
+	attempt int
+}
+
+// Queue deliver message in background and retry failed delivery with exponential backoff,
+// so delivery failures never break the originating request
+type Queue struct {
+	emailSender EmailSender
+	smsSender   SMSSender
+	templates   *Templates
+	jobs        chan job
+	maxRetry    int
+	backoff     time.Duration
+	wg          sync.WaitGroup
+	pending     sync.WaitGroup
+	mu          sync.RWMutex
+	closed      bool
+}
+
+func NewQueue(emailSender EmailSender, smsSender SMSSender, templates *Templates, size, maxRetry int, backoff time.Duration) *Queue {
+	if size < 1 {
+		size = 100
+	}
+	if backoff <= 0 {
+		backoff = time.Second
+	}
+	return &Queue{
+		emailSender: emailSender,
+		smsSender:   smsSender,
+		templates:   templates,
+		jobs:        make(chan job, size),
+		maxRetry:    maxRetry,
+		backoff:     backoff,
+	}
+}
+
+// Start run workers that consume the queue
+func (q *Queue) Start(workers int) {
+	if workers < 1 {
+		workers = 1
+	}
+	for i := 0; i < workers; i++ {
+		q.wg.Add(1)
+		go func() {
+			defer q.wg.Done()
+			for j := range q.jobs {
+				q.deliver(j)
+				q.pending.Done()
+			}
+		}()
+	}
+}
+
+// Stop wait until every queued and retrying message is finished then stop the workers
+func (q *Queue) Stop() {
+	q.mu.Lock()
+	if q.closed {
+		q.mu.Unlock()
+		return
+	}
+	q.closed = true
+	q.mu.Unlock()
+
+	q.pending.Wait()
+	close(q.jobs)
+	q.wg.Wait()
+}
+
+func (q *Queue) KirimEmail(to, templateName, lang string, data interface{}) error {
+	if to == "" {
+		return errors.New("email recipient is empty")
+	}
+	message, err := q.templates.Render(templateName, lang, data)
+	if err != nil {
+		return err
+	}
+	return q.enqueue(job{email: &Email{
+		To:      to,
+		Subject: message.Subject,
+		Body:    message.Email,
+	}})
+}
+
+func (q *Queue) KirimSMS(to, templateName, lang string, data interface{}) error {
+	if to == "" {
+		return errors.New("sms recipient is empty")
+	}
+	message, err := q.templates.Render(templateName, lang, data)
+	if err != nil {
+		return err
+	}
+	return q.enqueue(job{sms: &SMS{
+		To:   to,
+		Body: message.SMS,
+	}})
+}
+
+func (q *Queue) enqueue(j job) error {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	if q.closed {
+		return errors.New("messaging queue is stopped")
+	}
+
+	q.pending.Add(1)
+	select {
+	case q.jobs <- j:
+		return nil
+	default:
+		q.pending.Done()
+		return ErrQueueFull
+	}
+}
+
+func (q *Queue) deliver(j job) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	var err error
+	var to string
+	if j.email != nil {
+		to = j.email.To
+		err = q.emailSender.SendEmail(ctx, *j.email)
+	} else {
+		to = j.sms.To
+		err = q.smsSender.SendSMS(ctx, *j.sms)
+	}
+	if err == nil {
+		return
+	}
+
+	// give up when max retry reached
+	j.attempt++
+	if j.attempt > q.maxRetry {
+		helper.Logger(currentfilepath, helper.LoggerLevelError, fmt.Sprintf("failed to deliver message to %s after %d attempt : %s", to, j.attempt, err.Error()))
+		return
+	}
+
+	// retry with exponential backoff without blocking the worker
+	helper.Logger(currentfilepath, helper.LoggerLevelWarn, fmt.Sprintf("failed to deliver message to %s, retry attempt %d : %s", to, j.attempt, err.Error()))
+	q.pending.Add(1)
+	time.AfterFunc(q.backoff*time.Duration(1<<uint(j.attempt-1)), func() {
+		q.jobs <- j
+	})
+}
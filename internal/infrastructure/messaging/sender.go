@@ -0,0 +1,30 @@
+package messaging
+
+import "context"
+
+// Email outbound email message
+type Email struct {
+	To      string `json:"to"`
+	Subject string `json:"subject"`
+	Body    string `json:"body"`
+}
+
+// SMS outbound sms message
+type SMS struct {
+	To   string `json:"to"`
+	Body string `json:"body"`
+}
+
+type EmailSender interface {
+	SendEmail(ctx context.Context, email Email) error
+}
+
+type SMSSender interface {
+	SendSMS(ctx context.Context, sms SMS) error
+}
+
+// Messenger render template and deliver it in background, used by useCase layer
+type Messenger interface {
+	KirimEmail(to, templateName, lang string, data interface{}) error
+	KirimSMS(to, templateName, lang string, data interface{}) error
+}
@@ -0,0 +1,107 @@
+package messaging
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+// batasWaktuSMTP deadline of the whole smtp session when ctx has no deadline
+const batasWaktuSMTP = 30 * time.Second
+
+// errHeaderTidakValid header value contain line break that would start a new header
+var errHeaderTidakValid = errors.New("email header must not contain line break")
+
+type SMTPSender struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+}
+
+func NewSMTPSender(host string, port int, username, password, from string) EmailSender {
+	return &SMTPSender{
+		Host:     host,
+		Port:     port,
+		Username: username,
+		Password: password,
+		From:     from,
+	}
+}
+
+func (ss *SMTPSender) SendEmail(ctx context.Context, email Email) error {
+	// subject is rendered from user data, line break would inject another header
+	if strings.ContainsAny(email.To, "\r\n") || strings.ContainsAny(email.Subject, "\r\n") {
+		return errHeaderTidakValid
+	}
+
+	// build mime message, non ascii subject is encoded
+	var msg strings.Builder
+	msg.WriteString(fmt.Sprintf("From: %s\r\n", ss.From))
+	msg.WriteString(fmt.Sprintf("To: %s\r\n", email.To))
+	msg.WriteString(fmt.Sprintf("Subject: %s\r\n", mime.QEncoding.Encode("UTF-8", email.Subject)))
+	msg.WriteString(fmt.Sprintf("Date: %s\r\n", time.Now().Format(time.RFC1123Z)))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=\"UTF-8\"\r\n")
+	msg.WriteString("\r\n")
+	msg.WriteString(strings.ReplaceAll(email.Body, "\n", "\r\n"))
+
+	// every read and write of the session stop at the deadline of ctx
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, batasWaktuSMTP)
+		defer cancel()
+	}
+	deadline, _ := ctx.Deadline()
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(ss.Host, fmt.Sprint(ss.Port)))
+	if err != nil {
+		return err
+	}
+	if err = conn.SetDeadline(deadline); err != nil {
+		conn.Close()
+		return err
+	}
+	client, err := smtp.NewClient(conn, ss.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	// same step as smtp.SendMail, starttls when offered and plain auth only when credential is configured
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err = client.StartTLS(&tls.Config{ServerName: ss.Host}); err != nil {
+			return err
+		}
+	}
+	if ss.Username != "" {
+		if err = client.Auth(smtp.PlainAuth("", ss.Username, ss.Password, ss.Host)); err != nil {
+			return err
+		}
+	}
+	if err = client.Mail(ss.From); err != nil {
+		return err
+	}
+	if err = client.Rcpt(email.To); err != nil {
+		return err
+	}
+	writer, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err = writer.Write([]byte(msg.String())); err != nil {
+		return err
+	}
+	if err = writer.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
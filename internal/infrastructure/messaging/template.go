@@ -0,0 +1,105 @@
+package messaging
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"path"
+	"strings"
+	"text/template"
+)
+
+const (
+	TemplateRegistrasi     = "registrasi"
+	TemplatePesananDibuat  = "pesanan_dibuat"
+	TemplatePesananMasuk   = "pesanan_masuk"
+	TemplatePembayaran     = "pembayaran"
+	TemplatePengiriman     = "pengiriman"
+	TemplateStokRendah     = "stok_rendah"
+	TemplateResetKataSandi = "reset_kata_sandi"
+	LangIndonesia          = "id"
+	LangEnglish            = "en"
+)
+
+//go:embed templates/*.tmpl
+var templateFS embed.FS
+
+// DataNotifikasi data of template sent for notifikasi, Payload is the struct the notifikasi payload is built from
+type DataNotifikasi struct {
+	Nama    string
+	Payload interface{}
+}
+
+// DataResetKataSandi data of reset kata sandi template, Tautan is sent by email and Kode by sms
+type DataResetKataSandi struct {
+	Nama          string
+	Tautan        string
+	Kode          string
+	BerlakuHingga string
+}
+
+// Message rendered template, every channel use its own block
+type Message struct {
+	Subject string
+	Email   string
+	SMS     string
+}
+
+// Templates hold parsed template with key <name>.<lang>
+type Templates struct {
+	defaultLang string
+	list        map[string]*template.Template
+}
+
+func NewTemplates(defaultLang string) (*Templates, error) {
+	if defaultLang == "" {
+		defaultLang = LangIndonesia
+	}
+	templates := &Templates{
+		defaultLang: defaultLang,
+		list:        map[string]*template.Template{},
+	}
+
+	// parse every file, file name format is <name>.<lang>.tmpl
+	files, err := templateFS.ReadDir("templates")
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range files {
+		key := strings.TrimSuffix(file.Name(), ".tmpl")
+		parsed, err := template.ParseFS(templateFS, path.Join("templates", file.Name()))
+		if err != nil {
+			return nil, err
+		}
+		templates.list[key] = parsed
+	}
+	return templates, nil
+}
+
+// Render execute subject, email and sms block of template, fallback to default language
+func (t *Templates) Render(name, lang string, data interface{}) (message Message, err error) {
+	tmpl, ok := t.list[fmt.Sprintf("%s.%s", name, lang)]
+	if !ok {
+		tmpl, ok = t.list[fmt.Sprintf("%s.%s", name, t.defaultLang)]
+	}
+	if !ok {
+		return message, fmt.Errorf("template %s not found", name)
+	}
+
+	blocks := map[string]*string{
+		"subject": &message.Subject,
+		"email":   &message.Email,
+		"sms":     &message.SMS,
+	}
+	for block, result := range blocks {
+		if tmpl.Lookup(block) == nil {
+			continue
+		}
+		var buf bytes.Buffer
+		if err = tmpl.ExecuteTemplate(&buf, block, data); err != nil {
+			return message, err
+		}
+		*result = strings.TrimSpace(buf.String())
+	}
+	return message, nil
+}
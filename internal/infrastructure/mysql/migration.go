@@ -11,6 +11,11 @@ import (
 func RunMigration(mysqlDB *gorm.DB) {
 	err := mysqlDB.AutoMigrate(
 		&daos.User{}, &daos.Toko{}, &daos.Category{}, &daos.Alamat{}, &daos.Produk{}, &daos.FotoProduk{}, &daos.LogProduk{}, &daos.TRX{}, &daos.DetailTRX{}, &daos.LogFotoProduk{},
+		&daos.Notifikasi{}, &daos.Percakapan{}, &daos.Pesan{}, &daos.OpsiVarian{}, &daos.SKU{}, &daos.ImporProduk{}, &daos.BarisImporGagal{},
+		&daos.Slug{}, &daos.MutasiStok{}, &daos.Promo{}, &daos.ItemPromo{}, &daos.PembelianPromo{}, &daos.RiwayatHarga{},
+		&daos.ProdukTerkait{}, &daos.StatistikProduk{}, &daos.StatistikHarian{}, &daos.RiwayatModerasi{},
+		&daos.AtributCategory{}, &daos.AtributProduk{}, &daos.DiskusiProduk{}, &daos.TanggapanDiskusi{},
+		&daos.ResetKataSandi{},
 	)
 
 	if err != nil {
@@ -0,0 +1,153 @@
+package search
+
+import (
+	"strings"
+	"unicode"
+)
+
+// token one word of text with its position in rune slice, used for highlighting
+type token struct {
+	Kata  string
+	Awal  int
+	Akhir int
+}
+
+// stopword common Indonesian and English word that does not help ranking
+var stopword = map[string]bool{
+	"yang": true, "dan": true, "di": true, "ke": true, "dari": true, "untuk": true, "dengan": true,
+	"ini": true, "itu": true, "atau": true, "pada": true, "juga": true, "dalam": true, "akan": true,
+	"ada": true, "adalah": true, "sebagai": true, "oleh": true, "bisa": true, "karena": true,
+	"sudah": true, "saja": true, "tidak": true, "lebih": true, "sangat": true, "the": true,
+	"and": true, "for": true, "with": true, "of": true, "a": true, "an": true, "to": true, "in": true,
+}
+
+// tokenize split text into lower case word, every rune that is not letter or digit is separator
+func tokenize(text string) (listToken []token) {
+	runes := []rune(text)
+	awal := -1
+	for i := 0; i <= len(runes); i++ {
+		if i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i])) {
+			if awal < 0 {
+				awal = i
+			}
+			continue
+		}
+		if awal >= 0 {
+			listToken = append(listToken, token{
+				Kata:  strings.ToLower(string(runes[awal:i])),
+				Awal:  awal,
+				Akhir: i,
+			})
+			awal = -1
+		}
+	}
+	return listToken
+}
+
+// analyze return indexed term of text, stopword and single letter are dropped
+func analyze(text string) (listTerm []string) {
+	for _, t := range tokenize(text) {
+		if stopword[t.Kata] || len([]rune(t.Kata)) < 2 {
+			continue
+		}
+		listTerm = append(listTerm, t.Kata)
+	}
+	return listTerm
+}
+
+// stem light Indonesian stemmer, remove particle, possessive, derivational suffix and common prefix.
+// It is not a full Nazief-Adriani stemmer, stem is only used as secondary match so over-stemming only lower precision.
+func stem(kata string) string {
+	if len([]rune(kata)) <= 5 || strings.IndexFunc(kata, unicode.IsDigit) >= 0 {
+		return kata
+	}
+	potong := func(akhiran []string) {
+		for _, a := range akhiran {
+			if strings.HasSuffix(kata, a) && len([]rune(kata))-len([]rune(a)) >= 4 {
+				kata = strings.TrimSuffix(kata, a)
+				return
+			}
+		}
+	}
+	potong([]string{"lah", "kah", "tah", "pun"})
+	potong([]string{"nya", "ku", "mu"})
+	potong([]string{"kan", "an"})
+
+	awalan := []struct {
+		Awalan string
+		Ganti  string
+	}{
+		{"meng", ""}, {"meny", "s"}, {"mem", "p"}, {"men", "t"}, {"me", ""},
+		{"peng", ""}, {"peny", "s"}, {"pem", "p"}, {"pen", "t"},
+		{"ber", ""}, {"ter", ""}, {"per", ""}, {"di", ""},
+	}
+	for _, a := range awalan {
+		if !strings.HasPrefix(kata, a.Awalan) {
+			continue
+		}
+		sisa := strings.TrimPrefix(kata, a.Awalan)
+		if len([]rune(sisa)) < 4 {
+			break
+		}
+		// nasal prefix replace first consonant of root word when followed by vowel
+		if a.Ganti != "" && strings.IndexAny(sisa[:1], "aiueo") >= 0 {
+			sisa = a.Ganti + sisa
+		}
+		return sisa
+	}
+	return kata
+}
+
+// jarakEdit levenshtein distance between a and b, stop early when distance is bigger than batas
+func jarakEdit(a, b string, batas int) int {
+	ra, rb := []rune(a), []rune(b)
+	if selisih := len(ra) - len(rb); selisih > batas || -selisih > batas {
+		return batas + 1
+	}
+	sebelum := make([]int, len(rb)+1)
+	sekarang := make([]int, len(rb)+1)
+	for j := range sebelum {
+		sebelum[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		sekarang[0] = i
+		terkecil := sekarang[0]
+		for j := 1; j <= len(rb); j++ {
+			biaya := 1
+			if ra[i-1] == rb[j-1] {
+				biaya = 0
+			}
+			sekarang[j] = sebelum[j-1] + biaya
+			if sebelum[j]+1 < sekarang[j] {
+				sekarang[j] = sebelum[j] + 1
+			}
+			if sekarang[j-1]+1 < sekarang[j] {
+				sekarang[j] = sekarang[j-1] + 1
+			}
+			if sekarang[j] < terkecil {
+				terkecil = sekarang[j]
+			}
+		}
+		if terkecil > batas {
+			return batas + 1
+		}
+		sebelum, sekarang = sekarang, sebelum
+	}
+	return sebelum[len(rb)]
+}
+
+// batasTypo maximum edit distance allowed for query word, short word must match exactly
+func batasTypo(kata string, maxTypo int) int {
+	panjang := len([]rune(kata))
+	batas := 0
+	switch {
+	case panjang >= 8:
+		batas = 2
+	case panjang >= 4:
+		batas = 1
+	}
+	if batas > maxTypo {
+		batas = maxTypo
+	}
+	return batas
+}
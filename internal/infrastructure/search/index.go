@@ -0,0 +1,41 @@
+package search
+
+// Dokumen produk indexed for search, text field are analyzed and the rest only used for filter
+type Dokumen struct {
+	ID            uint
+	NamaProduk    string
+	Deskripsi     string
+	NamaCategory  string
+	NamaToko      string
+	CategoryID    uint
+	TokoID        uint
+	HargaKonsumen uint
+}
+
+// Filter applied before ranking, zero value means not filtered
+type Filter struct {
+	CategoryID uint
+	TokoID     uint
+	MinHarga   uint
+	MaxHarga   uint
+}
+
+// Hasil one ranked dokumen
+type Hasil struct {
+	ID   uint
+	Skor float64
+}
+
+// Sorotan nama produk and deskripsi snippet with matched word wrapped in <em> tag
+type Sorotan struct {
+	NamaProduk string
+	Deskripsi  string
+}
+
+// Index full text index of produk, implementation must be safe for concurrent use
+type Index interface {
+	Simpan(dokumen Dokumen)
+	Hapus(ID uint)
+	Cari(query string, filter Filter, limit, offset int) (listHasil []Hasil, total int)
+	Sorot(query string, listID []uint) map[uint]Sorotan
+}
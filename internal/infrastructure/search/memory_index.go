@@ -0,0 +1,330 @@
+package search
+
+import (
+	"html"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const (
+	// bm25 parameter
+	k1 = 1.2
+	b  = 0.75
+
+	// weight of match kind, exact word is the most relevant
+	bobotTepat   = 1.0
+	bobotAwalan  = 0.8
+	bobotTypo    = 0.7
+	bobotAkar    = 0.6
+	maxEkspansi  = 50
+	panjangSorot = 160
+)
+
+// bobotField weight of every text field of produk, nama produk matter the most
+var bobotField = struct {
+	NamaProduk   float64
+	NamaCategory float64
+	NamaToko     float64
+	Deskripsi    float64
+}{3, 2, 1.5, 1}
+
+// MemoryIndex in-process inverted index ranked with BM25, data is lost on restart and rebuilt from database
+type MemoryIndex struct {
+	mu           sync.RWMutex
+	maxTypo      int
+	dokumen      map[uint]Dokumen
+	panjang      map[uint]float64
+	totalPanjang float64
+	kata         map[string]map[uint]float64
+	akar         map[string]map[uint]float64
+	termDokumen  map[uint][]string
+}
+
+func NewMemoryIndex(maxTypo int) *MemoryIndex {
+	return &MemoryIndex{
+		maxTypo:     maxTypo,
+		dokumen:     map[uint]Dokumen{},
+		panjang:     map[uint]float64{},
+		kata:        map[string]map[uint]float64{},
+		akar:        map[string]map[uint]float64{},
+		termDokumen: map[uint][]string{},
+	}
+}
+
+// Simpan add or replace dokumen in index
+func (mi *MemoryIndex) Simpan(dokumen Dokumen) {
+	mi.mu.Lock()
+	defer mi.mu.Unlock()
+	mi.hapus(dokumen.ID)
+
+	var panjang float64
+	tambah := func(text string, bobot float64) {
+		for _, term := range analyze(text) {
+			if mi.kata[term] == nil {
+				mi.kata[term] = map[uint]float64{}
+			}
+			mi.kata[term][dokumen.ID] += bobot
+			akar := stem(term)
+			if mi.akar[akar] == nil {
+				mi.akar[akar] = map[uint]float64{}
+			}
+			mi.akar[akar][dokumen.ID] += bobot
+			mi.termDokumen[dokumen.ID] = append(mi.termDokumen[dokumen.ID], term)
+			panjang += bobot
+		}
+	}
+	tambah(dokumen.NamaProduk, bobotField.NamaProduk)
+	tambah(dokumen.NamaCategory, bobotField.NamaCategory)
+	tambah(dokumen.NamaToko, bobotField.NamaToko)
+	tambah(dokumen.Deskripsi, bobotField.Deskripsi)
+
+	mi.dokumen[dokumen.ID] = dokumen
+	mi.panjang[dokumen.ID] = panjang
+	mi.totalPanjang += panjang
+}
+
+// Hapus remove dokumen from index
+func (mi *MemoryIndex) Hapus(ID uint) {
+	mi.mu.Lock()
+	defer mi.mu.Unlock()
+	mi.hapus(ID)
+}
+
+func (mi *MemoryIndex) hapus(ID uint) {
+	if _, ok := mi.dokumen[ID]; !ok {
+		return
+	}
+	for _, term := range mi.termDokumen[ID] {
+		if posting, ok := mi.kata[term]; ok {
+			delete(posting, ID)
+			if len(posting) == 0 {
+				delete(mi.kata, term)
+			}
+		}
+		akar := stem(term)
+		if posting, ok := mi.akar[akar]; ok {
+			delete(posting, ID)
+			if len(posting) == 0 {
+				delete(mi.akar, akar)
+			}
+		}
+	}
+	delete(mi.termDokumen, ID)
+	mi.totalPanjang -= mi.panjang[ID]
+	delete(mi.panjang, ID)
+	delete(mi.dokumen, ID)
+}
+
+// ekspansi posting list matched by one query word and its weight
+type ekspansi struct {
+	posting map[uint]float64
+	bobot   float64
+}
+
+// kueri expansion of every query word and word matched by the query, used for highlighting
+type kueri struct {
+	listEkspansi [][]ekspansi
+	kataCocok    map[string]bool
+	akarCocok    map[string]bool
+}
+
+// cocok report whether word of dokumen is matched by the query
+func (k kueri) cocok(kata string) bool {
+	return k.kataCocok[kata] || k.akarCocok[stem(kata)]
+}
+
+// ekspansiKueri match every query word exactly, by prefix for the last word,
+// by typo tolerance when the word is unknown and by its stem. Caller must hold read lock.
+func (mi *MemoryIndex) ekspansiKueri(listQuery []string) (hasil kueri) {
+	hasil.kataCocok = map[string]bool{}
+	hasil.akarCocok = map[string]bool{}
+	for i, q := range listQuery {
+		var listEkspansi []ekspansi
+		if posting, ok := mi.kata[q]; ok {
+			listEkspansi = append(listEkspansi, ekspansi{posting: posting, bobot: bobotTepat})
+			hasil.kataCocok[q] = true
+		} else if batas := batasTypo(q, mi.maxTypo); batas > 0 {
+			for term, posting := range mi.kata {
+				if len(listEkspansi) >= maxEkspansi {
+					break
+				}
+				if jarakEdit(q, term, batas) <= batas {
+					listEkspansi = append(listEkspansi, ekspansi{posting: posting, bobot: bobotTypo})
+					hasil.kataCocok[term] = true
+				}
+			}
+		}
+		// last word may still be typed by user
+		if i == len(listQuery)-1 && len([]rune(q)) >= 3 {
+			jumlah := 0
+			for term, posting := range mi.kata {
+				if jumlah >= maxEkspansi {
+					break
+				}
+				if term != q && strings.HasPrefix(term, q) {
+					listEkspansi = append(listEkspansi, ekspansi{posting: posting, bobot: bobotAwalan})
+					hasil.kataCocok[term] = true
+					jumlah++
+				}
+			}
+		}
+		if posting, ok := mi.akar[stem(q)]; ok {
+			listEkspansi = append(listEkspansi, ekspansi{posting: posting, bobot: bobotAkar})
+			hasil.akarCocok[stem(q)] = true
+		}
+		hasil.listEkspansi = append(hasil.listEkspansi, listEkspansi)
+	}
+	return hasil
+}
+
+// Cari rank dokumen matching query with BM25, limit 0 return every matching dokumen
+func (mi *MemoryIndex) Cari(query string, filter Filter, limit, offset int) (listHasil []Hasil, total int) {
+	listQuery := analyze(query)
+	if len(listQuery) == 0 {
+		return nil, 0
+	}
+
+	mi.mu.RLock()
+	defer mi.mu.RUnlock()
+	if len(mi.dokumen) == 0 {
+		return nil, 0
+	}
+	rataPanjang := mi.totalPanjang / float64(len(mi.dokumen))
+
+	skor := map[uint]float64{}
+	jumlahCocok := map[uint]int{}
+	for _, listEkspansi := range mi.ekspansiKueri(listQuery).listEkspansi {
+		// score of query word is the best score of its expansion
+		skorKata := map[uint]float64{}
+		for _, e := range listEkspansi {
+			idf := math.Log(1 + (float64(len(mi.dokumen))-float64(len(e.posting))+0.5)/(float64(len(e.posting))+0.5))
+			for ID, tf := range e.posting {
+				if !cocokFilter(mi.dokumen[ID], filter) {
+					continue
+				}
+				nilai := e.bobot * idf * (tf * (k1 + 1)) / (tf + k1*(1-b+b*mi.panjang[ID]/rataPanjang))
+				if nilai > skorKata[ID] {
+					skorKata[ID] = nilai
+				}
+			}
+		}
+		for ID, nilai := range skorKata {
+			skor[ID] += nilai
+			jumlahCocok[ID]++
+		}
+	}
+
+	// dokumen matching more query word rank higher
+	for ID := range skor {
+		skor[ID] *= float64(jumlahCocok[ID]) / float64(len(listQuery))
+		listHasil = append(listHasil, Hasil{ID: ID, Skor: skor[ID]})
+	}
+	sort.Slice(listHasil, func(i, j int) bool {
+		if listHasil[i].Skor == listHasil[j].Skor {
+			return listHasil[i].ID > listHasil[j].ID
+		}
+		return listHasil[i].Skor > listHasil[j].Skor
+	})
+
+	// pagination
+	total = len(listHasil)
+	if offset >= total {
+		return []Hasil{}, total
+	}
+	if limit > 0 && offset+limit < total {
+		return listHasil[offset : offset+limit], total
+	}
+	return listHasil[offset:], total
+}
+
+// Sorot highlight word matched by query in nama produk and deskripsi of every dokumen in listID
+func (mi *MemoryIndex) Sorot(query string, listID []uint) map[uint]Sorotan {
+	listSorotan := map[uint]Sorotan{}
+	listQuery := analyze(query)
+	if len(listQuery) == 0 {
+		return listSorotan
+	}
+
+	mi.mu.RLock()
+	defer mi.mu.RUnlock()
+	kueri := mi.ekspansiKueri(listQuery)
+	for _, ID := range listID {
+		dokumen, ok := mi.dokumen[ID]
+		if !ok {
+			continue
+		}
+		listSorotan[ID] = Sorotan{
+			NamaProduk: sorot(dokumen.NamaProduk, kueri.cocok, 0),
+			Deskripsi:  sorot(dokumen.Deskripsi, kueri.cocok, panjangSorot),
+		}
+	}
+	return listSorotan
+}
+
+func cocokFilter(dokumen Dokumen, filter Filter) bool {
+	switch {
+	case filter.CategoryID != 0 && dokumen.CategoryID != filter.CategoryID:
+		return false
+	case filter.TokoID != 0 && dokumen.TokoID != filter.TokoID:
+		return false
+	case filter.MinHarga != 0 && dokumen.HargaKonsumen < filter.MinHarga:
+		return false
+	case filter.MaxHarga != 0 && dokumen.HargaKonsumen > filter.MaxHarga:
+		return false
+	}
+	return true
+}
+
+// sorot wrap matched word with <em> tag and escape the rest, long text is cut around the first match
+func sorot(text string, cocok func(kata string) bool, maxPanjang int) string {
+	runes := []rune(text)
+	listToken := tokenize(text)
+
+	awal, akhir := 0, len(runes)
+	if maxPanjang > 0 && len(runes) > maxPanjang {
+		for _, t := range listToken {
+			if cocok(t.Kata) {
+				awal = t.Awal - maxPanjang/4
+				break
+			}
+		}
+		if awal < 0 {
+			awal = 0
+		}
+		akhir = awal + maxPanjang
+		if akhir > len(runes) {
+			akhir = len(runes)
+			awal = akhir - maxPanjang
+		}
+		// do not cut in the middle of word
+		for _, t := range listToken {
+			if t.Awal < awal && t.Akhir > awal {
+				awal = t.Akhir
+			}
+			if t.Awal < akhir && t.Akhir > akhir {
+				akhir = t.Awal
+			}
+		}
+	}
+
+	var hasil strings.Builder
+	if awal > 0 {
+		hasil.WriteString("…")
+	}
+	posisi := awal
+	for _, t := range listToken {
+		if t.Awal < awal || t.Akhir > akhir || !cocok(t.Kata) {
+			continue
+		}
+		hasil.WriteString(html.EscapeString(string(runes[posisi:t.Awal])))
+		hasil.WriteString("<em>" + html.EscapeString(string(runes[t.Awal:t.Akhir])) + "</em>")
+		posisi = t.Akhir
+	}
+	hasil.WriteString(html.EscapeString(string(runes[posisi:akhir])))
+	if akhir < len(runes) {
+		hasil.WriteString("…")
+	}
+	return strings.TrimSpace(hasil.String())
+}
@@ -0,0 +1,35 @@
+package search
+
+import (
+	"fmt"
+
+	"github.com/spf13/viper"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/helper"
+)
+
+const (
+	currentfilepath = "internal/infrastructure/search/search.go"
+	DriverMemory    = "memory"
+)
+
+type SearchConf struct {
+	Driver  string `mapstructure:"search_driver"`
+	MaxTypo int    `mapstructure:"search_max_typo"`
+}
+
+// SearchInit setup search index from configuration, index is filled by produk usecase on startup
+func SearchInit(v *viper.Viper) Index {
+	var searchConf SearchConf
+	if err := v.Unmarshal(&searchConf); err != nil {
+		helper.Logger(currentfilepath, helper.LoggerLevelPanic, fmt.Sprintf("failed init search : %s", err.Error()))
+	}
+
+	// in-process inverted index is the only driver for now
+	if searchConf.Driver != "" && searchConf.Driver != DriverMemory {
+		helper.Logger(currentfilepath, helper.LoggerLevelWarn, fmt.Sprintf("unknown search driver %s, using %s", searchConf.Driver, DriverMemory))
+	}
+	index := NewMemoryIndex(searchConf.MaxTypo)
+
+	helper.Logger(currentfilepath, helper.LoggerLevelInfo, "⇨ Search index ready with memory driver")
+	return index
+}
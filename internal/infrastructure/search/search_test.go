@@ -0,0 +1,79 @@
+package search
+
+import (
+	"strings"
+	"testing"
+)
+
+func indexContoh() *MemoryIndex {
+	index := NewMemoryIndex(2)
+	index.Simpan(Dokumen{ID: 1, NamaProduk: "Kemeja Batik Pria", Deskripsi: "Kemeja batik lengan panjang bahan katun", NamaCategory: "Pakaian", NamaToko: "Toko Budi", CategoryID: 1, TokoID: 1, HargaKonsumen: 150000})
+	index.Simpan(Dokumen{ID: 2, NamaProduk: "Sepatu Lari", Deskripsi: "Sepatu olahraga ringan, cocok untuk berlari", NamaCategory: "Sepatu", NamaToko: "Toko Sari", CategoryID: 2, TokoID: 2, HargaKonsumen: 300000})
+	index.Simpan(Dokumen{ID: 3, NamaProduk: "Meja Belajar", Deskripsi: "Meja kayu jati untuk belajar & bekerja", NamaCategory: "Perabot", NamaToko: "Toko Budi", CategoryID: 3, TokoID: 1, HargaKonsumen: 500000})
+	return index
+}
+
+func TestCariRelevansi(t *testing.T) {
+	index := indexContoh()
+
+	listHasil, total := index.Cari("kemeja batik", Filter{}, 10, 0)
+	if total != 1 || listHasil[0].ID != 1 {
+		t.Fatalf("unexpected result %+v", listHasil)
+	}
+	if sorotan := index.Sorot("kemeja batik", []uint{1})[1]; sorotan.NamaProduk != "<em>Kemeja</em> <em>Batik</em> Pria" {
+		t.Errorf("unexpected highlight %q", sorotan.NamaProduk)
+	}
+
+	// nama produk weigh more than deskripsi
+	listHasil, _ = index.Cari("sepatu", Filter{}, 10, 0)
+	if len(listHasil) != 1 || listHasil[0].ID != 2 {
+		t.Fatalf("unexpected result %+v", listHasil)
+	}
+
+	// filter applied before pagination
+	listHasil, total = index.Cari("toko budi", Filter{CategoryID: 3}, 10, 0)
+	if total != 1 || listHasil[0].ID != 3 {
+		t.Fatalf("unexpected filtered result %+v", listHasil)
+	}
+	if sorotan := index.Sorot("meja kayu", []uint{3})[3]; !strings.Contains(sorotan.Deskripsi, "<em>kayu</em>") || !strings.Contains(sorotan.Deskripsi, "&amp;") {
+		t.Errorf("unexpected escaped highlight %q", sorotan.Deskripsi)
+	}
+}
+
+func TestCariTypoDanAwalan(t *testing.T) {
+	index := indexContoh()
+
+	listHasil, _ := index.Cari("kemja", Filter{}, 10, 0)
+	if len(listHasil) == 0 || listHasil[0].ID != 1 {
+		t.Fatalf("typo not tolerated %+v", listHasil)
+	}
+	listHasil, _ = index.Cari("sepa", Filter{}, 10, 0)
+	if len(listHasil) == 0 || listHasil[0].ID != 2 {
+		t.Fatalf("prefix not matched %+v", listHasil)
+	}
+	// berlari share stem with lari
+	listHasil, _ = index.Cari("berlari", Filter{}, 10, 0)
+	if len(listHasil) == 0 || listHasil[0].ID != 2 {
+		t.Fatalf("stem not matched %+v", listHasil)
+	}
+
+	index.Hapus(2)
+	if listHasil, total := index.Cari("sepatu", Filter{}, 10, 0); total != 0 {
+		t.Fatalf("deleted dokumen still found %+v", listHasil)
+	}
+}
+
+func TestStem(t *testing.T) {
+	listKata := map[string]string{
+		"berlari":    "lari",
+		"pakaiannya": "pakai",
+		"menulis":    "tulis",
+		"baju":       "baju",
+		"sepatu":     "sepatu",
+	}
+	for kata, akar := range listKata {
+		if hasil := stem(kata); hasil != akar {
+			t.Errorf("stem(%s) = %s, want %s", kata, hasil, akar)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package storage
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"regexp"
+	"time"
+)
+
+// BlobStorage object storage of uploaded media, object is addressed by key and never modified after saved
+type BlobStorage interface {
+	Simpan(ctx context.Context, kunci string, data []byte, contentType string) (err error)
+	Ambil(ctx context.Context, kunci string) (isi io.ReadCloser, info Info, err error)
+	Hapus(ctx context.Context, kunci string) (err error)
+	// URL public address of object, value that is not an object key (uploaded before storage existed) is returned as is
+	URL(kunci string) string
+}
+
+// Info metadata of stored object
+type Info struct {
+	Ukuran      int64
+	ContentType string
+	DiubahPada  time.Time
+}
+
+var ErrTidakAda = errors.New("object not found")
+
+// polaKunci content addressed key created by KunciKonten
+var polaKunci = regexp.MustCompile(`^[a-z0-9_-]+/[0-9a-f]{2}/[0-9a-f]{64}\.[a-z0-9]+$`)
+
+// KunciKonten key derived from sha256 of data, the same content is always stored once under the same key
+func KunciKonten(prefix string, data []byte, ekstensi string) string {
+	hash := sha256.Sum256(data)
+	nama := hex.EncodeToString(hash[:])
+	return prefix + "/" + nama[:2] + "/" + nama + ekstensi
+}
+
+// AdalahKunci report whether text is an object key of blob storage
+func AdalahKunci(text string) bool {
+	return polaKunci.MatchString(text)
+}
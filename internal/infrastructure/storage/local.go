@@ -0,0 +1,113 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"mime"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// LocalStorage save object as file under one folder, only usable by single instance or shared volume
+type LocalStorage struct {
+	direktori string
+	publicURL string
+}
+
+func NewLocalStorage(direktori, publicURL string) *LocalStorage {
+	return &LocalStorage{
+		direktori: direktori,
+		publicURL: strings.TrimSuffix(publicURL, "/"),
+	}
+}
+
+// path file location of key, key with path traversal is rejected
+func (ls *LocalStorage) path(kunci string) (string, error) {
+	bersih := path.Clean("/" + kunci)
+	if bersih == "/" || bersih != "/"+kunci {
+		return "", ErrTidakAda
+	}
+	return filepath.Join(ls.direktori, filepath.FromSlash(bersih)), nil
+}
+
+func (ls *LocalStorage) Simpan(ctx context.Context, kunci string, data []byte, contentType string) (err error) {
+	lokasi, err := ls.path(kunci)
+	if err != nil {
+		return err
+	}
+	// content addressed object already exist
+	if _, err := os.Stat(lokasi); err == nil {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(lokasi), 0755); err != nil {
+		return err
+	}
+
+	// write to temporary file then rename so reader never see half written file
+	sementara, err := os.CreateTemp(filepath.Dir(lokasi), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(sementara.Name())
+	if _, err := sementara.Write(data); err != nil {
+		sementara.Close()
+		return err
+	}
+	if err := sementara.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(sementara.Name(), 0644); err != nil {
+		return err
+	}
+	return os.Rename(sementara.Name(), lokasi)
+}
+
+func (ls *LocalStorage) Ambil(ctx context.Context, kunci string) (isi io.ReadCloser, info Info, err error) {
+	lokasi, err := ls.path(kunci)
+	if err != nil {
+		return nil, info, err
+	}
+	file, err := os.Open(lokasi)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, info, ErrTidakAda
+		}
+		return nil, info, err
+	}
+	stat, err := file.Stat()
+	if err != nil || stat.IsDir() {
+		file.Close()
+		return nil, info, ErrTidakAda
+	}
+	info = Info{
+		Ukuran:      stat.Size(),
+		ContentType: mime.TypeByExtension(filepath.Ext(lokasi)),
+		DiubahPada:  stat.ModTime(),
+	}
+	if info.ContentType == "" {
+		info.ContentType = "application/octet-stream"
+	}
+	return file, info, nil
+}
+
+func (ls *LocalStorage) Hapus(ctx context.Context, kunci string) (err error) {
+	lokasi, err := ls.path(kunci)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(lokasi); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (ls *LocalStorage) URL(kunci string) string {
+	if !AdalahKunci(kunci) {
+		return kunci
+	}
+	return ls.publicURL + "/" + kunci
+}
@@ -0,0 +1,113 @@
+package storage
+
+import (
+	"crypto/hmac"
+	"encoding/hex"
+	"errors"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrTandaTanganInvalid    = errors.New("invalid media signature")
+	ErrTandaTanganKadaluarsa = errors.New("media url is expired")
+)
+
+// MediaStorage BlobStorage served by media route of this api. URL return absolute url of the route,
+// signed with HMAC and expiry time when media is private.
+type MediaStorage struct {
+	BlobStorage
+	baseURL    string
+	prefixLama string
+	rahasia    []byte
+	bertanda   bool
+	ttl        time.Duration
+	sekarang   func() time.Time
+}
+
+func NewMediaStorage(blobStorage BlobStorage, conf StorageConf) *MediaStorage {
+	ms := &MediaStorage{
+		BlobStorage: blobStorage,
+		baseURL:     strings.TrimSuffix(conf.MediaBaseURL, "/"),
+		rahasia:     []byte(conf.MediaSecret),
+		bertanda:    conf.MediaSigned,
+		ttl:         time.Duration(conf.MediaTTL) * time.Second,
+		sekarang:    time.Now,
+	}
+	if ms.ttl <= 0 {
+		ms.ttl = time.Hour
+	}
+	// file path stored before blob storage existed is served from local folder
+	if conf.Driver == DriverLocal {
+		ms.prefixLama = strings.TrimSuffix(conf.LocalDir, "/") + "/"
+	}
+	return ms
+}
+
+// Kunci object key of value stored in database, false when the value is not stored in blob storage
+func (ms *MediaStorage) Kunci(nilai string) (string, bool) {
+	if AdalahKunci(nilai) {
+		return nilai, true
+	}
+	if ms.prefixLama != "" && ms.prefixLama != "/" && strings.HasPrefix(nilai, ms.prefixLama) {
+		kunci := strings.TrimPrefix(nilai, ms.prefixLama)
+		if kunci != "" && path.Clean("/"+kunci) == "/"+kunci {
+			return kunci, true
+		}
+	}
+	return "", false
+}
+
+// Bertanda report whether media route require signed url
+func (ms *MediaStorage) Bertanda() bool {
+	return ms.bertanda
+}
+
+// URL absolute url of media route, fall back to url of the underlying storage when base url is not configured
+func (ms *MediaStorage) URL(nilai string) string {
+	if ms.baseURL == "" {
+		return ms.BlobStorage.URL(nilai)
+	}
+	kunci, ok := ms.Kunci(nilai)
+	if !ok {
+		return nilai
+	}
+	URL := ms.baseURL + "/" + kunci
+	if ms.bertanda {
+		// expiry is rounded so the same url is returned for a while and can be cached by client
+		kadaluarsa := ms.sekarang().Truncate(ms.ttl).Add(2 * ms.ttl).Unix()
+		URL += "?expires=" + strconv.FormatInt(kadaluarsa, 10) + "&signature=" + ms.tandaTangan(kunci, kadaluarsa)
+	}
+	return URL
+}
+
+// Verifikasi check signature of media url, return expiry time of the url
+func (ms *MediaStorage) Verifikasi(kunci, expires, signature string) (kadaluarsa time.Time, err error) {
+	detik, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil {
+		return kadaluarsa, ErrTandaTanganInvalid
+	}
+	if !hmac.Equal([]byte(ms.tandaTangan(kunci, detik)), []byte(signature)) {
+		return kadaluarsa, ErrTandaTanganInvalid
+	}
+	kadaluarsa = time.Unix(detik, 0)
+	if !ms.sekarang().Before(kadaluarsa) {
+		return kadaluarsa, ErrTandaTanganKadaluarsa
+	}
+	return kadaluarsa, nil
+}
+
+func (ms *MediaStorage) tandaTangan(kunci string, kadaluarsa int64) string {
+	return hex.EncodeToString(hmacSHA256(ms.rahasia, kunci+"\n"+strconv.FormatInt(kadaluarsa, 10)))
+}
+
+// ETag strong entity tag of content addressed key taken from its name, empty for other key
+func ETag(kunci string) string {
+	if !AdalahKunci(kunci) {
+		return ""
+	}
+	nama := path.Base(kunci)
+	return `"` + strings.TrimSuffix(nama, path.Ext(nama)) + `"`
+}
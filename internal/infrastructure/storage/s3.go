@@ -0,0 +1,233 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// emptyHash sha256 of empty payload
+const emptyHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+// S3Storage object storage speaking S3 API signed with AWS signature version 4, works with AWS S3, MinIO and other compatible server
+type S3Storage struct {
+	endpoint  *url.URL
+	region    string
+	bucket    string
+	accessKey string
+	secretKey string
+	pathStyle bool
+	publicURL string
+	client    *http.Client
+	sekarang  func() time.Time
+}
+
+func NewS3Storage(conf StorageConf) (*S3Storage, error) {
+	endpoint, err := url.Parse(conf.S3Endpoint)
+	if err != nil || endpoint.Host == "" {
+		return nil, fmt.Errorf("invalid s3 endpoint %q", conf.S3Endpoint)
+	}
+	if conf.S3Bucket == "" {
+		return nil, fmt.Errorf("s3 bucket is required")
+	}
+	region := conf.S3Region
+	if region == "" {
+		region = "us-east-1"
+	}
+	return &S3Storage{
+		endpoint:  endpoint,
+		region:    region,
+		bucket:    conf.S3Bucket,
+		accessKey: conf.S3AccessKey,
+		secretKey: conf.S3SecretKey,
+		pathStyle: conf.S3PathStyle,
+		publicURL: strings.TrimSuffix(conf.PublicURL, "/"),
+		client:    &http.Client{Timeout: 30 * time.Second},
+		sekarang:  time.Now,
+	}, nil
+}
+
+// objekURL address of object, path style (endpoint/bucket/key) is used by MinIO
+func (s *S3Storage) objekURL(kunci string) *url.URL {
+	alamat := *s.endpoint
+	if s.pathStyle {
+		alamat.Path = "/" + s.bucket + "/" + kunci
+	} else {
+		alamat.Host = s.bucket + "." + alamat.Host
+		alamat.Path = "/" + kunci
+	}
+	return &alamat
+}
+
+func (s *S3Storage) kirim(ctx context.Context, method, kunci string, data []byte, header http.Header) (*http.Response, error) {
+	var body io.Reader
+	if data != nil {
+		body = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, s.objekURL(kunci).String(), body)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	hashPayload := emptyHash
+	if data != nil {
+		hash := sha256.Sum256(data)
+		hashPayload = hex.EncodeToString(hash[:])
+	}
+	s.tandatangan(req, hashPayload, s.sekarang().UTC())
+	return s.client.Do(req)
+}
+
+// tandatangan add AWS signature version 4 authorization header to request
+func (s *S3Storage) tandatangan(req *http.Request, hashPayload string, waktu time.Time) {
+	tanggal := waktu.Format("20060102")
+	amzDate := waktu.Format("20060102T150405Z")
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", hashPayload)
+
+	// canonical header is every signed header in lower case sorted by name
+	header := map[string]string{"host": req.URL.Host}
+	for k, v := range req.Header {
+		nama := strings.ToLower(k)
+		if nama == "host" || nama == "range" || nama == "content-type" || strings.HasPrefix(nama, "x-amz-") {
+			header[nama] = strings.TrimSpace(strings.Join(v, ","))
+		}
+	}
+	var listNama []string
+	for k := range header {
+		listNama = append(listNama, k)
+	}
+	sort.Strings(listNama)
+	var kanonikHeader strings.Builder
+	for _, k := range listNama {
+		kanonikHeader.WriteString(k + ":" + header[k] + "\n")
+	}
+	signedHeader := strings.Join(listNama, ";")
+
+	kanonikRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		kanonikQuery(req.URL.Query()),
+		kanonikHeader.String(),
+		signedHeader,
+		hashPayload,
+	}, "\n")
+	hashRequest := sha256.Sum256([]byte(kanonikRequest))
+
+	scope := tanggal + "/" + s.region + "/s3/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(hashRequest[:])
+
+	kunci := hmacSHA256([]byte("AWS4"+s.secretKey), tanggal)
+	kunci = hmacSHA256(kunci, s.region)
+	kunci = hmacSHA256(kunci, "s3")
+	kunci = hmacSHA256(kunci, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(kunci, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.accessKey, scope, signedHeader, signature))
+}
+
+func kanonikQuery(query url.Values) string {
+	var listKunci []string
+	for k := range query {
+		listKunci = append(listKunci, k)
+	}
+	sort.Strings(listKunci)
+	var listPasangan []string
+	for _, k := range listKunci {
+		listNilai := query[k]
+		sort.Strings(listNilai)
+		for _, v := range listNilai {
+			listPasangan = append(listPasangan, escapeS3(k)+"="+escapeS3(v))
+		}
+	}
+	return strings.Join(listPasangan, "&")
+}
+
+// escapeS3 uri encoding of signature version 4, space is %20 and only unreserved character is kept
+func escapeS3(text string) string {
+	return strings.ReplaceAll(url.QueryEscape(text), "+", "%20")
+}
+
+func hmacSHA256(kunci []byte, data string) []byte {
+	mac := hmac.New(sha256.New, kunci)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// errorRespon turn unexpected S3 response into error, body contain the S3 error code
+func errorRespon(resp *http.Response) error {
+	isi, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	return fmt.Errorf("s3 responded %s: %s", resp.Status, strings.TrimSpace(string(isi)))
+}
+
+func (s *S3Storage) Simpan(ctx context.Context, kunci string, data []byte, contentType string) (err error) {
+	header := http.Header{}
+	header.Set("Content-Type", contentType)
+	// content addressed object is immutable so it can be cached forever
+	header.Set("Cache-Control", "public, max-age=31536000, immutable")
+	resp, err := s.kirim(ctx, http.MethodPut, kunci, data, header)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return errorRespon(resp)
+	}
+	return nil
+}
+
+func (s *S3Storage) Ambil(ctx context.Context, kunci string) (isi io.ReadCloser, info Info, err error) {
+	resp, err := s.kirim(ctx, http.MethodGet, kunci, nil, nil)
+	if err != nil {
+		return nil, info, err
+	}
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		resp.Body.Close()
+		return nil, info, ErrTidakAda
+	default:
+		defer resp.Body.Close()
+		return nil, info, errorRespon(resp)
+	}
+	info.ContentType = resp.Header.Get("Content-Type")
+	info.Ukuran, _ = strconv.ParseInt(resp.Header.Get("Content-Length"), 10, 64)
+	info.DiubahPada, _ = http.ParseTime(resp.Header.Get("Last-Modified"))
+	return resp.Body, info, nil
+}
+
+func (s *S3Storage) Hapus(ctx context.Context, kunci string) (err error) {
+	resp, err := s.kirim(ctx, http.MethodDelete, kunci, nil, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	// S3 respond 204 even when object does not exist
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotFound {
+		return errorRespon(resp)
+	}
+	return nil
+}
+
+func (s *S3Storage) URL(kunci string) string {
+	if !AdalahKunci(kunci) {
+		return kunci
+	}
+	if s.publicURL != "" {
+		return s.publicURL + "/" + kunci
+	}
+	return s.objekURL(kunci).String()
+}
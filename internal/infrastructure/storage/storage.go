@@ -0,0 +1,73 @@
+package storage
+
+import (
+	"fmt"
+
+	"github.com/spf13/viper"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/helper"
+)
+
+const (
+	currentfilepath = "internal/infrastructure/storage/storage.go"
+	DriverLocal     = "local"
+	DriverS3        = "s3"
+)
+
+type StorageConf struct {
+	Driver      string `mapstructure:"storage_driver"`
+	PublicURL   string `mapstructure:"storage_public_url"`
+	LocalDir    string `mapstructure:"storage_local_dir"`
+	S3Endpoint  string `mapstructure:"storage_s3_endpoint"`
+	S3Region    string `mapstructure:"storage_s3_region"`
+	S3Bucket    string `mapstructure:"storage_s3_bucket"`
+	S3AccessKey string `mapstructure:"storage_s3_access_key"`
+	S3SecretKey string `mapstructure:"storage_s3_secret_key"`
+	S3PathStyle bool   `mapstructure:"storage_s3_path_style"`
+
+	// MediaBaseURL absolute url of media route, when set every media url point to the route
+	MediaBaseURL string `mapstructure:"storage_media_base_url"`
+	MediaSigned  bool   `mapstructure:"storage_media_signed"`
+	MediaSecret  string `mapstructure:"storage_media_secret"`
+	MediaTTL     int    `mapstructure:"storage_media_ttl"`
+}
+
+// StorageInit setup blob storage from configuration and wrap it to be served by media route
+func StorageInit(v *viper.Viper) *MediaStorage {
+	var storageConf StorageConf
+	if err := v.Unmarshal(&storageConf); err != nil {
+		helper.Logger(currentfilepath, helper.LoggerLevelPanic, fmt.Sprintf("failed init storage : %s", err.Error()))
+	}
+	if storageConf.LocalDir == "" {
+		storageConf.LocalDir = "./public/images"
+	}
+	if storageConf.Driver != DriverS3 {
+		if storageConf.Driver != "" && storageConf.Driver != DriverLocal {
+			helper.Logger(currentfilepath, helper.LoggerLevelWarn, fmt.Sprintf("unknown storage driver %s, using %s", storageConf.Driver, DriverLocal))
+		}
+		storageConf.Driver = DriverLocal
+	}
+	if storageConf.MediaSigned && storageConf.MediaSecret == "" {
+		helper.Logger(currentfilepath, helper.LoggerLevelPanic, "storage_media_secret is required for signed media url")
+	}
+	return NewMediaStorage(blobStorageInit(storageConf), storageConf)
+}
+
+// blobStorageInit create storage driver from configuration
+func blobStorageInit(storageConf StorageConf) BlobStorage {
+	if storageConf.Driver == DriverS3 {
+		s3Storage, err := NewS3Storage(storageConf)
+		if err != nil {
+			helper.Logger(currentfilepath, helper.LoggerLevelPanic, fmt.Sprintf("failed init storage : %s", err.Error()))
+		}
+		helper.Logger(currentfilepath, helper.LoggerLevelInfo, "⇨ Storage ready with s3 driver")
+		return s3Storage
+	}
+	// without public url, key is returned as file path like foto uploaded before storage existed
+	if storageConf.PublicURL == "" {
+		storageConf.PublicURL = storageConf.LocalDir
+	}
+	localStorage := NewLocalStorage(storageConf.LocalDir, storageConf.PublicURL)
+
+	helper.Logger(currentfilepath, helper.LoggerLevelInfo, "⇨ Storage ready with local driver")
+	return localStorage
+}
@@ -0,0 +1,35 @@
+package tabel
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"strings"
+)
+
+func bacaCSV(data []byte) (listSel [][]string, err error) {
+	// file saved by spreadsheet application often start with utf-8 byte order mark
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	// semicolon is used by spreadsheet in locale with decimal comma
+	if baris := bytes.SplitN(data, []byte("\n"), 2)[0]; bytes.Count(baris, []byte(";")) > bytes.Count(baris, []byte(",")) {
+		reader.Comma = ';'
+	}
+
+	listSel, err = reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrFileRusak, err.Error())
+	}
+	// apostrophe written by penulisCSV before formula is not part of the value
+	for _, sel := range listSel {
+		for i, v := range sel {
+			if strings.HasPrefix(v, "'") && (awalanFormula(v[1:]) || strings.HasPrefix(v[1:], "'")) {
+				sel[i] = v[1:]
+			}
+		}
+	}
+	return listSel, nil
+}
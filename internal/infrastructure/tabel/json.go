@@ -0,0 +1,88 @@
+package tabel
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// bacaJSON array of object, header is every key in order of first appearance.
+// Array value is joined with new line and null is empty, nested object is refused.
+func bacaJSON(data []byte) (listSel [][]string, err error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if token, err := decoder.Token(); err != nil || token != json.Delim('[') {
+		return nil, fmt.Errorf("%w: json must be an array of object", ErrFileRusak)
+	}
+
+	var header []string
+	kolom := map[string]int{}
+	for decoder.More() {
+		if token, err := decoder.Token(); err != nil || token != json.Delim('{') {
+			return nil, fmt.Errorf("%w: json must be an array of object", ErrFileRusak)
+		}
+		var sel []string
+		for decoder.More() {
+			token, err := decoder.Token()
+			if err != nil {
+				return nil, fmt.Errorf("%w: %s", ErrFileRusak, err.Error())
+			}
+			kunci, _ := token.(string)
+			var nilai interface{}
+			if err := decoder.Decode(&nilai); err != nil {
+				return nil, fmt.Errorf("%w: %s", ErrFileRusak, err.Error())
+			}
+			teks, err := teksJSON(nilai, true)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %s %s", ErrFileRusak, kunci, err.Error())
+			}
+
+			i, ada := kolom[kunci]
+			if !ada {
+				i = len(header)
+				kolom[kunci] = i
+				header = append(header, kunci)
+			}
+			for len(sel) <= i {
+				sel = append(sel, "")
+			}
+			sel[i] = teks
+		}
+		if _, err := decoder.Token(); err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrFileRusak, err.Error())
+		}
+		listSel = append(listSel, sel)
+	}
+	if _, err := decoder.Token(); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrFileRusak, err.Error())
+	}
+	// header is known only after every object is read
+	return append([][]string{header}, listSel...), nil
+}
+
+func teksJSON(nilai interface{}, bolehArray bool) (string, error) {
+	switch v := nilai.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case json.Number:
+		return v.String(), nil
+	case bool:
+		return strings.ToUpper(fmt.Sprint(v)), nil
+	case []interface{}:
+		if bolehArray {
+			var listTeks []string
+			for _, elemen := range v {
+				teks, err := teksJSON(elemen, false)
+				if err != nil {
+					return "", err
+				}
+				listTeks = append(listTeks, teks)
+			}
+			return strings.Join(listTeks, "\n"), nil
+		}
+	}
+	return "", fmt.Errorf("value must be text, number, boolean or array of them")
+}
@@ -0,0 +1,174 @@
+package tabel
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/csv"
+	"encoding/xml"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Penulis write table row by row to the writer, so big table is streamed without being held in memory.
+// Tutup must be called after the last row.
+type Penulis interface {
+	Tulis(sel []string) error
+	Tutup() error
+}
+
+// NewPenulis writer of csv or xlsx, json is written by the caller because its value is not only text
+func NewPenulis(format string, w io.Writer) (Penulis, error) {
+	switch format {
+	case FormatCSV:
+		return newPenulisCSV(w)
+	case FormatXLSX:
+		return newPenulisXLSX(w)
+	}
+	return nil, ErrFormatTidakDidukung
+}
+
+// ContentType media type of the format
+func ContentType(format string) string {
+	switch format {
+	case FormatCSV:
+		return "text/csv; charset=utf-8"
+	case FormatXLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	case FormatJSON:
+		return "application/json"
+	}
+	return "application/octet-stream"
+}
+
+type penulisCSV struct {
+	writer *csv.Writer
+}
+
+func newPenulisCSV(w io.Writer) (*penulisCSV, error) {
+	// byte order mark make spreadsheet application open the file as utf-8
+	if _, err := io.WriteString(w, "\xef\xbb\xbf"); err != nil {
+		return nil, err
+	}
+	return &penulisCSV{writer: csv.NewWriter(w)}, nil
+}
+
+func (p *penulisCSV) Tulis(sel []string) error {
+	// cell that spreadsheet would run as formula is written as text, bacaCSV remove the apostrophe again.
+	// Cell already starting with apostrophe is escaped too so it is read back unchanged.
+	aman := make([]string, len(sel))
+	for i, v := range sel {
+		aman[i] = v
+		if awalanFormula(v) || strings.HasPrefix(v, "'") {
+			aman[i] = "'" + v
+		}
+	}
+	return p.writer.Write(aman)
+}
+
+// awalanFormula report whether spreadsheet application read the cell as formula
+func awalanFormula(v string) bool {
+	return v != "" && strings.ContainsRune("=+-@\t\r", rune(v[0]))
+}
+
+func (p *penulisCSV) Tutup() error {
+	p.writer.Flush()
+	return p.writer.Error()
+}
+
+// bagianTetapXLSX part of xlsx file that is the same for every file with one sheet
+var bagianTetapXLSX = []struct {
+	nama string
+	isi  string
+}{
+	{"[Content_Types].xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+		`<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+		`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+		`</Types>`},
+	{"_rels/.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+		`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+		`</Relationships>`},
+	{"xl/workbook.xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+		`<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+		`<sheets><sheet name="Sheet1" sheetId="1" r:id="rId1"/></sheets></workbook>`},
+	{"xl/_rels/workbook.xml.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+		`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+		`</Relationships>`},
+}
+
+// polaAngkaXLSX whole number written as number cell, number with leading zero such as phone number stay text
+var polaAngkaXLSX = regexp.MustCompile(`^(0|[1-9][0-9]{0,14})$`)
+
+// penulisXLSX write the sheet as the last part of zip so row can be streamed, text is written as inline string
+type penulisXLSX struct {
+	arsip *zip.Writer
+	sheet *bufio.Writer
+	nomor int
+}
+
+func newPenulisXLSX(w io.Writer) (*penulisXLSX, error) {
+	arsip := zip.NewWriter(w)
+	for _, v := range bagianTetapXLSX {
+		f, err := arsip.Create(v.nama)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := io.WriteString(f, v.isi); err != nil {
+			return nil, err
+		}
+	}
+	f, err := arsip.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return nil, err
+	}
+	sheet := bufio.NewWriter(f)
+	sheet.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+		`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+	return &penulisXLSX{arsip: arsip, sheet: sheet}, nil
+}
+
+func (p *penulisXLSX) Tulis(sel []string) error {
+	p.nomor++
+	baris := strconv.Itoa(p.nomor)
+	p.sheet.WriteString(`<row r="` + baris + `">`)
+	for i, v := range sel {
+		if v == "" {
+			continue
+		}
+		referensi := namaKolomXLSX(i) + baris
+		if polaAngkaXLSX.MatchString(v) {
+			p.sheet.WriteString(`<c r="` + referensi + `"><v>` + v + `</v></c>`)
+			continue
+		}
+		p.sheet.WriteString(`<c r="` + referensi + `" t="inlineStr"><is><t xml:space="preserve">`)
+		if err := xml.EscapeText(p.sheet, []byte(v)); err != nil {
+			return err
+		}
+		p.sheet.WriteString(`</t></is></c>`)
+	}
+	_, err := p.sheet.WriteString(`</row>`)
+	return err
+}
+
+func (p *penulisXLSX) Tutup() error {
+	p.sheet.WriteString(`</sheetData></worksheet>`)
+	if err := p.sheet.Flush(); err != nil {
+		return err
+	}
+	return p.arsip.Close()
+}
+
+// namaKolomXLSX column letter of zero based index, the opposite of kolomXLSX
+func namaKolomXLSX(kolom int) string {
+	var huruf []byte
+	for kolom++; kolom > 0; kolom = (kolom - 1) / 26 {
+		huruf = append([]byte{byte('A' + (kolom-1)%26)}, huruf...)
+	}
+	return string(huruf)
+}
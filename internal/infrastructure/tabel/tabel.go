@@ -0,0 +1,79 @@
+package tabel
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	FormatCSV  = "csv"
+	FormatXLSX = "xlsx"
+	FormatJSON = "json"
+)
+
+var (
+	ErrFormatTidakDidukung = errors.New("file format is not supported, only csv, xlsx and json are accepted")
+	ErrFileRusak           = errors.New("file is corrupted or can not be read")
+)
+
+// FormatDariNama format of the file from its extension
+func FormatDariNama(nama string) (format string, err error) {
+	switch strings.ToLower(strings.TrimPrefix(filepath.Ext(nama), ".")) {
+	case FormatCSV:
+		return FormatCSV, nil
+	case FormatXLSX:
+		return FormatXLSX, nil
+	case FormatJSON:
+		return FormatJSON, nil
+	}
+	return "", ErrFormatTidakDidukung
+}
+
+// Baca every row of the file, for xlsx only the first sheet is read and for json the first row is the header
+// taken from object key. Cell is trimmed and empty row is skipped, so row number in the file is kept in NomorBaris.
+func Baca(format string, data []byte) (listBaris []Baris, err error) {
+	var listSel [][]string
+	switch format {
+	case FormatCSV:
+		listSel, err = bacaCSV(data)
+	case FormatXLSX:
+		listSel, err = bacaXLSX(data)
+	case FormatJSON:
+		listSel, err = bacaJSON(data)
+	default:
+		return nil, ErrFormatTidakDidukung
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for i, sel := range listSel {
+		kosong := true
+		for j := range sel {
+			sel[j] = strings.TrimSpace(sel[j])
+			if sel[j] != "" {
+				kosong = false
+			}
+		}
+		if kosong {
+			continue
+		}
+		listBaris = append(listBaris, Baris{NomorBaris: i + 1, Sel: sel})
+	}
+	return listBaris, nil
+}
+
+// Baris one non empty row, NomorBaris start from 1 like in spreadsheet
+type Baris struct {
+	NomorBaris int
+	Sel        []string
+}
+
+// Ambil cell at index, missing cell at the end of row is empty
+func (b Baris) Ambil(i int) string {
+	if i < 0 || i >= len(b.Sel) {
+		return ""
+	}
+	return b.Sel[i]
+}
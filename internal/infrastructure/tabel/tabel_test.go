@@ -0,0 +1,183 @@
+package tabel
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func buatXLSX(t *testing.T, listBagian map[string]string) []byte {
+	var buffer bytes.Buffer
+	arsip := zip.NewWriter(&buffer)
+	for nama, isi := range listBagian {
+		w, err := arsip.Create(nama)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write([]byte(isi))
+	}
+	if err := arsip.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buffer.Bytes()
+}
+
+func TestFormatDariNama(t *testing.T) {
+	for nama, harapan := range map[string]string{"produk.csv": FormatCSV, "PRODUK.XLSX": FormatXLSX} {
+		if format, err := FormatDariNama(nama); err != nil || format != harapan {
+			t.Errorf("%s: expected %s, got %s %v", nama, harapan, format, err)
+		}
+	}
+	if _, err := FormatDariNama("produk.xls"); !errors.Is(err, ErrFormatTidakDidukung) {
+		t.Errorf("xls must not be supported, got %v", err)
+	}
+}
+
+func TestBacaCSV(t *testing.T) {
+	data := "\xef\xbb\xbfnama_produk,deskripsi\n\"Kaos, Polos\",\"baris\npanjang\"\n,\n  Topi ;  \n"
+	listBaris, err := Baca(FormatCSV, []byte(data))
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	harapan := []Baris{
+		{NomorBaris: 1, Sel: []string{"nama_produk", "deskripsi"}},
+		{NomorBaris: 2, Sel: []string{"Kaos, Polos", "baris\npanjang"}},
+		{NomorBaris: 4, Sel: []string{"Topi ;"}},
+	}
+	if !reflect.DeepEqual(listBaris, harapan) {
+		t.Errorf("unexpected rows %#v", listBaris)
+	}
+	if listBaris[2].Ambil(1) != "" {
+		t.Errorf("missing cell must be empty")
+	}
+
+	// semicolon separated file
+	listBaris, err = Baca(FormatCSV, []byte("nama;harga\nKaos;15000\n"))
+	if err != nil || len(listBaris) != 2 || listBaris[1].Ambil(1) != "15000" {
+		t.Errorf("unexpected semicolon rows %#v %v", listBaris, err)
+	}
+}
+
+func TestBacaXLSX(t *testing.T) {
+	data := buatXLSX(t, map[string]string{
+		"xl/workbook.xml": `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+			<sheets><sheet name="Produk" sheetId="1" r:id="rId3"/><sheet name="Lain" sheetId="2" r:id="rId1"/></sheets></workbook>`,
+		"xl/_rels/workbook.xml.rels": `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+			<Relationship Id="rId1" Target="worksheets/sheet1.xml"/><Relationship Id="rId3" Target="/xl/worksheets/produk.xml"/></Relationships>`,
+		"xl/sharedStrings.xml": `<sst xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+			<si><t>nama_produk</t></si><si><t>stok</t></si><si><r><t>Kaos </t></r><r><t>Polos</t></r></si></sst>`,
+		"xl/worksheets/produk.xml": `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>
+			<row r="1"><c r="A1" t="s"><v>0</v></c><c r="C1" t="s"><v>1</v></c></row>
+			<row r="3"><c r="A3" t="s"><v>2</v></c><c r="B3" t="b"><v>1</v></c><c r="C3"><v>12</v></c></row>
+			<row r="4"><c r="A4" t="inlineStr"><is><t>Topi</t></is></c></row>
+			</sheetData></worksheet>`,
+		"xl/worksheets/sheet1.xml": `<worksheet><sheetData><row r="1"><c r="A1" t="inlineStr"><is><t>salah</t></is></c></row></sheetData></worksheet>`,
+	})
+	listBaris, err := Baca(FormatXLSX, data)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	harapan := []Baris{
+		{NomorBaris: 1, Sel: []string{"nama_produk", "", "stok"}},
+		{NomorBaris: 3, Sel: []string{"Kaos Polos", "TRUE", "12"}},
+		{NomorBaris: 4, Sel: []string{"Topi"}},
+	}
+	if !reflect.DeepEqual(listBaris, harapan) {
+		t.Errorf("unexpected rows %#v", listBaris)
+	}
+}
+
+func TestBacaRusak(t *testing.T) {
+	if _, err := Baca(FormatXLSX, []byte("bukan zip")); !errors.Is(err, ErrFileRusak) {
+		t.Errorf("expected ErrFileRusak, got %v", err)
+	}
+	data := buatXLSX(t, map[string]string{
+		"xl/worksheets/sheet1.xml": `<worksheet><sheetData><row r="1"><c r="A1" t="s"><v>5</v></c></row></sheetData></worksheet>`,
+	})
+	if _, err := Baca(FormatXLSX, data); !errors.Is(err, ErrFileRusak) {
+		t.Errorf("missing shared string must be refused, got %v", err)
+	}
+	if _, err := Baca("xls", nil); !errors.Is(err, ErrFormatTidakDidukung) {
+		t.Errorf("expected ErrFormatTidakDidukung, got %v", err)
+	}
+}
+
+func TestBacaJSON(t *testing.T) {
+	data := `[{"nama_produk":"Kaos","harga":15000,"foto":["a.jpg","b.jpg"],"aktif":true},{"deskripsi":null,"nama_produk":"Topi"}]`
+	listBaris, err := Baca(FormatJSON, []byte(data))
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	harapan := []Baris{
+		{NomorBaris: 1, Sel: []string{"nama_produk", "harga", "foto", "aktif", "deskripsi"}},
+		{NomorBaris: 2, Sel: []string{"Kaos", "15000", "a.jpg\nb.jpg", "TRUE"}},
+		{NomorBaris: 3, Sel: []string{"Topi", "", "", "", ""}},
+	}
+	if !reflect.DeepEqual(listBaris, harapan) {
+		t.Errorf("unexpected rows %#v", listBaris)
+	}
+	for _, v := range []string{`{"a":1}`, `[{"a":{"b":1}}]`, `[{"a":[[1]]}]`, `[{"a":1}`} {
+		if _, err := Baca(FormatJSON, []byte(v)); !errors.Is(err, ErrFileRusak) {
+			t.Errorf("%s: expected ErrFileRusak, got %v", v, err)
+		}
+	}
+}
+
+func TestPenulis(t *testing.T) {
+	listSel := [][]string{
+		{"id", "nama_produk", "stok", "deskripsi"},
+		{"1", "Kaos \"Polos\"", "0", "<b>baris</b>\npanjang & lebar"},
+		{"2", " Topi ", "", "0812"},
+		{"3", "=HYPERLINK(\"http://example.com\")", "-1", "@SUM(A1)"},
+		{"4", "+62812", "'biasa", "'=kutip"},
+	}
+	for _, format := range []string{FormatCSV, FormatXLSX} {
+		var buffer bytes.Buffer
+		penulis, err := NewPenulis(format, &buffer)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, sel := range listSel {
+			if err := penulis.Tulis(sel); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := penulis.Tutup(); err != nil {
+			t.Fatal(err)
+		}
+		if format == FormatCSV && !strings.Contains(buffer.String(), "'=HYPERLINK") {
+			t.Errorf("formula is not escaped in csv %s", buffer.String())
+		}
+
+		// what is written can be read back, cell is trimmed by Baca
+		listBaris, err := Baca(format, buffer.Bytes())
+		if err != nil {
+			t.Fatalf("%s: unexpected error %v", format, err)
+		}
+		if len(listBaris) != len(listSel) {
+			t.Fatalf("%s: expected %d rows, got %d", format, len(listSel), len(listBaris))
+		}
+		for i, baris := range listBaris {
+			for j, v := range listSel[i] {
+				if baris.Ambil(j) != strings.TrimSpace(v) {
+					t.Errorf("%s: row %d column %d expected %q, got %q", format, i+1, j+1, v, baris.Ambil(j))
+				}
+			}
+		}
+	}
+	if _, err := NewPenulis(FormatJSON, &bytes.Buffer{}); !errors.Is(err, ErrFormatTidakDidukung) {
+		t.Errorf("json writer is not provided, got %v", err)
+	}
+}
+
+func TestNamaKolomXLSX(t *testing.T) {
+	for _, kolom := range []int{0, 25, 26, 51, 701, 702, 16383} {
+		referensi := namaKolomXLSX(kolom) + "1"
+		if hasil, err := kolomXLSX(referensi); err != nil || hasil != kolom {
+			t.Errorf("%d: %s read back as %d %v", kolom, referensi, hasil, err)
+		}
+	}
+}
@@ -0,0 +1,189 @@
+package tabel
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+)
+
+const (
+	// maxBarisXLSX last row number of a sheet in spreadsheet application
+	maxBarisXLSX = 1 << 20
+	// maxUkuranBagian decompressed size limit of one part of xlsx file, guard against zip bomb
+	maxUkuranBagian = 64 << 20
+)
+
+type workbookXLSX struct {
+	Sheet []struct {
+		ID string `xml:"id,attr"`
+	} `xml:"sheets>sheet"`
+}
+
+type relasiXLSX struct {
+	Relationship []struct {
+		ID     string `xml:"Id,attr"`
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+type teksXLSX struct {
+	T string `xml:"t"`
+	R []struct {
+		T string `xml:"t"`
+	} `xml:"r"`
+}
+
+// String rich text is stored as several run, the plain text is every run joined
+func (t teksXLSX) String() string {
+	if len(t.R) == 0 {
+		return t.T
+	}
+	var builder strings.Builder
+	for _, r := range t.R {
+		builder.WriteString(r.T)
+	}
+	return builder.String()
+}
+
+type sharedStringXLSX struct {
+	SI []teksXLSX `xml:"si"`
+}
+
+type sheetXLSX struct {
+	Row []struct {
+		R string `xml:"r,attr"`
+		C []struct {
+			R  string   `xml:"r,attr"`
+			T  string   `xml:"t,attr"`
+			V  string   `xml:"v"`
+			Is teksXLSX `xml:"is"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+func bacaXLSX(data []byte) (listSel [][]string, err error) {
+	arsip, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrFileRusak, err.Error())
+	}
+	listBagian := map[string]*zip.File{}
+	for _, f := range arsip.File {
+		listBagian[strings.TrimPrefix(f.Name, "/")] = f
+	}
+
+	// shared string is optional, sheet with only number or inline string does not have it
+	var sharedString sharedStringXLSX
+	if f, ada := listBagian["xl/sharedStrings.xml"]; ada {
+		if err := bacaBagianXLSX(f, &sharedString); err != nil {
+			return nil, err
+		}
+	}
+
+	f, ada := listBagian[lokasiSheetPertama(listBagian)]
+	if !ada {
+		return nil, fmt.Errorf("%w: worksheet not found", ErrFileRusak)
+	}
+	var sheet sheetXLSX
+	if err := bacaBagianXLSX(f, &sheet); err != nil {
+		return nil, err
+	}
+
+	for _, row := range sheet.Row {
+		// row without cell value is not written, so position is taken from its reference
+		nomor := len(listSel) + 1
+		if row.R != "" {
+			if nomor, err = strconv.Atoi(row.R); err != nil || nomor < len(listSel)+1 || nomor > maxBarisXLSX {
+				return nil, fmt.Errorf("%w: invalid row %q", ErrFileRusak, row.R)
+			}
+		}
+		for len(listSel) < nomor-1 {
+			listSel = append(listSel, nil)
+		}
+
+		var sel []string
+		for _, c := range row.C {
+			kolom := len(sel)
+			if c.R != "" {
+				if kolom, err = kolomXLSX(c.R); err != nil {
+					return nil, err
+				}
+			}
+			for len(sel) <= kolom {
+				sel = append(sel, "")
+			}
+			switch c.T {
+			case "s":
+				i, errConv := strconv.Atoi(c.V)
+				if errConv != nil || i < 0 || i >= len(sharedString.SI) {
+					return nil, fmt.Errorf("%w: invalid shared string in %s", ErrFileRusak, c.R)
+				}
+				sel[kolom] = sharedString.SI[i].String()
+			case "inlineStr":
+				sel[kolom] = c.Is.String()
+			case "b":
+				sel[kolom] = strings.ToUpper(strconv.FormatBool(c.V == "1"))
+			default:
+				sel[kolom] = c.V
+			}
+		}
+		listSel = append(listSel, sel)
+	}
+	return listSel, nil
+}
+
+// lokasiSheetPertama path of the first sheet in workbook order, fall back to the usual name
+func lokasiSheetPertama(listBagian map[string]*zip.File) string {
+	const lokasiDefault = "xl/worksheets/sheet1.xml"
+	var workbook workbookXLSX
+	var relasi relasiXLSX
+	fWorkbook, adaWorkbook := listBagian["xl/workbook.xml"]
+	fRelasi, adaRelasi := listBagian["xl/_rels/workbook.xml.rels"]
+	if !adaWorkbook || !adaRelasi || bacaBagianXLSX(fWorkbook, &workbook) != nil || bacaBagianXLSX(fRelasi, &relasi) != nil || len(workbook.Sheet) == 0 {
+		return lokasiDefault
+	}
+	for _, v := range relasi.Relationship {
+		if v.ID != workbook.Sheet[0].ID {
+			continue
+		}
+		// target is relative to xl folder unless it is absolute
+		if strings.HasPrefix(v.Target, "/") {
+			return strings.TrimPrefix(v.Target, "/")
+		}
+		return path.Join("xl", v.Target)
+	}
+	return lokasiDefault
+}
+
+func bacaBagianXLSX(f *zip.File, v interface{}) error {
+	r, err := f.Open()
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrFileRusak, err.Error())
+	}
+	defer r.Close()
+	if err := xml.NewDecoder(io.LimitReader(r, maxUkuranBagian)).Decode(v); err != nil {
+		return fmt.Errorf("%w: %s", ErrFileRusak, err.Error())
+	}
+	return nil
+}
+
+// kolomXLSX zero based column index of cell reference such as AB12
+func kolomXLSX(referensi string) (kolom int, err error) {
+	huruf := 0
+	for _, r := range strings.ToUpper(referensi) {
+		if r < 'A' || r > 'Z' {
+			break
+		}
+		kolom = kolom*26 + int(r-'A') + 1
+		huruf++
+	}
+	// spreadsheet application has at most 3 letter column (XFD)
+	if huruf == 0 || huruf > 3 {
+		return 0, fmt.Errorf("%w: invalid cell %q", ErrFileRusak, referensi)
+	}
+	return kolom - 1, nil
+}
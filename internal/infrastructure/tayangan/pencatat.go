@@ -0,0 +1,74 @@
+package tayangan
+
+import (
+	"sync"
+	"time"
+)
+
+type kunciTayangan struct {
+	produkID   uint
+	pengunjung string
+}
+
+// Pencatat count produk view in memory so reading produk does not wait for database.
+// Dedup only covers view received by this instance.
+type Pencatat struct {
+	jendela  time.Duration
+	Interval time.Duration
+	sekarang func() time.Time
+
+	mu       sync.Mutex
+	terakhir map[kunciTayangan]time.Time
+	jumlah   map[uint]uint
+}
+
+func NewPencatat(jendela, interval time.Duration) *Pencatat {
+	return &Pencatat{
+		jendela:  jendela,
+		Interval: interval,
+		sekarang: time.Now,
+		terakhir: map[kunciTayangan]time.Time{},
+		jumlah:   map[uint]uint{},
+	}
+}
+
+// Catat count view of produk by pengunjung (user or ip), return false when the same pengunjung
+// already viewed the produk within the window
+func (p *Pencatat) Catat(produkID uint, pengunjung string) bool {
+	sekarang := p.sekarang()
+	kunci := kunciTayangan{produkID: produkID, pengunjung: pengunjung}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if waktu, ok := p.terakhir[kunci]; ok && sekarang.Sub(waktu) < p.jendela {
+		return false
+	}
+	p.terakhir[kunci] = sekarang
+	p.jumlah[produkID]++
+	return true
+}
+
+// Ambil return view counted since the last call per produk id and forget pengunjung whose window has passed
+func (p *Pencatat) Ambil() map[uint]uint {
+	sekarang := p.sekarang()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	jumlah := p.jumlah
+	p.jumlah = map[uint]uint{}
+	for kunci, waktu := range p.terakhir {
+		if sekarang.Sub(waktu) >= p.jendela {
+			delete(p.terakhir, kunci)
+		}
+	}
+	return jumlah
+}
+
+// Kembalikan put back view that failed to be written so it is written on the next call of Ambil
+func (p *Pencatat) Kembalikan(jumlah map[uint]uint) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for produkID, n := range jumlah {
+		p.jumlah[produkID] += n
+	}
+}
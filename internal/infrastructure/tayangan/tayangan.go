@@ -0,0 +1,34 @@
+package tayangan
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/helper"
+)
+
+const currentfilepath = "internal/infrastructure/tayangan/tayangan.go"
+
+type TayanganConf struct {
+	// Jendela view of the same produk by the same user or ip in this many minutes is counted once
+	Jendela int `mapstructure:"tayangan_jendela_menit"`
+	// Interval view counted in memory is written to database every this many seconds
+	Interval int `mapstructure:"tayangan_interval_detik"`
+}
+
+// TayanganInit setup recorder of produk view, counted view is written to database by background task added in handler
+func TayanganInit(v *viper.Viper) *Pencatat {
+	var tayanganConf TayanganConf
+	if err := v.Unmarshal(&tayanganConf); err != nil {
+		helper.Logger(currentfilepath, helper.LoggerLevelPanic, fmt.Sprintf("failed init tayangan : %s", err.Error()))
+	}
+	if tayanganConf.Jendela < 1 {
+		tayanganConf.Jendela = 30
+	}
+	if tayanganConf.Interval < 1 {
+		tayanganConf.Interval = 60
+	}
+
+	return NewPencatat(time.Duration(tayanganConf.Jendela)*time.Minute, time.Duration(tayanganConf.Interval)*time.Second)
+}
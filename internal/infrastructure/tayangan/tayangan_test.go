@@ -0,0 +1,66 @@
+package tayangan
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPencatat(t *testing.T) {
+	waktu := time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC)
+	pencatat := NewPencatat(30*time.Minute, time.Minute)
+	pencatat.sekarang = func() time.Time { return waktu }
+
+	// the same pengunjung is counted once per window, other pengunjung and produk are counted separately
+	if !pencatat.Catat(1, "user:1") {
+		t.Errorf("first view is not counted")
+	}
+	if pencatat.Catat(1, "user:1") {
+		t.Errorf("repeated view in window is counted")
+	}
+	pencatat.Catat(1, "ip:10.0.0.1")
+	pencatat.Catat(2, "user:1")
+
+	jumlah := pencatat.Ambil()
+	if jumlah[1] != 2 || jumlah[2] != 1 {
+		t.Errorf("Ambil = %v, want produk 1 = 2 and produk 2 = 1", jumlah)
+	}
+	if jumlah := pencatat.Ambil(); len(jumlah) != 0 {
+		t.Errorf("second Ambil = %v, want empty", jumlah)
+	}
+
+	// view after the window is counted again
+	waktu = waktu.Add(29 * time.Minute)
+	if pencatat.Catat(1, "user:1") {
+		t.Errorf("view before window end is counted")
+	}
+	waktu = waktu.Add(time.Minute)
+	if !pencatat.Catat(1, "user:1") {
+		t.Errorf("view after window end is not counted")
+	}
+
+	// view that failed to be written is returned on the next Ambil
+	jumlah = pencatat.Ambil()
+	pencatat.Kembalikan(jumlah)
+	pencatat.Catat(3, "user:2")
+	jumlah = pencatat.Ambil()
+	if jumlah[1] != 1 || jumlah[3] != 1 {
+		t.Errorf("Ambil after Kembalikan = %v, want produk 1 = 1 and produk 3 = 1", jumlah)
+	}
+}
+
+func TestPencatatLupakanPengunjung(t *testing.T) {
+	waktu := time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC)
+	pencatat := NewPencatat(time.Minute, time.Minute)
+	pencatat.sekarang = func() time.Time { return waktu }
+
+	pencatat.Catat(1, "user:1")
+	pencatat.Catat(2, "user:1")
+	waktu = waktu.Add(time.Minute)
+	pencatat.Catat(2, "user:1")
+	pencatat.Ambil()
+
+	// only pengunjung still in window is remembered
+	if len(pencatat.terakhir) != 1 {
+		t.Errorf("remembered %d pengunjung, want 1", len(pencatat.terakhir))
+	}
+}
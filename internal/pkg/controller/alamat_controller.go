@@ -14,6 +14,8 @@ type AlamatController interface {
 	GetAlamatByID(ctx *fiber.Ctx) (err error)
 	UpdateAlamatByID(ctx *fiber.Ctx) (err error)
 	DeleteAlamatByID(ctx *fiber.Ctx) (err error)
+	RestoreAlamatByID(ctx *fiber.Ctx) (err error)
+	GetSampahAlamat(ctx *fiber.Ctx) (err error)
 }
 
 type AlamatControllerImpl struct {
@@ -236,3 +238,68 @@ func (ac *AlamatControllerImpl) DeleteAlamatByID(ctx *fiber.Ctx) (err error) {
 	}
 	return ctx.Status(fiber.StatusOK).JSON(response)
 }
+
+func (ac *AlamatControllerImpl) RestoreAlamatByID(ctx *fiber.Ctx) (err error) {
+	// get userID from middleware
+	userIDMiddleware := ctx.Locals("userID")
+	userID, _ := strconv.Atoi(fmt.Sprintf("%v", userIDMiddleware))
+
+	// get ID from url parameter
+	ID, err := strconv.Atoi(ctx.Params("id"))
+	if err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "ID must integer > 0",
+			Error:   []string{err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+	// call RestoreAlamatByID from alamat useCase to bring back alamat record and get error information
+	c := ctx.Context()
+	errUseCase := ac.alamatUseCase.RestoreAlamatByID(c, uint(userID), uint(ID))
+	if errUseCase.Err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "Failed to PUT data",
+			Error:   []string{errUseCase.Err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(errUseCase.Code).JSON(response)
+	}
+	// success response
+	response := BaseResponse{
+		Status:  true,
+		Message: "Succeed to PUT data",
+		Error:   nil,
+		Data:    "",
+	}
+	return ctx.Status(fiber.StatusOK).JSON(response)
+}
+
+func (ac *AlamatControllerImpl) GetSampahAlamat(ctx *fiber.Ctx) (err error) {
+	// get userID from middleware
+	userIDMiddleware := ctx.Locals("userID")
+	userID, _ := strconv.Atoi(fmt.Sprintf("%v", userIDMiddleware))
+
+	// call GetSampahAlamat from alamat useCase to get deleted alamat
+	c := ctx.Context()
+	responseUseCase, errUseCase := ac.alamatUseCase.GetSampahAlamat(c, uint(userID))
+	if errUseCase.Err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "Failed to GET data",
+			Error:   []string{errUseCase.Err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(errUseCase.Code).JSON(response)
+	}
+	// success response
+	response := BaseResponse{
+		Status:  true,
+		Message: "Succeed to GET data",
+		Error:   nil,
+		Data:    responseUseCase,
+	}
+	return ctx.Status(fiber.StatusOK).JSON(response)
+}
@@ -0,0 +1,265 @@
+package controller
+
+import (
+	"fmt"
+	"github.com/gofiber/fiber/v2"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/pkg/dto"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/pkg/usecase"
+	"strconv"
+)
+
+type AtributController interface {
+	GetAtributCategory(ctx *fiber.Ctx) (err error)
+	CreateAtributCategory(ctx *fiber.Ctx) (err error)
+	UpdateAtributCategory(ctx *fiber.Ctx) (err error)
+	DeleteAtributCategory(ctx *fiber.Ctx) (err error)
+	SimpanAtributProduk(ctx *fiber.Ctx) (err error)
+}
+
+type AtributControllerImpl struct {
+	atributUseCase usecase.AtributUseCase
+}
+
+func NewAtributController(atributUseCase usecase.AtributUseCase) AtributController {
+	return &AtributControllerImpl{atributUseCase: atributUseCase}
+}
+
+func (ac *AtributControllerImpl) GetAtributCategory(ctx *fiber.Ctx) (err error) {
+	// get id category from url parameter
+	categoryID, err := strconv.Atoi(ctx.Params("id"))
+	if err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "ID must integer > 0",
+			Error:   []string{err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	// call GetAtributCategory from atribut useCase
+	c := ctx.Context()
+	responseUseCase, errUseCase := ac.atributUseCase.GetAtributCategory(c, uint(categoryID))
+	if errUseCase.Err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "Failed to GET data",
+			Error:   []string{errUseCase.Err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(errUseCase.Code).JSON(response)
+	}
+	// success response
+	response := BaseResponse{
+		Status:  true,
+		Message: "Succeed to GET data",
+		Error:   nil,
+		Data:    responseUseCase,
+	}
+	return ctx.Status(fiber.StatusOK).JSON(response)
+}
+
+func (ac *AtributControllerImpl) CreateAtributCategory(ctx *fiber.Ctx) (err error) {
+	// get id category from url parameter
+	categoryID, err := strconv.Atoi(ctx.Params("id"))
+	if err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "ID must integer > 0",
+			Error:   []string{err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	// parse body request
+	data := new(dto.AtributCategoryRequest)
+	if errParse := ctx.BodyParser(data); errParse != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "Failed to POST data",
+			Error:   []string{errParse.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+	data.CategoryID = uint(categoryID)
+
+	// call CreateAtributCategory from atribut useCase
+	c := ctx.Context()
+	responseUseCase, errUseCase := ac.atributUseCase.CreateAtributCategory(c, *data)
+	if errUseCase.Err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "Failed to POST data",
+			Error:   []string{errUseCase.Err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(errUseCase.Code).JSON(response)
+	}
+	// success response
+	response := BaseResponse{
+		Status:  true,
+		Message: "Succeed to POST data",
+		Error:   nil,
+		Data:    responseUseCase,
+	}
+	return ctx.Status(fiber.StatusOK).JSON(response)
+}
+
+func (ac *AtributControllerImpl) UpdateAtributCategory(ctx *fiber.Ctx) (err error) {
+	// get id category and id attribute from url parameter
+	categoryID, err := strconv.Atoi(ctx.Params("id"))
+	if err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "ID must integer > 0",
+			Error:   []string{err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+	ID, err := strconv.Atoi(ctx.Params("attr_id"))
+	if err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "attr_id must integer > 0",
+			Error:   []string{err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	// parse body request
+	data := new(dto.AtributCategoryRequest)
+	if errParse := ctx.BodyParser(data); errParse != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "Failed to PUT data",
+			Error:   []string{errParse.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+	data.CategoryID = uint(categoryID)
+	data.ID = uint(ID)
+
+	// call UpdateAtributCategory from atribut useCase
+	c := ctx.Context()
+	errUseCase := ac.atributUseCase.UpdateAtributCategory(c, *data)
+	if errUseCase.Err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "Failed to PUT data",
+			Error:   []string{errUseCase.Err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(errUseCase.Code).JSON(response)
+	}
+	// success response
+	response := BaseResponse{
+		Status:  true,
+		Message: "Succeed to PUT data",
+		Error:   nil,
+		Data:    "",
+	}
+	return ctx.Status(fiber.StatusOK).JSON(response)
+}
+
+func (ac *AtributControllerImpl) DeleteAtributCategory(ctx *fiber.Ctx) (err error) {
+	// get id category and id attribute from url parameter
+	categoryID, err := strconv.Atoi(ctx.Params("id"))
+	if err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "ID must integer > 0",
+			Error:   []string{err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+	ID, err := strconv.Atoi(ctx.Params("attr_id"))
+	if err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "attr_id must integer > 0",
+			Error:   []string{err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	// call DeleteAtributCategory from atribut useCase
+	c := ctx.Context()
+	errUseCase := ac.atributUseCase.DeleteAtributCategory(c, uint(categoryID), uint(ID))
+	if errUseCase.Err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "Failed to DELETE data",
+			Error:   []string{errUseCase.Err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(errUseCase.Code).JSON(response)
+	}
+	// success response
+	response := BaseResponse{
+		Status:  true,
+		Message: "Succeed to DELETE data",
+		Error:   nil,
+		Data:    "",
+	}
+	return ctx.Status(fiber.StatusOK).JSON(response)
+}
+
+func (ac *AtributControllerImpl) SimpanAtributProduk(ctx *fiber.Ctx) (err error) {
+	// get tokoID (tokoID is the same as userID) from middleware
+	tokoIDMiddleware := ctx.Locals("userID")
+	tokoID, _ := strconv.Atoi(fmt.Sprintf("%v", tokoIDMiddleware))
+
+	// get id from url parameter
+	ID, err := strconv.Atoi(ctx.Params("id"))
+	if err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "ID must integer > 0",
+			Error:   []string{err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	// parse body request
+	data := new(dto.AtributProdukRequest)
+	if errParse := ctx.BodyParser(data); errParse != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "Failed to PUT data",
+			Error:   []string{errParse.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+	data.ProdukID = uint(ID)
+	data.TokoID = uint(tokoID)
+
+	// call SimpanAtributProduk from atribut useCase
+	c := ctx.Context()
+	errUseCase := ac.atributUseCase.SimpanAtributProduk(c, *data)
+	if errUseCase.Err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "Failed to PUT data",
+			Error:   []string{errUseCase.Err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(errUseCase.Code).JSON(response)
+	}
+	// success response
+	response := BaseResponse{
+		Status:  true,
+		Message: "Succeed to PUT data",
+		Error:   nil,
+		Data:    "",
+	}
+	return ctx.Status(fiber.StatusOK).JSON(response)
+}
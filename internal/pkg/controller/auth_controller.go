@@ -11,6 +11,7 @@ type Auth interface {
 	CheckJwt(ctx *fiber.Ctx) error
 	CheckJwtUser(ctx *fiber.Ctx) error
 	CheckJwtAdmin(ctx *fiber.Ctx) error
+	CheckJwtOptional(ctx *fiber.Ctx) error
 }
 
 type AuthImpl struct {
@@ -183,3 +184,28 @@ func (a *AuthImpl) CheckJwtAdmin(ctx *fiber.Ctx) error {
 	}
 	return err
 }
+
+// CheckJwtOptional for public endpoint that behave differently for logged in user,
+// userID is only sent to next controller when token is a valid user token and request without it is not rejected
+func (a *AuthImpl) CheckJwtOptional(ctx *fiber.Ctx) error {
+
+	// get token from header request
+	token := ctx.Get("token")
+	if token == "" {
+		return ctx.Next()
+	}
+
+	// verify jwt with VerifyJWt function from middleware useCase
+	c := ctx.Context()
+	claim, err := a.middleware.VerifyJwt(c, token)
+	valid := err == nil && claim.Issuer == "syahril" && claim.Audience == "user" && claim.Scope == "user" &&
+		claim.Type == "ACCESS_TOKEN" && time.Unix(claim.NotValidBefore, 0).Before(time.Now()) &&
+		!time.Unix(claim.ExpiredAT, 0).Before(time.Now())
+
+	// send userID to next controller
+	if valid {
+		userID := strconv.Itoa(int(claim.Subject))
+		ctx.Locals("userID", userID)
+	}
+	return ctx.Next()
+}
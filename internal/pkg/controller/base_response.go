@@ -1,6 +1,10 @@
 package controller
 
-import "fmt"
+import (
+	"fmt"
+	"github.com/gofiber/fiber/v2"
+	"strings"
+)
 
 type BaseResponse struct {
 	Status  bool        `json:"status"`
@@ -18,3 +22,9 @@ func (bs *BaseResponse) TokenErrorResp(methodErr string) BaseResponse {
 	}
 	return response
 }
+
+// alihkanSlug permanent redirect from old slug in the last path segment to the current slug
+func alihkanSlug(ctx *fiber.Ctx, slugLama, slug string) error {
+	lokasi := strings.TrimSuffix(ctx.Path(), slugLama) + slug
+	return ctx.Redirect(lokasi, fiber.StatusMovedPermanently)
+}
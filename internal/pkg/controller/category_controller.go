@@ -13,6 +13,8 @@ type CategoryController interface {
 	GetCategoryByID(ctx *fiber.Ctx) (err error)
 	UpdateCategoryByID(ctx *fiber.Ctx) (err error)
 	DeleteCategoryByID(ctx *fiber.Ctx) (err error)
+	RestoreCategoryByID(ctx *fiber.Ctx) (err error)
+	GetSampahCategory(ctx *fiber.Ctx) (err error)
 }
 
 type CategoryControllerImpl struct {
@@ -220,3 +222,64 @@ func (cc *CategoryControllerImpl) DeleteCategoryByID(ctx *fiber.Ctx) (err error)
 	}
 	return ctx.Status(fiber.StatusOK).JSON(response)
 }
+
+func (cc *CategoryControllerImpl) RestoreCategoryByID(ctx *fiber.Ctx) (err error) {
+	// get id from url parameter
+	ID, err := strconv.Atoi(ctx.Params("id"))
+	if err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "ID must integer > 0",
+			Error:   []string{err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	// call RestoreCategoryByID from category useCase to get error information
+	c := ctx.Context()
+	errUseCase := cc.categoryUseCase.RestoreCategoryByID(c, uint(ID))
+	if errUseCase.Err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "Failed to PUT data",
+			Error:   []string{errUseCase.Err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(errUseCase.Code).JSON(response)
+	}
+
+	// success response
+	response := BaseResponse{
+		Status:  true,
+		Message: "Succeed to PUT data",
+		Error:   nil,
+		Data:    "",
+	}
+	return ctx.Status(fiber.StatusOK).JSON(response)
+}
+
+func (cc *CategoryControllerImpl) GetSampahCategory(ctx *fiber.Ctx) (err error) {
+	c := ctx.Context()
+
+	// call GetSampahCategory function from category UseCase to get deleted categories
+	responseUseCase, errUseCase := cc.categoryUseCase.GetSampahCategory(c)
+	if errUseCase.Err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "Failed to GET data",
+			Error:   []string{errUseCase.Err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(errUseCase.Code).JSON(response)
+	}
+
+	// success response
+	response := BaseResponse{
+		Status:  true,
+		Message: "Succeed to GET data",
+		Error:   nil,
+		Data:    responseUseCase,
+	}
+	return ctx.Status(fiber.StatusOK).JSON(response)
+}
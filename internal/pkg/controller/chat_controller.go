@@ -0,0 +1,405 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/websocket/v2"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/helper"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/pkg/dto"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/pkg/usecase"
+	"strconv"
+	"sync"
+)
+
+const (
+	ChatEventPesan = "pesan"
+	ChatEventRead  = "read"
+	ChatEventError = "error"
+)
+
+// ChatHub keep websocket connection of online user, one user can open more than one connection.
+// Hub live in process memory, so event only delivered to user connected to the same instance.
+type ChatHub struct {
+	mu      sync.RWMutex
+	koneksi map[uint]map[*websocket.Conn]*sync.Mutex
+}
+
+func NewChatHub() *ChatHub {
+	return &ChatHub{koneksi: map[uint]map[*websocket.Conn]*sync.Mutex{}}
+}
+
+func (h *ChatHub) tambah(userID uint, conn *websocket.Conn) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.koneksi[userID] == nil {
+		h.koneksi[userID] = map[*websocket.Conn]*sync.Mutex{}
+	}
+	h.koneksi[userID][conn] = &sync.Mutex{}
+}
+
+func (h *ChatHub) hapus(userID uint, conn *websocket.Conn) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.koneksi[userID], conn)
+	if len(h.koneksi[userID]) == 0 {
+		delete(h.koneksi, userID)
+	}
+}
+
+// Kirim push event to every connection of user
+func (h *ChatHub) Kirim(userID uint, event dto.ChatEvent) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	for conn, lock := range h.koneksi[userID] {
+		// websocket connection does not support concurrent writer
+		lock.Lock()
+		if err := conn.WriteJSON(event); err != nil {
+			helper.Logger("chat_controller", helper.LoggerLevelWarn, fmt.Sprintf("failed to push chat event to user %d: %s", userID, err.Error()))
+		}
+		lock.Unlock()
+	}
+}
+
+type ChatController interface {
+	CreatePercakapan(ctx *fiber.Ctx) (err error)
+	GetMyPercakapan(ctx *fiber.Ctx) (err error)
+	GetPesan(ctx *fiber.Ctx) (err error)
+	KirimPesan(ctx *fiber.Ctx) (err error)
+	MarkRead(ctx *fiber.Ctx) (err error)
+	CountUnread(ctx *fiber.Ctx) (err error)
+	UpgradeWebSocket(ctx *fiber.Ctx) (err error)
+	WebSocket(conn *websocket.Conn)
+}
+
+type ChatControllerImpl struct {
+	chatUseCase usecase.ChatUseCase
+	hub         *ChatHub
+}
+
+func NewChatController(chatUseCase usecase.ChatUseCase, hub *ChatHub) ChatController {
+	return &ChatControllerImpl{
+		chatUseCase: chatUseCase,
+		hub:         hub,
+	}
+}
+
+// kirimPesanEvent push new pesan to sender and recipient
+func (cc *ChatControllerImpl) kirimPesanEvent(pesan dto.PesanResponse) {
+	event := dto.ChatEvent{Event: ChatEventPesan, Data: pesan}
+	cc.hub.Kirim(pesan.PengirimID, event)
+	cc.hub.Kirim(pesan.PenerimaID, event)
+}
+
+// kirimReadEvent tell the other participant that their pesan has been read
+func (cc *ChatControllerImpl) kirimReadEvent(lawanID, percakapanID uint) {
+	cc.hub.Kirim(lawanID, dto.ChatEvent{Event: ChatEventRead, Data: fiber.Map{"percakapan_id": percakapanID}})
+}
+
+func (cc *ChatControllerImpl) CreatePercakapan(ctx *fiber.Ctx) (err error) {
+	// get userID from middleware
+	userIDMiddleware := ctx.Locals("userID")
+	userID, _ := strconv.Atoi(fmt.Sprintf("%v", userIDMiddleware))
+
+	// parse body request
+	data := new(dto.CreatePercakapanRequest)
+	if errParse := ctx.BodyParser(data); errParse != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "Failed to POST data",
+			Error:   []string{errParse.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	// call CreatePercakapan from chat useCase
+	c := ctx.Context()
+	responseUseCase, pesan, errUseCase := cc.chatUseCase.CreatePercakapan(c, uint(userID), *data)
+	if errUseCase.Err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "Failed to POST data",
+			Error:   []string{errUseCase.Err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(errUseCase.Code).JSON(response)
+	}
+	if pesan != nil {
+		cc.kirimPesanEvent(*pesan)
+	}
+	// success response
+	response := BaseResponse{
+		Status:  true,
+		Message: "Succeed to POST data",
+		Error:   nil,
+		Data:    responseUseCase,
+	}
+	return ctx.Status(fiber.StatusOK).JSON(response)
+}
+
+func (cc *ChatControllerImpl) GetMyPercakapan(ctx *fiber.Ctx) (err error) {
+	// get userID from middleware
+	userIDMiddleware := ctx.Locals("userID")
+	userID, _ := strconv.Atoi(fmt.Sprintf("%v", userIDMiddleware))
+
+	// get limit and page from query parameter url
+	params := new(dto.FilterPercakapan)
+	if errQuery := ctx.QueryParser(params); errQuery != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "Failed to GET data",
+			Error:   []string{errQuery.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	// call GetMyPercakapan from chat useCase
+	c := ctx.Context()
+	responseUseCase, errUseCase := cc.chatUseCase.GetMyPercakapan(c, uint(userID), *params)
+	if errUseCase.Err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "Failed to GET data",
+			Error:   []string{errUseCase.Err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(errUseCase.Code).JSON(response)
+	}
+	type listPercakapanResponse struct {
+		Data []dto.PercakapanResponse `json:"data"`
+	}
+	// success response
+	response := BaseResponse{
+		Status:  true,
+		Message: "Succeed to GET data",
+		Error:   nil,
+		Data:    listPercakapanResponse{Data: responseUseCase},
+	}
+	return ctx.Status(fiber.StatusOK).JSON(response)
+}
+
+func (cc *ChatControllerImpl) GetPesan(ctx *fiber.Ctx) (err error) {
+	// get userID from middleware
+	userIDMiddleware := ctx.Locals("userID")
+	userID, _ := strconv.Atoi(fmt.Sprintf("%v", userIDMiddleware))
+
+	// get id percakapan from url parameter
+	ID, errParam := strconv.Atoi(ctx.Params("id"))
+	if errParam != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "ID must integer > 0",
+			Error:   []string{errParam.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	// get limit and before_id from query parameter url
+	params := new(dto.FilterPesan)
+	if errQuery := ctx.QueryParser(params); errQuery != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "Failed to GET data",
+			Error:   []string{errQuery.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	// call GetPesan from chat useCase
+	c := ctx.Context()
+	responseUseCase, errUseCase := cc.chatUseCase.GetPesan(c, uint(userID), uint(ID), *params)
+	if errUseCase.Err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "Failed to GET data",
+			Error:   []string{errUseCase.Err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(errUseCase.Code).JSON(response)
+	}
+	type listPesanResponse struct {
+		Data []dto.PesanResponse `json:"data"`
+	}
+	// success response
+	response := BaseResponse{
+		Status:  true,
+		Message: "Succeed to GET data",
+		Error:   nil,
+		Data:    listPesanResponse{Data: responseUseCase},
+	}
+	return ctx.Status(fiber.StatusOK).JSON(response)
+}
+
+func (cc *ChatControllerImpl) KirimPesan(ctx *fiber.Ctx) (err error) {
+	// get userID from middleware
+	userIDMiddleware := ctx.Locals("userID")
+	userID, _ := strconv.Atoi(fmt.Sprintf("%v", userIDMiddleware))
+
+	// get id percakapan from url parameter
+	ID, errParam := strconv.Atoi(ctx.Params("id"))
+	if errParam != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "ID must integer > 0",
+			Error:   []string{errParam.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	// parse body request
+	data := new(dto.CreatePesanRequest)
+	if errParse := ctx.BodyParser(data); errParse != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "Failed to POST data",
+			Error:   []string{errParse.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+	data.PercakapanID = uint(ID)
+
+	// call KirimPesan from chat useCase
+	c := ctx.Context()
+	responseUseCase, errUseCase := cc.chatUseCase.KirimPesan(c, uint(userID), *data)
+	if errUseCase.Err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "Failed to POST data",
+			Error:   []string{errUseCase.Err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(errUseCase.Code).JSON(response)
+	}
+	cc.kirimPesanEvent(responseUseCase)
+	// success response
+	response := BaseResponse{
+		Status:  true,
+		Message: "Succeed to POST data",
+		Error:   nil,
+		Data:    responseUseCase,
+	}
+	return ctx.Status(fiber.StatusOK).JSON(response)
+}
+
+func (cc *ChatControllerImpl) MarkRead(ctx *fiber.Ctx) (err error) {
+	// get userID from middleware
+	userIDMiddleware := ctx.Locals("userID")
+	userID, _ := strconv.Atoi(fmt.Sprintf("%v", userIDMiddleware))
+
+	// get id percakapan from url parameter
+	ID, errParam := strconv.Atoi(ctx.Params("id"))
+	if errParam != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "ID must integer > 0",
+			Error:   []string{errParam.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	// call MarkRead from chat useCase
+	c := ctx.Context()
+	lawanID, errUseCase := cc.chatUseCase.MarkRead(c, uint(userID), uint(ID))
+	if errUseCase.Err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "Failed to PUT data",
+			Error:   []string{errUseCase.Err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(errUseCase.Code).JSON(response)
+	}
+	cc.kirimReadEvent(lawanID, uint(ID))
+	// success response
+	response := BaseResponse{
+		Status:  true,
+		Message: "Succeed to PUT data",
+		Error:   nil,
+		Data:    "",
+	}
+	return ctx.Status(fiber.StatusOK).JSON(response)
+}
+
+func (cc *ChatControllerImpl) CountUnread(ctx *fiber.Ctx) (err error) {
+	// get userID from middleware
+	userIDMiddleware := ctx.Locals("userID")
+	userID, _ := strconv.Atoi(fmt.Sprintf("%v", userIDMiddleware))
+
+	// call CountUnread from chat useCase
+	c := ctx.Context()
+	responseUseCase, errUseCase := cc.chatUseCase.CountUnread(c, uint(userID))
+	if errUseCase.Err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "Failed to GET data",
+			Error:   []string{errUseCase.Err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(errUseCase.Code).JSON(response)
+	}
+	// success response
+	response := BaseResponse{
+		Status:  true,
+		Message: "Succeed to GET data",
+		Error:   nil,
+		Data:    responseUseCase,
+	}
+	return ctx.Status(fiber.StatusOK).JSON(response)
+}
+
+// UpgradeWebSocket only allow websocket upgrade request, browser cannot set custom header
+// on websocket handshake so token can be sent as query parameter
+func (cc *ChatControllerImpl) UpgradeWebSocket(ctx *fiber.Ctx) (err error) {
+	if !websocket.IsWebSocketUpgrade(ctx) {
+		response := BaseResponse{
+			Status:  false,
+			Message: "Failed to GET data",
+			Error:   []string{"websocket upgrade required"},
+			Data:    nil,
+		}
+		return ctx.Status(fiber.StatusUpgradeRequired).JSON(response)
+	}
+	if ctx.Get("token") == "" && ctx.Query("token") != "" {
+		ctx.Request().Header.Set("token", ctx.Query("token"))
+	}
+	return ctx.Next()
+}
+
+// WebSocket receive pesan from client and push event to participant of percakapan
+func (cc *ChatControllerImpl) WebSocket(conn *websocket.Conn) {
+	// get userID from middleware
+	userIDMiddleware := conn.Locals("userID")
+	userID, _ := strconv.Atoi(fmt.Sprintf("%v", userIDMiddleware))
+
+	cc.hub.tambah(uint(userID), conn)
+	defer cc.hub.hapus(uint(userID), conn)
+
+	for {
+		_, message, errRead := conn.ReadMessage()
+		if errRead != nil {
+			// connection closed, client must reconnect
+			return
+		}
+		data := new(dto.CreatePesanRequest)
+		if errParse := json.Unmarshal(message, data); errParse != nil {
+			cc.hub.Kirim(uint(userID), dto.ChatEvent{Event: ChatEventError, Error: errParse.Error()})
+			continue
+		}
+
+		// call KirimPesan from chat useCase
+		responseUseCase, errUseCase := cc.chatUseCase.KirimPesan(context.Background(), uint(userID), *data)
+		if errUseCase.Err != nil {
+			cc.hub.Kirim(uint(userID), dto.ChatEvent{Event: ChatEventError, Error: errUseCase.Err.Error()})
+			continue
+		}
+		cc.kirimPesanEvent(responseUseCase)
+	}
+}
@@ -0,0 +1,325 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"github.com/gofiber/fiber/v2"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/helper"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/pkg/dto"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/pkg/usecase"
+	"strconv"
+)
+
+type DiskusiController interface {
+	GetDiskusiProduk(ctx *fiber.Ctx) (err error)
+	CreatePertanyaan(ctx *fiber.Ctx) (err error)
+	CreateJawaban(ctx *fiber.Ctx) (err error)
+	VoteMembantu(ctx *fiber.Ctx) (err error)
+	HapusVoteMembantu(ctx *fiber.Ctx) (err error)
+	LaporkanPertanyaan(ctx *fiber.Ctx) (err error)
+	LaporkanJawaban(ctx *fiber.Ctx) (err error)
+	GetAntrianModerasi(ctx *fiber.Ctx) (err error)
+	ModerasiDiskusi(ctx *fiber.Ctx) (err error)
+}
+
+type DiskusiControllerImpl struct {
+	diskusiUseCase usecase.DiskusiUseCase
+}
+
+func NewDiskusiController(diskusiUseCase usecase.DiskusiUseCase) DiskusiController {
+	return &DiskusiControllerImpl{diskusiUseCase: diskusiUseCase}
+}
+
+func (dc *DiskusiControllerImpl) GetDiskusiProduk(ctx *fiber.Ctx) (err error) {
+	// get id produk from url parameter
+	ID, err := strconv.Atoi(ctx.Params("id"))
+	if err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "ID must integer > 0",
+			Error:   []string{err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	// parse query params
+	var params dto.FilterDiskusi
+	if err := ctx.QueryParser(&params); err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "Failed to GET data",
+			Error:   []string{err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	// logged in user (optional), answer voted helpful by the user is marked
+	penggunaID, _ := strconv.Atoi(fmt.Sprintf("%v", ctx.Locals("userID")))
+
+	// call GetDiskusiProduk from diskusi useCase
+	c := ctx.Context()
+	responseUseCase, errUseCase := dc.diskusiUseCase.GetDiskusiProduk(c, uint(ID), uint(penggunaID), params)
+	if errUseCase.Err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "Failed to GET data",
+			Error:   []string{errUseCase.Err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(errUseCase.Code).JSON(response)
+	}
+	// success response
+	response := BaseResponse{
+		Status:  true,
+		Message: "Succeed to GET data",
+		Error:   nil,
+		Data:    responseUseCase,
+	}
+	return ctx.Status(fiber.StatusOK).JSON(response)
+}
+
+func (dc *DiskusiControllerImpl) CreatePertanyaan(ctx *fiber.Ctx) (err error) {
+	// get userID from middleware
+	userID, _ := strconv.Atoi(fmt.Sprintf("%v", ctx.Locals("userID")))
+
+	// get id produk from url parameter
+	ID, err := strconv.Atoi(ctx.Params("id"))
+	if err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "ID must integer > 0",
+			Error:   []string{err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	// parse body request
+	data := new(dto.DiskusiRequest)
+	if errParse := ctx.BodyParser(data); errParse != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "Failed to POST data",
+			Error:   []string{errParse.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+	data.ProdukID = uint(ID)
+	data.UserID = uint(userID)
+
+	// call CreatePertanyaan from diskusi useCase
+	c := ctx.Context()
+	responseUseCase, errUseCase := dc.diskusiUseCase.CreatePertanyaan(c, *data)
+	if errUseCase.Err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "Failed to POST data",
+			Error:   []string{errUseCase.Err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(errUseCase.Code).JSON(response)
+	}
+	// success response
+	response := BaseResponse{
+		Status:  true,
+		Message: "Succeed to POST data",
+		Error:   nil,
+		Data:    responseUseCase,
+	}
+	return ctx.Status(fiber.StatusOK).JSON(response)
+}
+
+func (dc *DiskusiControllerImpl) CreateJawaban(ctx *fiber.Ctx) (err error) {
+	// get userID from middleware
+	userID, _ := strconv.Atoi(fmt.Sprintf("%v", ctx.Locals("userID")))
+
+	// get id question from url parameter
+	ID, err := strconv.Atoi(ctx.Params("id"))
+	if err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "ID must integer > 0",
+			Error:   []string{err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	// parse body request
+	data := new(dto.DiskusiRequest)
+	if errParse := ctx.BodyParser(data); errParse != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "Failed to POST data",
+			Error:   []string{errParse.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+	data.PertanyaanID = uint(ID)
+	data.UserID = uint(userID)
+
+	// call CreateJawaban from diskusi useCase
+	c := ctx.Context()
+	responseUseCase, errUseCase := dc.diskusiUseCase.CreateJawaban(c, *data)
+	if errUseCase.Err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "Failed to POST data",
+			Error:   []string{errUseCase.Err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(errUseCase.Code).JSON(response)
+	}
+	// success response
+	response := BaseResponse{
+		Status:  true,
+		Message: "Succeed to POST data",
+		Error:   nil,
+		Data:    responseUseCase,
+	}
+	return ctx.Status(fiber.StatusOK).JSON(response)
+}
+
+// tanggapanDiskusi run vote or report of logged in user on question or answer in url parameter
+func tanggapanDiskusi(ctx *fiber.Ctx, method string, aksi func(c context.Context, userID, ID uint) *helper.ErrorStruct) (err error) {
+	// get userID from middleware
+	userID, _ := strconv.Atoi(fmt.Sprintf("%v", ctx.Locals("userID")))
+
+	// get id from url parameter
+	ID, err := strconv.Atoi(ctx.Params("id"))
+	if err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "ID must integer > 0",
+			Error:   []string{err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	errUseCase := aksi(ctx.Context(), uint(userID), uint(ID))
+	if errUseCase.Err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: fmt.Sprintf("Failed to %s data", method),
+			Error:   []string{errUseCase.Err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(errUseCase.Code).JSON(response)
+	}
+	// success response
+	response := BaseResponse{
+		Status:  true,
+		Message: fmt.Sprintf("Succeed to %s data", method),
+		Error:   nil,
+		Data:    "",
+	}
+	return ctx.Status(fiber.StatusOK).JSON(response)
+}
+
+func (dc *DiskusiControllerImpl) VoteMembantu(ctx *fiber.Ctx) (err error) {
+	return tanggapanDiskusi(ctx, "POST", dc.diskusiUseCase.VoteMembantu)
+}
+
+func (dc *DiskusiControllerImpl) HapusVoteMembantu(ctx *fiber.Ctx) (err error) {
+	return tanggapanDiskusi(ctx, "DELETE", dc.diskusiUseCase.HapusVoteMembantu)
+}
+
+func (dc *DiskusiControllerImpl) LaporkanPertanyaan(ctx *fiber.Ctx) (err error) {
+	return tanggapanDiskusi(ctx, "POST", func(c context.Context, userID, ID uint) *helper.ErrorStruct {
+		return dc.diskusiUseCase.LaporkanDiskusi(c, userID, ID, false)
+	})
+}
+
+func (dc *DiskusiControllerImpl) LaporkanJawaban(ctx *fiber.Ctx) (err error) {
+	return tanggapanDiskusi(ctx, "POST", func(c context.Context, userID, ID uint) *helper.ErrorStruct {
+		return dc.diskusiUseCase.LaporkanDiskusi(c, userID, ID, true)
+	})
+}
+
+func (dc *DiskusiControllerImpl) GetAntrianModerasi(ctx *fiber.Ctx) (err error) {
+	// parse query params
+	var params dto.FilterModerasiDiskusi
+	if err := ctx.QueryParser(&params); err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "Failed to GET data",
+			Error:   []string{err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	// call GetAntrianModerasi from diskusi useCase
+	c := ctx.Context()
+	responseUseCase, errUseCase := dc.diskusiUseCase.GetAntrianModerasi(c, params)
+	if errUseCase.Err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "Failed to GET data",
+			Error:   []string{errUseCase.Err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(errUseCase.Code).JSON(response)
+	}
+	// success response
+	response := BaseResponse{
+		Status:  true,
+		Message: "Succeed to GET data",
+		Error:   nil,
+		Data:    responseUseCase,
+	}
+	return ctx.Status(fiber.StatusOK).JSON(response)
+}
+
+func (dc *DiskusiControllerImpl) ModerasiDiskusi(ctx *fiber.Ctx) (err error) {
+	// get id question or answer from url parameter
+	ID, err := strconv.Atoi(ctx.Params("id"))
+	if err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "ID must integer > 0",
+			Error:   []string{err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	// parse body request
+	data := new(dto.ModerasiDiskusiRequest)
+	if errParse := ctx.BodyParser(data); errParse != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "Failed to PUT data",
+			Error:   []string{errParse.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+	data.DiskusiID = uint(ID)
+
+	// call ModerasiDiskusi from diskusi useCase
+	c := ctx.Context()
+	errUseCase := dc.diskusiUseCase.ModerasiDiskusi(c, *data)
+	if errUseCase.Err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "Failed to PUT data",
+			Error:   []string{errUseCase.Err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(errUseCase.Code).JSON(response)
+	}
+	// success response
+	response := BaseResponse{
+		Status:  true,
+		Message: "Succeed to PUT data",
+		Error:   nil,
+		Data:    "",
+	}
+	return ctx.Status(fiber.StatusOK).JSON(response)
+}
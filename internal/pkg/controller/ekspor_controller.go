@@ -0,0 +1,99 @@
+package controller
+
+import (
+	"bufio"
+	"fmt"
+	"github.com/gofiber/fiber/v2"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/helper"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/pkg/dto"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/pkg/usecase"
+	"strconv"
+)
+
+type EksporController interface {
+	EksporProduk(ctx *fiber.Ctx) (err error)
+	EksporSemuaProduk(ctx *fiber.Ctx) (err error)
+}
+
+type EksporControllerImpl struct {
+	eksporUseCase usecase.EksporUseCase
+}
+
+func NewEksporController(eksporUseCase usecase.EksporUseCase) EksporController {
+	return &EksporControllerImpl{eksporUseCase: eksporUseCase}
+}
+
+// kirimEkspor stream the file, error after streaming started can only be logged because status is already sent
+func kirimEkspor(ctx *fiber.Ctx, file dto.FileEkspor) error {
+	ctx.Attachment(file.NamaFile)
+	ctx.Set(fiber.HeaderContentType, file.ContentType)
+	ctx.Status(fiber.StatusOK).Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		if err := file.Tulis(w); err != nil {
+			helper.Logger("ekspor_controller.go", helper.LoggerLevelError, fmt.Sprintf("failed to write %s : %s", file.NamaFile, err.Error()))
+		}
+		w.Flush()
+	})
+	return nil
+}
+
+func (ec *EksporControllerImpl) EksporProduk(ctx *fiber.Ctx) (err error) {
+	// get tokoID (tokoID is the same as userID) from middleware
+	tokoIDMiddleware := ctx.Locals("userID")
+	tokoID, _ := strconv.Atoi(fmt.Sprintf("%v", tokoIDMiddleware))
+
+	// parse query params
+	var params dto.FilterEkspor
+	if err := ctx.QueryParser(&params); err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "Failed to GET data",
+			Error:   []string{err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	// call EksporProduk from ekspor useCase
+	c := ctx.Context()
+	responseUseCase, errUseCase := ec.eksporUseCase.EksporProduk(c, uint(tokoID), params)
+	if errUseCase.Err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "Failed to GET data",
+			Error:   []string{errUseCase.Err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(errUseCase.Code).JSON(response)
+	}
+	// success response
+	return kirimEkspor(ctx, responseUseCase)
+}
+
+func (ec *EksporControllerImpl) EksporSemuaProduk(ctx *fiber.Ctx) (err error) {
+	// parse query params
+	var params dto.FilterEkspor
+	if err := ctx.QueryParser(&params); err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "Failed to GET data",
+			Error:   []string{err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	// call EksporSemuaProduk from ekspor useCase
+	c := ctx.Context()
+	responseUseCase, errUseCase := ec.eksporUseCase.EksporSemuaProduk(c, params)
+	if errUseCase.Err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "Failed to GET data",
+			Error:   []string{errUseCase.Err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(errUseCase.Code).JSON(response)
+	}
+	// success response
+	return kirimEkspor(ctx, responseUseCase)
+}
@@ -0,0 +1,59 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"github.com/gofiber/fiber/v2"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/infrastructure/gambar"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/infrastructure/storage"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/pkg/dto"
+	"mime/multipart"
+)
+
+// prefix of object key in blob storage
+const (
+	PrefixFotoProduk = "produk"
+	PrefixLogoToko   = "toko"
+)
+
+// simpanGambar run every uploaded image through image pipeline then save its rendition to blob storage with content addressed key.
+// All image are validated before anything is saved so rejected upload does not leave object behind.
+func simpanGambar(ctx context.Context, gambarPipeline *gambar.Pipeline, blobStorage storage.BlobStorage, files []*multipart.FileHeader, prefix string) (photos []dto.Photos, code int, err error) {
+	var listRendisi [][]gambar.Rendisi
+	for _, file := range files {
+		if file == nil {
+			continue
+		}
+		src, errOpen := file.Open()
+		if errOpen != nil {
+			return nil, fiber.StatusBadRequest, errOpen
+		}
+		rendisi, errProses := gambarPipeline.Proses(src)
+		src.Close()
+		if errProses != nil {
+			return nil, fiber.StatusBadRequest, fmt.Errorf("%s: %w", file.Filename, errProses)
+		}
+		listRendisi = append(listRendisi, rendisi)
+	}
+
+	for _, rendisi := range listRendisi {
+		var photo dto.Photos
+		for _, v := range rendisi {
+			kunci := storage.KunciKonten(prefix, v.Data, gambar.Ekstensi)
+			if errSimpan := blobStorage.Simpan(ctx, kunci, v.Data, gambar.ContentType); errSimpan != nil {
+				return nil, fiber.StatusInternalServerError, errSimpan
+			}
+			switch v.Nama {
+			case gambar.RendisiThumbnail:
+				photo.URLThumbnail = kunci
+			case gambar.RendisiMedium:
+				photo.URLMedium = kunci
+			case gambar.RendisiLarge:
+				photo.URLLarge = kunci
+				photo.URL = kunci
+			}
+		}
+		photos = append(photos, photo)
+	}
+	return photos, fiber.StatusOK, nil
+}
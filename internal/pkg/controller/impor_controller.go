@@ -0,0 +1,263 @@
+package controller
+
+import (
+	"fmt"
+	"github.com/gofiber/fiber/v2"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/pkg/dto"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/pkg/usecase"
+	"io"
+	"mime/multipart"
+	"strconv"
+)
+
+type ImporController interface {
+	ImporProduk(ctx *fiber.Ctx) (err error)
+	GetAllImpor(ctx *fiber.Ctx) (err error)
+	GetImporByID(ctx *fiber.Ctx) (err error)
+	GetBarisImporGagal(ctx *fiber.Ctx) (err error)
+	GetLaporanImpor(ctx *fiber.Ctx) (err error)
+}
+
+type ImporControllerImpl struct {
+	imporUseCase usecase.ImporUseCase
+}
+
+func NewImporController(imporUseCase usecase.ImporUseCase) ImporController {
+	return &ImporControllerImpl{imporUseCase: imporUseCase}
+}
+
+// bacaFileForm whole content of uploaded file, nil when the field is not sent
+func bacaFileForm(file *multipart.FileHeader) (data []byte, err error) {
+	if file == nil {
+		return nil, nil
+	}
+	src, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer src.Close()
+	return io.ReadAll(src)
+}
+
+func (ic *ImporControllerImpl) ImporProduk(ctx *fiber.Ctx) (err error) {
+	// get tokoID (tokoID is the same as userID) from middleware
+	tokoIDMiddleware := ctx.Locals("userID")
+	tokoID, _ := strconv.Atoi(fmt.Sprintf("%v", tokoIDMiddleware))
+
+	// get csv or xlsx file and optional zip of foto from form-data
+	file, err := ctx.FormFile("file")
+	if err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "file is required",
+			Error:   []string{err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+	data, err := bacaFileForm(file)
+	var zip []byte
+	if err == nil {
+		fileZip, _ := ctx.FormFile("foto_zip")
+		zip, err = bacaFileForm(fileZip)
+	}
+	if err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "Failed to POST data",
+			Error:   []string{err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	// call ImporProduk from impor useCase, rows are processed in background
+	c := ctx.Context()
+	responseUseCase, errUseCase := ic.imporUseCase.ImporProduk(c, dto.ImporProdukRequest{
+		TokoID:   uint(tokoID),
+		NamaFile: file.Filename,
+		Data:     data,
+		Zip:      zip,
+	})
+	if errUseCase.Err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "Failed to POST data",
+			Error:   []string{errUseCase.Err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(errUseCase.Code).JSON(response)
+	}
+	// success response, the job is accepted
+	response := BaseResponse{
+		Status:  true,
+		Message: "Succeed to POST data",
+		Error:   nil,
+		Data:    responseUseCase,
+	}
+	return ctx.Status(fiber.StatusAccepted).JSON(response)
+}
+
+func (ic *ImporControllerImpl) GetAllImpor(ctx *fiber.Ctx) (err error) {
+	// get tokoID (tokoID is the same as userID) from middleware
+	tokoIDMiddleware := ctx.Locals("userID")
+	tokoID, _ := strconv.Atoi(fmt.Sprintf("%v", tokoIDMiddleware))
+
+	// parse query params
+	var params dto.FilterImpor
+	if err := ctx.QueryParser(&params); err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "Failed to GET data",
+			Error:   []string{err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	// call GetAllImpor from impor useCase
+	c := ctx.Context()
+	responseUseCase, errUseCase := ic.imporUseCase.GetAllImpor(c, uint(tokoID), params)
+	if errUseCase.Err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "Failed to GET data",
+			Error:   []string{errUseCase.Err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(errUseCase.Code).JSON(response)
+	}
+	// success response
+	response := BaseResponse{
+		Status:  true,
+		Message: "Succeed to GET data",
+		Error:   nil,
+		Data:    responseUseCase,
+	}
+	return ctx.Status(fiber.StatusOK).JSON(response)
+}
+
+func (ic *ImporControllerImpl) GetImporByID(ctx *fiber.Ctx) (err error) {
+	// get tokoID (tokoID is the same as userID) from middleware
+	tokoIDMiddleware := ctx.Locals("userID")
+	tokoID, _ := strconv.Atoi(fmt.Sprintf("%v", tokoIDMiddleware))
+
+	// get id from url parameter
+	ID, err := strconv.Atoi(ctx.Params("id"))
+	if err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "ID must integer > 0",
+			Error:   []string{err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	// call GetImporByID from impor useCase
+	c := ctx.Context()
+	responseUseCase, errUseCase := ic.imporUseCase.GetImporByID(c, uint(tokoID), uint(ID))
+	if errUseCase.Err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "Failed to GET data",
+			Error:   []string{errUseCase.Err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(errUseCase.Code).JSON(response)
+	}
+	// success response
+	response := BaseResponse{
+		Status:  true,
+		Message: "Succeed to GET data",
+		Error:   nil,
+		Data:    responseUseCase,
+	}
+	return ctx.Status(fiber.StatusOK).JSON(response)
+}
+
+func (ic *ImporControllerImpl) GetBarisImporGagal(ctx *fiber.Ctx) (err error) {
+	// get tokoID (tokoID is the same as userID) from middleware
+	tokoIDMiddleware := ctx.Locals("userID")
+	tokoID, _ := strconv.Atoi(fmt.Sprintf("%v", tokoIDMiddleware))
+
+	// get id from url parameter
+	ID, err := strconv.Atoi(ctx.Params("id"))
+	if err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "ID must integer > 0",
+			Error:   []string{err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	// parse query params
+	var params dto.FilterImpor
+	if err := ctx.QueryParser(&params); err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "Failed to GET data",
+			Error:   []string{err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	// call GetBarisImporGagal from impor useCase
+	c := ctx.Context()
+	responseUseCase, errUseCase := ic.imporUseCase.GetBarisImporGagal(c, uint(tokoID), uint(ID), params)
+	if errUseCase.Err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "Failed to GET data",
+			Error:   []string{errUseCase.Err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(errUseCase.Code).JSON(response)
+	}
+	// success response
+	response := BaseResponse{
+		Status:  true,
+		Message: "Succeed to GET data",
+		Error:   nil,
+		Data:    responseUseCase,
+	}
+	return ctx.Status(fiber.StatusOK).JSON(response)
+}
+
+// GetLaporanImpor download rejected row as csv
+func (ic *ImporControllerImpl) GetLaporanImpor(ctx *fiber.Ctx) (err error) {
+	// get tokoID (tokoID is the same as userID) from middleware
+	tokoIDMiddleware := ctx.Locals("userID")
+	tokoID, _ := strconv.Atoi(fmt.Sprintf("%v", tokoIDMiddleware))
+
+	// get id from url parameter
+	ID, err := strconv.Atoi(ctx.Params("id"))
+	if err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "ID must integer > 0",
+			Error:   []string{err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	// call GetLaporanImpor from impor useCase
+	c := ctx.Context()
+	namaFile, data, errUseCase := ic.imporUseCase.GetLaporanImpor(c, uint(tokoID), uint(ID))
+	if errUseCase.Err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "Failed to GET data",
+			Error:   []string{errUseCase.Err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(errUseCase.Code).JSON(response)
+	}
+	// success response
+	ctx.Attachment(namaFile)
+	return ctx.Status(fiber.StatusOK).Send(data)
+}
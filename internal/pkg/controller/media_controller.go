@@ -0,0 +1,204 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"github.com/gofiber/fiber/v2"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/infrastructure/storage"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type MediaController interface {
+	GetMedia(ctx *fiber.Ctx) (err error)
+}
+
+type MediaControllerImpl struct {
+	mediaStorage *storage.MediaStorage
+}
+
+func NewMediaController(mediaStorage *storage.MediaStorage) MediaController {
+	return &MediaControllerImpl{mediaStorage: mediaStorage}
+}
+
+// GetMedia serve object of blob storage with validator, cache and range header
+func (mc *MediaControllerImpl) GetMedia(ctx *fiber.Ctx) (err error) {
+	kunci := ctx.Params("*")
+
+	// private media need valid signed url
+	cacheControl := "public, max-age=86400"
+	if storage.AdalahKunci(kunci) {
+		// content addressed object never change
+		cacheControl = "public, max-age=31536000, immutable"
+	}
+	if mc.mediaStorage.Bertanda() {
+		kadaluarsa, errVerifikasi := mc.mediaStorage.Verifikasi(kunci, ctx.Query("expires"), ctx.Query("signature"))
+		if errVerifikasi != nil {
+			response := BaseResponse{
+				Status:  false,
+				Message: "Failed to GET data",
+				Error:   []string{errVerifikasi.Error()},
+				Data:    nil,
+			}
+			return ctx.Status(fiber.StatusForbidden).JSON(response)
+		}
+		cacheControl = fmt.Sprintf("private, max-age=%d", int(time.Until(kadaluarsa).Seconds()))
+	}
+
+	// object is looked up before any conditional header, missing key is never answered with 304
+	isi, info, errAmbil := mc.mediaStorage.Ambil(ctx.Context(), kunci)
+	if errAmbil != nil {
+		code := fiber.StatusInternalServerError
+		if errors.Is(errAmbil, storage.ErrTidakAda) {
+			code = fiber.StatusNotFound
+		}
+		response := BaseResponse{
+			Status:  false,
+			Message: "Failed to GET data",
+			Error:   []string{errAmbil.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(code).JSON(response)
+	}
+
+	// etag of content addressed object is known without reading it, other object use weak etag of its size and modification time
+	etag := storage.ETag(kunci)
+	if etag == "" {
+		etag = fmt.Sprintf(`W/"%x-%x"`, info.Ukuran, info.DiubahPada.UnixNano())
+	}
+
+	ctx.Set(fiber.HeaderContentType, info.ContentType)
+	ctx.Set(fiber.HeaderETag, etag)
+	ctx.Set(fiber.HeaderCacheControl, cacheControl)
+	ctx.Set(fiber.HeaderAcceptRanges, "bytes")
+	ctx.Set(fiber.HeaderXContentTypeOptions, "nosniff")
+	if !info.DiubahPada.IsZero() {
+		ctx.Set(fiber.HeaderLastModified, info.DiubahPada.UTC().Format(http.TimeFormat))
+	}
+
+	// conditional request, if-none-match take precedence over if-modified-since
+	if ifNoneMatch := ctx.Get(fiber.HeaderIfNoneMatch); ifNoneMatch != "" {
+		if cocokETag(ifNoneMatch, etag) {
+			isi.Close()
+			return ctx.SendStatus(fiber.StatusNotModified)
+		}
+	} else if since, errWaktu := http.ParseTime(ctx.Get(fiber.HeaderIfModifiedSince)); errWaktu == nil && !info.DiubahPada.IsZero() {
+		if !info.DiubahPada.Truncate(time.Second).After(since) {
+			isi.Close()
+			return ctx.SendStatus(fiber.StatusNotModified)
+		}
+	}
+
+	// range is ignored when if-range does not match current object or size of object is unknown,
+	// weak etag never match if-range
+	rentang := ctx.Get(fiber.HeaderRange)
+	if ifRange := ctx.Get(fiber.HeaderIfRange); ifRange != "" && (ifRange != etag || strings.HasPrefix(etag, "W/")) {
+		rentang = ""
+	}
+	if rentang != "" && info.Ukuran > 0 {
+		awal, akhir, ok, errRentang := rentangByte(rentang, info.Ukuran)
+		if errRentang != nil {
+			isi.Close()
+			ctx.Set(fiber.HeaderContentRange, fmt.Sprintf("bytes */%d", info.Ukuran))
+			ctx.Set(fiber.HeaderContentType, fiber.MIMETextPlainCharsetUTF8)
+			return ctx.SendStatus(fiber.StatusRequestedRangeNotSatisfiable)
+		}
+		if ok {
+			if errLewati := lewatiByte(isi, awal); errLewati != nil {
+				isi.Close()
+				response := BaseResponse{
+					Status:  false,
+					Message: "Failed to GET data",
+					Error:   []string{errLewati.Error()},
+					Data:    nil,
+				}
+				return ctx.Status(fiber.StatusInternalServerError).JSON(response)
+			}
+			panjang := akhir - awal + 1
+			ctx.Set(fiber.HeaderContentRange, fmt.Sprintf("bytes %d-%d/%d", awal, akhir, info.Ukuran))
+			// stream is closed by fiber after the response is sent
+			return ctx.Status(fiber.StatusPartialContent).SendStream(bacaTutup{io.LimitReader(isi, panjang), isi}, int(panjang))
+		}
+	}
+	ukuran := -1
+	if info.Ukuran > 0 {
+		ukuran = int(info.Ukuran)
+	}
+	return ctx.Status(fiber.StatusOK).SendStream(isi, ukuran)
+}
+
+// bacaTutup reader of part of object that still close the whole object
+type bacaTutup struct {
+	io.Reader
+	io.Closer
+}
+
+// lewatiByte move stream to offset n, seek when possible and discard otherwise
+func lewatiByte(isi io.Reader, n int64) error {
+	if n == 0 {
+		return nil
+	}
+	if seeker, ok := isi.(io.Seeker); ok {
+		_, err := seeker.Seek(n, io.SeekStart)
+		return err
+	}
+	_, err := io.CopyN(io.Discard, isi, n)
+	return err
+}
+
+// cocokETag report whether If-None-Match header contain etag
+func cocokETag(header, etag string) bool {
+	for _, v := range strings.Split(header, ",") {
+		v = strings.TrimPrefix(strings.TrimSpace(v), "W/")
+		if v == "*" || v == etag {
+			return true
+		}
+	}
+	return false
+}
+
+var errRentangInvalid = errors.New("range not satisfiable")
+
+// rentangByte parse single byte range of Range header, ok is false when header should be ignored (multiple range or other unit)
+func rentangByte(header string, ukuran int64) (awal, akhir int64, ok bool, err error) {
+	if !strings.HasPrefix(header, "bytes=") || strings.Contains(header, ",") {
+		return 0, 0, false, nil
+	}
+	bagian := strings.SplitN(strings.TrimPrefix(header, "bytes="), "-", 2)
+	if len(bagian) != 2 {
+		return 0, 0, false, errRentangInvalid
+	}
+	mulai, selesai := strings.TrimSpace(bagian[0]), strings.TrimSpace(bagian[1])
+	switch {
+	case mulai == "":
+		// suffix range, last n byte
+		n, errParse := strconv.ParseInt(selesai, 10, 64)
+		if errParse != nil || n <= 0 || ukuran == 0 {
+			return 0, 0, false, errRentangInvalid
+		}
+		if n > ukuran {
+			n = ukuran
+		}
+		return ukuran - n, ukuran - 1, true, nil
+	default:
+		var errParse error
+		awal, errParse = strconv.ParseInt(mulai, 10, 64)
+		if errParse != nil || awal < 0 || awal >= ukuran {
+			return 0, 0, false, errRentangInvalid
+		}
+		akhir = ukuran - 1
+		if selesai != "" {
+			akhir, errParse = strconv.ParseInt(selesai, 10, 64)
+			if errParse != nil || akhir < awal {
+				return 0, 0, false, errRentangInvalid
+			}
+			if akhir >= ukuran {
+				akhir = ukuran - 1
+			}
+		}
+		return awal, akhir, true, nil
+	}
+}
@@ -0,0 +1,194 @@
+package controller
+
+import (
+	"fmt"
+	"github.com/gofiber/fiber/v2"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/pkg/dto"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/pkg/usecase"
+	"strconv"
+)
+
+type ModerasiController interface {
+	GetAntrianModerasi(ctx *fiber.Ctx) (err error)
+	ModerasiProduk(ctx *fiber.Ctx) (err error)
+	GetRiwayatModerasi(ctx *fiber.Ctx) (err error)
+	SetTokoTerpercaya(ctx *fiber.Ctx) (err error)
+}
+
+type ModerasiControllerImpl struct {
+	moderasiUseCase usecase.ModerasiUseCase
+}
+
+func NewModerasiController(moderasiUseCase usecase.ModerasiUseCase) ModerasiController {
+	return &ModerasiControllerImpl{moderasiUseCase: moderasiUseCase}
+}
+
+func (mc *ModerasiControllerImpl) GetAntrianModerasi(ctx *fiber.Ctx) (err error) {
+	// parse query params
+	var params dto.FilterModerasi
+	if err := ctx.QueryParser(&params); err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "Failed to GET data",
+			Error:   []string{err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	// call GetAntrianModerasi from moderasi useCase
+	c := ctx.Context()
+	responseUseCase, errUseCase := mc.moderasiUseCase.GetAntrianModerasi(c, params)
+	if errUseCase.Err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "Failed to GET data",
+			Error:   []string{errUseCase.Err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(errUseCase.Code).JSON(response)
+	}
+	// success response
+	response := BaseResponse{
+		Status:  true,
+		Message: "Succeed to GET data",
+		Error:   nil,
+		Data:    responseUseCase,
+	}
+	return ctx.Status(fiber.StatusOK).JSON(response)
+}
+
+func (mc *ModerasiControllerImpl) ModerasiProduk(ctx *fiber.Ctx) (err error) {
+	// get adminID from middleware
+	adminIDMiddleware := ctx.Locals("userID")
+	adminID, _ := strconv.Atoi(fmt.Sprintf("%v", adminIDMiddleware))
+
+	// get id from url parameter
+	ID, err := strconv.Atoi(ctx.Params("id"))
+	if err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "ID must integer > 0",
+			Error:   []string{err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	// parse body request
+	data := new(dto.ModerasiRequest)
+	if errParse := ctx.BodyParser(data); errParse != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "Failed to PUT data",
+			Error:   []string{errParse.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+	data.ProdukID = uint(ID)
+	data.AdminID = uint(adminID)
+
+	// call ModerasiProduk from moderasi useCase
+	c := ctx.Context()
+	errUseCase := mc.moderasiUseCase.ModerasiProduk(c, *data)
+	if errUseCase.Err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "Failed to PUT data",
+			Error:   []string{errUseCase.Err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(errUseCase.Code).JSON(response)
+	}
+	// success response
+	response := BaseResponse{
+		Status:  true,
+		Message: "Succeed to PUT data",
+		Error:   nil,
+		Data:    "",
+	}
+	return ctx.Status(fiber.StatusOK).JSON(response)
+}
+
+func (mc *ModerasiControllerImpl) GetRiwayatModerasi(ctx *fiber.Ctx) (err error) {
+	// get id from url parameter
+	ID, err := strconv.Atoi(ctx.Params("id"))
+	if err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "ID must integer > 0",
+			Error:   []string{err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	// call GetRiwayatModerasi from moderasi useCase
+	c := ctx.Context()
+	responseUseCase, errUseCase := mc.moderasiUseCase.GetRiwayatModerasi(c, uint(ID))
+	if errUseCase.Err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "Failed to GET data",
+			Error:   []string{errUseCase.Err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(errUseCase.Code).JSON(response)
+	}
+	// success response
+	response := BaseResponse{
+		Status:  true,
+		Message: "Succeed to GET data",
+		Error:   nil,
+		Data:    responseUseCase,
+	}
+	return ctx.Status(fiber.StatusOK).JSON(response)
+}
+
+func (mc *ModerasiControllerImpl) SetTokoTerpercaya(ctx *fiber.Ctx) (err error) {
+	// get id toko from url parameter
+	tokoID, err := strconv.Atoi(ctx.Params("id_toko"))
+	if err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "ID must integer > 0",
+			Error:   []string{err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	// parse body request
+	data := new(dto.TokoTerpercayaRequest)
+	if errParse := ctx.BodyParser(data); errParse != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "Failed to PUT data",
+			Error:   []string{errParse.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	// call SetTokoTerpercaya from moderasi useCase
+	c := ctx.Context()
+	errUseCase := mc.moderasiUseCase.SetTokoTerpercaya(c, uint(tokoID), *data)
+	if errUseCase.Err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "Failed to PUT data",
+			Error:   []string{errUseCase.Err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(errUseCase.Code).JSON(response)
+	}
+	// success response
+	response := BaseResponse{
+		Status:  true,
+		Message: "Succeed to PUT data",
+		Error:   nil,
+		Data:    "",
+	}
+	return ctx.Status(fiber.StatusOK).JSON(response)
+}
@@ -0,0 +1,157 @@
+package controller
+
+import (
+	"fmt"
+	"github.com/gofiber/fiber/v2"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/pkg/dto"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/pkg/usecase"
+	"strconv"
+)
+
+type NotifikasiController interface {
+	GetMyNotifikasi(ctx *fiber.Ctx) (err error)
+	CountUnread(ctx *fiber.Ctx) (err error)
+	MarkRead(ctx *fiber.Ctx) (err error)
+	MarkAllRead(ctx *fiber.Ctx) (err error)
+}
+
+type NotifikasiControllerImpl struct {
+	notifikasiUseCase usecase.NotifikasiUseCase
+}
+
+func NewNotifikasiController(notifikasiUseCase usecase.NotifikasiUseCase) NotifikasiController {
+	return &NotifikasiControllerImpl{notifikasiUseCase: notifikasiUseCase}
+}
+
+func (nc *NotifikasiControllerImpl) GetMyNotifikasi(ctx *fiber.Ctx) (err error) {
+	// get userID from middleware
+	userIDMiddleware := ctx.Locals("userID")
+	userID, _ := strconv.Atoi(fmt.Sprintf("%v", userIDMiddleware))
+
+	// get limit, page and filter from query parameter url
+	params := new(dto.FilterNotifikasi)
+	if errQuery := ctx.QueryParser(params); errQuery != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "Failed to GET data",
+			Error:   []string{errQuery.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	// call GetMyNotifikasi from notifikasi useCase
+	c := ctx.Context()
+	responseUseCase, errUseCase := nc.notifikasiUseCase.GetMyNotifikasi(c, uint(userID), *params)
+	if errUseCase.Err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "Failed to GET data",
+			Error:   []string{errUseCase.Err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(errUseCase.Code).JSON(response)
+	}
+	type listNotifikasiResponse struct {
+		Data []dto.NotifikasiResponse `json:"data"`
+	}
+	// success response
+	response := BaseResponse{
+		Status:  true,
+		Message: "Succeed to GET data",
+		Error:   nil,
+		Data:    listNotifikasiResponse{Data: responseUseCase},
+	}
+	return ctx.Status(fiber.StatusOK).JSON(response)
+}
+
+func (nc *NotifikasiControllerImpl) CountUnread(ctx *fiber.Ctx) (err error) {
+	// get userID from middleware
+	userIDMiddleware := ctx.Locals("userID")
+	userID, _ := strconv.Atoi(fmt.Sprintf("%v", userIDMiddleware))
+
+	// call CountUnread from notifikasi useCase
+	c := ctx.Context()
+	responseUseCase, errUseCase := nc.notifikasiUseCase.CountUnread(c, uint(userID))
+	if errUseCase.Err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "Failed to GET data",
+			Error:   []string{errUseCase.Err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(errUseCase.Code).JSON(response)
+	}
+	// success response
+	response := BaseResponse{
+		Status:  true,
+		Message: "Succeed to GET data",
+		Error:   nil,
+		Data:    responseUseCase,
+	}
+	return ctx.Status(fiber.StatusOK).JSON(response)
+}
+
+func (nc *NotifikasiControllerImpl) MarkRead(ctx *fiber.Ctx) (err error) {
+	// get userID from middleware
+	userIDMiddleware := ctx.Locals("userID")
+	userID, _ := strconv.Atoi(fmt.Sprintf("%v", userIDMiddleware))
+
+	// get id notifikasi from url parameter
+	ID, errParam := strconv.Atoi(ctx.Params("id"))
+	if errParam != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "ID must integer > 0",
+			Error:   []string{errParam.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	// call MarkRead from notifikasi useCase
+	c := ctx.Context()
+	if errUseCase := nc.notifikasiUseCase.MarkRead(c, uint(userID), uint(ID)); errUseCase.Err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "Failed to PUT data",
+			Error:   []string{errUseCase.Err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(errUseCase.Code).JSON(response)
+	}
+	// success response
+	response := BaseResponse{
+		Status:  true,
+		Message: "Succeed to PUT data",
+		Error:   nil,
+		Data:    "",
+	}
+	return ctx.Status(fiber.StatusOK).JSON(response)
+}
+
+func (nc *NotifikasiControllerImpl) MarkAllRead(ctx *fiber.Ctx) (err error) {
+	// get userID from middleware
+	userIDMiddleware := ctx.Locals("userID")
+	userID, _ := strconv.Atoi(fmt.Sprintf("%v", userIDMiddleware))
+
+	// call MarkAllRead from notifikasi useCase
+	c := ctx.Context()
+	if errUseCase := nc.notifikasiUseCase.MarkAllRead(c, uint(userID)); errUseCase.Err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "Failed to PUT data",
+			Error:   []string{errUseCase.Err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(errUseCase.Code).JSON(response)
+	}
+	// success response
+	response := BaseResponse{
+		Status:  true,
+		Message: "Succeed to PUT data",
+		Error:   nil,
+		Data:    "",
+	}
+	return ctx.Status(fiber.StatusOK).JSON(response)
+}
@@ -3,26 +3,55 @@ package controller
 import (
 	"fmt"
 	"github.com/gofiber/fiber/v2"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/infrastructure/gambar"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/infrastructure/storage"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/infrastructure/tayangan"
 	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/pkg/dto"
 	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/pkg/usecase"
 	"strconv"
 	"strings"
+	"time"
 )
 
 type ProdukController interface {
 	UploadProduk(ctx *fiber.Ctx) (err error)
 	GetProdukByID(ctx *fiber.Ctx) (err error)
+	GetProdukBySlug(ctx *fiber.Ctx) (err error)
 	UpdateProdukByID(ctx *fiber.Ctx) (err error)
 	DeleteProdukByID(ctx *fiber.Ctx) (err error)
 	GetAllProduk(ctx *fiber.Ctx) (err error)
+	UpdateVarian(ctx *fiber.Ctx) (err error)
+	UploadFotoSKU(ctx *fiber.Ctx) (err error)
+	GetFotoProduk(ctx *fiber.Ctx) (err error)
+	DeleteFotoProduk(ctx *fiber.Ctx) (err error)
+	UrutkanFotoProduk(ctx *fiber.Ctx) (err error)
+	SetFotoUtama(ctx *fiber.Ctx) (err error)
+	RestoreProdukByID(ctx *fiber.Ctx) (err error)
+	GetSampahProduk(ctx *fiber.Ctx) (err error)
+	GetProdukToko(ctx *fiber.Ctx) (err error)
+	UbahPublikasi(ctx *fiber.Ctx) (err error)
+	RestoreFotoProduk(ctx *fiber.Ctx) (err error)
+	GetSampahFotoProduk(ctx *fiber.Ctx) (err error)
 }
 
 type ProdukControllerImpl struct {
-	produkUseCase usecase.ProdukUseCase
+	produkUseCase    usecase.ProdukUseCase
+	gambarPipeline   *gambar.Pipeline
+	blobStorage      storage.BlobStorage
+	pencatatTayangan *tayangan.Pencatat
 }
 
-func NewProdukController(produkUseCase usecase.ProdukUseCase) ProdukController {
-	return &ProdukControllerImpl{produkUseCase: produkUseCase}
+func NewProdukController(produkUseCase usecase.ProdukUseCase, gambarPipeline *gambar.Pipeline, blobStorage storage.BlobStorage, pencatatTayangan *tayangan.Pencatat) ProdukController {
+	return &ProdukControllerImpl{produkUseCase: produkUseCase, gambarPipeline: gambarPipeline, blobStorage: blobStorage, pencatatTayangan: pencatatTayangan}
+}
+
+// catatTayangan count view of produk detail by logged in user or by ip, it is only kept in memory so response is not delayed
+func (pc *ProdukControllerImpl) catatTayangan(ctx *fiber.Ctx, produkID uint) {
+	pengunjung := "ip:" + ctx.IP()
+	if userID := ctx.Locals("userID"); userID != nil {
+		pengunjung = fmt.Sprintf("user:%v", userID)
+	}
+	pc.pencatatTayangan.Catat(produkID, pengunjung)
 }
 
 func (pc *ProdukControllerImpl) UploadProduk(ctx *fiber.Ctx) (err error) {
@@ -73,18 +102,35 @@ func (pc *ProdukControllerImpl) UploadProduk(ctx *fiber.Ctx) (err error) {
 		return ctx.Status(fiber.StatusBadRequest).JSON(response)
 	}
 	deskripsi := ctx.FormValue("deskripsi")
+	// produk is published right away when status_publikasi is not sent
+	var jadwalTerbit *time.Time
+	if ctx.FormValue("jadwal_terbit") != "" {
+		jadwal, errParse := time.Parse(time.RFC3339, ctx.FormValue("jadwal_terbit"))
+		if errParse != nil {
+			response := BaseResponse{
+				Status:  false,
+				Message: "jadwal_terbit must be RFC3339 time",
+				Error:   []string{errParse.Error()},
+				Data:    nil,
+			}
+			return ctx.Status(fiber.StatusBadRequest).JSON(response)
+		}
+		jadwalTerbit = &jadwal
+	}
 
 	// map form-data value to local struct
 	var data dto.UploadProdukRequest
 	data = dto.UploadProdukRequest{
-		NamaProduk:    namaProduk,
-		CategoryID:    uint(categoryId),
-		TokoID:        uint(tokoID),
-		HargaReseller: uint(hargaReseller),
-		HargaKonsumen: uint(hargaKonsumen),
-		Stok:          uint(stok),
-		Deskripsi:     deskripsi,
-		Photos:        nil,
+		NamaProduk:      namaProduk,
+		CategoryID:      uint(categoryId),
+		TokoID:          uint(tokoID),
+		HargaReseller:   uint(hargaReseller),
+		HargaKonsumen:   uint(hargaKonsumen),
+		Stok:            uint(stok),
+		Deskripsi:       deskripsi,
+		Photos:          nil,
+		StatusPublikasi: ctx.FormValue("status_publikasi"),
+		JadwalTerbit:    jadwalTerbit,
 	}
 
 	// initiate multiplatform to get data from form-data file
@@ -101,28 +147,18 @@ func (pc *ProdukControllerImpl) UploadProduk(ctx *fiber.Ctx) (err error) {
 
 	files := form.File["photos"]
 
-	// saving file input to local
-	for i, file := range files {
-		if file != nil {
-			var filename = file.Filename
-
-			filename = fmt.Sprintf("%d-%s-%d-%v", tokoID, namaProduk, i, filename)
-			filename = strings.ToLower(filename)
-			filenameSplit := strings.Split(filename, " ")
-			filename = strings.Join(filenameSplit, "_")
-			if errSaveFile := ctx.SaveFile(file, fmt.Sprintf("./public/images/toko/%s", filename)); errSaveFile != nil {
-				response := BaseResponse{
-					Status:  false,
-					Message: "Failed to POST data",
-					Error:   []string{errSaveFile.Error()},
-					Data:    nil,
-				}
-				return ctx.Status(fiber.StatusInternalServerError).JSON(response)
-			}
-			photo := dto.Photos{URL: fmt.Sprintf("./public/images/toko/%s", filename)}
-			data.Photos = append(data.Photos, photo)
+	// validate, re-encode and save file input to blob storage
+	photos, code, errFoto := simpanGambar(ctx.Context(), pc.gambarPipeline, pc.blobStorage, files, PrefixFotoProduk)
+	if errFoto != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "Failed to POST data",
+			Error:   []string{errFoto.Error()},
+			Data:    nil,
 		}
+		return ctx.Status(code).JSON(response)
 	}
+	data.Photos = photos
 	// call UploadProduk from produk useCase
 	c := ctx.Context()
 	responseUseCase, errUseCase := pc.produkUseCase.UploadProduk(c, data)
@@ -161,9 +197,44 @@ func (pc *ProdukControllerImpl) GetProdukByID(ctx *fiber.Ctx) (err error) {
 		return ctx.Status(fiber.StatusBadRequest).JSON(response)
 	}
 
+	// logged in user (optional), toko can see its own produk that is not approved yet
+	penggunaID, _ := strconv.Atoi(fmt.Sprintf("%v", ctx.Locals("userID")))
+
 	// call GetProdukByID from produk useCase
 	c := ctx.Context()
-	responseUseCase, errUseCase := pc.produkUseCase.GetProdukByID(c, uint(ID))
+	responseUseCase, errUseCase := pc.produkUseCase.GetProdukByID(c, uint(ID), uint(penggunaID))
+	if errUseCase.Err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "Failed to GET data",
+			Error:   []string{errUseCase.Err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(errUseCase.Code).JSON(response)
+	}
+	pc.catatTayangan(ctx, responseUseCase.ID)
+	ctx.Set(fiber.HeaderETag, etagVersi(responseUseCase.Versi))
+	// success response
+	response := BaseResponse{
+		Status:  true,
+		Message: "Succeed to GET data",
+		Error:   nil,
+		Data:    responseUseCase,
+	}
+	return ctx.Status(fiber.StatusOK).JSON(response)
+}
+
+// GetProdukBySlug old slug is redirected to the current slug of the produk
+func (pc *ProdukControllerImpl) GetProdukBySlug(ctx *fiber.Ctx) (err error) {
+	// get slug from url parameter
+	slug := ctx.Params("slug")
+
+	// logged in user (optional), toko can see its own produk that is not approved yet
+	penggunaID, _ := strconv.Atoi(fmt.Sprintf("%v", ctx.Locals("userID")))
+
+	// call GetProdukBySlug from produk useCase
+	c := ctx.Context()
+	responseUseCase, errUseCase := pc.produkUseCase.GetProdukBySlug(c, slug, uint(penggunaID))
 	if errUseCase.Err != nil {
 		response := BaseResponse{
 			Status:  false,
@@ -173,6 +244,11 @@ func (pc *ProdukControllerImpl) GetProdukByID(ctx *fiber.Ctx) (err error) {
 		}
 		return ctx.Status(errUseCase.Code).JSON(response)
 	}
+	if responseUseCase.Slug != slug {
+		return alihkanSlug(ctx, slug, responseUseCase.Slug)
+	}
+	pc.catatTayangan(ctx, responseUseCase.ID)
+	ctx.Set(fiber.HeaderETag, etagVersi(responseUseCase.Versi))
 	// success response
 	response := BaseResponse{
 		Status:  true,
@@ -202,6 +278,18 @@ func (pc *ProdukControllerImpl) UpdateProdukByID(ctx *fiber.Ctx) (err error) {
 		return ctx.Status(fiber.StatusBadRequest).JSON(response)
 	}
 
+	// versi of produk read by the client, checked before any photo is saved
+	versi, errVersi := versiIfMatch(ctx)
+	if errVersi.Err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "Failed to POST data",
+			Error:   []string{errVersi.Err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(errVersi.Code).JSON(response)
+	}
+
 	var data dto.UpdateProdukRequest
 
 	// get form value
@@ -273,6 +361,7 @@ func (pc *ProdukControllerImpl) UpdateProdukByID(ctx *fiber.Ctx) (err error) {
 		Stok:          uint(stok),
 		Deskripsi:     deskripsi,
 		Photos:        nil,
+		Versi:         versi,
 	}
 
 	// initiate multiplatform to get data from form-data file
@@ -289,32 +378,47 @@ func (pc *ProdukControllerImpl) UpdateProdukByID(ctx *fiber.Ctx) (err error) {
 	// get files from form-data with key photos
 	files := form.File["photos"]
 
-	for i, file := range files {
-
-		if file != nil {
-			var filename = file.Filename
-			filename = fmt.Sprintf("%d-%s-%d-%v", tokoID, namaProduk, i, filename)
-			filename = strings.ToLower(filename)
-			filenameSplit := strings.Split(filename, " ")
-			filename = strings.Join(filenameSplit, "_")
-			// save file to local folder with specified name
-			if errSaveFile := ctx.SaveFile(file, fmt.Sprintf("./public/images/toko/%s", filename)); errSaveFile != nil {
-				response := BaseResponse{
-					Status:  false,
-					Message: "Failed to POST data",
-					Error:   []string{errSaveFile.Error()},
-					Data:    nil,
-				}
-				return ctx.Status(fiber.StatusInternalServerError).JSON(response)
+	// photo is saved only for produk of the toko that is not changed since the client read it
+	if len(files) > 0 {
+		produk, errProduk := pc.produkUseCase.GetProdukByID(ctx.Context(), uint(ID), uint(tokoID))
+		if errProduk.Err == nil && produk.Toko.ID != uint(tokoID) {
+			response := BaseResponse{
+				Status:  false,
+				Message: "Failed to POST data",
+				Error:   []string{"No Data Product"},
+				Data:    nil,
+			}
+			return ctx.Status(fiber.StatusNotFound).JSON(response)
+		}
+		if errProduk.Err == nil {
+			errProduk = periksaVersi(versi, produk.Versi)
+		}
+		if errProduk.Err != nil {
+			response := BaseResponse{
+				Status:  false,
+				Message: "Failed to POST data",
+				Error:   []string{errProduk.Err.Error()},
+				Data:    nil,
 			}
-			// add photo to local struct
-			photo := dto.Photos{URL: fmt.Sprintf("./public/images/toko/%s", filename)}
-			data.Photos = append(data.Photos, photo)
+			return ctx.Status(errProduk.Code).JSON(response)
 		}
 	}
+
+	// validate, re-encode and save file input to blob storage
+	photos, code, errFoto := simpanGambar(ctx.Context(), pc.gambarPipeline, pc.blobStorage, files, PrefixFotoProduk)
+	if errFoto != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "Failed to POST data",
+			Error:   []string{errFoto.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(code).JSON(response)
+	}
+	data.Photos = photos
 	// call UploadProduk from produk useCase to update produk record with specified toko_id and id
 	c := ctx.Context()
-	errUseCase := pc.produkUseCase.UpdateProdukByID(c, data)
+	versiBaru, errUseCase := pc.produkUseCase.UpdateProdukByID(c, data)
 	if errUseCase.Err != nil {
 		response := BaseResponse{
 			Status:  false,
@@ -324,6 +428,7 @@ func (pc *ProdukControllerImpl) UpdateProdukByID(ctx *fiber.Ctx) (err error) {
 		}
 		return ctx.Status(errUseCase.Code).JSON(response)
 	}
+	ctx.Set(fiber.HeaderETag, etagVersi(versiBaru))
 	// success response
 	response := BaseResponse{
 		Status:  true,
@@ -374,6 +479,50 @@ func (pc *ProdukControllerImpl) DeleteProdukByID(ctx *fiber.Ctx) (err error) {
 	return ctx.Status(fiber.StatusOK).JSON(response)
 }
 
+// filterAtribut read attribute filter from query attr.<kode>=nilai1,nilai2, attr.<kode>.min and attr.<kode>.max
+func filterAtribut(ctx *fiber.Ctx) (listFilter []dto.FilterAtribut, err error) {
+	indexByKode := map[string]int{}
+	ctx.Context().QueryArgs().VisitAll(func(key, value []byte) {
+		nama := string(key)
+		if err != nil || !strings.HasPrefix(nama, "attr.") {
+			return
+		}
+		kode, batas := strings.TrimPrefix(nama, "attr."), ""
+		if i := strings.LastIndex(kode, "."); i >= 0 {
+			kode, batas = kode[:i], kode[i+1:]
+		}
+		if kode == "" || (batas != "" && batas != "min" && batas != "max") {
+			err = fmt.Errorf("unknown attribute filter %s", nama)
+			return
+		}
+		i, ok := indexByKode[kode]
+		if !ok {
+			i = len(listFilter)
+			indexByKode[kode] = i
+			listFilter = append(listFilter, dto.FilterAtribut{Kode: kode})
+		}
+		if batas == "" {
+			for _, v := range strings.Split(string(value), ",") {
+				if v = strings.TrimSpace(v); v != "" {
+					listFilter[i].Nilai = append(listFilter[i].Nilai, v)
+				}
+			}
+			return
+		}
+		angka, errParse := strconv.ParseFloat(string(value), 64)
+		if errParse != nil {
+			err = fmt.Errorf("%s must be number", nama)
+			return
+		}
+		if batas == "min" {
+			listFilter[i].Min = &angka
+		} else {
+			listFilter[i].Max = &angka
+		}
+	})
+	return listFilter, err
+}
+
 func (pc *ProdukControllerImpl) GetAllProduk(ctx *fiber.Ctx) (err error) {
 	// get limit and page from query parameter url
 	filter := new(dto.FilterProduk)
@@ -386,10 +535,21 @@ func (pc *ProdukControllerImpl) GetAllProduk(ctx *fiber.Ctx) (err error) {
 		}
 		return ctx.Status(fiber.StatusBadRequest).JSON(response)
 	}
+	listAtribut, errQuery := filterAtribut(ctx)
+	if errQuery != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "Failed to GET data",
+			Error:   []string{errQuery.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+	filter.Atribut = listAtribut
 
 	// call GetAllProduk from produk useCase to get produk records
 	c := ctx.Context()
-	responseUseCase, errUseCase := pc.produkUseCase.GetAllProduk(c, *filter)
+	responseUseCase, facet, nextCursor, errUseCase := pc.produkUseCase.GetAllProduk(c, *filter)
 	// error checking
 	if errUseCase.Err != nil {
 		response := BaseResponse{
@@ -401,7 +561,9 @@ func (pc *ProdukControllerImpl) GetAllProduk(ctx *fiber.Ctx) (err error) {
 		return ctx.Status(errUseCase.Code).JSON(response)
 	}
 	type DataProduct struct {
-		Data []dto.GetProduk `json:"data"`
+		Data       []dto.GetProduk `json:"data"`
+		Facet      dto.FacetProduk `json:"facet"`
+		NextCursor string          `json:"next_cursor,omitempty"`
 	}
 	// success response
 	response := BaseResponse{
@@ -409,8 +571,582 @@ func (pc *ProdukControllerImpl) GetAllProduk(ctx *fiber.Ctx) (err error) {
 		Message: "Succeed to GET data",
 		Error:   nil,
 		Data: DataProduct{
-			Data: responseUseCase,
+			Data:       responseUseCase,
+			Facet:      facet,
+			NextCursor: nextCursor,
 		},
 	}
 	return ctx.Status(fiber.StatusOK).JSON(response)
 }
+
+func (pc *ProdukControllerImpl) UpdateVarian(ctx *fiber.Ctx) (err error) {
+	// get tokoID (tokoID is the same as userID) from middleware
+	tokoIDMiddleware := ctx.Locals("userID")
+	tokoID, _ := strconv.Atoi(fmt.Sprintf("%v", tokoIDMiddleware))
+
+	// get id from url parameter
+	ID, err := strconv.Atoi(ctx.Params("id"))
+	if err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "ID must integer > 0",
+			Error:   []string{err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	// parse body request
+	data := new(dto.UpdateVarianRequest)
+	if errParse := ctx.BodyParser(data); errParse != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "Failed to PUT data",
+			Error:   []string{errParse.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+	data.ProdukID = uint(ID)
+	data.TokoID = uint(tokoID)
+
+	// call UpdateVarian from produk useCase
+	c := ctx.Context()
+	errUseCase := pc.produkUseCase.UpdateVarian(c, *data)
+	if errUseCase.Err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "Failed to PUT data",
+			Error:   []string{errUseCase.Err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(errUseCase.Code).JSON(response)
+	}
+	// success response
+	response := BaseResponse{
+		Status:  true,
+		Message: "Succeed to PUT data",
+		Error:   nil,
+		Data:    "",
+	}
+	return ctx.Status(fiber.StatusOK).JSON(response)
+}
+
+func (pc *ProdukControllerImpl) UploadFotoSKU(ctx *fiber.Ctx) (err error) {
+	// get tokoID (tokoID is the same as userID) from middleware
+	tokoIDMiddleware := ctx.Locals("userID")
+	tokoID, _ := strconv.Atoi(fmt.Sprintf("%v", tokoIDMiddleware))
+
+	// get id produk and id sku from url parameter
+	ID, err := strconv.Atoi(ctx.Params("id"))
+	if err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "ID must integer > 0",
+			Error:   []string{err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+	skuID, err := strconv.Atoi(ctx.Params("sku_id"))
+	if err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "sku_id must integer > 0",
+			Error:   []string{err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	// initiate multiplatform to get data from form-data file
+	form, err := ctx.MultipartForm()
+	if err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "Failed to POST data",
+			Error:   []string{err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+	// get files from form-data with key photos
+	files := form.File["photos"]
+
+	// validate, re-encode and save file input to blob storage
+	photos, code, errFoto := simpanGambar(ctx.Context(), pc.gambarPipeline, pc.blobStorage, files, PrefixFotoProduk)
+	if errFoto != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "Failed to POST data",
+			Error:   []string{errFoto.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(code).JSON(response)
+	}
+
+	// call CreateFotoSKU from produk useCase
+	c := ctx.Context()
+	errUseCase := pc.produkUseCase.CreateFotoSKU(c, uint(tokoID), uint(ID), uint(skuID), photos)
+	if errUseCase.Err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "Failed to POST data",
+			Error:   []string{errUseCase.Err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(errUseCase.Code).JSON(response)
+	}
+	// success response
+	response := BaseResponse{
+		Status:  true,
+		Message: "Succeed to POST data",
+		Error:   nil,
+		Data:    "",
+	}
+	return ctx.Status(fiber.StatusOK).JSON(response)
+}
+
+func (pc *ProdukControllerImpl) GetFotoProduk(ctx *fiber.Ctx) (err error) {
+	// get id from url parameter
+	ID, err := strconv.Atoi(ctx.Params("id"))
+	if err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "ID must integer > 0",
+			Error:   []string{err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	// call GetFotoProduk from produk useCase
+	c := ctx.Context()
+	responseUseCase, errUseCase := pc.produkUseCase.GetFotoProduk(c, uint(ID))
+	if errUseCase.Err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "Failed to GET data",
+			Error:   []string{errUseCase.Err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(errUseCase.Code).JSON(response)
+	}
+	// success response
+	response := BaseResponse{
+		Status:  true,
+		Message: "Succeed to GET data",
+		Error:   nil,
+		Data:    responseUseCase,
+	}
+	return ctx.Status(fiber.StatusOK).JSON(response)
+}
+
+func (pc *ProdukControllerImpl) DeleteFotoProduk(ctx *fiber.Ctx) (err error) {
+	// get tokoID (tokoID is the same as userID) from middleware
+	tokoIDMiddleware := ctx.Locals("userID")
+	tokoID, _ := strconv.Atoi(fmt.Sprintf("%v", tokoIDMiddleware))
+
+	// get id produk and id foto from url parameter
+	ID, err := strconv.Atoi(ctx.Params("id"))
+	if err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "ID must integer > 0",
+			Error:   []string{err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+	fotoID, err := strconv.Atoi(ctx.Params("photo_id"))
+	if err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "photo_id must integer > 0",
+			Error:   []string{err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	// call DeleteFotoProduk from produk useCase
+	c := ctx.Context()
+	errUseCase := pc.produkUseCase.DeleteFotoProduk(c, uint(tokoID), uint(ID), uint(fotoID))
+	if errUseCase.Err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "Failed to DELETE data",
+			Error:   []string{errUseCase.Err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(errUseCase.Code).JSON(response)
+	}
+	// success response
+	response := BaseResponse{
+		Status:  true,
+		Message: "Succeed to DELETE data",
+		Error:   nil,
+		Data:    "",
+	}
+	return ctx.Status(fiber.StatusOK).JSON(response)
+}
+
+func (pc *ProdukControllerImpl) UrutkanFotoProduk(ctx *fiber.Ctx) (err error) {
+	// get tokoID (tokoID is the same as userID) from middleware
+	tokoIDMiddleware := ctx.Locals("userID")
+	tokoID, _ := strconv.Atoi(fmt.Sprintf("%v", tokoIDMiddleware))
+
+	// get id from url parameter
+	ID, err := strconv.Atoi(ctx.Params("id"))
+	if err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "ID must integer > 0",
+			Error:   []string{err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	// parse body request
+	data := new(dto.UrutanFotoRequest)
+	if errParse := ctx.BodyParser(data); errParse != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "Failed to PUT data",
+			Error:   []string{errParse.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+	data.ProdukID = uint(ID)
+	data.TokoID = uint(tokoID)
+
+	// call UrutkanFotoProduk from produk useCase
+	c := ctx.Context()
+	errUseCase := pc.produkUseCase.UrutkanFotoProduk(c, *data)
+	if errUseCase.Err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "Failed to PUT data",
+			Error:   []string{errUseCase.Err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(errUseCase.Code).JSON(response)
+	}
+	// success response
+	response := BaseResponse{
+		Status:  true,
+		Message: "Succeed to PUT data",
+		Error:   nil,
+		Data:    "",
+	}
+	return ctx.Status(fiber.StatusOK).JSON(response)
+}
+
+func (pc *ProdukControllerImpl) SetFotoUtama(ctx *fiber.Ctx) (err error) {
+	// get tokoID (tokoID is the same as userID) from middleware
+	tokoIDMiddleware := ctx.Locals("userID")
+	tokoID, _ := strconv.Atoi(fmt.Sprintf("%v", tokoIDMiddleware))
+
+	// get id produk and id foto from url parameter
+	ID, err := strconv.Atoi(ctx.Params("id"))
+	if err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "ID must integer > 0",
+			Error:   []string{err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+	fotoID, err := strconv.Atoi(ctx.Params("photo_id"))
+	if err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "photo_id must integer > 0",
+			Error:   []string{err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	// call SetFotoUtama from produk useCase
+	c := ctx.Context()
+	errUseCase := pc.produkUseCase.SetFotoUtama(c, uint(tokoID), uint(ID), uint(fotoID))
+	if errUseCase.Err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "Failed to PUT data",
+			Error:   []string{errUseCase.Err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(errUseCase.Code).JSON(response)
+	}
+	// success response
+	response := BaseResponse{
+		Status:  true,
+		Message: "Succeed to PUT data",
+		Error:   nil,
+		Data:    "",
+	}
+	return ctx.Status(fiber.StatusOK).JSON(response)
+}
+
+func (pc *ProdukControllerImpl) RestoreProdukByID(ctx *fiber.Ctx) (err error) {
+	// get tokoID (tokoID is the same as userID) from middleware
+	tokoIDMiddleware := ctx.Locals("userID")
+	tokoID, _ := strconv.Atoi(fmt.Sprintf("%v", tokoIDMiddleware))
+
+	// get id from url parameter
+	ID, err := strconv.Atoi(ctx.Params("id"))
+	if err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "ID must integer > 0",
+			Error:   []string{err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	// call RestoreProdukByID from produk useCase
+	c := ctx.Context()
+	errUseCase := pc.produkUseCase.RestoreProdukByID(c, uint(tokoID), uint(ID))
+	if errUseCase.Err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "Failed to PUT data",
+			Error:   []string{errUseCase.Err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(errUseCase.Code).JSON(response)
+	}
+	// success response
+	response := BaseResponse{
+		Status:  true,
+		Message: "Succeed to PUT data",
+		Error:   nil,
+		Data:    "",
+	}
+	return ctx.Status(fiber.StatusOK).JSON(response)
+}
+
+func (pc *ProdukControllerImpl) GetSampahProduk(ctx *fiber.Ctx) (err error) {
+	// get tokoID (tokoID is the same as userID) from middleware
+	tokoIDMiddleware := ctx.Locals("userID")
+	tokoID, _ := strconv.Atoi(fmt.Sprintf("%v", tokoIDMiddleware))
+
+	// parse pagination query
+	params := new(dto.FilterSampah)
+	if errParse := ctx.QueryParser(params); errParse != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "Failed to GET data",
+			Error:   []string{errParse.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	// call GetSampahProduk from produk useCase
+	c := ctx.Context()
+	responseUseCase, errUseCase := pc.produkUseCase.GetSampahProduk(c, uint(tokoID), *params)
+	if errUseCase.Err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "Failed to GET data",
+			Error:   []string{errUseCase.Err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(errUseCase.Code).JSON(response)
+	}
+	// success response
+	response := BaseResponse{
+		Status:  true,
+		Message: "Succeed to GET data",
+		Error:   nil,
+		Data:    responseUseCase,
+	}
+	return ctx.Status(fiber.StatusOK).JSON(response)
+}
+
+func (pc *ProdukControllerImpl) GetProdukToko(ctx *fiber.Ctx) (err error) {
+	// get tokoID (tokoID is the same as userID) from middleware
+	tokoIDMiddleware := ctx.Locals("userID")
+	tokoID, _ := strconv.Atoi(fmt.Sprintf("%v", tokoIDMiddleware))
+
+	// parse query params
+	params := new(dto.FilterProdukToko)
+	if errParse := ctx.QueryParser(params); errParse != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "Failed to GET data",
+			Error:   []string{errParse.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	// call GetProdukToko from produk useCase
+	c := ctx.Context()
+	responseUseCase, errUseCase := pc.produkUseCase.GetProdukToko(c, uint(tokoID), *params)
+	if errUseCase.Err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "Failed to GET data",
+			Error:   []string{errUseCase.Err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(errUseCase.Code).JSON(response)
+	}
+	// success response
+	response := BaseResponse{
+		Status:  true,
+		Message: "Succeed to GET data",
+		Error:   nil,
+		Data:    responseUseCase,
+	}
+	return ctx.Status(fiber.StatusOK).JSON(response)
+}
+
+func (pc *ProdukControllerImpl) UbahPublikasi(ctx *fiber.Ctx) (err error) {
+	// get tokoID (tokoID is the same as userID) from middleware
+	tokoIDMiddleware := ctx.Locals("userID")
+	tokoID, _ := strconv.Atoi(fmt.Sprintf("%v", tokoIDMiddleware))
+
+	// get id from url parameter
+	ID, err := strconv.Atoi(ctx.Params("id"))
+	if err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "ID must integer > 0",
+			Error:   []string{err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	// parse body request
+	data := new(dto.PublikasiRequest)
+	if errParse := ctx.BodyParser(data); errParse != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "Failed to PUT data",
+			Error:   []string{errParse.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+	data.ProdukID = uint(ID)
+	data.TokoID = uint(tokoID)
+
+	// call UbahPublikasi from produk useCase
+	c := ctx.Context()
+	errUseCase := pc.produkUseCase.UbahPublikasi(c, *data)
+	if errUseCase.Err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "Failed to PUT data",
+			Error:   []string{errUseCase.Err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(errUseCase.Code).JSON(response)
+	}
+	// success response
+	response := BaseResponse{
+		Status:  true,
+		Message: "Succeed to PUT data",
+		Error:   nil,
+		Data:    "",
+	}
+	return ctx.Status(fiber.StatusOK).JSON(response)
+}
+
+func (pc *ProdukControllerImpl) RestoreFotoProduk(ctx *fiber.Ctx) (err error) {
+	// get tokoID (tokoID is the same as userID) from middleware
+	tokoIDMiddleware := ctx.Locals("userID")
+	tokoID, _ := strconv.Atoi(fmt.Sprintf("%v", tokoIDMiddleware))
+
+	// get id produk and id foto from url parameter
+	ID, err := strconv.Atoi(ctx.Params("id"))
+	if err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "ID must integer > 0",
+			Error:   []string{err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+	fotoID, err := strconv.Atoi(ctx.Params("photo_id"))
+	if err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "photo_id must integer > 0",
+			Error:   []string{err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	// call RestoreFotoProduk from produk useCase
+	c := ctx.Context()
+	errUseCase := pc.produkUseCase.RestoreFotoProduk(c, uint(tokoID), uint(ID), uint(fotoID))
+	if errUseCase.Err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "Failed to PUT data",
+			Error:   []string{errUseCase.Err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(errUseCase.Code).JSON(response)
+	}
+	// success response
+	response := BaseResponse{
+		Status:  true,
+		Message: "Succeed to PUT data",
+		Error:   nil,
+		Data:    "",
+	}
+	return ctx.Status(fiber.StatusOK).JSON(response)
+}
+
+func (pc *ProdukControllerImpl) GetSampahFotoProduk(ctx *fiber.Ctx) (err error) {
+	// get tokoID (tokoID is the same as userID) from middleware
+	tokoIDMiddleware := ctx.Locals("userID")
+	tokoID, _ := strconv.Atoi(fmt.Sprintf("%v", tokoIDMiddleware))
+
+	// get id from url parameter
+	ID, err := strconv.Atoi(ctx.Params("id"))
+	if err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "ID must integer > 0",
+			Error:   []string{err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	// call GetSampahFotoProduk from produk useCase
+	c := ctx.Context()
+	responseUseCase, errUseCase := pc.produkUseCase.GetSampahFotoProduk(c, uint(tokoID), uint(ID))
+	if errUseCase.Err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "Failed to GET data",
+			Error:   []string{errUseCase.Err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(errUseCase.Code).JSON(response)
+	}
+	// success response
+	response := BaseResponse{
+		Status:  true,
+		Message: "Succeed to GET data",
+		Error:   nil,
+		Data:    responseUseCase,
+	}
+	return ctx.Status(fiber.StatusOK).JSON(response)
+}
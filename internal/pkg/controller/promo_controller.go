@@ -0,0 +1,261 @@
+package controller
+
+import (
+	"fmt"
+	"github.com/gofiber/fiber/v2"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/pkg/dto"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/pkg/usecase"
+	"strconv"
+)
+
+type PromoController interface {
+	CreatePromo(ctx *fiber.Ctx) (err error)
+	GetMyPromo(ctx *fiber.Ctx) (err error)
+	GetPromoAktif(ctx *fiber.Ctx) (err error)
+	GetPromoByID(ctx *fiber.Ctx) (err error)
+	BatalkanPromo(ctx *fiber.Ctx) (err error)
+	GetRiwayatHarga(ctx *fiber.Ctx) (err error)
+}
+
+type PromoControllerImpl struct {
+	promoUseCase usecase.PromoUseCase
+}
+
+func NewPromoController(promoUseCase usecase.PromoUseCase) PromoController {
+	return &PromoControllerImpl{promoUseCase: promoUseCase}
+}
+
+func (pc *PromoControllerImpl) CreatePromo(ctx *fiber.Ctx) (err error) {
+	// get tokoID (tokoID is the same as userID) from middleware
+	tokoIDMiddleware := ctx.Locals("userID")
+	tokoID, _ := strconv.Atoi(fmt.Sprintf("%v", tokoIDMiddleware))
+
+	// parse body request
+	data := new(dto.CreatePromoRequest)
+	if errParse := ctx.BodyParser(data); errParse != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "Failed to POST data",
+			Error:   []string{errParse.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+	data.TokoID = uint(tokoID)
+
+	// call CreatePromo from promo useCase
+	c := ctx.Context()
+	responseUseCase, errUseCase := pc.promoUseCase.CreatePromo(c, *data)
+	if errUseCase.Err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "Failed to POST data",
+			Error:   []string{errUseCase.Err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(errUseCase.Code).JSON(response)
+	}
+	// success response
+	response := BaseResponse{
+		Status:  true,
+		Message: "Succeed to POST data",
+		Error:   nil,
+		Data:    responseUseCase,
+	}
+	return ctx.Status(fiber.StatusOK).JSON(response)
+}
+
+func (pc *PromoControllerImpl) GetMyPromo(ctx *fiber.Ctx) (err error) {
+	// get tokoID (tokoID is the same as userID) from middleware
+	tokoIDMiddleware := ctx.Locals("userID")
+	tokoID, _ := strconv.Atoi(fmt.Sprintf("%v", tokoIDMiddleware))
+
+	// parse query params
+	var params dto.FilterPromo
+	if err := ctx.QueryParser(&params); err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "Failed to GET data",
+			Error:   []string{err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	// call GetMyPromo from promo useCase
+	c := ctx.Context()
+	responseUseCase, errUseCase := pc.promoUseCase.GetMyPromo(c, uint(tokoID), params)
+	if errUseCase.Err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "Failed to GET data",
+			Error:   []string{errUseCase.Err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(errUseCase.Code).JSON(response)
+	}
+	// success response
+	response := BaseResponse{
+		Status:  true,
+		Message: "Succeed to GET data",
+		Error:   nil,
+		Data:    responseUseCase,
+	}
+	return ctx.Status(fiber.StatusOK).JSON(response)
+}
+
+func (pc *PromoControllerImpl) GetPromoAktif(ctx *fiber.Ctx) (err error) {
+	// parse query params
+	var params dto.FilterPromo
+	if err := ctx.QueryParser(&params); err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "Failed to GET data",
+			Error:   []string{err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	// call GetPromoAktif from promo useCase
+	c := ctx.Context()
+	responseUseCase, errUseCase := pc.promoUseCase.GetPromoAktif(c, params)
+	if errUseCase.Err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "Failed to GET data",
+			Error:   []string{errUseCase.Err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(errUseCase.Code).JSON(response)
+	}
+	// success response
+	response := BaseResponse{
+		Status:  true,
+		Message: "Succeed to GET data",
+		Error:   nil,
+		Data:    responseUseCase,
+	}
+	return ctx.Status(fiber.StatusOK).JSON(response)
+}
+
+func (pc *PromoControllerImpl) GetPromoByID(ctx *fiber.Ctx) (err error) {
+	// get id from url parameter
+	ID, err := strconv.Atoi(ctx.Params("id"))
+	if err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "ID must integer > 0",
+			Error:   []string{err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	// call GetPromoByID from promo useCase
+	c := ctx.Context()
+	responseUseCase, errUseCase := pc.promoUseCase.GetPromoByID(c, uint(ID))
+	if errUseCase.Err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "Failed to GET data",
+			Error:   []string{errUseCase.Err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(errUseCase.Code).JSON(response)
+	}
+	// success response
+	response := BaseResponse{
+		Status:  true,
+		Message: "Succeed to GET data",
+		Error:   nil,
+		Data:    responseUseCase,
+	}
+	return ctx.Status(fiber.StatusOK).JSON(response)
+}
+
+func (pc *PromoControllerImpl) BatalkanPromo(ctx *fiber.Ctx) (err error) {
+	// get tokoID (tokoID is the same as userID) from middleware
+	tokoIDMiddleware := ctx.Locals("userID")
+	tokoID, _ := strconv.Atoi(fmt.Sprintf("%v", tokoIDMiddleware))
+
+	// get id from url parameter
+	ID, err := strconv.Atoi(ctx.Params("id"))
+	if err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "ID must integer > 0",
+			Error:   []string{err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	// call BatalkanPromo from promo useCase
+	c := ctx.Context()
+	errUseCase := pc.promoUseCase.BatalkanPromo(c, uint(tokoID), uint(ID))
+	if errUseCase.Err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "Failed to DELETE data",
+			Error:   []string{errUseCase.Err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(errUseCase.Code).JSON(response)
+	}
+	// success response
+	response := BaseResponse{
+		Status:  true,
+		Message: "Succeed to DELETE data",
+		Error:   nil,
+		Data:    "",
+	}
+	return ctx.Status(fiber.StatusOK).JSON(response)
+}
+
+func (pc *PromoControllerImpl) GetRiwayatHarga(ctx *fiber.Ctx) (err error) {
+	// get id from url parameter
+	ID, err := strconv.Atoi(ctx.Params("id"))
+	if err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "ID must integer > 0",
+			Error:   []string{err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	// parse query params
+	var params dto.FilterRiwayatHarga
+	if err := ctx.QueryParser(&params); err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "Failed to GET data",
+			Error:   []string{err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	// call GetRiwayatHarga from promo useCase
+	c := ctx.Context()
+	responseUseCase, errUseCase := pc.promoUseCase.GetRiwayatHarga(c, uint(ID), params)
+	if errUseCase.Err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "Failed to GET data",
+			Error:   []string{errUseCase.Err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(errUseCase.Code).JSON(response)
+	}
+	// success response
+	response := BaseResponse{
+		Status:  true,
+		Message: "Succeed to GET data",
+		Error:   nil,
+		Data:    responseUseCase,
+	}
+	return ctx.Status(fiber.StatusOK).JSON(response)
+}
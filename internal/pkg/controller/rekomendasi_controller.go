@@ -0,0 +1,108 @@
+package controller
+
+import (
+	"fmt"
+	"github.com/gofiber/fiber/v2"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/pkg/dto"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/pkg/usecase"
+	"strconv"
+)
+
+type RekomendasiController interface {
+	GetProdukTerkait(ctx *fiber.Ctx) (err error)
+	GetRekomendasiUser(ctx *fiber.Ctx) (err error)
+}
+
+type RekomendasiControllerImpl struct {
+	rekomendasiUseCase usecase.RekomendasiUseCase
+}
+
+func NewRekomendasiController(rekomendasiUseCase usecase.RekomendasiUseCase) RekomendasiController {
+	return &RekomendasiControllerImpl{rekomendasiUseCase: rekomendasiUseCase}
+}
+
+func (rc *RekomendasiControllerImpl) GetProdukTerkait(ctx *fiber.Ctx) (err error) {
+	// get id from url parameter
+	ID, err := strconv.Atoi(ctx.Params("id"))
+	if err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "ID must integer > 0",
+			Error:   []string{err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	// parse query params
+	var params dto.FilterRekomendasi
+	if err := ctx.QueryParser(&params); err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "Failed to GET data",
+			Error:   []string{err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	// call GetProdukTerkait from rekomendasi useCase
+	c := ctx.Context()
+	responseUseCase, errUseCase := rc.rekomendasiUseCase.GetProdukTerkait(c, uint(ID), params)
+	if errUseCase.Err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "Failed to GET data",
+			Error:   []string{errUseCase.Err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(errUseCase.Code).JSON(response)
+	}
+	// success response
+	response := BaseResponse{
+		Status:  true,
+		Message: "Succeed to GET data",
+		Error:   nil,
+		Data:    responseUseCase,
+	}
+	return ctx.Status(fiber.StatusOK).JSON(response)
+}
+
+func (rc *RekomendasiControllerImpl) GetRekomendasiUser(ctx *fiber.Ctx) (err error) {
+	// get userID from middleware
+	userIDMiddleware := ctx.Locals("userID")
+	userID, _ := strconv.Atoi(fmt.Sprintf("%v", userIDMiddleware))
+
+	// parse query params
+	var params dto.FilterRekomendasi
+	if err := ctx.QueryParser(&params); err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "Failed to GET data",
+			Error:   []string{err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	// call GetRekomendasiUser from rekomendasi useCase
+	c := ctx.Context()
+	responseUseCase, errUseCase := rc.rekomendasiUseCase.GetRekomendasiUser(c, uint(userID), params)
+	if errUseCase.Err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "Failed to GET data",
+			Error:   []string{errUseCase.Err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(errUseCase.Code).JSON(response)
+	}
+	// success response
+	response := BaseResponse{
+		Status:  true,
+		Message: "Succeed to GET data",
+		Error:   nil,
+		Data:    responseUseCase,
+	}
+	return ctx.Status(fiber.StatusOK).JSON(response)
+}
@@ -0,0 +1,66 @@
+package controller
+
+import (
+	"context"
+	"github.com/gofiber/fiber/v2"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/helper"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/pkg/dto"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/pkg/usecase"
+)
+
+type StatistikController interface {
+	GetProdukTrending(ctx *fiber.Ctx) (err error)
+	GetProdukTerlaris(ctx *fiber.Ctx) (err error)
+}
+
+type StatistikControllerImpl struct {
+	statistikUseCase usecase.StatistikUseCase
+}
+
+func NewStatistikController(statistikUseCase usecase.StatistikUseCase) StatistikController {
+	return &StatistikControllerImpl{statistikUseCase: statistikUseCase}
+}
+
+func (sc *StatistikControllerImpl) GetProdukTrending(ctx *fiber.Ctx) (err error) {
+	return sc.getProdukPopuler(ctx, sc.statistikUseCase.GetProdukTrending)
+}
+
+func (sc *StatistikControllerImpl) GetProdukTerlaris(ctx *fiber.Ctx) (err error) {
+	return sc.getProdukPopuler(ctx, sc.statistikUseCase.GetProdukTerlaris)
+}
+
+// getProdukPopuler trending and best seller have the same query params and response
+func (sc *StatistikControllerImpl) getProdukPopuler(ctx *fiber.Ctx, getProduk func(ctx context.Context, params dto.FilterPopuler) ([]dto.GetProduk, *helper.ErrorStruct)) (err error) {
+	// parse query params
+	var params dto.FilterPopuler
+	if err := ctx.QueryParser(&params); err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "Failed to GET data",
+			Error:   []string{err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	// call statistik useCase
+	c := ctx.Context()
+	responseUseCase, errUseCase := getProduk(c, params)
+	if errUseCase.Err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "Failed to GET data",
+			Error:   []string{errUseCase.Err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(errUseCase.Code).JSON(response)
+	}
+	// success response
+	response := BaseResponse{
+		Status:  true,
+		Message: "Succeed to GET data",
+		Error:   nil,
+		Data:    responseUseCase,
+	}
+	return ctx.Status(fiber.StatusOK).JSON(response)
+}
@@ -0,0 +1,241 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"github.com/gofiber/fiber/v2"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/helper"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/pkg/dto"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/pkg/usecase"
+	"strconv"
+)
+
+type StokController interface {
+	SesuaikanStok(ctx *fiber.Ctx) (err error)
+	RestockStok(ctx *fiber.Ctx) (err error)
+	GetMutasiStok(ctx *fiber.Ctx) (err error)
+	SetBatasStokRendahProduk(ctx *fiber.Ctx) (err error)
+	SetBatasStokRendahToko(ctx *fiber.Ctx) (err error)
+	GetProdukStokRendah(ctx *fiber.Ctx) (err error)
+}
+
+type StokControllerImpl struct {
+	stokUseCase usecase.StokUseCase
+}
+
+func NewStokController(stokUseCase usecase.StokUseCase) StokController {
+	return &StokControllerImpl{stokUseCase: stokUseCase}
+}
+
+func (sc *StokControllerImpl) SesuaikanStok(ctx *fiber.Ctx) (err error) {
+	return sc.ubahStok(ctx, sc.stokUseCase.SesuaikanStok)
+}
+
+func (sc *StokControllerImpl) RestockStok(ctx *fiber.Ctx) (err error) {
+	return sc.ubahStok(ctx, sc.stokUseCase.RestockStok)
+}
+
+// ubahStok adjust and restock have the same request and response
+func (sc *StokControllerImpl) ubahStok(ctx *fiber.Ctx, ubah func(ctx context.Context, data dto.UbahStokRequest) (dto.MutasiStokResponse, *helper.ErrorStruct)) (err error) {
+	// get tokoID (tokoID is the same as userID) from middleware
+	tokoIDMiddleware := ctx.Locals("userID")
+	tokoID, _ := strconv.Atoi(fmt.Sprintf("%v", tokoIDMiddleware))
+
+	// get id from url parameter
+	ID, err := strconv.Atoi(ctx.Params("id"))
+	if err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "ID must integer > 0",
+			Error:   []string{err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	// parse body request
+	data := new(dto.UbahStokRequest)
+	if errParse := ctx.BodyParser(data); errParse != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "Failed to POST data",
+			Error:   []string{errParse.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+	data.ProdukID = uint(ID)
+	data.TokoID = uint(tokoID)
+
+	// call stok useCase, every change is recorded as stock movement
+	c := ctx.Context()
+	responseUseCase, errUseCase := ubah(c, *data)
+	if errUseCase.Err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "Failed to POST data",
+			Error:   []string{errUseCase.Err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(errUseCase.Code).JSON(response)
+	}
+	// success response
+	response := BaseResponse{
+		Status:  true,
+		Message: "Succeed to POST data",
+		Error:   nil,
+		Data:    responseUseCase,
+	}
+	return ctx.Status(fiber.StatusOK).JSON(response)
+}
+
+func (sc *StokControllerImpl) GetMutasiStok(ctx *fiber.Ctx) (err error) {
+	// get tokoID (tokoID is the same as userID) from middleware
+	tokoIDMiddleware := ctx.Locals("userID")
+	tokoID, _ := strconv.Atoi(fmt.Sprintf("%v", tokoIDMiddleware))
+
+	// get id from url parameter
+	ID, err := strconv.Atoi(ctx.Params("id"))
+	if err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "ID must integer > 0",
+			Error:   []string{err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	// parse query params
+	var params dto.FilterMutasiStok
+	if err := ctx.QueryParser(&params); err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "Failed to GET data",
+			Error:   []string{err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	// call GetMutasiStok from stok useCase
+	c := ctx.Context()
+	responseUseCase, errUseCase := sc.stokUseCase.GetMutasiStok(c, uint(tokoID), uint(ID), params)
+	if errUseCase.Err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "Failed to GET data",
+			Error:   []string{errUseCase.Err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(errUseCase.Code).JSON(response)
+	}
+	// success response
+	response := BaseResponse{
+		Status:  true,
+		Message: "Succeed to GET data",
+		Error:   nil,
+		Data:    responseUseCase,
+	}
+	return ctx.Status(fiber.StatusOK).JSON(response)
+}
+
+func (sc *StokControllerImpl) SetBatasStokRendahProduk(ctx *fiber.Ctx) (err error) {
+	// get id from url parameter
+	ID, err := strconv.Atoi(ctx.Params("id"))
+	if err != nil || ID < 1 {
+		response := BaseResponse{
+			Status:  false,
+			Message: "ID must integer > 0",
+			Error:   []string{fmt.Sprintf("invalid id %s", ctx.Params("id"))},
+			Data:    nil,
+		}
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+	return sc.setBatasStokRendah(ctx, uint(ID))
+}
+
+func (sc *StokControllerImpl) SetBatasStokRendahToko(ctx *fiber.Ctx) (err error) {
+	return sc.setBatasStokRendah(ctx, 0)
+}
+
+// setBatasStokRendah threshold of produk, or of the toko when produkID is 0
+func (sc *StokControllerImpl) setBatasStokRendah(ctx *fiber.Ctx, produkID uint) (err error) {
+	// get tokoID (tokoID is the same as userID) from middleware
+	tokoIDMiddleware := ctx.Locals("userID")
+	tokoID, _ := strconv.Atoi(fmt.Sprintf("%v", tokoIDMiddleware))
+
+	// parse body request
+	data := new(dto.BatasStokRendahRequest)
+	if errParse := ctx.BodyParser(data); errParse != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "Failed to PUT data",
+			Error:   []string{errParse.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+	data.ProdukID = produkID
+	data.TokoID = uint(tokoID)
+
+	// call SetBatasStokRendah from stok useCase
+	c := ctx.Context()
+	errUseCase := sc.stokUseCase.SetBatasStokRendah(c, *data)
+	if errUseCase.Err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "Failed to PUT data",
+			Error:   []string{errUseCase.Err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(errUseCase.Code).JSON(response)
+	}
+	// success response
+	response := BaseResponse{
+		Status:  true,
+		Message: "Succeed to PUT data",
+		Error:   nil,
+		Data:    "",
+	}
+	return ctx.Status(fiber.StatusOK).JSON(response)
+}
+
+func (sc *StokControllerImpl) GetProdukStokRendah(ctx *fiber.Ctx) (err error) {
+	// get tokoID (tokoID is the same as userID) from middleware
+	tokoIDMiddleware := ctx.Locals("userID")
+	tokoID, _ := strconv.Atoi(fmt.Sprintf("%v", tokoIDMiddleware))
+
+	// parse query params
+	var params dto.FilterStokRendah
+	if err := ctx.QueryParser(&params); err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "Failed to GET data",
+			Error:   []string{err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	// call GetProdukStokRendah from stok useCase
+	c := ctx.Context()
+	responseUseCase, errUseCase := sc.stokUseCase.GetProdukStokRendah(c, uint(tokoID), params)
+	if errUseCase.Err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "Failed to GET data",
+			Error:   []string{errUseCase.Err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(errUseCase.Code).JSON(response)
+	}
+	// success response
+	response := BaseResponse{
+		Status:  true,
+		Message: "Succeed to GET data",
+		Error:   nil,
+		Data:    responseUseCase,
+	}
+	return ctx.Status(fiber.StatusOK).JSON(response)
+}
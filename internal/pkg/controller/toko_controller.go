@@ -3,25 +3,33 @@ package controller
 import (
 	"fmt"
 	"github.com/gofiber/fiber/v2"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/infrastructure/gambar"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/infrastructure/storage"
 	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/pkg/dto"
 	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/pkg/usecase"
 	"github.com/valyala/fasthttp"
+	"mime/multipart"
 	"strconv"
 )
 
 type TokoController interface {
 	GetTokoByID(ctx *fiber.Ctx) (err error)
+	GetTokoBySlug(ctx *fiber.Ctx) (err error)
 	GetMyToko(ctx *fiber.Ctx) (err error)
 	GetAllToko(ctx *fiber.Ctx) (err error)
 	UpdateToko(ctx *fiber.Ctx) (err error)
+	DeleteToko(ctx *fiber.Ctx) (err error)
+	RestoreToko(ctx *fiber.Ctx) (err error)
 }
 
 type TokoControllerImpl struct {
-	tokoUseCase usecase.TokoUseCase
+	tokoUseCase    usecase.TokoUseCase
+	gambarPipeline *gambar.Pipeline
+	blobStorage    storage.BlobStorage
 }
 
-func NewTokoController(tokoUseCase usecase.TokoUseCase) TokoController {
-	return &TokoControllerImpl{tokoUseCase: tokoUseCase}
+func NewTokoController(tokoUseCase usecase.TokoUseCase, gambarPipeline *gambar.Pipeline, blobStorage storage.BlobStorage) TokoController {
+	return &TokoControllerImpl{tokoUseCase: tokoUseCase, gambarPipeline: gambarPipeline, blobStorage: blobStorage}
 }
 
 func (tc *TokoControllerImpl) GetTokoByID(ctx *fiber.Ctx) (err error) {
@@ -53,6 +61,39 @@ func (tc *TokoControllerImpl) GetTokoByID(ctx *fiber.Ctx) (err error) {
 		}
 		return ctx.Status(errUseCase.Code).JSON(response)
 	}
+	ctx.Set(fiber.HeaderETag, etagVersi(responseUseCase.Versi))
+
+	// success response
+	response := BaseResponse{
+		Status:  true,
+		Message: "Succeed to GET data",
+		Error:   nil,
+		Data:    responseUseCase,
+	}
+	return ctx.Status(fiber.StatusOK).JSON(response)
+}
+
+// GetTokoBySlug old slug is redirected to the current slug of the toko
+func (tc *TokoControllerImpl) GetTokoBySlug(ctx *fiber.Ctx) (err error) {
+	// get slug from url parameters
+	slug := ctx.Params("slug")
+
+	// call GetTokoBySlug function from toko useCase to get toko data and error information
+	c := ctx.Context()
+	responseUseCase, errUseCase := tc.tokoUseCase.GetTokoBySlug(c, slug)
+	if errUseCase.Err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "Failed to GET DATA",
+			Error:   []string{errUseCase.Err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(errUseCase.Code).JSON(response)
+	}
+	if responseUseCase.Slug != slug {
+		return alihkanSlug(ctx, slug, responseUseCase.Slug)
+	}
+	ctx.Set(fiber.HeaderETag, etagVersi(responseUseCase.Versi))
 
 	// success response
 	response := BaseResponse{
@@ -83,6 +124,7 @@ func (tc *TokoControllerImpl) GetMyToko(ctx *fiber.Ctx) (err error) {
 		}
 		return ctx.Status(errUseCase.Code).JSON(response)
 	}
+	ctx.Set(fiber.HeaderETag, etagVersi(responseUseCase.Versi))
 
 	// success response
 	response := BaseResponse{
@@ -108,10 +150,11 @@ func (tc *TokoControllerImpl) GetAllToko(ctx *fiber.Ctx) (err error) {
 	}
 	// call GetAllToko from toko useCase to get all toko and error information
 	c := ctx.Context()
-	responseUseCase, errUseCase := tc.tokoUseCase.GetAllToko(c, dto.TokoFilter{
-		Limit: filter.Limit,
-		Page:  filter.Page,
-		Nama:  filter.Nama,
+	responseUseCase, nextCursor, errUseCase := tc.tokoUseCase.GetAllToko(c, dto.TokoFilter{
+		Limit:  filter.Limit,
+		Page:   filter.Page,
+		Cursor: filter.Cursor,
+		Nama:   filter.Nama,
 	})
 	// error checking
 	if errUseCase.Err != nil {
@@ -123,12 +166,24 @@ func (tc *TokoControllerImpl) GetAllToko(ctx *fiber.Ctx) (err error) {
 		}
 		return ctx.Status(errUseCase.Code).JSON(response)
 	}
+	// page mode keep returning list of toko, cursor mode (cursor query exist, empty for first page) wrap it with next cursor
+	var data interface{} = responseUseCase
+	if ctx.Context().QueryArgs().Has("cursor") {
+		type DataToko struct {
+			Data       []dto.GetAllTokoResponse `json:"data"`
+			NextCursor string                   `json:"next_cursor,omitempty"`
+		}
+		data = DataToko{
+			Data:       responseUseCase,
+			NextCursor: nextCursor,
+		}
+	}
 	// success response
 	response := BaseResponse{
 		Status:  true,
 		Message: "Succeed to GET data",
 		Error:   nil,
-		Data:    responseUseCase,
+		Data:    data,
 	}
 	return ctx.Status(fiber.StatusOK).JSON(response)
 
@@ -141,6 +196,18 @@ func (tc *TokoControllerImpl) UpdateToko(ctx *fiber.Ctx) (err error) {
 	getLocalContext := ctx.Locals("userID")
 	userID, _ = strconv.Atoi(fmt.Sprintf("%v", getLocalContext))
 
+	// versi of toko read by the client, checked before logo is saved
+	versi, errVersi := versiIfMatch(ctx)
+	if errVersi.Err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "Failed to POST data",
+			Error:   []string{errVersi.Err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(errVersi.Code).JSON(response)
+	}
+
 	// get new nama toko from user input
 	namaToko := ctx.FormValue("nama_toko")
 	// get photo file
@@ -161,24 +228,42 @@ func (tc *TokoControllerImpl) UpdateToko(ctx *fiber.Ctx) (err error) {
 		}
 	}
 	if file != nil {
-		filename = file.Filename
-		if errSaveFile := ctx.SaveFile(file, fmt.Sprintf("./public/images/toko/%s", filename)); errSaveFile != nil {
+		// logo is saved only for toko that is not changed since the client read it
+		toko, errToko := tc.tokoUseCase.GetTokoByUserID(ctx.Context(), uint(userID))
+		if errToko.Err == nil {
+			errToko = periksaVersi(versi, toko.Versi)
+		}
+		if errToko.Err != nil {
+			response := BaseResponse{
+				Status:  false,
+				Message: "Failed to POST data",
+				Error:   []string{errToko.Err.Error()},
+				Data:    nil,
+			}
+			return ctx.Status(errToko.Code).JSON(response)
+		}
+		// validate, re-encode and save logo to blob storage, medium rendition is used as logo
+		photos, code, errFoto := simpanGambar(ctx.Context(), tc.gambarPipeline, tc.blobStorage, []*multipart.FileHeader{file}, PrefixLogoToko)
+		if errFoto != nil {
 			response := BaseResponse{
 				Status:  false,
 				Message: "Failed to POST data",
-				Error:   []string{errSaveFile.Error()},
+				Error:   []string{errFoto.Error()},
 				Data:    nil,
 			}
-			return ctx.Status(fiber.StatusInternalServerError).JSON(response)
+			return ctx.Status(code).JSON(response)
 		}
+		filename = photos[0].URLMedium
 	}
 
 	// call UpdateToko from toko useCase to update toko record and get error information
 	c := ctx.Context()
-	if errUseCase := tc.tokoUseCase.UpdateToko(c, uint(userID), dto.UpdateTokoRequest{
+	versiBaru, errUseCase := tc.tokoUseCase.UpdateToko(c, uint(userID), dto.UpdateTokoRequest{
 		NamaToko: namaToko,
 		Photo:    filename,
-	}); errUseCase.Err != nil {
+		Versi:    versi,
+	})
+	if errUseCase.Err != nil {
 		response := BaseResponse{
 			Status:  true,
 			Message: "Failed to POST data",
@@ -187,6 +272,7 @@ func (tc *TokoControllerImpl) UpdateToko(ctx *fiber.Ctx) (err error) {
 		}
 		return ctx.Status(errUseCase.Code).JSON(response)
 	}
+	ctx.Set(fiber.HeaderETag, etagVersi(versiBaru))
 	response := BaseResponse{
 		Status:  true,
 		Message: "Succeed to UPDATE data",
@@ -195,3 +281,59 @@ func (tc *TokoControllerImpl) UpdateToko(ctx *fiber.Ctx) (err error) {
 	}
 	return ctx.Status(fiber.StatusOK).JSON(response)
 }
+
+func (tc *TokoControllerImpl) DeleteToko(ctx *fiber.Ctx) (err error) {
+	// get userID from middleware
+	userIDMiddleware := ctx.Locals("userID")
+	userID, _ := strconv.Atoi(fmt.Sprintf("%v", userIDMiddleware))
+
+	// call DeleteToko function from toko useCase
+	c := ctx.Context()
+	errUseCase := tc.tokoUseCase.DeleteToko(c, uint(userID))
+	if errUseCase.Err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "Failed to DELETE data",
+			Error:   []string{errUseCase.Err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(errUseCase.Code).JSON(response)
+	}
+
+	// success response
+	response := BaseResponse{
+		Status:  true,
+		Message: "Succeed to DELETE data",
+		Error:   nil,
+		Data:    "",
+	}
+	return ctx.Status(fiber.StatusOK).JSON(response)
+}
+
+func (tc *TokoControllerImpl) RestoreToko(ctx *fiber.Ctx) (err error) {
+	// get userID from middleware
+	userIDMiddleware := ctx.Locals("userID")
+	userID, _ := strconv.Atoi(fmt.Sprintf("%v", userIDMiddleware))
+
+	// call RestoreToko function from toko useCase
+	c := ctx.Context()
+	errUseCase := tc.tokoUseCase.RestoreToko(c, uint(userID))
+	if errUseCase.Err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "Failed to PUT data",
+			Error:   []string{errUseCase.Err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(errUseCase.Code).JSON(response)
+	}
+
+	// success response
+	response := BaseResponse{
+		Status:  true,
+		Message: "Succeed to PUT data",
+		Error:   nil,
+		Data:    "",
+	}
+	return ctx.Status(fiber.StatusOK).JSON(response)
+}
@@ -12,6 +12,8 @@ type TRXController interface {
 	GetALlTRX(ctx *fiber.Ctx) (err error)
 	GetTRXByID(ctx *fiber.Ctx) (err error)
 	CreateTRX(ctx *fiber.Ctx) (err error)
+	KonfirmasiPembayaran(ctx *fiber.Ctx) (err error)
+	KirimTRX(ctx *fiber.Ctx) (err error)
 }
 
 type TRXControllerImpl struct {
@@ -38,7 +40,7 @@ func (trxc *TRXControllerImpl) GetALlTRX(ctx *fiber.Ctx) (err error) {
 	}
 	// call GetTRXByID from trx useCase
 	c := ctx.Context()
-	trxUsecase, errUseCase := trxc.trxUseCase.GetAllTRX(c, uint(userID), *params)
+	trxUsecase, nextCursor, errUseCase := trxc.trxUseCase.GetAllTRX(c, uint(userID), *params)
 	if errUseCase.Err != nil {
 		response := BaseResponse{
 			Status:  false,
@@ -49,14 +51,15 @@ func (trxc *TRXControllerImpl) GetALlTRX(ctx *fiber.Ctx) (err error) {
 		return ctx.Status(errUseCase.Code).JSON(response)
 	}
 	type listTRXResponse struct {
-		Data []dto.TRXGetResponse `json:"data"`
+		Data       []dto.TRXGetResponse `json:"data"`
+		NextCursor string               `json:"next_cursor,omitempty"`
 	}
 	// success response
 	response := BaseResponse{
 		Status:  true,
 		Message: "Succeed to GET data",
 		Error:   nil,
-		Data:    listTRXResponse{Data: trxUsecase},
+		Data:    listTRXResponse{Data: trxUsecase, NextCursor: nextCursor},
 	}
 	return ctx.Status(fiber.StatusOK).JSON(response)
 }
@@ -138,3 +141,86 @@ func (trxc *TRXControllerImpl) CreateTRX(ctx *fiber.Ctx) (err error) {
 	}
 	return ctx.Status(fiber.StatusOK).JSON(response)
 }
+
+func (trxc *TRXControllerImpl) KonfirmasiPembayaran(ctx *fiber.Ctx) (err error) {
+	// get id trx from url parameter
+	getParam := ctx.Params("id")
+	IDParam, errParam := strconv.Atoi(getParam)
+	if errParam != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "ID must integer > 0",
+			Error:   []string{errParam.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+	// call KonfirmasiPembayaran from trx useCase
+	c := ctx.Context()
+	if errUseCase := trxc.trxUseCase.KonfirmasiPembayaran(c, uint(IDParam)); errUseCase.Err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "Failed to PUT data",
+			Error:   []string{errUseCase.Err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(errUseCase.Code).JSON(response)
+	}
+	// success response
+	response := BaseResponse{
+		Status:  true,
+		Message: "Succeed to PUT data",
+		Error:   nil,
+		Data:    "",
+	}
+	return ctx.Status(fiber.StatusOK).JSON(response)
+}
+
+func (trxc *TRXControllerImpl) KirimTRX(ctx *fiber.Ctx) (err error) {
+	// get user id from middleware
+	userIDMiddleware := ctx.Locals("userID")
+	userID, _ := strconv.Atoi(fmt.Sprintf("%v", userIDMiddleware))
+
+	// get id trx from url parameter
+	getParam := ctx.Params("id")
+	IDParam, errParam := strconv.Atoi(getParam)
+	if errParam != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "ID must integer > 0",
+			Error:   []string{errParam.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+	data := new(dto.PengirimanTRX)
+	// get user input
+	if err = ctx.BodyParser(data); err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "Failed to PUT data",
+			Error:   []string{err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+	// call KirimTRX from trx useCase
+	c := ctx.Context()
+	if errUseCase := trxc.trxUseCase.KirimTRX(c, uint(userID), uint(IDParam), *data); errUseCase.Err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "Failed to PUT data",
+			Error:   []string{errUseCase.Err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(errUseCase.Code).JSON(response)
+	}
+	// success response
+	response := BaseResponse{
+		Status:  true,
+		Message: "Succeed to PUT data",
+		Error:   nil,
+		Data:    "",
+	}
+	return ctx.Status(fiber.StatusOK).JSON(response)
+}
@@ -17,6 +17,8 @@ type UserController interface {
 	Login(ctx *fiber.Ctx) (err error)
 	GetMyProfile(ctx *fiber.Ctx) (err error)
 	UpdateProfile(ctx *fiber.Ctx) (err error)
+	MintaResetKataSandi(ctx *fiber.Ctx) (err error)
+	ResetKataSandi(ctx *fiber.Ctx) (err error)
 }
 
 type UserControllerImpl struct {
@@ -306,3 +308,75 @@ func (uc *UserControllerImpl) UpdateProfile(ctx *fiber.Ctx) (err error) {
 	}
 	return ctx.Status(fiber.StatusOK).JSON(response)
 }
+
+func (uc *UserControllerImpl) MintaResetKataSandi(ctx *fiber.Ctx) (err error) {
+	c := ctx.Context()
+
+	data := new(dto.MintaResetKataSandi)
+
+	// get user input
+	if err = ctx.BodyParser(data); err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "Failed to POST data",
+			Error:   []string{err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	// call MintaResetKataSandi from user useCase to send reset token
+	if errUseCase := uc.userUseCase.MintaResetKataSandi(c, *data); errUseCase.Err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "Failed to POST data",
+			Error:   []string{errUseCase.Err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(errUseCase.Code).JSON(response)
+	}
+	// success response
+	response := BaseResponse{
+		Status:  true,
+		Message: "Succeed to POST data",
+		Error:   nil,
+		Data:    "Reset kata sandi dikirim jika email terdaftar",
+	}
+	return ctx.Status(fiber.StatusOK).JSON(response)
+}
+
+func (uc *UserControllerImpl) ResetKataSandi(ctx *fiber.Ctx) (err error) {
+	c := ctx.Context()
+
+	data := new(dto.ResetKataSandi)
+
+	// get user input
+	if err = ctx.BodyParser(data); err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "Failed to POST data",
+			Error:   []string{err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	// call ResetKataSandi from user useCase to save new kata sandi
+	if errUseCase := uc.userUseCase.ResetKataSandi(c, *data); errUseCase.Err != nil {
+		response := BaseResponse{
+			Status:  false,
+			Message: "Failed to POST data",
+			Error:   []string{errUseCase.Err.Error()},
+			Data:    nil,
+		}
+		return ctx.Status(errUseCase.Code).JSON(response)
+	}
+	// success response
+	response := BaseResponse{
+		Status:  true,
+		Message: "Succeed to POST data",
+		Error:   nil,
+		Data:    "Reset kata sandi berhasil",
+	}
+	return ctx.Status(fiber.StatusOK).JSON(response)
+}
@@ -0,0 +1,61 @@
+package controller
+
+import (
+	"errors"
+	"github.com/gofiber/fiber/v2"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/helper"
+	"strconv"
+	"strings"
+)
+
+// etagVersi strong entity tag of versi of produk or toko
+func etagVersi(versi uint) string {
+	return `"` + strconv.FormatUint(uint64(versi), 10) + `"`
+}
+
+// versiIfMatch versi the client read before updating produk or toko, taken from If-Match header.
+// Update without If-Match is rejected so concurrent update is not overwritten silently, * update any versi.
+func versiIfMatch(ctx *fiber.Ctx) (versi uint, errHelper *helper.ErrorStruct) {
+	header := strings.TrimSpace(ctx.Get(fiber.HeaderIfMatch))
+	if header == "" {
+		errHelper = &helper.ErrorStruct{
+			Err:  errors.New("If-Match header with ETag from GET is required"),
+			Code: fiber.StatusPreconditionRequired,
+		}
+		return versi, errHelper
+	}
+	if header != "*" {
+		// weak entity tag never match in If-Match
+		nilai, err := strconv.ParseUint(strings.TrimPrefix(strings.TrimSuffix(header, `"`), `"`), 10, 32)
+		if err != nil || nilai == 0 || !strings.HasPrefix(header, `"`) {
+			errHelper = &helper.ErrorStruct{
+				Err:  errors.New("If-Match header does not match ETag of the data"),
+				Code: fiber.StatusPreconditionFailed,
+			}
+			return versi, errHelper
+		}
+		versi = uint(nilai)
+	}
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: fiber.StatusOK,
+	}
+	return versi, errHelper
+}
+
+// periksaVersi compare versi from If-Match with versi of the data before uploaded file is saved,
+// so rejected update does not leave file behind. The update check it again inside its transaction.
+func periksaVersi(versi, versiData uint) (errHelper *helper.ErrorStruct) {
+	if versi != 0 && versi != versiData {
+		errHelper = &helper.ErrorStruct{
+			Err:  errors.New("data has been changed since it was read, get the latest data and try again"),
+			Code: fiber.StatusPreconditionFailed,
+		}
+		return errHelper
+	}
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: fiber.StatusOK,
+	}
+	return errHelper
+}
@@ -0,0 +1,69 @@
+package controller
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestVersiIfMatch(t *testing.T) {
+	listKasus := []struct {
+		nama       string
+		header     string
+		harapVersi uint
+		harapKode  int
+	}{
+		{"tanpa header", "", 0, fiber.StatusPreconditionRequired},
+		{"bintang", "*", 0, fiber.StatusOK},
+		{"etag kuat", `"3"`, 3, fiber.StatusOK},
+		{"etag dengan spasi", ` "12" `, 12, fiber.StatusOK},
+		{"etag lemah", `W/"3"`, 0, fiber.StatusPreconditionFailed},
+		{"tanpa kutip", "3", 0, fiber.StatusPreconditionFailed},
+		{"bukan angka", `"abc"`, 0, fiber.StatusPreconditionFailed},
+		{"versi nol", `"0"`, 0, fiber.StatusPreconditionFailed},
+		{"negatif", `"-1"`, 0, fiber.StatusPreconditionFailed},
+		{"terlalu besar", `"99999999999"`, 0, fiber.StatusPreconditionFailed},
+	}
+	for _, kasus := range listKasus {
+		app := fiber.New()
+		var versi uint
+		var kode int
+		app.Put("/", func(ctx *fiber.Ctx) error {
+			hasil, errHelper := versiIfMatch(ctx)
+			versi, kode = hasil, errHelper.Code
+			return nil
+		})
+		request := httptest.NewRequest(fiber.MethodPut, "/", nil)
+		if kasus.header != "" {
+			request.Header.Set(fiber.HeaderIfMatch, kasus.header)
+		}
+		if _, err := app.Test(request); err != nil {
+			t.Fatal(err)
+		}
+		if versi != kasus.harapVersi || kode != kasus.harapKode {
+			t.Errorf("%s: expected versi %d code %d, got %d %d", kasus.nama, kasus.harapVersi, kasus.harapKode, versi, kode)
+		}
+	}
+}
+
+func TestPeriksaVersi(t *testing.T) {
+	listKasus := []struct {
+		versi     uint
+		versiData uint
+		harapKode int
+	}{
+		{0, 5, fiber.StatusOK},
+		{5, 5, fiber.StatusOK},
+		{4, 5, fiber.StatusPreconditionFailed},
+		{6, 5, fiber.StatusPreconditionFailed},
+	}
+	for _, kasus := range listKasus {
+		if errHelper := periksaVersi(kasus.versi, kasus.versiData); errHelper.Code != kasus.harapKode {
+			t.Errorf("versi %d data %d: expected code %d, got %d", kasus.versi, kasus.versiData, kasus.harapKode, errHelper.Code)
+		}
+	}
+	if etag := etagVersi(7); etag != `"7"` {
+		t.Errorf("unexpected etag %s", etag)
+	}
+}
@@ -9,6 +9,7 @@ type Alamat struct {
 	DetailAlamat string `json:"detail_alamat" validate:"required"`
 	CreatedAt    string `json:"created_at,omitempty"`
 	UpdatedAt    string `json:"updated_at,omitempty"`
+	DeletedAt    string `json:"deleted_at,omitempty"`
 }
 
 // request struct
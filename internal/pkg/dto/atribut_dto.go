@@ -0,0 +1,50 @@
+package dto
+
+// AtributCategoryRequest opsi is required for enum attribute only, kode and tipe are not changed by update
+type AtributCategoryRequest struct {
+	ID         uint     `json:"-"`
+	CategoryID uint     `json:"-"`
+	Kode       string   `json:"kode" validate:"required,max=50"`
+	Nama       string   `json:"nama" validate:"required,max=100"`
+	Tipe       string   `json:"tipe" validate:"required,oneof=string number enum boolean"`
+	Opsi       []string `json:"opsi" validate:"dive,required,max=255"`
+	Satuan     string   `json:"satuan" validate:"max=20"`
+	Wajib      bool     `json:"wajib"`
+	Urutan     uint     `json:"urutan"`
+}
+
+type AtributCategoryResponse struct {
+	ID         uint     `json:"id"`
+	CategoryID uint     `json:"category_id"`
+	Kode       string   `json:"kode"`
+	Nama       string   `json:"nama"`
+	Tipe       string   `json:"tipe"`
+	Opsi       []string `json:"opsi,omitempty"`
+	Satuan     string   `json:"satuan,omitempty"`
+	Wajib      bool     `json:"wajib"`
+	Urutan     uint     `json:"urutan"`
+}
+
+// AtributProdukRequest value of every attribute by its kode, attribute that is not sent or null is removed from produk
+type AtributProdukRequest struct {
+	ProdukID uint                   `json:"-"`
+	TokoID   uint                   `json:"-"`
+	Atribut  map[string]interface{} `json:"atribut"`
+}
+
+// AtributProdukResponse nilai is string, number or boolean following tipe
+type AtributProdukResponse struct {
+	Kode   string      `json:"kode"`
+	Nama   string      `json:"nama"`
+	Tipe   string      `json:"tipe"`
+	Nilai  interface{} `json:"nilai"`
+	Satuan string      `json:"satuan,omitempty"`
+}
+
+// FilterAtribut parsed from query attr.<kode>=nilai1,nilai2 and attr.<kode>.min / attr.<kode>.max
+type FilterAtribut struct {
+	Kode  string
+	Nilai []string
+	Min   *float64
+	Max   *float64
+}
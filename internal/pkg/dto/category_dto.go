@@ -1,8 +1,12 @@
 package dto
 
+import "time"
+
 type CategoryWithID struct {
 	ID           uint   `json:"id"`
 	NamaCategory string `json:"nama_category"`
+	// DeletedAt only filled in trash listing
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
 }
 
 type CategoryIDOnly struct {
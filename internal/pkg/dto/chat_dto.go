@@ -0,0 +1,62 @@
+package dto
+
+type CreatePercakapanRequest struct {
+	TokoID   uint   `json:"toko_id" validate:"required"`
+	ProdukID uint   `json:"produk_id"`
+	TRXID    uint   `json:"trx_id"`
+	IsiPesan string `json:"isi_pesan"`
+}
+
+type CreatePesanRequest struct {
+	PercakapanID uint   `json:"percakapan_id"`
+	IsiPesan     string `json:"isi_pesan" validate:"required"`
+}
+
+type UserChat struct {
+	ID   uint   `json:"id"`
+	Nama string `json:"nama"`
+}
+
+type PercakapanResponse struct {
+	ID              uint                `json:"id"`
+	Peran           string              `json:"peran"`
+	User            UserChat            `json:"user"`
+	Toko            GetTokoByIDResponse `json:"toko"`
+	ProdukID        uint                `json:"produk_id,omitempty"`
+	TRXID           uint                `json:"trx_id,omitempty"`
+	PesanTerakhir   string              `json:"pesan_terakhir"`
+	PesanTerakhirAt string              `json:"pesan_terakhir_at,omitempty"`
+	Unread          uint                `json:"unread"`
+}
+
+type PesanResponse struct {
+	ID           uint   `json:"id"`
+	PercakapanID uint   `json:"percakapan_id"`
+	PengirimID   uint   `json:"pengirim_id"`
+	PenerimaID   uint   `json:"-"`
+	DariToko     bool   `json:"dari_toko"`
+	IsiPesan     string `json:"isi_pesan"`
+	IsRead       bool   `json:"is_read"`
+	CreatedAt    string `json:"created_at"`
+}
+
+type ChatUnreadResponse struct {
+	Total uint `json:"total"`
+}
+
+type FilterPercakapan struct {
+	Limit int `query:"limit"`
+	Page  int `query:"page"`
+}
+
+type FilterPesan struct {
+	Limit    int  `query:"limit"`
+	BeforeID uint `query:"before_id"`
+}
+
+// ChatEvent message pushed to websocket client
+type ChatEvent struct {
+	Event string      `json:"event"`
+	Data  interface{} `json:"data,omitempty"`
+	Error string      `json:"error,omitempty"`
+}
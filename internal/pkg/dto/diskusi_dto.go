@@ -0,0 +1,62 @@
+package dto
+
+// DiskusiRequest question on produk or answer of a question
+type DiskusiRequest struct {
+	ProdukID     uint   `json:"-"`
+	PertanyaanID uint   `json:"-"`
+	UserID       uint   `json:"-"`
+	Isi          string `json:"isi" validate:"required,max=1000"`
+}
+
+// JawabanResponse membantu is true when the answer is voted helpful by logged in user
+type JawabanResponse struct {
+	ID             uint     `json:"id"`
+	PertanyaanID   uint     `json:"pertanyaan_id"`
+	User           UserChat `json:"user"`
+	DariToko       bool     `json:"dari_toko"`
+	Isi            string   `json:"isi"`
+	JumlahMembantu uint     `json:"jumlah_membantu"`
+	Membantu       bool     `json:"membantu"`
+	CreatedAt      string   `json:"created_at"`
+}
+
+type PertanyaanResponse struct {
+	ID            uint              `json:"id"`
+	ProdukID      uint              `json:"produk_id"`
+	User          UserChat          `json:"user"`
+	Isi           string            `json:"isi"`
+	JumlahJawaban uint              `json:"jumlah_jawaban"`
+	Jawaban       []JawabanResponse `json:"jawaban"`
+	CreatedAt     string            `json:"created_at"`
+}
+
+// DiskusiModerasiResponse pertanyaan_id is null for question
+type DiskusiModerasiResponse struct {
+	ID             uint     `json:"id"`
+	ProdukID       uint     `json:"produk_id"`
+	PertanyaanID   *uint    `json:"pertanyaan_id"`
+	User           UserChat `json:"user"`
+	Isi            string   `json:"isi"`
+	StatusModerasi string   `json:"status_moderasi"`
+	AlasanModerasi string   `json:"alasan_moderasi,omitempty"`
+	JumlahLaporan  uint     `json:"jumlah_laporan"`
+	CreatedAt      string   `json:"created_at"`
+}
+
+type FilterDiskusi struct {
+	Limit int `query:"limit"`
+	Page  int `query:"page"`
+}
+
+// FilterModerasiDiskusi reported question and answer are listed when status is empty
+type FilterModerasiDiskusi struct {
+	Limit  int    `query:"limit"`
+	Page   int    `query:"page"`
+	Status string `query:"status" validate:"omitempty,oneof=approved rejected"`
+}
+
+type ModerasiDiskusiRequest struct {
+	DiskusiID uint   `json:"-"`
+	Status    string `json:"status" validate:"required,oneof=approved rejected"`
+	Alasan    string `json:"alasan" validate:"max=255"`
+}
@@ -0,0 +1,29 @@
+package dto
+
+import "io"
+
+type FilterEkspor struct {
+	Format string `query:"format"`
+}
+
+// EksporProduk one produk in json export, field name is the same as column of import file
+type EksporProduk struct {
+	ID            uint     `json:"id"`
+	NamaProduk    string   `json:"nama_produk"`
+	Category      string   `json:"category"`
+	HargaReseller uint     `json:"harga_reseller"`
+	HargaKonsumen uint     `json:"harga_konsumen"`
+	Stok          uint     `json:"stok"`
+	Deskripsi     string   `json:"deskripsi"`
+	Foto          []string `json:"foto"`
+	Versi         uint     `json:"versi"`
+	TokoID        uint     `json:"toko_id,omitempty"`
+	NamaToko      string   `json:"nama_toko,omitempty"`
+}
+
+// FileEkspor export file written by Tulis while the response is streamed
+type FileEkspor struct {
+	NamaFile    string
+	ContentType string
+	Tulis       func(w io.Writer) error
+}
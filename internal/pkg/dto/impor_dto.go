@@ -0,0 +1,36 @@
+package dto
+
+// ImporProdukRequest uploaded csv or xlsx file, Zip is optional archive of foto referred by file name in foto column
+type ImporProdukRequest struct {
+	TokoID   uint
+	NamaFile string
+	Data     []byte
+	Zip      []byte
+}
+
+type ImporProdukResponse struct {
+	ID            uint   `json:"id"`
+	NamaFile      string `json:"nama_file"`
+	Format        string `json:"format"`
+	Status        string `json:"status"`
+	TotalBaris    int    `json:"total_baris"`
+	BarisDiproses int    `json:"baris_diproses"`
+	Progres       int    `json:"progres"`
+	Berhasil      int    `json:"berhasil"`
+	Gagal         int    `json:"gagal"`
+	Pesan         string `json:"pesan,omitempty"`
+	CreatedAt     string `json:"created_at"`
+	SelesaiAt     string `json:"selesai_at,omitempty"`
+}
+
+// BarisImporGagalResponse rejected row with its original cell
+type BarisImporGagalResponse struct {
+	NomorBaris int      `json:"nomor_baris"`
+	Data       []string `json:"data"`
+	Pesan      string   `json:"pesan"`
+}
+
+type FilterImpor struct {
+	Limit int `query:"limit"`
+	Page  int `query:"page"`
+}
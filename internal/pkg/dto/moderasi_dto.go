@@ -0,0 +1,30 @@
+package dto
+
+// ModerasiRequest alasan is required when produk is rejected so seller know what to fix
+type ModerasiRequest struct {
+	ProdukID uint   `json:"-"`
+	AdminID  uint   `json:"-"`
+	Status   string `json:"status" validate:"required,oneof=approved rejected"`
+	Alasan   string `json:"alasan" validate:"max=255"`
+}
+
+type FilterModerasi struct {
+	Limit  int    `query:"limit"`
+	Page   int    `query:"page"`
+	Status string `query:"status" validate:"omitempty,oneof=pending approved rejected"`
+	TokoID uint   `query:"toko_id"`
+}
+
+// RiwayatModerasiResponse admin_id is null for status given by the system
+type RiwayatModerasiResponse struct {
+	ID        uint   `json:"id"`
+	ProdukID  uint   `json:"produk_id"`
+	Status    string `json:"status"`
+	Alasan    string `json:"alasan"`
+	AdminID   *uint  `json:"admin_id"`
+	CreatedAt string `json:"created_at"`
+}
+
+type TokoTerpercayaRequest struct {
+	Terpercaya *bool `json:"terpercaya" validate:"required"`
+}
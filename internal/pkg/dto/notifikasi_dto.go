@@ -0,0 +1,28 @@
+package dto
+
+import "encoding/json"
+
+type NotifikasiResponse struct {
+	ID        uint            `json:"id"`
+	Tipe      string          `json:"tipe"`
+	Judul     string          `json:"judul"`
+	Pesan     string          `json:"pesan"`
+	Payload   json.RawMessage `json:"payload,omitempty"`
+	RefTipe   string          `json:"ref_tipe,omitempty"`
+	RefID     uint            `json:"ref_id,omitempty"`
+	IsRead    bool            `json:"is_read"`
+	ReadAt    string          `json:"read_at,omitempty"`
+	CreatedAt string          `json:"created_at"`
+}
+
+type NotifikasiUnreadResponse struct {
+	Total   uint            `json:"total"`
+	PerTipe map[string]uint `json:"per_tipe"`
+}
+
+type FilterNotifikasi struct {
+	Limit  int    `query:"limit"`
+	Page   int    `query:"page"`
+	Tipe   string `query:"tipe"`
+	Unread bool   `query:"unread"`
+}
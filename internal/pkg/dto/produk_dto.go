@@ -1,5 +1,7 @@
 package dto
 
+import "time"
+
 type UploadProdukRequest struct {
 	NamaProduk    string `validate:"reuqired"`
 	CategoryID    uint   `validate:"reuqired"`
@@ -9,28 +11,113 @@ type UploadProdukRequest struct {
 	Stok          uint   `validate:"reuqired"`
 	Deskripsi     string `validate:"reuqired"`
 	Photos        []Photos
+	// StatusPublikasi empty is published, JadwalTerbit is only for published produk
+	StatusPublikasi string
+	JadwalTerbit    *time.Time
 }
 
 type Photos struct {
-	URL string
+	URL          string
+	URLThumbnail string
+	URLMedium    string
+	URLLarge     string
 }
 type FotoProdukGetProduk struct {
-	ID       uint   `json:"id"`
-	ProdukID uint   `json:"produk_id"`
-	URL      string `json:"url"`
+	ID           uint   `json:"id"`
+	ProdukID     uint   `json:"produk_id"`
+	SKUID        uint   `json:"sku_id,omitempty"`
+	URL          string `json:"url"`
+	URLThumbnail string `json:"url_thumbnail,omitempty"`
+	URLMedium    string `json:"url_medium,omitempty"`
+	URLLarge     string `json:"url_large,omitempty"`
+	Urutan       int    `json:"urutan"`
+	Utama        bool   `json:"utama"`
+	// DeletedAt only filled in trash listing
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+}
+
+// UrutanFotoRequest every foto id of produk in the new order
+type UrutanFotoRequest struct {
+	ProdukID uint   `json:"-"`
+	TokoID   uint   `json:"-"`
+	ListID   []uint `json:"urutan" validate:"required,min=1,dive,gt=0"`
 }
 
 type GetProduk struct {
+	ID            uint                    `json:"id"`
+	NamaProduk    string                  `json:"nama_produk"`
+	Slug          string                  `json:"slug"`
+	HargaReseller uint                    `json:"harga_reseller"`
+	HargaKonsumen uint                    `json:"harga_konsumen"`
+	Stok          uint                    `json:"stok"`
+	Deskripsi     string                  `json:"deskripsi"`
+	Toko          GetTokoByIDResponse     `json:"toko"`
+	Category      CategoryWithID          `json:"category"`
+	FotoProduk    []FotoProdukGetProduk   `json:"foto_produk"`
+	OpsiVarian    []OpsiVarianResponse    `json:"opsi_varian,omitempty"`
+	SKU           []SKUResponse           `json:"sku,omitempty"`
+	Atribut       []AtributProdukResponse `json:"atribut,omitempty"`
+	Highlight     *HighlightProduk        `json:"highlight,omitempty"`
+	Promo         *PromoProdukResponse    `json:"promo,omitempty"`
+	Statistik     *StatistikProduk        `json:"statistik,omitempty"`
+	// StatusModerasi pending and rejected produk is only shown to its toko and admin
+	StatusModerasi string `json:"status_moderasi,omitempty"`
+	AlasanModerasi string `json:"alasan_moderasi,omitempty"`
+	// StatusPublikasi draft and archived produk is only shown to its toko, published produk is hidden until jadwal_terbit
+	StatusPublikasi string     `json:"status_publikasi,omitempty"`
+	JadwalTerbit    *time.Time `json:"jadwal_terbit,omitempty"`
+	// DeletedAt only filled in trash listing
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	// Versi is also sent as ETag of produk detail, send it back in If-Match header to update the produk
+	Versi uint `json:"versi,omitempty"`
+}
+
+// HighlightProduk matched word of search query wrapped with <em> tag
+type HighlightProduk struct {
+	NamaProduk string `json:"nama_produk"`
+	Deskripsi  string `json:"deskripsi"`
+}
+
+type OpsiVarianRequest struct {
+	Nama  string   `json:"nama" validate:"required"`
+	Nilai []string `json:"nilai" validate:"required,min=1,dive,required"`
+}
+
+type SKURequest struct {
+	ID            uint              `json:"id"`
+	KodeSKU       string            `json:"kode_sku" validate:"required"`
+	Varian        map[string]string `json:"varian" validate:"required"`
+	HargaReseller uint              `json:"harga_reseller"`
+	HargaKonsumen uint              `json:"harga_konsumen"`
+	Stok          uint              `json:"stok"`
+}
+
+type UpdateVarianRequest struct {
+	ProdukID   uint                `json:"-"`
+	TokoID     uint                `json:"-"`
+	OpsiVarian []OpsiVarianRequest `json:"opsi_varian" validate:"dive"`
+	SKU        []SKURequest        `json:"sku" validate:"dive"`
+	// stok of produk when it has no sku, nil keeps the current stok
+	Stok *uint `json:"stok"`
+}
+
+type OpsiVarianResponse struct {
+	ID    uint     `json:"id"`
+	Nama  string   `json:"nama"`
+	Nilai []string `json:"nilai"`
+}
+
+type SKUResponse struct {
 	ID            uint                  `json:"id"`
-	NamaProduk    string                `json:"nama_produk"`
-	Slug          string                `json:"slug"`
+	KodeSKU       string                `json:"kode_sku"`
+	Varian        map[string]string     `json:"varian"`
 	HargaReseller uint                  `json:"harga_reseller"`
 	HargaKonsumen uint                  `json:"harga_konsumen"`
 	Stok          uint                  `json:"stok"`
-	Deskripsi     string                `json:"deskripsi"`
-	Toko          GetTokoByIDResponse   `json:"toko"`
-	Category      CategoryWithID        `json:"category"`
 	FotoProduk    []FotoProdukGetProduk `json:"foto_produk"`
+	// HargaResellerNormal and HargaKonsumenNormal price of sku outside promo, only filled when produk is in promo
+	HargaResellerNormal uint `json:"harga_reseller_normal,omitempty"`
+	HargaKonsumenNormal uint `json:"harga_konsumen_normal,omitempty"`
 }
 
 type UpdateProdukRequest struct {
@@ -43,14 +130,75 @@ type UpdateProdukRequest struct {
 	Stok          uint   `validate:"reuqired"`
 	Deskripsi     string `validate:"reuqired"`
 	Photos        []Photos
+	// Versi from If-Match header, zero update any versi
+	Versi uint
+}
+
+// PublikasiRequest jadwal_terbit schedule published produk, null publish it right away
+type PublikasiRequest struct {
+	ProdukID        uint       `json:"-"`
+	TokoID          uint       `json:"-"`
+	StatusPublikasi string     `json:"status_publikasi" validate:"required,oneof=draft published archived"`
+	JadwalTerbit    *time.Time `json:"jadwal_terbit"`
+}
+
+// FilterProdukToko filter produk of the logged in toko
+type FilterProdukToko struct {
+	Limit           int    `query:"limit"`
+	Page            int    `query:"page"`
+	NamaProduk      string `query:"nama_produk"`
+	StatusPublikasi string `query:"status_publikasi" validate:"omitempty,oneof=draft published archived"`
+	StatusModerasi  string `query:"status_moderasi" validate:"omitempty,oneof=pending approved rejected"`
 }
 
 type FilterProduk struct {
 	Limit      int    `query:"limit"`
 	Page       int    `query:"page"`
+	Cursor     string `query:"cursor"`
 	NamaProduk string `query:"nama_produk"`
 	CategoryID uint   `query:"category_id"`
 	TokoID     uint   `query:"toko_id"`
-	MaxHarga   uint   `query:"max_harga"`
+	MaxHarga   uint   `query:"max_harga" validate:"omitempty,gtefield=MinHarga"`
 	MinHarga   uint   `query:"min_harga"`
+	Tersedia   bool   `query:"tersedia"`
+	Sort       string `query:"sort" validate:"omitempty,oneof=relevansi harga_asc harga_desc terbaru terlaris"`
+	// Atribut is not parsed by query parser, it is read from attr.* query
+	Atribut []FilterAtribut `query:"-"`
+}
+
+// FacetProduk number of produk per category and per price range for current filter,
+// every facet ignore its own filter so other option stay visible
+type FacetProduk struct {
+	Category []FacetCategory `json:"category"`
+	Harga    []FacetHarga    `json:"harga"`
+}
+
+type FacetCategory struct {
+	ID           uint   `json:"id"`
+	NamaCategory string `json:"nama_category"`
+	Jumlah       uint   `json:"jumlah"`
+}
+
+// FacetHarga produk with min <= harga_konsumen < max, max 0 means no upper bound
+type FacetHarga struct {
+	Min    uint `json:"min"`
+	Max    uint `json:"max,omitempty"`
+	Jumlah uint `json:"jumlah"`
+}
+
+type FilterRekomendasi struct {
+	Limit int `query:"limit"`
+}
+
+// StatistikProduk view and sold kuantitas, trending count only the requested last days
+type StatistikProduk struct {
+	Dilihat uint `json:"dilihat"`
+	Terjual uint `json:"terjual"`
+}
+
+type FilterPopuler struct {
+	Limit      int  `query:"limit"`
+	Page       int  `query:"page"`
+	CategoryID uint `query:"category_id"`
+	Hari       int  `query:"hari" validate:"omitempty,min=1,max=30"`
 }
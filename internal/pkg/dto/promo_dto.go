@@ -0,0 +1,83 @@
+package dto
+
+import "time"
+
+const (
+	StatusPromoTerjadwal = "scheduled"
+	StatusPromoAktif     = "active"
+	StatusPromoSelesai   = "ended"
+)
+
+// ItemPromoRequest sale price of one produk, kuota and batas_per_user are only for flash sale and 0 means unlimited
+type ItemPromoRequest struct {
+	ProdukID      uint `json:"produk_id" validate:"required"`
+	HargaReseller uint `json:"harga_reseller"`
+	HargaKonsumen uint `json:"harga_konsumen" validate:"required"`
+	Kuota         uint `json:"kuota"`
+	BatasPerUser  uint `json:"batas_per_user"`
+}
+
+type CreatePromoRequest struct {
+	TokoID    uint               `json:"-"`
+	Tipe      string             `json:"tipe" validate:"required,oneof=sale flash_sale"`
+	Nama      string             `json:"nama" validate:"required,max=255"`
+	Mulai     time.Time          `json:"mulai" validate:"required"`
+	Selesai   time.Time          `json:"selesai" validate:"required,gtfield=Mulai"`
+	ItemPromo []ItemPromoRequest `json:"item" validate:"required,min=1,dive"`
+}
+
+type ItemPromoResponse struct {
+	ID            uint `json:"id"`
+	ProdukID      uint `json:"produk_id"`
+	HargaReseller uint `json:"harga_reseller"`
+	HargaKonsumen uint `json:"harga_konsumen"`
+	Kuota         uint `json:"kuota"`
+	Terjual       uint `json:"terjual"`
+	BatasPerUser  uint `json:"batas_per_user"`
+}
+
+type PromoResponse struct {
+	ID        uint                `json:"id"`
+	TokoID    uint                `json:"toko_id"`
+	Tipe      string              `json:"tipe"`
+	Nama      string              `json:"nama"`
+	Mulai     string              `json:"mulai"`
+	Selesai   string              `json:"selesai"`
+	Status    string              `json:"status"`
+	ItemPromo []ItemPromoResponse `json:"item"`
+}
+
+// PromoProdukResponse promo running on produk, harga in produk is already the sale price.
+// Sisa is remaining quota of flash sale, omitted when quota is unlimited
+type PromoProdukResponse struct {
+	ID                  uint   `json:"id"`
+	Tipe                string `json:"tipe"`
+	Nama                string `json:"nama"`
+	Selesai             string `json:"selesai"`
+	HargaResellerNormal uint   `json:"harga_reseller_normal"`
+	HargaKonsumenNormal uint   `json:"harga_konsumen_normal"`
+	Sisa                *uint  `json:"sisa,omitempty"`
+	BatasPerUser        uint   `json:"batas_per_user,omitempty"`
+}
+
+type FilterPromo struct {
+	Limit int    `query:"limit"`
+	Page  int    `query:"page"`
+	Tipe  string `query:"tipe" validate:"omitempty,oneof=sale flash_sale"`
+}
+
+// RiwayatHargaResponse berlaku_sampai is only set for price from promo
+type RiwayatHargaResponse struct {
+	ID            uint   `json:"id"`
+	HargaReseller uint   `json:"harga_reseller"`
+	HargaKonsumen uint   `json:"harga_konsumen"`
+	Sumber        string `json:"sumber"`
+	PromoID       *uint  `json:"promo_id,omitempty"`
+	BerlakuMulai  string `json:"berlaku_mulai"`
+	BerlakuSampai string `json:"berlaku_sampai,omitempty"`
+}
+
+type FilterRiwayatHarga struct {
+	Limit int `query:"limit"`
+	Page  int `query:"page"`
+}
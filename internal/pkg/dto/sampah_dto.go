@@ -0,0 +1,7 @@
+package dto
+
+// FilterSampah pagination of trash listing
+type FilterSampah struct {
+	Limit int `query:"limit"`
+	Page  int `query:"page"`
+}
@@ -0,0 +1,56 @@
+package dto
+
+// UbahStokRequest Jumlah is added to stok, negative Jumlah of adjustment reduce it.
+// Stok is counted stok that replace the current one, only for adjustment and used instead of Jumlah.
+type UbahStokRequest struct {
+	ProdukID uint   `json:"-"`
+	TokoID   uint   `json:"-"`
+	SKUID    uint   `json:"sku_id"`
+	Jumlah   int    `json:"jumlah"`
+	Stok     *uint  `json:"stok"`
+	Tipe     string `json:"tipe"`
+	Alasan   string `json:"alasan"`
+}
+
+type MutasiStokResponse struct {
+	ID          uint   `json:"id"`
+	ProdukID    uint   `json:"produk_id"`
+	SKUID       *uint  `json:"sku_id"`
+	Tipe        string `json:"tipe"`
+	Perubahan   int    `json:"perubahan"`
+	Saldo       uint   `json:"saldo"`
+	SaldoProduk uint   `json:"saldo_produk"`
+	PelakuID    *uint  `json:"pelaku_id"`
+	Alasan      string `json:"alasan"`
+	TRXID       *uint  `json:"trx_id"`
+	CreatedAt   string `json:"created_at"`
+}
+
+type FilterMutasiStok struct {
+	Limit int    `query:"limit"`
+	Page  int    `query:"page"`
+	SKUID uint   `query:"sku_id"`
+	Tipe  string `query:"tipe"`
+}
+
+// BatasStokRendahRequest null reset the threshold, produk then use threshold of the toko and toko use the default
+type BatasStokRendahRequest struct {
+	ProdukID        uint  `json:"-"`
+	TokoID          uint  `json:"-"`
+	BatasStokRendah *uint `json:"batas_stok_rendah"`
+}
+
+// StokRendahResponse BatasStokRendah is the threshold in use, BatasStokRendahProduk is null when it come from the toko
+type StokRendahResponse struct {
+	ID                    uint   `json:"id"`
+	NamaProduk            string `json:"nama_produk"`
+	Slug                  string `json:"slug"`
+	Stok                  uint   `json:"stok"`
+	BatasStokRendah       uint   `json:"batas_stok_rendah"`
+	BatasStokRendahProduk *uint  `json:"batas_stok_rendah_produk"`
+}
+
+type FilterStokRendah struct {
+	Limit int `query:"limit"`
+	Page  int `query:"page"`
+}
@@ -10,30 +10,39 @@ type GetTokoResponse struct {
 type GetTokoByUserIDResponse struct {
 	ID       uint   `json:"id"`
 	NamaToko string `json:"nama_toko"`
+	Slug     string `json:"slug"`
 	UrlFoto  string `json:"url_foto"`
 	UserID   uint   `json:"user_id"`
+	Versi    uint   `json:"versi"`
 }
 
+// GetTokoByIDResponse versi is only filled in toko detail, where it is also sent as ETag
 type GetTokoByIDResponse struct {
 	ID       uint   `json:"id"`
 	NamaToko string `json:"nama_toko"`
+	Slug     string `json:"slug"`
 	UrlFoto  string `json:"url_foto"`
+	Versi    uint   `json:"versi,omitempty"`
 }
 type TokoFilter struct {
-	Limit int    `query:"limit"`
-	Page  int    `query:"page"`
-	Nama  string `query:"nama"`
+	Limit  int    `query:"limit"`
+	Page   int    `query:"page"`
+	Cursor string `query:"cursor"`
+	Nama   string `query:"nama"`
 }
 
 type GetAllTokoResponse struct {
 	ID       uint   `json:"id"`
 	NamaToko string `json:"nama_toko"`
+	Slug     string `json:"slug"`
 	UrlFoto  string `json:"url_foto"`
 }
 
 type UpdateTokoRequest struct {
 	NamaToko string `json:"nama_toko"`
 	Photo    string `json:"photo"`
+	// Versi from If-Match header, zero update any versi
+	Versi uint `json:"-"`
 }
 
 type TokoTRX struct {
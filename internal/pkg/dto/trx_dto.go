@@ -10,16 +10,19 @@ type TRX struct {
 
 type DetailTRX struct {
 	ProductID uint `json:"product_id" validate:"required"`
+	SKUID     uint `json:"sku_id"`
 	Kuantitas uint `json:"kuantitas" validate:"required"`
 }
 
 type TRXGetResponse struct {
-	ID          uint                   `json:"id"`
-	HargaTotal  uint                   `json:"harga_total"`
-	KodeInvoice string                 `json:"kode_invoice"`
-	MethodBayar string                 `json:"method_bayar"`
-	Alamat      AlamatTRX              `json:"alamat_kirim"`
-	DetailTRX   []DetailTRXGetResponse `json:"detail_trx"`
+	ID          uint   `json:"id"`
+	HargaTotal  uint   `json:"harga_total"`
+	KodeInvoice string `json:"kode_invoice"`
+	MethodBayar string `json:"method_bayar"`
+	// StatusPembayaran pending or paid
+	StatusPembayaran string                 `json:"status_pembayaran"`
+	Alamat           AlamatTRX              `json:"alamat_kirim"`
+	DetailTRX        []DetailTRXGetResponse `json:"detail_trx"`
 }
 
 type DetailTRXGetResponse struct {
@@ -27,6 +30,12 @@ type DetailTRXGetResponse struct {
 	Toko       GetTokoByIDResponse  `json:"toko"`
 	Kuantitas  uint                 `json:"kuantitas"`
 	HargaTotal uint                 `json:"harga_total"`
+	NoResi     string               `json:"no_resi,omitempty"`
+}
+
+// PengirimanTRX resi of every detail trx of the toko shipped together
+type PengirimanTRX struct {
+	NoResi string `json:"no_resi" validate:"required,max=100"`
 }
 
 type LogProdukGetResponse struct {
@@ -37,6 +46,9 @@ type LogProdukGetResponse struct {
 	HargaReseller uint                    `json:"harga_reseller"`
 	HargaKonsumen uint                    `json:"harga_konsumen"`
 	Deskripsi     string                  `json:"deskripsi"`
+	SKUID         uint                    `json:"sku_id,omitempty"`
+	KodeSKU       string                  `json:"kode_sku,omitempty"`
+	Varian        map[string]string       `json:"varian,omitempty"`
 	Toko          TokoTRX                 `json:"toko"`
 	Category      CategoryWithID          `json:"category"`
 	Photos        []LogFotoProdukResponse `json:"photos"`
@@ -49,6 +61,7 @@ type LogFotoProdukResponse struct {
 }
 
 type FilterTRX struct {
-	Limit int `query:"limit"`
-	Page  int `query:"page"`
+	Limit  int    `query:"limit"`
+	Page   int    `query:"page"`
+	Cursor string `query:"cursor"`
 }
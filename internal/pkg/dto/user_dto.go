@@ -15,6 +15,15 @@ type UserLogin struct {
 	Notelp    string `json:"no_telp" validate:"required"`
 	KataSandi string `json:"kata_sandi" validate:"required"`
 }
+type MintaResetKataSandi struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+type ResetKataSandi struct {
+	Token     string `json:"token" validate:"required"`
+	KataSandi string `json:"kata_sandi" validate:"required"`
+}
+
 type UserUpdate struct {
 	Nama         string `json:"nama" validate:"required"`
 	KataSandi    string `json:"kata_sandi" validate:"required"`
@@ -15,6 +15,8 @@ type AlamatRepository interface {
 	GetAlamatByID(ctx context.Context, ID uint) (response daos.Alamat, errHelper *helper.ErrorStruct)
 	UpdateAlamatByID(ctx context.Context, data daos.Alamat) (errHelper *helper.ErrorStruct)
 	DeleteAlamatByID(ctx context.Context, userID, ID uint) (errHelper *helper.ErrorStruct)
+	RestoreAlamatByID(ctx context.Context, userID, ID uint) (errHelper *helper.ErrorStruct)
+	GetSampahAlamat(ctx context.Context, userID uint) (response []daos.Alamat, errHelper *helper.ErrorStruct)
 }
 
 type AlamatRepositoryImpl struct {
@@ -178,3 +180,60 @@ func (ar *AlamatRepositoryImpl) DeleteAlamatByID(ctx context.Context, userID, ID
 	}
 	return errHelper
 }
+
+func (ar *AlamatRepositoryImpl) RestoreAlamatByID(ctx context.Context, userID, ID uint) (errHelper *helper.ErrorStruct) {
+	// get gorm client
+	db := ar.db
+
+	// variable to store response from database
+	alamat := daos.Alamat{}
+
+	// bring back alamat record with specified userID and ID from trash and get error information
+	errDb := db.Unscoped().Where("user_id = ? AND id = ? AND deleted_at IS NOT NULL", userID, ID).First(&alamat).Error
+	if errDb == nil {
+		errDb = db.Unscoped().Model(&alamat).Update("deleted_at", nil).Error
+	}
+	if errDb != nil {
+		// check if error is record not found
+		if errDb == gorm.ErrRecordNotFound {
+			errHelper = &helper.ErrorStruct{
+				Err:  errors.New("alamat not found in trash"),
+				Code: http.StatusNotFound,
+			}
+			return errHelper
+		}
+		// response another error
+		errHelper = &helper.ErrorStruct{
+			Err:  errDb,
+			Code: http.StatusInternalServerError,
+		}
+		return errHelper
+	}
+
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return errHelper
+}
+
+func (ar *AlamatRepositoryImpl) GetSampahAlamat(ctx context.Context, userID uint) (response []daos.Alamat, errHelper *helper.ErrorStruct) {
+	// get gorm client
+	db := ar.db
+
+	// get deleted alamat with specified user_id from database, newest deleted first
+	if errDb := db.Unscoped().Where("user_id = ? AND deleted_at IS NOT NULL", userID).Order("deleted_at DESC, id DESC").Find(&response).Error; errDb != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errDb,
+			Code: http.StatusInternalServerError,
+		}
+		return response, errHelper
+	}
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return response, errHelper
+}
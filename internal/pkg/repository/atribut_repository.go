@@ -0,0 +1,237 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/go-sql-driver/mysql"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/daos"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/helper"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"net/http"
+)
+
+var (
+	errCategoryTidakAda = errors.New("No Data Category")
+	errAtributTidakAda  = errors.New("attribute not found")
+)
+
+type AtributRepository interface {
+	GetAtributCategory(ctx context.Context, categoryID uint) (response []daos.AtributCategory, errHelper *helper.ErrorStruct)
+	CreateAtributCategory(ctx context.Context, data daos.AtributCategory) (ID uint, errHelper *helper.ErrorStruct)
+	UpdateAtributCategory(ctx context.Context, data daos.AtributCategory) (errHelper *helper.ErrorStruct)
+	DeleteAtributCategory(ctx context.Context, categoryID, ID uint) (errHelper *helper.ErrorStruct)
+	SimpanAtributProduk(ctx context.Context, tokoID, produkID uint, listNilai map[string]interface{}) (errHelper *helper.ErrorStruct)
+}
+
+type AtributRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewAtributRepository(db *gorm.DB) AtributRepository {
+	return &AtributRepositoryImpl{db: db}
+}
+
+// urutanAtribut order attribute as arranged by admin
+func urutanAtribut(db *gorm.DB) *gorm.DB {
+	return db.Order("urutan, id")
+}
+
+// errorAtribut mapping error of attribute schema management into error response
+func errorAtribut(errDb error) *helper.ErrorStruct {
+	var mysqlErr *mysql.MySQLError
+	switch {
+	case errDb == errCategoryTidakAda || errDb == errAtributTidakAda:
+		return &helper.ErrorStruct{Err: errDb, Code: http.StatusNotFound}
+	case errDb == gorm.ErrRecordNotFound:
+		return &helper.ErrorStruct{Err: errAtributTidakAda, Code: http.StatusNotFound}
+	case errors.As(errDb, &mysqlErr) && mysqlErr.Number == 1062:
+		return &helper.ErrorStruct{Err: errors.New("kode is already used in this category"), Code: http.StatusConflict}
+	}
+	return &helper.ErrorStruct{Err: errDb, Code: http.StatusInternalServerError}
+}
+
+// cekCategory make sure category exist and is not deleted
+func cekCategory(db *gorm.DB, categoryID uint) error {
+	var category daos.Category
+	if err := db.Select("id").Where("id = ?", categoryID).First(&category).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return errCategoryTidakAda
+		}
+		return err
+	}
+	return nil
+}
+
+// GetAtributCategory attribute schema of the category
+func (ar *AtributRepositoryImpl) GetAtributCategory(ctx context.Context, categoryID uint) (response []daos.AtributCategory, errHelper *helper.ErrorStruct) {
+	// get gorm client
+	db := ar.db
+
+	errDb := cekCategory(db, categoryID)
+	if errDb == nil {
+		errDb = db.Where("category_id = ?", categoryID).Scopes(urutanAtribut).Find(&response).Error
+	}
+	if errDb != nil {
+		return response, errorAtribut(errDb)
+	}
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return response, errHelper
+}
+
+func (ar *AtributRepositoryImpl) CreateAtributCategory(ctx context.Context, data daos.AtributCategory) (ID uint, errHelper *helper.ErrorStruct) {
+	// get gorm client
+	db := ar.db
+
+	errDb := cekCategory(db, data.CategoryID)
+	if errDb == nil {
+		errDb = db.Create(&data).Error
+	}
+	if errDb != nil {
+		return ID, errorAtribut(errDb)
+	}
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return data.ID, errHelper
+}
+
+// UpdateAtributCategory kode and tipe are kept so value already filled by seller stay valid
+func (ar *AtributRepositoryImpl) UpdateAtributCategory(ctx context.Context, data daos.AtributCategory) (errHelper *helper.ErrorStruct) {
+	// get gorm client
+	db := ar.db
+
+	var atribut daos.AtributCategory
+	errDb := db.Select("id").Where("category_id = ? AND id = ?", data.CategoryID, data.ID).First(&atribut).Error
+	if errDb == nil {
+		errDb = db.Model(&atribut).Updates(map[string]interface{}{
+			"nama":   data.Nama,
+			"opsi":   data.Opsi,
+			"satuan": data.Satuan,
+			"wajib":  data.Wajib,
+			"urutan": data.Urutan,
+		}).Error
+	}
+	if errDb != nil {
+		return errorAtribut(errDb)
+	}
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return errHelper
+}
+
+// DeleteAtributCategory remove attribute from the schema with every value filled on produk
+func (ar *AtributRepositoryImpl) DeleteAtributCategory(ctx context.Context, categoryID, ID uint) (errHelper *helper.ErrorStruct) {
+	// get gorm client
+	db := ar.db
+
+	errDb := db.Transaction(func(tx *gorm.DB) error {
+		var atribut daos.AtributCategory
+		if err := tx.Select("id").Where("category_id = ? AND id = ?", categoryID, ID).First(&atribut).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("atribut_category_id = ?", ID).Delete(&daos.AtributProduk{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("id = ?", ID).Delete(&daos.AtributCategory{}).Error
+	})
+	if errDb != nil {
+		return errorAtribut(errDb)
+	}
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return errHelper
+}
+
+// SimpanAtributProduk replace attribute value of produk owned by the toko, value is checked against schema of its category
+// and every required attribute must be filled
+func (ar *AtributRepositoryImpl) SimpanAtributProduk(ctx context.Context, tokoID, produkID uint, listNilai map[string]interface{}) (errHelper *helper.ErrorStruct) {
+	// get gorm client
+	db := ar.db
+
+	errDb := db.Transaction(func(tx *gorm.DB) error {
+		var produk daos.Produk
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id", "category_id").
+			Where("toko_id = ? AND id = ?", tokoID, produkID).First(&produk).Error; err != nil {
+			return err
+		}
+		var listAtribut []daos.AtributCategory
+		if err := tx.Where("category_id = ?", produk.CategoryID).Scopes(urutanAtribut).Find(&listAtribut).Error; err != nil {
+			return err
+		}
+
+		// check every value against the schema
+		listAtributByKode := map[string]daos.AtributCategory{}
+		for _, v := range listAtribut {
+			listAtributByKode[v.Kode] = v
+		}
+		for kode := range listNilai {
+			if _, ok := listAtributByKode[kode]; !ok {
+				errHelper = &helper.ErrorStruct{Err: fmt.Errorf("%s is not an attribute of the category", kode), Code: http.StatusBadRequest}
+				return errHelper.Err
+			}
+		}
+		var listSimpan []daos.AtributProduk
+		for _, v := range listAtribut {
+			atribut, kosong, err := v.NilaiProduk(listNilai[v.Kode])
+			if err == nil && kosong && v.Wajib {
+				err = fmt.Errorf("%s is required", v.Kode)
+			}
+			if err != nil {
+				errHelper = &helper.ErrorStruct{Err: err, Code: http.StatusBadRequest}
+				return err
+			}
+			if !kosong {
+				atribut.ProdukID = produkID
+				listSimpan = append(listSimpan, atribut)
+			}
+		}
+
+		// replace the value
+		if err := tx.Where("produk_id = ?", produkID).Delete(&daos.AtributProduk{}).Error; err != nil {
+			return err
+		}
+		if len(listSimpan) > 0 {
+			if err := tx.Create(&listSimpan).Error; err != nil {
+				return err
+			}
+		}
+		return naikkanVersi(tx, produkID)
+	})
+	if errDb != nil {
+		if errHelper != nil {
+			return errHelper
+		}
+		if errDb == gorm.ErrRecordNotFound {
+			errHelper = &helper.ErrorStruct{
+				Err:  errors.New("No Data Product"),
+				Code: http.StatusNotFound,
+			}
+			return errHelper
+		}
+		errHelper = &helper.ErrorStruct{
+			Err:  errDb,
+			Code: http.StatusInternalServerError,
+		}
+		return errHelper
+	}
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return errHelper
+}
@@ -16,6 +16,8 @@ type CategoryRepository interface {
 	GetCategoryByID(ctx context.Context, ID uint) (response daos.Category, errHelper *helper.ErrorStruct)
 	UpdateCategoryByID(ctx context.Context, data daos.Category) (errHelper *helper.ErrorStruct)
 	DeleteCategoryByID(cxx context.Context, data daos.Category) (errHelper *helper.ErrorStruct)
+	RestoreCategoryByID(ctx context.Context, ID uint) (errHelper *helper.ErrorStruct)
+	GetSampahCategory(ctx context.Context) (response []daos.Category, errHelper *helper.ErrorStruct)
 }
 
 type CategoryRepositoryImpl struct {
@@ -165,8 +167,22 @@ func (cr *CategoryRepositoryImpl) UpdateCategoryByID(ctx context.Context, data d
 func (cr *CategoryRepositoryImpl) DeleteCategoryByID(ctx context.Context, data daos.Category) (errHelper *helper.ErrorStruct) {
 	db := cr.db
 
-	// delete record in categories with id as identifier
-	errDb := db.First(&data).Delete(&data).Error
+	// move record in categories to trash with id as identifier, category still used by produk can not be deleted
+	errDb := db.First(&data).Error
+	if errDb == nil {
+		var jumlahProduk int64
+		errDb = db.Model(&daos.Produk{}).Where("category_id = ?", data.ID).Count(&jumlahProduk).Error
+		if errDb == nil && jumlahProduk > 0 {
+			errHelper = &helper.ErrorStruct{
+				Err:  errors.New("category is still used by product"),
+				Code: http.StatusBadRequest,
+			}
+			return errHelper
+		}
+	}
+	if errDb == nil {
+		errDb = db.Delete(&data).Error
+	}
 
 	// check another error
 	if errDb != nil {
@@ -194,3 +210,62 @@ func (cr *CategoryRepositoryImpl) DeleteCategoryByID(ctx context.Context, data d
 	}
 	return errHelper
 }
+
+// RestoreCategoryByID bring back category from trash
+func (cr *CategoryRepositoryImpl) RestoreCategoryByID(ctx context.Context, ID uint) (errHelper *helper.ErrorStruct) {
+	db := cr.db
+
+	// restore record in categories with id as identifier
+	var data daos.Category
+	errDb := db.Unscoped().Where("id = ? AND deleted_at IS NOT NULL", ID).First(&data).Error
+	if errDb == nil {
+		errDb = db.Unscoped().Model(&data).Update("deleted_at", nil).Error
+	}
+	if errDb != nil {
+		// check if id available in trash
+		if errDb == gorm.ErrRecordNotFound {
+			errHelper = &helper.ErrorStruct{
+				Err:  errors.New("No Data Category in trash"),
+				Code: http.StatusNotFound,
+			}
+			return errHelper
+		}
+
+		// response another error
+		errHelper = &helper.ErrorStruct{
+			Err:  errDb,
+			Code: http.StatusInternalServerError,
+		}
+		return errHelper
+	}
+
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return errHelper
+}
+
+func (cr *CategoryRepositoryImpl) GetSampahCategory(ctx context.Context) (response []daos.Category, errHelper *helper.ErrorStruct) {
+	db := cr.db
+
+	// get deleted categories from database, newest deleted first
+	errDb := db.Unscoped().Where("deleted_at IS NOT NULL").Order("deleted_at DESC, id DESC").Find(&response).Error
+
+	// error checking
+	if errDb != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errDb,
+			Code: http.StatusInternalServerError,
+		}
+		return response, errHelper
+	}
+
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return response, errHelper
+}
@@ -0,0 +1,318 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/daos"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/helper"
+	"gorm.io/gorm"
+	"net/http"
+	"time"
+)
+
+type ChatRepository interface {
+	GetOrCreatePercakapan(ctx context.Context, data daos.Percakapan) (response daos.Percakapan, errHelper *helper.ErrorStruct)
+	GetMyPercakapan(ctx context.Context, userID uint, params daos.FilterPercakapan) (response []daos.Percakapan, errHelper *helper.ErrorStruct)
+	GetPercakapanByID(ctx context.Context, userID, ID uint) (response daos.Percakapan, errHelper *helper.ErrorStruct)
+	GetPesan(ctx context.Context, percakapanID uint, params daos.FilterPesan) (response []daos.Pesan, errHelper *helper.ErrorStruct)
+	CreatePesan(ctx context.Context, data daos.Pesan) (response daos.Pesan, errHelper *helper.ErrorStruct)
+	MarkRead(ctx context.Context, percakapanID uint, dariToko bool) (errHelper *helper.ErrorStruct)
+	CountUnread(ctx context.Context, userID uint) (total uint, errHelper *helper.ErrorStruct)
+}
+
+type ChatRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewChatRepository(db *gorm.DB) ChatRepository {
+	return &ChatRepositoryImpl{db: db}
+}
+
+func (cr *ChatRepositoryImpl) GetOrCreatePercakapan(ctx context.Context, data daos.Percakapan) (response daos.Percakapan, errHelper *helper.ErrorStruct) {
+	// get gorm client
+	db := cr.db
+
+	errDb := db.Transaction(func(tx *gorm.DB) error {
+		// check toko, user cannot chat with their own toko
+		var toko daos.Toko
+		if err := tx.First(&toko, data.TokoID).Error; err != nil {
+			return err
+		}
+		if toko.UserID == data.UserID {
+			return errors.New("user cannot chat with their own toko")
+		}
+		// referenced produk must belong to toko
+		if data.ProdukID != nil {
+			var produk daos.Produk
+			if err := tx.Where("id = ? AND toko_id = ?", *data.ProdukID, data.TokoID).First(&produk).Error; err != nil {
+				return err
+			}
+		}
+		// referenced trx must belong to user and contain item from toko
+		if data.TRXID != nil {
+			var detailTRX daos.DetailTRX
+			if err := tx.Joins("JOIN trxes ON trxes.id = detail_trxes.trx_id").Where("trxes.id = ? AND trxes.user_id = ? AND detail_trxes.toko_id = ?", *data.TRXID, data.UserID, data.TokoID).First(&detailTRX).Error; err != nil {
+				return err
+			}
+		}
+
+		// reuse existing thread with the same reference
+		query := tx.Where("user_id = ? AND toko_id = ?", data.UserID, data.TokoID)
+		if data.ProdukID != nil {
+			query = query.Where("produk_id = ?", *data.ProdukID)
+		} else {
+			query = query.Where("produk_id IS NULL")
+		}
+		if data.TRXID != nil {
+			query = query.Where("trx_id = ?", *data.TRXID)
+		} else {
+			query = query.Where("trx_id IS NULL")
+		}
+		err := query.First(&response).Error
+		if err == nil {
+			return nil
+		}
+		if err != gorm.ErrRecordNotFound {
+			return err
+		}
+		if err = tx.Create(&data).Error; err != nil {
+			return err
+		}
+		response = data
+		return nil
+	})
+	// error checking
+	if errDb != nil {
+		if errDb == gorm.ErrRecordNotFound {
+			errHelper = &helper.ErrorStruct{
+				Err:  errors.New("toko, produk or trx not found"),
+				Code: http.StatusNotFound,
+			}
+			return response, errHelper
+		}
+		if errDb.Error() == "user cannot chat with their own toko" {
+			errHelper = &helper.ErrorStruct{
+				Err:  errDb,
+				Code: http.StatusBadRequest,
+			}
+			return response, errHelper
+		}
+		errHelper = &helper.ErrorStruct{
+			Err:  errDb,
+			Code: http.StatusInternalServerError,
+		}
+		return response, errHelper
+	}
+
+	// load toko and user for response
+	if errDb := db.Preload("Toko").Preload("User").First(&response, response.ID).Error; errDb != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errDb,
+			Code: http.StatusInternalServerError,
+		}
+		return response, errHelper
+	}
+
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return response, errHelper
+}
+
+func (cr *ChatRepositoryImpl) GetMyPercakapan(ctx context.Context, userID uint, params daos.FilterPercakapan) (response []daos.Percakapan, errHelper *helper.ErrorStruct) {
+	// get gorm client
+	db := cr.db
+
+	// get percakapan where user is the buyer or the owner of toko, latest message first
+	if errDb := db.Preload("Toko").Preload("User").
+		Where("user_id = ? OR toko_id IN (?)", userID, db.Model(&daos.Toko{}).Select("id").Where("user_id = ?", userID)).
+		Order("pesan_terakhir_at DESC").Order("id DESC").
+		Limit(params.Limit).Offset(params.Offset).Find(&response).Error; errDb != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errDb,
+			Code: http.StatusInternalServerError,
+		}
+		return response, errHelper
+	}
+	// check if record not found
+	if len(response) <= 0 {
+		errHelper = &helper.ErrorStruct{
+			Err:  errors.New("no conversation found"),
+			Code: http.StatusNotFound,
+		}
+		return []daos.Percakapan{}, errHelper
+	}
+
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return response, errHelper
+}
+
+func (cr *ChatRepositoryImpl) GetPercakapanByID(ctx context.Context, userID, ID uint) (response daos.Percakapan, errHelper *helper.ErrorStruct) {
+	// get gorm client
+	db := cr.db
+
+	// get percakapan only when user is one of the participant
+	errDb := db.Preload("Toko").Preload("User").
+		Where("id = ?", ID).
+		Where("user_id = ? OR toko_id IN (?)", userID, db.Model(&daos.Toko{}).Select("id").Where("user_id = ?", userID)).
+		First(&response).Error
+	if errDb != nil {
+		// check if error is record not found
+		if errDb == gorm.ErrRecordNotFound {
+			errHelper = &helper.ErrorStruct{
+				Err:  errors.New("conversation not found"),
+				Code: http.StatusNotFound,
+			}
+			return response, errHelper
+		}
+		// response another error
+		errHelper = &helper.ErrorStruct{
+			Err:  errDb,
+			Code: http.StatusInternalServerError,
+		}
+		return response, errHelper
+	}
+
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return response, errHelper
+}
+
+func (cr *ChatRepositoryImpl) GetPesan(ctx context.Context, percakapanID uint, params daos.FilterPesan) (response []daos.Pesan, errHelper *helper.ErrorStruct) {
+	// get gorm client
+	db := cr.db
+
+	// get pesan newest first, before_id used to load older history
+	query := db.Where("percakapan_id = ?", percakapanID)
+	if params.BeforeID != 0 {
+		query = query.Where("id < ?", params.BeforeID)
+	}
+	if errDb := query.Order("id DESC").Limit(params.Limit).Find(&response).Error; errDb != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errDb,
+			Code: http.StatusInternalServerError,
+		}
+		return response, errHelper
+	}
+
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return response, errHelper
+}
+
+func (cr *ChatRepositoryImpl) CreatePesan(ctx context.Context, data daos.Pesan) (response daos.Pesan, errHelper *helper.ErrorStruct) {
+	// get gorm client
+	db := cr.db
+
+	// create pesan and update last message and unread counter of the other side
+	errDb := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&data).Error; err != nil {
+			return err
+		}
+		pesanTerakhir := data.IsiPesan
+		if len([]rune(pesanTerakhir)) > 255 {
+			pesanTerakhir = string([]rune(pesanTerakhir)[:255])
+		}
+		unreadColumn := "unread_toko"
+		if data.DariToko {
+			unreadColumn = "unread_user"
+		}
+		if err := tx.Model(&daos.Percakapan{}).Where("id = ?", data.PercakapanID).Updates(map[string]interface{}{
+			"pesan_terakhir":    pesanTerakhir,
+			"pesan_terakhir_at": data.CreatedAt,
+			unreadColumn:        gorm.Expr(unreadColumn + " + 1"),
+		}).Error; err != nil {
+			return err
+		}
+		return nil
+	})
+	if errDb != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errDb,
+			Code: http.StatusInternalServerError,
+		}
+		return response, errHelper
+	}
+
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return data, errHelper
+}
+
+func (cr *ChatRepositoryImpl) MarkRead(ctx context.Context, percakapanID uint, dariToko bool) (errHelper *helper.ErrorStruct) {
+	// get gorm client
+	db := cr.db
+
+	// mark pesan from the other side as read and reset unread counter of reader side
+	errDb := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&daos.Pesan{}).Where("percakapan_id = ? AND dari_toko = ? AND read_at IS NULL", percakapanID, !dariToko).Update("read_at", time.Now()).Error; err != nil {
+			return err
+		}
+		unreadColumn := "unread_user"
+		if dariToko {
+			unreadColumn = "unread_toko"
+		}
+		if err := tx.Model(&daos.Percakapan{}).Where("id = ?", percakapanID).Update(unreadColumn, 0).Error; err != nil {
+			return err
+		}
+		return nil
+	})
+	if errDb != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errDb,
+			Code: http.StatusInternalServerError,
+		}
+		return errHelper
+	}
+
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return errHelper
+}
+
+func (cr *ChatRepositoryImpl) CountUnread(ctx context.Context, userID uint) (total uint, errHelper *helper.ErrorStruct) {
+	// get gorm client
+	db := cr.db
+
+	// sum unread counter as buyer and as owner of toko
+	var unreadUser, unreadToko uint
+	if errDb := db.Model(&daos.Percakapan{}).Select("COALESCE(SUM(unread_user), 0)").Where("user_id = ?", userID).Scan(&unreadUser).Error; errDb != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errDb,
+			Code: http.StatusInternalServerError,
+		}
+		return total, errHelper
+	}
+	if errDb := db.Model(&daos.Percakapan{}).Select("COALESCE(SUM(unread_toko), 0)").Where("toko_id IN (?)", db.Model(&daos.Toko{}).Select("id").Where("user_id = ?", userID)).Scan(&unreadToko).Error; errDb != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errDb,
+			Code: http.StatusInternalServerError,
+		}
+		return total, errHelper
+	}
+
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return unreadUser + unreadToko, errHelper
+}
@@ -0,0 +1,370 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/go-sql-driver/mysql"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/daos"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/helper"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"net/http"
+	"time"
+)
+
+var (
+	errDiskusiTidakAda   = errors.New("question or answer not found")
+	errPertanyaanSendiri = errors.New("user cannot ask question on their own produk")
+	errTanggapanSendiri  = errors.New("user cannot vote or report their own question or answer")
+	errTanggapanTidakAda = errors.New("vote not found")
+)
+
+type DiskusiRepository interface {
+	GetDiskusiProduk(ctx context.Context, produkID, penggunaID uint, params daos.FilterDiskusi) (response []daos.DiskusiProduk, errHelper *helper.ErrorStruct)
+	GetTanggapanUser(ctx context.Context, userID uint, listDiskusiID []uint, jenis string) (response []uint, errHelper *helper.ErrorStruct)
+	CreatePertanyaan(ctx context.Context, data daos.DiskusiProduk) (response daos.DiskusiProduk, errHelper *helper.ErrorStruct)
+	CreateJawaban(ctx context.Context, data daos.DiskusiProduk) (response daos.DiskusiProduk, errHelper *helper.ErrorStruct)
+	BeriTanggapan(ctx context.Context, userID, ID uint, jenis string, jawaban bool) (errHelper *helper.ErrorStruct)
+	HapusTanggapan(ctx context.Context, userID, ID uint, jenis string) (errHelper *helper.ErrorStruct)
+	GetAntrianModerasiDiskusi(ctx context.Context, params daos.FilterModerasiDiskusi) (response []daos.DiskusiProduk, errHelper *helper.ErrorStruct)
+	ModerasiDiskusi(ctx context.Context, ID uint, status, alasan string) (errHelper *helper.ErrorStruct)
+}
+
+type DiskusiRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewDiskusiRepository(db *gorm.DB) DiskusiRepository {
+	return &DiskusiRepositoryImpl{db: db}
+}
+
+// errorDiskusi mapping error of question and answer into error response
+func errorDiskusi(errDb error) *helper.ErrorStruct {
+	var mysqlErr *mysql.MySQLError
+	switch {
+	case errDb == gorm.ErrRecordNotFound:
+		return &helper.ErrorStruct{Err: errors.New("No Data Product"), Code: http.StatusNotFound}
+	case errDb == errDiskusiTidakAda || errDb == errTanggapanTidakAda:
+		return &helper.ErrorStruct{Err: errDb, Code: http.StatusNotFound}
+	case errDb == errPertanyaanSendiri || errDb == errTanggapanSendiri:
+		return &helper.ErrorStruct{Err: errDb, Code: http.StatusBadRequest}
+	case errDb == errSudahDimoderasi:
+		return &helper.ErrorStruct{Err: errors.New("question or answer is already reviewed with the same status"), Code: http.StatusConflict}
+	case errors.As(errDb, &mysqlErr) && mysqlErr.Number == 1062:
+		return &helper.ErrorStruct{Err: errors.New("question or answer is already voted or reported by user"), Code: http.StatusConflict}
+	}
+	return &helper.ErrorStruct{Err: errDb, Code: http.StatusInternalServerError}
+}
+
+// produkDiskusi produk that can be discussed by user, produk not shown to buyer can only be seen by its toko
+func produkDiskusi(db *gorm.DB, produkID, penggunaID uint) (produk daos.Produk, err error) {
+	err = db.Select("id", "toko_id", "nama_produk", "status_moderasi", "status_publikasi", "jadwal_terbit").Preload("Toko").
+		Where("id = ?", produkID).First(&produk).Error
+	if err == nil && !produk.Tampil(time.Now()) && produk.Toko.UserID != penggunaID {
+		err = gorm.ErrRecordNotFound
+	}
+	return produk, err
+}
+
+// GetDiskusiProduk approved question of produk, the newest first, with approved answer where answer of the toko
+// and the most helpful answer come first
+func (dr *DiskusiRepositoryImpl) GetDiskusiProduk(ctx context.Context, produkID, penggunaID uint, params daos.FilterDiskusi) (response []daos.DiskusiProduk, errHelper *helper.ErrorStruct) {
+	// get gorm client
+	db := dr.db
+
+	_, errDb := produkDiskusi(db, produkID, penggunaID)
+	if errDb == nil {
+		errDb = db.Where("produk_id = ? AND pertanyaan_id IS NULL AND status_moderasi = ?", produkID, daos.ModerasiDisetujui).
+			Preload("User").
+			Preload("Jawaban", func(db *gorm.DB) *gorm.DB {
+				return db.Where("status_moderasi = ?", daos.ModerasiDisetujui).Order("dari_toko DESC, jumlah_membantu DESC, id")
+			}).
+			Preload("Jawaban.User").
+			Order("id DESC").Limit(params.Limit).Offset(params.Offset).Find(&response).Error
+	}
+	if errDb != nil {
+		return response, errorDiskusi(errDb)
+	}
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return response, errHelper
+}
+
+// GetTanggapanUser id of question or answer in listDiskusiID that is already given jenis by user
+func (dr *DiskusiRepositoryImpl) GetTanggapanUser(ctx context.Context, userID uint, listDiskusiID []uint, jenis string) (response []uint, errHelper *helper.ErrorStruct) {
+	// get gorm client
+	db := dr.db
+
+	if len(listDiskusiID) > 0 {
+		if errDb := db.Model(&daos.TanggapanDiskusi{}).Where("user_id = ? AND jenis = ? AND diskusi_id IN ?", userID, jenis, listDiskusiID).
+			Pluck("diskusi_id", &response).Error; errDb != nil {
+			errHelper = &helper.ErrorStruct{
+				Err:  errDb,
+				Code: http.StatusInternalServerError,
+			}
+			return response, errHelper
+		}
+	}
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return response, errHelper
+}
+
+// CreatePertanyaan save question and notify owner of the produk in the same transaction
+func (dr *DiskusiRepositoryImpl) CreatePertanyaan(ctx context.Context, data daos.DiskusiProduk) (response daos.DiskusiProduk, errHelper *helper.ErrorStruct) {
+	// get gorm client
+	db := dr.db
+
+	errDb := db.Transaction(func(tx *gorm.DB) error {
+		produk, err := produkDiskusi(tx, data.ProdukID, data.UserID)
+		if err != nil {
+			return err
+		}
+		if produk.Toko.UserID == data.UserID {
+			return errPertanyaanSendiri
+		}
+		if err := tx.Create(&data).Error; err != nil {
+			return err
+		}
+
+		notifikasi := newNotifikasi(produk.Toko.UserID, daos.NotifikasiTipePertanyaanProduk, "Pertanyaan baru",
+			fmt.Sprintf("Ada pertanyaan baru pada produk %s", produk.NamaProduk),
+			daos.NotifikasiRefProduk, produk.ID, map[string]interface{}{
+				"produk_id":     produk.ID,
+				"nama_produk":   produk.NamaProduk,
+				"pertanyaan_id": data.ID,
+			})
+		return tx.Create(&notifikasi).Error
+	})
+	if errDb == nil {
+		errDb = db.Preload("User").Where("id = ?", data.ID).First(&response).Error
+	}
+	if errDb != nil {
+		return response, errorDiskusi(errDb)
+	}
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return response, errHelper
+}
+
+// CreateJawaban answer approved question, the one who asked is notified when it is answered by someone else
+func (dr *DiskusiRepositoryImpl) CreateJawaban(ctx context.Context, data daos.DiskusiProduk) (response daos.DiskusiProduk, errHelper *helper.ErrorStruct) {
+	// get gorm client
+	db := dr.db
+
+	errDb := db.Transaction(func(tx *gorm.DB) error {
+		var pertanyaan daos.DiskusiProduk
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND pertanyaan_id IS NULL AND status_moderasi = ?", *data.PertanyaanID, daos.ModerasiDisetujui).
+			First(&pertanyaan).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return errDiskusiTidakAda
+			}
+			return err
+		}
+		produk, err := produkDiskusi(tx, pertanyaan.ProdukID, data.UserID)
+		if err != nil {
+			return err
+		}
+		data.ProdukID = produk.ID
+		data.DariToko = produk.Toko.UserID == data.UserID
+		if err := tx.Create(&data).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&daos.DiskusiProduk{}).Where("id = ?", pertanyaan.ID).Update("jumlah_jawaban", gorm.Expr("jumlah_jawaban + 1")).Error; err != nil {
+			return err
+		}
+		if pertanyaan.UserID == data.UserID {
+			return nil
+		}
+
+		notifikasi := newNotifikasi(pertanyaan.UserID, daos.NotifikasiTipeJawabanProduk, "Pertanyaan dijawab",
+			fmt.Sprintf("Pertanyaan kamu pada produk %s sudah dijawab", produk.NamaProduk),
+			daos.NotifikasiRefProduk, produk.ID, map[string]interface{}{
+				"produk_id":     produk.ID,
+				"nama_produk":   produk.NamaProduk,
+				"pertanyaan_id": pertanyaan.ID,
+				"jawaban_id":    data.ID,
+				"dari_toko":     data.DariToko,
+			})
+		return tx.Create(&notifikasi).Error
+	})
+	if errDb == nil {
+		errDb = db.Preload("User").Where("id = ?", data.ID).First(&response).Error
+	}
+	if errDb != nil {
+		return response, errorDiskusi(errDb)
+	}
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return response, errHelper
+}
+
+// BeriTanggapan vote or report approved question or answer, jawaban tell whether ID is expected to be an answer
+func (dr *DiskusiRepositoryImpl) BeriTanggapan(ctx context.Context, userID, ID uint, jenis string, jawaban bool) (errHelper *helper.ErrorStruct) {
+	// get gorm client
+	db := dr.db
+
+	errDb := db.Transaction(func(tx *gorm.DB) error {
+		query := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ? AND status_moderasi = ?", ID, daos.ModerasiDisetujui)
+		if jawaban {
+			query = query.Where("pertanyaan_id IS NOT NULL")
+		} else {
+			query = query.Where("pertanyaan_id IS NULL")
+		}
+		var diskusi daos.DiskusiProduk
+		if err := query.First(&diskusi).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return errDiskusiTidakAda
+			}
+			return err
+		}
+		if diskusi.UserID == userID {
+			return errTanggapanSendiri
+		}
+		if err := tx.Create(&daos.TanggapanDiskusi{DiskusiID: ID, UserID: userID, Jenis: jenis}).Error; err != nil {
+			return err
+		}
+		kolom := kolomTanggapan(jenis)
+		return tx.Model(&daos.DiskusiProduk{}).Where("id = ?", ID).Update(kolom, gorm.Expr(kolom+" + 1")).Error
+	})
+	if errDb != nil {
+		return errorDiskusi(errDb)
+	}
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return errHelper
+}
+
+// HapusTanggapan take back vote given by user
+func (dr *DiskusiRepositoryImpl) HapusTanggapan(ctx context.Context, userID, ID uint, jenis string) (errHelper *helper.ErrorStruct) {
+	// get gorm client
+	db := dr.db
+
+	errDb := db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("diskusi_id = ? AND user_id = ? AND jenis = ?", ID, userID, jenis).Delete(&daos.TanggapanDiskusi{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errTanggapanTidakAda
+		}
+		kolom := kolomTanggapan(jenis)
+		return tx.Model(&daos.DiskusiProduk{}).Where("id = ? AND "+kolom+" > 0", ID).Update(kolom, gorm.Expr(kolom+" - 1")).Error
+	})
+	if errDb != nil {
+		return errorDiskusi(errDb)
+	}
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return errHelper
+}
+
+// kolomTanggapan counter column of the jenis
+func kolomTanggapan(jenis string) string {
+	if jenis == daos.TanggapanLaporan {
+		return "jumlah_laporan"
+	}
+	return "jumlah_membantu"
+}
+
+// GetAntrianModerasiDiskusi question and answer with the moderation status, reported approved one is listed
+// the most reported first, otherwise the newest first
+func (dr *DiskusiRepositoryImpl) GetAntrianModerasiDiskusi(ctx context.Context, params daos.FilterModerasiDiskusi) (response []daos.DiskusiProduk, errHelper *helper.ErrorStruct) {
+	// get gorm client
+	db := dr.db
+
+	query := db.Where("status_moderasi = ?", params.Status)
+	if params.Dilaporkan {
+		query = query.Where("jumlah_laporan > 0").Order("jumlah_laporan DESC")
+	}
+	errDb := query.Preload("User").Order("id DESC").Limit(params.Limit).Offset(params.Offset).Find(&response).Error
+	if errDb != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errDb,
+			Code: http.StatusInternalServerError,
+		}
+		return response, errHelper
+	}
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return response, errHelper
+}
+
+// ModerasiDiskusi approve or reject question or answer. Approving clear its report, rejected answer is no longer
+// counted in jumlah_jawaban of its question.
+func (dr *DiskusiRepositoryImpl) ModerasiDiskusi(ctx context.Context, ID uint, status, alasan string) (errHelper *helper.ErrorStruct) {
+	// get gorm client
+	db := dr.db
+
+	errDb := db.Transaction(func(tx *gorm.DB) error {
+		var diskusi daos.DiskusiProduk
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", ID).First(&diskusi).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return errDiskusiTidakAda
+			}
+			return err
+		}
+		disetujui, sebelumnyaDisetujui := status == daos.ModerasiDisetujui, diskusi.StatusModerasi == daos.ModerasiDisetujui
+		if diskusi.StatusModerasi == status && diskusi.AlasanModerasi == alasan && (!disetujui || diskusi.JumlahLaporan == 0) {
+			return errSudahDimoderasi
+		}
+
+		data := map[string]interface{}{
+			"status_moderasi": status,
+			"alasan_moderasi": alasan,
+		}
+		if disetujui {
+			if err := tx.Where("diskusi_id = ? AND jenis = ?", ID, daos.TanggapanLaporan).Delete(&daos.TanggapanDiskusi{}).Error; err != nil {
+				return err
+			}
+			data["jumlah_laporan"] = 0
+		}
+		if err := tx.Model(&daos.DiskusiProduk{}).Where("id = ?", ID).Updates(data).Error; err != nil {
+			return err
+		}
+
+		// keep jumlah_jawaban of the question equal to its approved answer
+		if diskusi.Pertanyaan() || sebelumnyaDisetujui == disetujui {
+			return nil
+		}
+		jumlah := gorm.Expr("jumlah_jawaban + 1")
+		query := tx.Model(&daos.DiskusiProduk{}).Where("id = ?", *diskusi.PertanyaanID)
+		if !disetujui {
+			jumlah = gorm.Expr("jumlah_jawaban - 1")
+			query = query.Where("jumlah_jawaban > 0")
+		}
+		return query.Update("jumlah_jawaban", jumlah).Error
+	})
+	if errDb != nil {
+		return errorDiskusi(errDb)
+	}
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return errHelper
+}
@@ -0,0 +1,283 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/daos"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/helper"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"net/http"
+	"time"
+)
+
+type ImporRepository interface {
+	CreateImpor(ctx context.Context, data daos.ImporProduk) (ID uint, errHelper *helper.ErrorStruct)
+	UpdateProgresImpor(ctx context.Context, data daos.ImporProduk, listBarisGagal []daos.BarisImporGagal) (errHelper *helper.ErrorStruct)
+	SelesaikanImpor(ctx context.Context, data daos.ImporProduk) (errHelper *helper.ErrorStruct)
+	GetImporByID(ctx context.Context, tokoID, ID uint) (response daos.ImporProduk, errHelper *helper.ErrorStruct)
+	GetAllImpor(ctx context.Context, tokoID uint, limit, offset int) (response []daos.ImporProduk, errHelper *helper.ErrorStruct)
+	GetBarisImporGagal(ctx context.Context, ID uint, limit, offset int) (response []daos.BarisImporGagal, errHelper *helper.ErrorStruct)
+	HentikanImporTerputus(ctx context.Context, batas time.Time) (jumlah int64, errHelper *helper.ErrorStruct)
+}
+
+type ImporRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewImporRepository(db *gorm.DB) ImporRepository {
+	return &ImporRepositoryImpl{db: db}
+}
+
+// pesanImporTerputus reason of job that stop without finishing, for example because server restart
+const pesanImporTerputus = "import is interrupted, please upload the file again"
+
+var ErrImporDihentikan = errors.New(pesanImporTerputus)
+
+// kunciImporBerjalan lock job that is still running, job already stopped by HentikanImporTerputus must not be reopened
+func kunciImporBerjalan(tx *gorm.DB, ID uint) error {
+	var impor daos.ImporProduk
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").
+		Where("id = ? AND status IN ?", ID, []string{daos.StatusImporMenunggu, daos.StatusImporDiproses}).First(&impor).Error
+	if err == gorm.ErrRecordNotFound {
+		return ErrImporDihentikan
+	}
+	return err
+}
+
+func (ir *ImporRepositoryImpl) CreateImpor(ctx context.Context, data daos.ImporProduk) (ID uint, errHelper *helper.ErrorStruct) {
+	// get gorm client
+	db := ir.db
+
+	// create impor_produks record in database
+	if errDb := db.Create(&data).Error; errDb != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errDb,
+			Code: http.StatusInternalServerError,
+		}
+		return ID, errHelper
+	}
+
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return data.ID, errHelper
+}
+
+// UpdateProgresImpor save progress of running job together with row rejected since the last update
+func (ir *ImporRepositoryImpl) UpdateProgresImpor(ctx context.Context, data daos.ImporProduk, listBarisGagal []daos.BarisImporGagal) (errHelper *helper.ErrorStruct) {
+	db := ir.db
+
+	errDb := db.Transaction(func(tx *gorm.DB) error {
+		if err := kunciImporBerjalan(tx, data.ID); err != nil {
+			return err
+		}
+		if len(listBarisGagal) > 0 {
+			if err := tx.Create(&listBarisGagal).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Model(&daos.ImporProduk{}).Where("id = ?", data.ID).Updates(map[string]interface{}{
+			"status":         daos.StatusImporDiproses,
+			"baris_diproses": data.BarisDiproses,
+			"berhasil":       data.Berhasil,
+			"gagal":          data.Gagal,
+		}).Error
+	})
+	// error checking
+	if errDb == ErrImporDihentikan {
+		errHelper = &helper.ErrorStruct{
+			Err:  errDb,
+			Code: http.StatusConflict,
+		}
+		return errHelper
+	}
+	if errDb != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errDb,
+			Code: http.StatusInternalServerError,
+		}
+		return errHelper
+	}
+
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return errHelper
+}
+
+// SelesaikanImpor save final status of the job and notify the toko owner
+func (ir *ImporRepositoryImpl) SelesaikanImpor(ctx context.Context, data daos.ImporProduk) (errHelper *helper.ErrorStruct) {
+	db := ir.db
+
+	errDb := db.Transaction(func(tx *gorm.DB) error {
+		if err := kunciImporBerjalan(tx, data.ID); err != nil {
+			return err
+		}
+		selesaiAt := time.Now()
+		if err := tx.Model(&daos.ImporProduk{}).Where("id = ?", data.ID).Updates(map[string]interface{}{
+			"status":         data.Status,
+			"baris_diproses": data.BarisDiproses,
+			"berhasil":       data.Berhasil,
+			"gagal":          data.Gagal,
+			"pesan":          data.Pesan,
+			"selesai_at":     selesaiAt,
+		}).Error; err != nil {
+			return err
+		}
+
+		// toko may be deleted while the job is running, the job is still finished
+		var toko daos.Toko
+		if err := tx.Unscoped().Select("id", "user_id").Where("id = ?", data.TokoID).First(&toko).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return nil
+			}
+			return err
+		}
+		judul := "Impor produk selesai"
+		pesan := fmt.Sprintf("Impor %s selesai, %d produk berhasil dan %d baris ditolak", data.NamaFile, data.Berhasil, data.Gagal)
+		if data.Status == daos.StatusImporGagal {
+			judul = "Impor produk gagal"
+			pesan = fmt.Sprintf("Impor %s gagal : %s", data.NamaFile, data.Pesan)
+		}
+		notifikasi := newNotifikasi(toko.UserID, daos.NotifikasiTipeImporSelesai, judul, pesan,
+			daos.NotifikasiRefImpor, data.ID, map[string]interface{}{
+				"impor_id":  data.ID,
+				"status":    data.Status,
+				"berhasil":  data.Berhasil,
+				"gagal":     data.Gagal,
+				"nama_file": data.NamaFile,
+			})
+		return tx.Create(&notifikasi).Error
+	})
+	// error checking
+	if errDb == ErrImporDihentikan {
+		errHelper = &helper.ErrorStruct{
+			Err:  errDb,
+			Code: http.StatusConflict,
+		}
+		return errHelper
+	}
+	if errDb != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errDb,
+			Code: http.StatusInternalServerError,
+		}
+		return errHelper
+	}
+
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return errHelper
+}
+
+func (ir *ImporRepositoryImpl) GetImporByID(ctx context.Context, tokoID, ID uint) (response daos.ImporProduk, errHelper *helper.ErrorStruct) {
+	db := ir.db
+
+	// get impor record owned by the toko
+	errDb := db.Where("toko_id = ? AND id = ?", tokoID, ID).First(&response).Error
+	if errDb != nil {
+		// check if record not found
+		if errDb == gorm.ErrRecordNotFound {
+			errHelper = &helper.ErrorStruct{
+				Err:  errors.New("No Data Import"),
+				Code: http.StatusNotFound,
+			}
+			return response, errHelper
+		}
+
+		// response another error
+		errHelper = &helper.ErrorStruct{
+			Err:  errDb,
+			Code: http.StatusInternalServerError,
+		}
+		return response, errHelper
+	}
+
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return response, errHelper
+}
+
+func (ir *ImporRepositoryImpl) GetAllImpor(ctx context.Context, tokoID uint, limit, offset int) (response []daos.ImporProduk, errHelper *helper.ErrorStruct) {
+	db := ir.db
+
+	// get impor of the toko, newest first
+	errDb := db.Where("toko_id = ?", tokoID).Order("id DESC").Limit(limit).Offset(offset).Find(&response).Error
+	if errDb != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errDb,
+			Code: http.StatusInternalServerError,
+		}
+		return response, errHelper
+	}
+
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return response, errHelper
+}
+
+// GetBarisImporGagal rejected row of the job ordered like in the file, limit <= 0 return every row
+func (ir *ImporRepositoryImpl) GetBarisImporGagal(ctx context.Context, ID uint, limit, offset int) (response []daos.BarisImporGagal, errHelper *helper.ErrorStruct) {
+	db := ir.db
+
+	query := db.Where("impor_produk_id = ?", ID).Order("nomor_baris, id")
+	if limit > 0 {
+		query = query.Limit(limit).Offset(offset)
+	}
+	if errDb := query.Find(&response).Error; errDb != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errDb,
+			Code: http.StatusInternalServerError,
+		}
+		return response, errHelper
+	}
+
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return response, errHelper
+}
+
+// HentikanImporTerputus mark job without progress since batas as failed, its worker is gone
+func (ir *ImporRepositoryImpl) HentikanImporTerputus(ctx context.Context, batas time.Time) (jumlah int64, errHelper *helper.ErrorStruct) {
+	db := ir.db
+
+	result := db.Model(&daos.ImporProduk{}).
+		Where("status IN ? AND updated_at < ?", []string{daos.StatusImporMenunggu, daos.StatusImporDiproses}, batas).
+		Updates(map[string]interface{}{
+			"status":     daos.StatusImporGagal,
+			"pesan":      pesanImporTerputus,
+			"selesai_at": time.Now(),
+		})
+	if result.Error != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  result.Error,
+			Code: http.StatusInternalServerError,
+		}
+		return 0, errHelper
+	}
+
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return result.RowsAffected, errHelper
+}
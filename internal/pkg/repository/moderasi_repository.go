@@ -0,0 +1,203 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/daos"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/helper"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"net/http"
+)
+
+var errSudahDimoderasi = errors.New("product is already reviewed with the same status")
+
+type ModerasiRepository interface {
+	GetAntrianModerasi(ctx context.Context, params daos.FilterModerasi) (response []daos.Produk, errHelper *helper.ErrorStruct)
+	ModerasiProduk(ctx context.Context, adminID, produkID uint, status, alasan string) (errHelper *helper.ErrorStruct)
+	GetRiwayatModerasi(ctx context.Context, produkID uint) (response []daos.RiwayatModerasi, errHelper *helper.ErrorStruct)
+	SetTokoTerpercaya(ctx context.Context, tokoID uint, terpercaya bool) (errHelper *helper.ErrorStruct)
+}
+
+type ModerasiRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewModerasiRepository(db *gorm.DB) ModerasiRepository {
+	return &ModerasiRepositoryImpl{db: db}
+}
+
+// catatRiwayatModerasi save moderation status given to produk, adminID is nil for status given by the system
+func catatRiwayatModerasi(tx *gorm.DB, produkID uint, status, alasan string, adminID *uint) error {
+	return tx.Create(&daos.RiwayatModerasi{
+		ProdukID: produkID,
+		Status:   status,
+		Alasan:   alasan,
+		AdminID:  adminID,
+	}).Error
+}
+
+// ubahStatusModerasi change moderation status of produk and save it in the history, alasan is cleared when it is not rejected
+func ubahStatusModerasi(tx *gorm.DB, produkID uint, status, alasan string, adminID *uint) error {
+	if err := tx.Model(&daos.Produk{}).Where("id = ?", produkID).Updates(map[string]interface{}{
+		"status_moderasi": status,
+		"alasan_moderasi": alasan,
+	}).Error; err != nil {
+		return err
+	}
+	return catatRiwayatModerasi(tx, produkID, status, alasan, adminID)
+}
+
+// GetAntrianModerasi produk with the moderation status, the longest waiting first
+func (mr *ModerasiRepositoryImpl) GetAntrianModerasi(ctx context.Context, params daos.FilterModerasi) (response []daos.Produk, errHelper *helper.ErrorStruct) {
+	// get gorm client
+	db := mr.db
+
+	query := db.Where("status_moderasi = ?", params.Status)
+	if params.TokoID != 0 {
+		query = query.Where("toko_id = ?", params.TokoID)
+	}
+	errDb := query.Preload("FotoProduk", urutanFotoProduk).Preload("Category").Preload("Toko").
+		Order("updated_at, id").Limit(params.Limit).Offset(params.Offset).Find(&response).Error
+	if errDb != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errDb,
+			Code: http.StatusInternalServerError,
+		}
+		return response, errHelper
+	}
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return response, errHelper
+}
+
+// ModerasiProduk approve or reject produk by admin, seller of the produk is notified in the same transaction
+func (mr *ModerasiRepositoryImpl) ModerasiProduk(ctx context.Context, adminID, produkID uint, status, alasan string) (errHelper *helper.ErrorStruct) {
+	// get gorm client
+	db := mr.db
+
+	errDb := db.Transaction(func(tx *gorm.DB) error {
+		var produk daos.Produk
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Preload("Toko").Where("id = ?", produkID).First(&produk).Error; err != nil {
+			return err
+		}
+		if produk.StatusModerasi == status && produk.AlasanModerasi == alasan {
+			return errSudahDimoderasi
+		}
+		if err := ubahStatusModerasi(tx, produkID, status, alasan, &adminID); err != nil {
+			return err
+		}
+		// status given by UpdateProdukByID is already counted in its own versi
+		if err := naikkanVersi(tx, produkID); err != nil {
+			return err
+		}
+
+		judul, pesan := "Produk disetujui", fmt.Sprintf("Produk %s sudah disetujui dan tampil untuk pembeli", produk.NamaProduk)
+		if status == daos.ModerasiDitolak {
+			judul, pesan = "Produk ditolak", fmt.Sprintf("Produk %s ditolak: %s", produk.NamaProduk, alasan)
+		}
+		notifikasi := newNotifikasi(produk.Toko.UserID, daos.NotifikasiTipeModerasiProduk, judul, pesan,
+			daos.NotifikasiRefProduk, produk.ID, map[string]interface{}{
+				"produk_id":       produk.ID,
+				"nama_produk":     produk.NamaProduk,
+				"status_moderasi": status,
+				"alasan":          alasan,
+			})
+		return tx.Create(&notifikasi).Error
+	})
+	if errDb != nil {
+		if errDb == gorm.ErrRecordNotFound {
+			errHelper = &helper.ErrorStruct{
+				Err:  errors.New("No Data Product"),
+				Code: http.StatusNotFound,
+			}
+			return errHelper
+		}
+		if errDb == errSudahDimoderasi {
+			errHelper = &helper.ErrorStruct{
+				Err:  errDb,
+				Code: http.StatusConflict,
+			}
+			return errHelper
+		}
+		errHelper = &helper.ErrorStruct{
+			Err:  errDb,
+			Code: http.StatusInternalServerError,
+		}
+		return errHelper
+	}
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return errHelper
+}
+
+// GetRiwayatModerasi every moderation status given to produk, the newest first
+func (mr *ModerasiRepositoryImpl) GetRiwayatModerasi(ctx context.Context, produkID uint) (response []daos.RiwayatModerasi, errHelper *helper.ErrorStruct) {
+	// get gorm client
+	db := mr.db
+
+	var produk daos.Produk
+	errDb := db.Unscoped().Select("id").Where("id = ?", produkID).First(&produk).Error
+	if errDb == nil {
+		errDb = db.Where("produk_id = ?", produkID).Order("id DESC").Find(&response).Error
+	}
+	if errDb != nil {
+		if errDb == gorm.ErrRecordNotFound {
+			errHelper = &helper.ErrorStruct{
+				Err:  errors.New("No Data Product"),
+				Code: http.StatusNotFound,
+			}
+			return response, errHelper
+		}
+		errHelper = &helper.ErrorStruct{
+			Err:  errDb,
+			Code: http.StatusInternalServerError,
+		}
+		return response, errHelper
+	}
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return response, errHelper
+}
+
+// SetTokoTerpercaya mark toko as trusted so its new produk is approved automatically, produk already waiting keep waiting for review
+func (mr *ModerasiRepositoryImpl) SetTokoTerpercaya(ctx context.Context, tokoID uint, terpercaya bool) (errHelper *helper.ErrorStruct) {
+	// get gorm client
+	db := mr.db
+
+	var toko daos.Toko
+	errDb := db.Select("id").Where("id = ?", tokoID).First(&toko).Error
+	if errDb == nil {
+		errDb = db.Model(&toko).Update("terpercaya", terpercaya).Error
+	}
+	if errDb != nil {
+		if errDb == gorm.ErrRecordNotFound {
+			errHelper = &helper.ErrorStruct{
+				Err:  errors.New("Toko tidak ditemukan"),
+				Code: http.StatusNotFound,
+			}
+			return errHelper
+		}
+		errHelper = &helper.ErrorStruct{
+			Err:  errDb,
+			Code: http.StatusInternalServerError,
+		}
+		return errHelper
+	}
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return errHelper
+}
@@ -0,0 +1,260 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/daos"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/helper"
+	"gorm.io/gorm"
+	"net/http"
+	"time"
+)
+
+type NotifikasiRepository interface {
+	GetMyNotifikasi(ctx context.Context, userID uint, params daos.FilterNotifikasi) (response []daos.Notifikasi, errHelper *helper.ErrorStruct)
+	GetNotifikasiByID(ctx context.Context, listID []uint) (response []daos.Notifikasi, errHelper *helper.ErrorStruct)
+	CountUnread(ctx context.Context, userID uint) (response []daos.NotifikasiUnread, errHelper *helper.ErrorStruct)
+	MarkRead(ctx context.Context, userID, ID uint) (errHelper *helper.ErrorStruct)
+	MarkAllRead(ctx context.Context, userID uint) (errHelper *helper.ErrorStruct)
+}
+
+type NotifikasiRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewNotifikasiRepository(db *gorm.DB) NotifikasiRepository {
+	return &NotifikasiRepositoryImpl{db: db}
+}
+
+// newNotifikasi build notifikasi record with payload encoded as json
+func newNotifikasi(userID uint, tipe, judul, pesan, refTipe string, refID uint, payload interface{}) daos.Notifikasi {
+	payloadByte, _ := json.Marshal(payload)
+	return daos.Notifikasi{
+		UserID:  userID,
+		Tipe:    tipe,
+		Judul:   judul,
+		Pesan:   pesan,
+		Payload: string(payloadByte),
+		RefTipe: refTipe,
+		RefID:   refID,
+	}
+}
+
+// newTRXNotifikasi build notifikasi produced by a new trx for the buyer, every seller and low stock produk
+func newTRXNotifikasi(trx daos.TRX, listDetailTRX []daos.DetailTRX, listPenjual map[uint]uint, listStokRendah []daos.Produk) (listNotifikasi []daos.Notifikasi) {
+	// notifikasi for buyer
+	listNotifikasi = append(listNotifikasi, newNotifikasi(trx.UserID, daos.NotifikasiTipePesananDibuat,
+		"Pesanan berhasil dibuat",
+		fmt.Sprintf("Pesanan %s sebesar %d berhasil dibuat", trx.KodeInvoice, trx.HargaTotal),
+		daos.NotifikasiRefTRX, trx.ID, daos.PayloadPesananDibuat{
+			TRXID:       trx.ID,
+			KodeInvoice: trx.KodeInvoice,
+			HargaTotal:  trx.HargaTotal,
+		}))
+
+	// notifikasi for every seller, summarize detail trx per toko
+	kuantitasToko := map[uint]uint{}
+	hargaToko := map[uint]uint{}
+	var listTokoID []uint
+	for _, v := range listDetailTRX {
+		if _, ok := kuantitasToko[v.TokoID]; !ok {
+			listTokoID = append(listTokoID, v.TokoID)
+		}
+		kuantitasToko[v.TokoID] += v.Kuantitas
+		hargaToko[v.TokoID] += v.HargaTotal
+	}
+	for _, tokoID := range listTokoID {
+		listNotifikasi = append(listNotifikasi, newNotifikasi(listPenjual[tokoID], daos.NotifikasiTipePesananMasuk,
+			"Pesanan baru",
+			fmt.Sprintf("Pesanan %s berisi %d barang dari toko anda", trx.KodeInvoice, kuantitasToko[tokoID]),
+			daos.NotifikasiRefTRX, trx.ID, daos.PayloadPesananMasuk{
+				TRXID:       trx.ID,
+				KodeInvoice: trx.KodeInvoice,
+				TokoID:      tokoID,
+				Kuantitas:   kuantitasToko[tokoID],
+				HargaTotal:  hargaToko[tokoID],
+			}))
+	}
+
+	// notifikasi for seller when stok produk running low
+	for _, p := range listStokRendah {
+		listNotifikasi = append(listNotifikasi, newNotifikasi(listPenjual[p.TokoID], daos.NotifikasiTipeStokRendah,
+			"Stok produk menipis",
+			fmt.Sprintf("Stok %s tersisa %d", p.NamaProduk, p.Stok),
+			daos.NotifikasiRefProduk, p.ID, daos.PayloadStokRendah{
+				TRXID:           trx.ID,
+				ProdukID:        p.ID,
+				NamaProduk:      p.NamaProduk,
+				Stok:            p.Stok,
+				BatasStokRendah: p.BatasStokRendahEfektif(),
+			}))
+	}
+	return listNotifikasi
+}
+
+// newPembayaranNotifikasi build notifikasi for buyer when payment of trx is confirmed
+func newPembayaranNotifikasi(trx daos.TRX) daos.Notifikasi {
+	return newNotifikasi(trx.UserID, daos.NotifikasiTipePembayaran,
+		"Pembayaran diterima",
+		fmt.Sprintf("Pembayaran untuk pesanan %s sudah kami terima", trx.KodeInvoice),
+		daos.NotifikasiRefTRX, trx.ID, daos.PayloadPembayaran{
+			TRXID:       trx.ID,
+			KodeInvoice: trx.KodeInvoice,
+			HargaTotal:  trx.HargaTotal,
+		})
+}
+
+// newPengirimanNotifikasi build notifikasi for buyer when a toko ship its part of trx
+func newPengirimanNotifikasi(trx daos.TRX, toko daos.Toko, noResi string) daos.Notifikasi {
+	return newNotifikasi(trx.UserID, daos.NotifikasiTipePengiriman,
+		"Pesanan dikirim",
+		fmt.Sprintf("Pesanan %s dari %s sedang dikirim dengan nomor resi %s", trx.KodeInvoice, toko.NamaToko, noResi),
+		daos.NotifikasiRefTRX, trx.ID, daos.PayloadPengiriman{
+			TRXID:       trx.ID,
+			KodeInvoice: trx.KodeInvoice,
+			TokoID:      toko.ID,
+			NamaToko:    toko.NamaToko,
+			NoResi:      noResi,
+		})
+}
+
+func (nr *NotifikasiRepositoryImpl) GetMyNotifikasi(ctx context.Context, userID uint, params daos.FilterNotifikasi) (response []daos.Notifikasi, errHelper *helper.ErrorStruct) {
+	// get gorm client
+	db := nr.db
+
+	// build query with optional filter
+	query := db.Where("user_id = ?", userID)
+	if params.Tipe != "" {
+		query = query.Where("tipe = ?", params.Tipe)
+	}
+	if params.UnreadOnly {
+		query = query.Where("is_read = ?", false)
+	}
+
+	// get notifikasi records from database, newest first
+	if errDb := query.Order("created_at DESC").Order("id DESC").Limit(params.Limit).Offset(params.Offset).Find(&response).Error; errDb != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errDb,
+			Code: http.StatusInternalServerError,
+		}
+		return response, errHelper
+	}
+	// check if record not found
+	if len(response) <= 0 {
+		errHelper = &helper.ErrorStruct{
+			Err:  errors.New("no notification found"),
+			Code: http.StatusNotFound,
+		}
+		return []daos.Notifikasi{}, errHelper
+	}
+
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return response, errHelper
+}
+
+func (nr *NotifikasiRepositoryImpl) GetNotifikasiByID(ctx context.Context, listID []uint) (response []daos.Notifikasi, errHelper *helper.ErrorStruct) {
+	// get gorm client
+	db := nr.db
+
+	// get notifikasi records together with the recipient
+	if errDb := db.Preload("User").Where("id IN ?", listID).Order("id").Find(&response).Error; errDb != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errDb,
+			Code: http.StatusInternalServerError,
+		}
+		return response, errHelper
+	}
+
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return response, errHelper
+}
+
+func (nr *NotifikasiRepositoryImpl) CountUnread(ctx context.Context, userID uint) (response []daos.NotifikasiUnread, errHelper *helper.ErrorStruct) {
+	// get gorm client
+	db := nr.db
+
+	// count unread notifikasi grouped by tipe
+	if errDb := db.Model(&daos.Notifikasi{}).Select("tipe, COUNT(*) AS jumlah").Where("user_id = ? AND is_read = ?", userID, false).Group("tipe").Scan(&response).Error; errDb != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errDb,
+			Code: http.StatusInternalServerError,
+		}
+		return response, errHelper
+	}
+
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return response, errHelper
+}
+
+func (nr *NotifikasiRepositoryImpl) MarkRead(ctx context.Context, userID, ID uint) (errHelper *helper.ErrorStruct) {
+	// get gorm client
+	db := nr.db
+	var notifikasi daos.Notifikasi
+
+	// mark notifikasi with specified user_id and id as read
+	errDb := db.Where("user_id = ? AND id = ?", userID, ID).First(&notifikasi).Updates(map[string]interface{}{
+		"is_read": true,
+		"read_at": time.Now(),
+	}).Error
+	if errDb != nil {
+		// check if error is record not found
+		if errDb == gorm.ErrRecordNotFound {
+			errHelper = &helper.ErrorStruct{
+				Err:  errDb,
+				Code: http.StatusNotFound,
+			}
+			return errHelper
+		}
+		// response another error
+		errHelper = &helper.ErrorStruct{
+			Err:  errDb,
+			Code: http.StatusInternalServerError,
+		}
+		return errHelper
+	}
+
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return errHelper
+}
+
+func (nr *NotifikasiRepositoryImpl) MarkAllRead(ctx context.Context, userID uint) (errHelper *helper.ErrorStruct) {
+	// get gorm client
+	db := nr.db
+
+	// mark every unread notifikasi owned by user as read
+	if errDb := db.Model(&daos.Notifikasi{}).Where("user_id = ? AND is_read = ?", userID, false).Updates(map[string]interface{}{
+		"is_read": true,
+		"read_at": time.Now(),
+	}).Error; errDb != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errDb,
+			Code: http.StatusInternalServerError,
+		}
+		return errHelper
+	}
+
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return errHelper
+}
@@ -3,20 +3,41 @@ package repository
 import (
 	"context"
 	"errors"
+	"fmt"
+	"github.com/go-sql-driver/mysql"
 	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/daos"
 	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/helper"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 	"net/http"
-	"os"
+	"strings"
+	"time"
 )
 
 type ProdukRepository interface {
 	UploadProduk(ctx context.Context, data daos.Produk) (ID uint, errHelper *helper.ErrorStruct)
 	GetProdukByID(ctx context.Context, ID uint) (response daos.Produk, errHelper *helper.ErrorStruct)
-	UpdateProdukByID(ctx context.Context, data daos.Produk) (errHelper *helper.ErrorStruct)
+	GetProdukBySlug(ctx context.Context, slug string) (response daos.Produk, errHelper *helper.ErrorStruct)
+	UpdateProdukByID(ctx context.Context, data daos.Produk) (versi uint, errHelper *helper.ErrorStruct)
 	DeleteProdukByID(ctx context.Context, tokoID, ID uint) (errHelper *helper.ErrorStruct)
+	RestoreProdukByID(ctx context.Context, tokoID, ID uint) (errHelper *helper.ErrorStruct)
+	GetSampahProduk(ctx context.Context, tokoID uint, params daos.FilterProduk) (response []daos.Produk, errHelper *helper.ErrorStruct)
+	GetFotoProduk(ctx context.Context, produkID uint) (response []daos.FotoProduk, errHelper *helper.ErrorStruct)
+	DeleteFotoProduk(ctx context.Context, tokoID, produkID, ID uint) (errHelper *helper.ErrorStruct)
+	RestoreFotoProduk(ctx context.Context, tokoID, produkID, ID uint) (errHelper *helper.ErrorStruct)
+	GetSampahFotoProduk(ctx context.Context, tokoID, produkID uint) (response []daos.FotoProduk, errHelper *helper.ErrorStruct)
+	UrutkanFotoProduk(ctx context.Context, tokoID, produkID uint, listID []uint) (errHelper *helper.ErrorStruct)
+	SetFotoUtama(ctx context.Context, tokoID, produkID, ID uint) (errHelper *helper.ErrorStruct)
+	GetURLFotoDipakai(ctx context.Context, listURL []string) (listDipakai []string, errHelper *helper.ErrorStruct)
 	GetAllProduk(ctx context.Context, params daos.FilterProduk) (response []daos.Produk, errHelper *helper.ErrorStruct)
+	GetFacetProduk(ctx context.Context, params daos.FilterProduk) (response daos.FacetProduk, errHelper *helper.ErrorStruct)
+	GetProdukToko(ctx context.Context, tokoID uint, params daos.FilterProdukToko) (response []daos.Produk, errHelper *helper.ErrorStruct)
+	UbahPublikasi(ctx context.Context, tokoID, produkID uint, status string, jadwalTerbit *time.Time) (errHelper *helper.ErrorStruct)
+	GetProdukBatch(ctx context.Context, afterID uint, limit int, params daos.FilterBatchProduk) (response []daos.Produk, errHelper *helper.ErrorStruct)
+	GetProdukEkspor(ctx context.Context, tokoID, afterID uint, limit int) (response []daos.Produk, errHelper *helper.ErrorStruct)
+	GetFotoProdukByURL(ctx context.Context, listURL []string) (response []daos.FotoProduk, errHelper *helper.ErrorStruct)
+	UpdateVarian(ctx context.Context, tokoID, produkID uint, listOpsi []daos.OpsiVarian, listSKU []daos.SKU, stok *uint) (errHelper *helper.ErrorStruct)
+	CreateFotoSKU(ctx context.Context, tokoID, produkID, skuID uint, listFoto []daos.FotoProduk) (errHelper *helper.ErrorStruct)
 }
 
 type ProdukRepositoryImpl struct {
@@ -31,8 +52,53 @@ func (pr *ProdukRepositoryImpl) UploadProduk(ctx context.Context, dataProduk dao
 	// get gorm client
 	db := pr.db
 
-	//create produk and foto_produks record in database and get error information
-	if errDb := db.Create(&dataProduk).Error; errDb != nil {
+	// deleted toko cannot upload produk
+	var toko daos.Toko
+	if errDb := db.Select("id", "terpercaya").Where("id = ?", dataProduk.TokoID).First(&toko).Error; errDb != nil {
+		if errDb == gorm.ErrRecordNotFound {
+			errHelper = &helper.ErrorStruct{
+				Err:  errTokoDihapus,
+				Code: http.StatusBadRequest,
+			}
+			return ID, errHelper
+		}
+		errHelper = &helper.ErrorStruct{
+			Err:  errDb,
+			Code: http.StatusInternalServerError,
+		}
+		return ID, errHelper
+	}
+
+	// produk of untrusted toko wait for admin review before it is shown to buyer
+	dataProduk.StatusModerasi = daos.ModerasiMenunggu
+	if toko.Terpercaya {
+		dataProduk.StatusModerasi = daos.ModerasiDisetujui
+	}
+
+	//create produk and foto_produks record in database with its slug and get error information
+	errDb := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&dataProduk).Error; err != nil {
+			return err
+		}
+		if err := catatRiwayatModerasi(tx, dataProduk.ID, dataProduk.StatusModerasi, "", nil); err != nil {
+			return err
+		}
+		if _, err := simpanSlug(tx, daos.SlugTipeProduk, dataProduk.ID, dataProduk.NamaProduk); err != nil {
+			return err
+		}
+		if err := catatRiwayatHarga(tx, dataProduk.ID, dataProduk.HargaReseller, dataProduk.HargaKonsumen); err != nil {
+			return err
+		}
+		return catatMutasiStok(tx, []daos.MutasiStok{{
+			ProdukID:    dataProduk.ID,
+			Tipe:        daos.MutasiStokAwal,
+			Perubahan:   int(dataProduk.Stok),
+			Saldo:       dataProduk.Stok,
+			SaldoProduk: dataProduk.Stok,
+			PelakuID:    &dataProduk.TokoID,
+		}})
+	})
+	if errDb != nil {
 		errHelper = &helper.ErrorStruct{
 			Err:  errDb,
 			Code: http.StatusInternalServerError,
@@ -53,7 +119,9 @@ func (pr *ProdukRepositoryImpl) GetProdukByID(ctx context.Context, ID uint) (res
 	db := pr.db
 
 	// get produk record from database and error information
-	errDb := db.Preload("FotoProduk").Preload("Category").Preload("Toko").First(&response, ID)
+	errDb := db.Preload("FotoProduk", urutanFotoProduk).Preload("Category").Preload("Toko").
+		Preload("OpsiVarian", func(db *gorm.DB) *gorm.DB { return db.Order("urutan") }).
+		Preload("SKU").Preload("SKU.FotoProduk", urutanFotoProduk).Preload("Atribut.AtributCategory").First(&response, ID)
 	// error handle if record not found
 	if errDb.Error != nil {
 		if errDb.Error == gorm.ErrRecordNotFound {
@@ -70,6 +138,16 @@ func (pr *ProdukRepositoryImpl) GetProdukByID(ctx context.Context, ID uint) (res
 		}
 		return response, errHelper
 	}
+	// sale price of promo running now
+	listProduk := []daos.Produk{response}
+	if err := lengkapiPromoAktif(db, listProduk); err != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  err,
+			Code: http.StatusInternalServerError,
+		}
+		return response, errHelper
+	}
+	response = listProduk[0]
 	// success response
 	errHelper = &helper.ErrorStruct{
 		Err:  nil,
@@ -78,7 +156,33 @@ func (pr *ProdukRepositoryImpl) GetProdukByID(ctx context.Context, ID uint) (res
 	return response, errHelper
 }
 
-func (pr *ProdukRepositoryImpl) UpdateProdukByID(ctx context.Context, data daos.Produk) (errHelper *helper.ErrorStruct) {
+// GetProdukBySlug produk that has or had the slug, slug of produk in response is its current slug
+func (pr *ProdukRepositoryImpl) GetProdukBySlug(ctx context.Context, slug string) (response daos.Produk, errHelper *helper.ErrorStruct) {
+	// get gorm client
+	db := pr.db
+
+	// find produk id from current and old slug
+	ID, errDb := cariSlug(db, daos.SlugTipeProduk, slug)
+	if errDb != nil {
+		if errDb == gorm.ErrRecordNotFound {
+			errHelper = &helper.ErrorStruct{
+				Err:  errors.New("No Data Product"),
+				Code: http.StatusNotFound,
+			}
+			return response, errHelper
+		}
+		errHelper = &helper.ErrorStruct{
+			Err:  errDb,
+			Code: http.StatusInternalServerError,
+		}
+		return response, errHelper
+	}
+	return pr.GetProdukByID(ctx, ID)
+}
+
+// UpdateProdukByID data.Versi is the versi read by the client, update is rejected when produk is changed after that.
+// Zero data.Versi update any versi.
+func (pr *ProdukRepositoryImpl) UpdateProdukByID(ctx context.Context, data daos.Produk) (versi uint, errHelper *helper.ErrorStruct) {
 	// get gorm client
 	var responseDb daos.Produk
 
@@ -88,11 +192,83 @@ func (pr *ProdukRepositoryImpl) UpdateProdukByID(ctx context.Context, data daos.
 	errDb := db.Transaction(func(tx *gorm.DB) error {
 		// do some database operations in the transaction (use 'tx' from this point, not 'db')
 
-		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("toko_id = ? AND id = ?", data.TokoID, data.ID).First(&responseDb).Updates(data).Error; err != nil {
+		// stok of produk with variant is the sum of sku stok
+		var jumlahSKU int64
+		if err := tx.Model(&daos.SKU{}).Where("produk_id = ?", data.ID).Count(&jumlahSKU).Error; err != nil {
+			return err
+		}
+		if jumlahSKU > 0 {
+			data.Stok = 0
+		}
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("toko_id = ? AND id = ?", data.TokoID, data.ID).First(&responseDb).Error; err != nil {
 			// return any error will roll back
 			return err
 		}
+		if data.Versi != 0 && data.Versi != responseDb.Versi {
+			return errVersiBerbeda
+		}
+		data.Versi = responseDb.Versi + 1
+		// Updates write the new value into responseDb, keep the value before update
+		lama := responseDb
+		if err := tx.Model(&responseDb).Updates(data).Error; err != nil {
+			return err
+		}
+		if data.NamaProduk != "" {
+			if _, err := simpanSlug(tx, daos.SlugTipeProduk, data.ID, data.NamaProduk); err != nil {
+				return err
+			}
+		}
+		// attribute value follow the schema of the old category
+		if data.CategoryID != 0 && data.CategoryID != lama.CategoryID {
+			if err := tx.Where("produk_id = ?", data.ID).Delete(&daos.AtributProduk{}).Error; err != nil {
+				return err
+			}
+		}
+		// changed nama, deskripsi or foto of untrusted toko is reviewed again
+		berubah := (data.NamaProduk != "" && data.NamaProduk != lama.NamaProduk) ||
+			(data.Deskripsi != "" && data.Deskripsi != lama.Deskripsi) || len(data.FotoProduk) > 0
+		if berubah && lama.StatusModerasi != daos.ModerasiMenunggu {
+			var toko daos.Toko
+			if err := tx.Select("id", "terpercaya").Where("id = ?", data.TokoID).First(&toko).Error; err != nil {
+				return err
+			}
+			if !toko.Terpercaya {
+				if err := ubahStatusModerasi(tx, data.ID, daos.ModerasiMenunggu, "", nil); err != nil {
+					return err
+				}
+			}
+		}
+		// zero harga is not updated, the other harga is kept
+		if data.HargaReseller != 0 || data.HargaKonsumen != 0 {
+			hargaReseller, hargaKonsumen := responseDb.HargaReseller, responseDb.HargaKonsumen
+			if data.HargaReseller != 0 {
+				hargaReseller = data.HargaReseller
+			}
+			if data.HargaKonsumen != 0 {
+				hargaKonsumen = data.HargaKonsumen
+			}
+			if err := catatRiwayatHarga(tx, data.ID, hargaReseller, hargaKonsumen); err != nil {
+				return err
+			}
+		}
+		// zero stok is not updated, same as other field
+		if data.Stok != 0 {
+			if err := catatMutasiStok(tx, []daos.MutasiStok{{
+				ProdukID:    data.ID,
+				Tipe:        daos.MutasiStokPenyesuaian,
+				Perubahan:   int(data.Stok) - int(lama.Stok),
+				Saldo:       data.Stok,
+				SaldoProduk: data.Stok,
+				PelakuID:    &data.TokoID,
+				Alasan:      "produk updated",
+			}}); err != nil {
+				return err
+			}
+		}
 		if len(data.FotoProduk) > 0 {
+			if err := siapkanFotoBaru(tx, data.ID, data.FotoProduk); err != nil {
+				return err
+			}
 			if err := tx.Create(&data.FotoProduk).Error; err != nil {
 				return err
 			}
@@ -109,48 +285,49 @@ func (pr *ProdukRepositoryImpl) UpdateProdukByID(ctx context.Context, data daos.
 				Err:  errDb,
 				Code: http.StatusNotFound,
 			}
-			return errHelper
+			return versi, errHelper
+		}
+		if errDb == errVersiBerbeda {
+			errHelper = &helper.ErrorStruct{
+				Err:  errDb,
+				Code: http.StatusPreconditionFailed,
+			}
+			return versi, errHelper
 		}
 		// check another error
 		errHelper = &helper.ErrorStruct{
 			Err:  errDb,
 			Code: http.StatusInternalServerError,
 		}
-		return errHelper
+		return versi, errHelper
 	}
 	// success response
 	errHelper = &helper.ErrorStruct{
 		Err:  nil,
 		Code: http.StatusOK,
 	}
-	return errHelper
+	return responseDb.Versi, errHelper
 
 }
 
+// DeleteProdukByID move produk owned by toko with its foto to trash, variant and sku are kept so produk can be restored
 func (pr *ProdukRepositoryImpl) DeleteProdukByID(ctx context.Context, tokoID, ID uint) (errHelper *helper.ErrorStruct) {
 	// get gorm client
 	db := pr.db
-	var fotoDb daos.FotoProduk
 	var produkDb daos.Produk
-	var listFoto []daos.FotoProduk
 	// update with transaction
 	errDb := db.Transaction(func(tx *gorm.DB) error {
 		// do some database operations in the transaction (use 'tx' from this point, not 'db')
-		getFoto := tx.Where("produk_id = ?", ID).Find(&listFoto)
-		if getFoto.Error == nil {
-			for _, v := range listFoto {
-				if _, err := os.Stat(v.URL); err == nil {
-					if e := os.Remove(v.URL); e != nil {
-						helper.Logger("produk_repository", helper.LoggerLevelInfo, "Failed to DELETE foto")
-					}
-				}
-			}
+		if err := tx.Where("toko_id = ? AND id = ?", tokoID, ID).First(&produkDb).Error; err != nil {
+			return err
 		}
-		if err := tx.Where("produk_id = ?", ID).Delete(&fotoDb).Error; err != nil {
+		// foto deleted together with produk share its deleted_at, so restore only bring back those foto
+		dihapus := time.Now()
+		if err := tx.Model(&daos.FotoProduk{}).Where("produk_id = ?", ID).Update("deleted_at", dihapus).Error; err != nil {
 			// return any error will roll back
 			return err
 		}
-		if err := tx.Where("toko_id = ? AND id = ?", tokoID, ID).First(&produkDb).Delete(&produkDb).Error; err != nil {
+		if err := tx.Model(&produkDb).Update("deleted_at", dihapus).Error; err != nil {
 			return err
 		}
 		// return nil will commit the whole transaction
@@ -180,119 +357,1002 @@ func (pr *ProdukRepositoryImpl) DeleteProdukByID(ctx context.Context, tokoID, ID
 	return errHelper
 }
 
-func (pr *ProdukRepositoryImpl) GetAllProduk(ctx context.Context, params daos.FilterProduk) (response []daos.Produk, errHelper *helper.ErrorStruct) {
-
+// RestoreProdukByID bring back produk owned by toko from trash with foto deleted together with it
+func (pr *ProdukRepositoryImpl) RestoreProdukByID(ctx context.Context, tokoID, ID uint) (errHelper *helper.ErrorStruct) {
 	// get gorm client
 	db := pr.db
-	filter := daos.Produk{
-		TokoID:     params.TokoID,
-		CategoryID: params.CategoryID,
-	}
-	// get produk records from database
-	if errDb := db.Where(&filter).Limit(params.Limit).Offset(params.Offset).Preload("FotoProduk").Preload("Category").Preload("Toko").Find(&response).Error; errDb != nil {
-		// response another error
-		errHelper = &helper.ErrorStruct{
-			Err:  errDb,
-			Code: http.StatusInternalServerError,
+	var produkDb daos.Produk
+	// update with transaction
+	errDb := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("toko_id = ? AND id = ? AND deleted_at IS NOT NULL", tokoID, ID).First(&produkDb).Error; err != nil {
+			return err
 		}
-		return response, errHelper
-	}
-	if params.NamaProduk != "" {
-		errDb := db.Where(&filter).Where("slug LIKE ?", "%"+params.NamaProduk+"%").Limit(params.Limit).Offset(params.Offset).Preload("FotoProduk").Preload("Category").Preload("Toko").Find(&response)
-		if params.MaxHarga != 0 {
-			errDb = db.Where(&filter).Where("slug LIKE ?", "%"+params.NamaProduk+"%").Where("harga_konsumen <= ?", params.MaxHarga).Limit(params.Limit).Offset(params.Offset).Preload("FotoProduk").Preload("Category").Preload("Toko").Find(&response)
+		// produk of deleted toko is restored together with the toko
+		var toko daos.Toko
+		if err := tx.Select("id").Where("id = ?", tokoID).First(&toko).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return errTokoDihapus
+			}
+			return err
 		}
-		if params.MinHarga != 0 {
-			errDb = db.Where(&filter).Where("slug LIKE ?", "%"+params.NamaProduk+"%").Where("harga_konsumen >= ?", params.MinHarga).Limit(params.Limit).Offset(params.Offset).Preload("FotoProduk").Preload("Category").Preload("Toko").Find(&response)
+		if err := tx.Unscoped().Model(&daos.FotoProduk{}).Where("produk_id = ? AND deleted_at = ?", ID, produkDb.DeletedAt).
+			Update("deleted_at", nil).Error; err != nil {
+			return err
 		}
-		if params.MinHarga != 0 && params.MaxHarga != 0 {
-			errDb = db.Where(&filter).Where("slug LIKE ?", "%"+params.NamaProduk+"%").Where("harga_konsumen >= ?", params.MinHarga).Where("harga_konsumen <= ?", params.MaxHarga).Limit(params.Limit).Offset(params.Offset).Preload("FotoProduk").Preload("Category").Preload("Toko").Find(&response)
+		if err := tx.Unscoped().Model(&produkDb).Update("deleted_at", nil).Error; err != nil {
+			return err
 		}
-		// check if error is record not found
-		if len(response) <= 0 {
+		// return nil will commit the whole transaction
+		return nil
+	})
+	// error checking
+	if errDb != nil {
+		switch errDb {
+		case gorm.ErrRecordNotFound:
 			errHelper = &helper.ErrorStruct{
-				Err:  errors.New("no product found"),
+				Err:  errors.New("No Data Product in trash"),
 				Code: http.StatusNotFound,
 			}
-			return response, errHelper
-		}
-		// response another error
-		if errDb.Error != nil {
-
+		case errTokoDihapus:
+			errHelper = &helper.ErrorStruct{
+				Err:  errDb,
+				Code: http.StatusBadRequest,
+			}
+		default:
 			errHelper = &helper.ErrorStruct{
-				Err:  errDb.Error,
+				Err:  errDb,
 				Code: http.StatusInternalServerError,
 			}
-			return response, errHelper
 		}
-		// success response
+		return errHelper
+	}
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return errHelper
+}
+
+// GetSampahProduk get deleted produk of toko, newest deleted first, with foto deleted together with it
+func (pr *ProdukRepositoryImpl) GetSampahProduk(ctx context.Context, tokoID uint, params daos.FilterProduk) (response []daos.Produk, errHelper *helper.ErrorStruct) {
+	// get gorm client
+	db := pr.db
+
+	if errDb := db.Unscoped().Where("toko_id = ? AND deleted_at IS NOT NULL", tokoID).
+		Order("deleted_at DESC, id DESC").Limit(params.Limit).Offset(params.Offset).
+		Preload("FotoProduk", func(db *gorm.DB) *gorm.DB { return db.Unscoped().Scopes(urutanFotoProduk) }).
+		Preload("Category", func(db *gorm.DB) *gorm.DB { return db.Unscoped() }).
+		Find(&response).Error; errDb != nil {
 		errHelper = &helper.ErrorStruct{
-			Err:  nil,
-			Code: http.StatusOK,
+			Err:  errDb,
+			Code: http.StatusInternalServerError,
 		}
 		return response, errHelper
 	}
-	if params.MaxHarga != 0 {
-		errDb := db.Where(&filter).Where("harga_konsumen <= ?", params.MaxHarga).Limit(params.Limit).Offset(params.Offset).Preload("FotoProduk").Preload("Category").Preload("Toko").Find(&response)
-		if params.MinHarga != 0 {
-			errDb = db.Where(&filter).Where("slug LIKE ?", "%"+params.NamaProduk+"%").Where("harga_konsumen >= ?", params.MinHarga).Where("harga_konsumen <= ?", params.MaxHarga).Limit(params.Limit).Offset(params.Offset).Preload("FotoProduk").Preload("Category").Preload("Toko").Find(&response)
-		}
-		// check if error is record not found
-		if len(response) <= 0 {
-			errHelper = &helper.ErrorStruct{
-				Err:  errors.New("no product found"),
-				Code: http.StatusNotFound,
-			}
-			return response, errHelper
-		}
-		// response another error
-		if errDb.Error != nil {
-			errHelper = &helper.ErrorStruct{
-				Err:  errDb.Error,
-				Code: http.StatusInternalServerError,
+	for i, v := range response {
+		var listFoto []daos.FotoProduk
+		for _, f := range v.FotoProduk {
+			if !f.DeletedAt.Valid || f.DeletedAt.Time.Equal(v.DeletedAt.Time) {
+				listFoto = append(listFoto, f)
 			}
-			return response, errHelper
 		}
+		response[i].FotoProduk = listFoto
+	}
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return response, errHelper
+}
 
-		// success response
+// GetProdukToko every produk of the toko in any publication and moderation state, the last updated first
+func (pr *ProdukRepositoryImpl) GetProdukToko(ctx context.Context, tokoID uint, params daos.FilterProdukToko) (response []daos.Produk, errHelper *helper.ErrorStruct) {
+	// get gorm client
+	db := pr.db
+
+	query := db.Where("toko_id = ?", tokoID)
+	if params.NamaProduk != "" {
+		query = query.Where("nama_produk LIKE ?", "%"+params.NamaProduk+"%")
+	}
+	if params.StatusPublikasi != "" {
+		query = query.Where("status_publikasi = ?", params.StatusPublikasi)
+	}
+	if params.StatusModerasi != "" {
+		query = query.Where("status_moderasi = ?", params.StatusModerasi)
+	}
+	if errDb := query.Preload("FotoProduk", urutanFotoProduk).Preload("Category").Preload("Toko").
+		Order("updated_at DESC, id DESC").Limit(params.Limit).Offset(params.Offset).Find(&response).Error; errDb != nil {
 		errHelper = &helper.ErrorStruct{
-			Err:  nil,
-			Code: http.StatusOK,
+			Err:  errDb,
+			Code: http.StatusInternalServerError,
 		}
 		return response, errHelper
 	}
-	if params.MinHarga != 0 {
-		errDb := db.Where(&filter).Where("harga_konsumen >= ?", params.MinHarga).Limit(params.Limit).Offset(params.Offset).Preload("FotoProduk").Preload("Category").Preload("Toko").Find(&response)
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return response, errHelper
+}
 
-		// check if error is record not found
-		if len(response) <= 0 {
+// UbahPublikasi change publication state of produk owned by the toko, jadwalTerbit is only kept for published produk
+func (pr *ProdukRepositoryImpl) UbahPublikasi(ctx context.Context, tokoID, produkID uint, status string, jadwalTerbit *time.Time) (errHelper *helper.ErrorStruct) {
+	// get gorm client
+	db := pr.db
+
+	if status != daos.PublikasiTerbit {
+		jadwalTerbit = nil
+	}
+	var produk daos.Produk
+	errDb := db.Select("id").Where("toko_id = ? AND id = ?", tokoID, produkID).First(&produk).Error
+	if errDb == nil {
+		errDb = db.Model(&produk).Updates(map[string]interface{}{
+			"status_publikasi": status,
+			"jadwal_terbit":    jadwalTerbit,
+			"versi":            versiBaru,
+		}).Error
+	}
+	if errDb != nil {
+		if errDb == gorm.ErrRecordNotFound {
 			errHelper = &helper.ErrorStruct{
-				Err:  errors.New("no product found"),
+				Err:  errors.New("No Data Product"),
 				Code: http.StatusNotFound,
 			}
-			return response, errHelper
+			return errHelper
 		}
-		// response another error
-		if errDb.Error != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errDb,
+			Code: http.StatusInternalServerError,
+		}
+		return errHelper
+	}
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return errHelper
+}
+
+// denganSampah include record in trash, used where history must still show deleted toko, category or alamat
+func denganSampah(db *gorm.DB) *gorm.DB {
+	return db.Unscoped()
+}
+
+// urutanFotoProduk order foto produk as arranged by the toko, foto never arranged follow upload order
+func urutanFotoProduk(db *gorm.DB) *gorm.DB {
+	return db.Order("urutan, id")
+}
+
+// siapkanFotoBaru put new foto after the last foto of produk, the first foto of produk without primary foto become primary
+func siapkanFotoBaru(db *gorm.DB, produkID uint, listFoto []daos.FotoProduk) error {
+	var terakhir struct {
+		Jumlah int64
+		Urutan int
+		Utama  bool
+	}
+	if err := db.Model(&daos.FotoProduk{}).Where("produk_id = ?", produkID).
+		Select("COUNT(*) AS jumlah, COALESCE(MAX(urutan), 0) AS urutan, COALESCE(MAX(utama), FALSE) AS utama").
+		Scan(&terakhir).Error; err != nil {
+		return err
+	}
+	for i := range listFoto {
+		listFoto[i].Urutan = terakhir.Urutan + i
+		if terakhir.Jumlah > 0 {
+			listFoto[i].Urutan++
+		}
+		listFoto[i].Utama = i == 0 && !terakhir.Utama
+	}
+	return nil
+}
+
+// kunciProdukToko lock produk row owned by toko so foto of the same produk is not arranged concurrently
+func kunciProdukToko(tx *gorm.DB, tokoID, produkID uint) error {
+	var produk daos.Produk
+	return tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").
+		Where("toko_id = ? AND id = ?", tokoID, produkID).First(&produk).Error
+}
+
+// versiBaru value of versi column for write of produk outside UpdateProdukByID, so If-Match read before the write no longer match
+var versiBaru = gorm.Expr("versi + 1")
+
+// naikkanVersi bump versi of produk whose foto, variant or attribute is changed
+func naikkanVersi(tx *gorm.DB, produkID uint) error {
+	return tx.Model(&daos.Produk{}).Where("id = ?", produkID).UpdateColumn("versi", versiBaru).Error
+}
+
+func (pr *ProdukRepositoryImpl) GetFotoProduk(ctx context.Context, produkID uint) (response []daos.FotoProduk, errHelper *helper.ErrorStruct) {
+	// get gorm client
+	db := pr.db
+
+	// produk must exist, produk without foto is an empty list
+	var produk daos.Produk
+	errDb := db.Select("id").First(&produk, produkID).Error
+	if errDb == nil {
+		errDb = db.Where("produk_id = ?", produkID).Scopes(urutanFotoProduk).Find(&response).Error
+	}
+	// error checking
+	if errDb != nil {
+		if errDb == gorm.ErrRecordNotFound {
 			errHelper = &helper.ErrorStruct{
-				Err:  errDb.Error,
-				Code: http.StatusInternalServerError,
+				Err:  errors.New("No Data Product"),
+				Code: http.StatusNotFound,
 			}
 			return response, errHelper
 		}
-
-		// success response
+		// response another error
 		errHelper = &helper.ErrorStruct{
-			Err:  nil,
-			Code: http.StatusOK,
+			Err:  errDb,
+			Code: http.StatusInternalServerError,
 		}
 		return response, errHelper
 	}
-	// check if error is record not found
-	if len(response) <= 0 {
-		errHelper = &helper.ErrorStruct{
-			Err:  errors.New("no product found"),
-			Code: http.StatusNotFound,
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return response, errHelper
+}
+
+// DeleteFotoProduk move one foto of produk owned by toko to trash, its file is removed when the trash is purged.
+// When primary foto is deleted the next foto become primary.
+func (pr *ProdukRepositoryImpl) DeleteFotoProduk(ctx context.Context, tokoID, produkID, ID uint) (errHelper *helper.ErrorStruct) {
+	// get gorm client
+	db := pr.db
+	var foto daos.FotoProduk
+
+	// delete with transaction
+	errDb := db.Transaction(func(tx *gorm.DB) error {
+		if err := kunciProdukToko(tx, tokoID, produkID); err != nil {
+			return err
+		}
+		if err := tx.Where("produk_id = ? AND id = ?", produkID, ID).First(&foto).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return errFotoTidakAda
+			}
+			return err
+		}
+		if err := tx.Delete(&foto).Error; err != nil {
+			return err
+		}
+		if err := naikkanVersi(tx, produkID); err != nil {
+			return err
+		}
+		if foto.Utama {
+			var pengganti daos.FotoProduk
+			errPengganti := tx.Where("produk_id = ?", produkID).Scopes(urutanFotoProduk).First(&pengganti).Error
+			if errPengganti == gorm.ErrRecordNotFound {
+				return nil
+			}
+			if errPengganti != nil {
+				return errPengganti
+			}
+			if err := tx.Model(&pengganti).Update("utama", true).Error; err != nil {
+				return err
+			}
+		}
+		// return nil will commit the whole transaction
+		return nil
+	})
+	// error checking
+	if errDb != nil {
+		errHelper = errorFotoProduk(errDb)
+		return errHelper
+	}
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return errHelper
+}
+
+// RestoreFotoProduk bring back one foto of produk owned by toko from trash, it stays primary only when produk has no other primary foto
+func (pr *ProdukRepositoryImpl) RestoreFotoProduk(ctx context.Context, tokoID, produkID, ID uint) (errHelper *helper.ErrorStruct) {
+	// get gorm client
+	db := pr.db
+
+	// update with transaction
+	errDb := db.Transaction(func(tx *gorm.DB) error {
+		if err := kunciProdukToko(tx, tokoID, produkID); err != nil {
+			return err
+		}
+		var foto daos.FotoProduk
+		if err := tx.Unscoped().Where("produk_id = ? AND id = ? AND deleted_at IS NOT NULL", produkID, ID).First(&foto).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return errFotoTidakAda
+			}
+			return err
+		}
+		var jumlahUtama int64
+		if err := tx.Model(&daos.FotoProduk{}).Where("produk_id = ? AND utama = ?", produkID, true).Count(&jumlahUtama).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Model(&foto).Updates(map[string]interface{}{
+			"deleted_at": nil,
+			"utama":      jumlahUtama == 0,
+		}).Error; err != nil {
+			return err
+		}
+		if err := naikkanVersi(tx, produkID); err != nil {
+			return err
+		}
+		// return nil will commit the whole transaction
+		return nil
+	})
+	// error checking
+	if errDb != nil {
+		errHelper = errorFotoProduk(errDb)
+		return errHelper
+	}
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return errHelper
+}
+
+// GetSampahFotoProduk get foto of produk owned by toko that is deleted one by one, newest deleted first
+func (pr *ProdukRepositoryImpl) GetSampahFotoProduk(ctx context.Context, tokoID, produkID uint) (response []daos.FotoProduk, errHelper *helper.ErrorStruct) {
+	// get gorm client
+	db := pr.db
+
+	var produk daos.Produk
+	errDb := db.Select("id").Where("toko_id = ? AND id = ?", tokoID, produkID).First(&produk).Error
+	if errDb == nil {
+		errDb = db.Unscoped().Where("produk_id = ? AND deleted_at IS NOT NULL", produkID).Order("deleted_at DESC, id DESC").Find(&response).Error
+	}
+	// error checking
+	if errDb != nil {
+		errHelper = errorFotoProduk(errDb)
+		return response, errHelper
+	}
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return response, errHelper
+}
+
+// UrutkanFotoProduk arrange foto of produk owned by toko, listID must contain every foto of the produk exactly once
+func (pr *ProdukRepositoryImpl) UrutkanFotoProduk(ctx context.Context, tokoID, produkID uint, listID []uint) (errHelper *helper.ErrorStruct) {
+	// get gorm client
+	db := pr.db
+
+	// update with transaction
+	errDb := db.Transaction(func(tx *gorm.DB) error {
+		if err := kunciProdukToko(tx, tokoID, produkID); err != nil {
+			return err
+		}
+		var listIDDb []uint
+		if err := tx.Model(&daos.FotoProduk{}).Where("produk_id = ?", produkID).Pluck("id", &listIDDb).Error; err != nil {
+			return err
+		}
+		milikProduk := map[uint]bool{}
+		for _, v := range listIDDb {
+			milikProduk[v] = true
+		}
+		if len(listID) != len(listIDDb) {
+			return errUrutanFotoTidakLengkap
+		}
+		for _, v := range listID {
+			if !milikProduk[v] {
+				return errUrutanFotoTidakLengkap
+			}
+			// foto listed twice is rejected
+			delete(milikProduk, v)
+		}
+		for i, v := range listID {
+			if err := tx.Model(&daos.FotoProduk{}).Where("id = ?", v).Update("urutan", i).Error; err != nil {
+				return err
+			}
+		}
+		if err := naikkanVersi(tx, produkID); err != nil {
+			return err
+		}
+		// return nil will commit the whole transaction
+		return nil
+	})
+	// error checking
+	if errDb != nil {
+		errHelper = errorFotoProduk(errDb)
+		return errHelper
+	}
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return errHelper
+}
+
+// SetFotoUtama make one foto the primary foto of produk owned by toko
+func (pr *ProdukRepositoryImpl) SetFotoUtama(ctx context.Context, tokoID, produkID, ID uint) (errHelper *helper.ErrorStruct) {
+	// get gorm client
+	db := pr.db
+
+	// update with transaction
+	errDb := db.Transaction(func(tx *gorm.DB) error {
+		if err := kunciProdukToko(tx, tokoID, produkID); err != nil {
+			return err
+		}
+		var foto daos.FotoProduk
+		if err := tx.Where("produk_id = ? AND id = ?", produkID, ID).First(&foto).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return errFotoTidakAda
+			}
+			return err
+		}
+		if err := tx.Model(&daos.FotoProduk{}).Where("produk_id = ? AND id <> ?", produkID, ID).Update("utama", false).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&foto).Update("utama", true).Error; err != nil {
+			return err
+		}
+		if err := naikkanVersi(tx, produkID); err != nil {
+			return err
+		}
+		// return nil will commit the whole transaction
+		return nil
+	})
+	// error checking
+	if errDb != nil {
+		errHelper = errorFotoProduk(errDb)
+		return errHelper
+	}
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return errHelper
+}
+
+var (
+	errFotoTidakAda           = errors.New("photo not found")
+	errTokoDihapus            = errors.New("toko is deleted, restore the toko first")
+	errProdukTidakTersedia    = errors.New("product is not available")
+	errUrutanFotoTidakLengkap = errors.New("order must contain every photo of the product exactly once")
+	errVersiBerbeda           = errors.New("data has been changed since it was read, get the latest data and try again")
+)
+
+// errorFotoProduk mapping error of foto management into error response
+func errorFotoProduk(errDb error) *helper.ErrorStruct {
+	switch errDb {
+	case gorm.ErrRecordNotFound:
+		return &helper.ErrorStruct{Err: errors.New("No Data Product"), Code: http.StatusNotFound}
+	case errFotoTidakAda:
+		return &helper.ErrorStruct{Err: errDb, Code: http.StatusNotFound}
+	case errUrutanFotoTidakLengkap:
+		return &helper.ErrorStruct{Err: errDb, Code: http.StatusBadRequest}
+	}
+	return &helper.ErrorStruct{Err: errDb, Code: http.StatusInternalServerError}
+}
+
+// GetURLFotoDipakai return url in listURL that is still used by foto produk, trx snapshot or toko logo.
+// Content addressed file can be shared so it is only removed when nothing use it anymore.
+func (pr *ProdukRepositoryImpl) GetURLFotoDipakai(ctx context.Context, listURL []string) (listDipakai []string, errHelper *helper.ErrorStruct) {
+	// get gorm client
+	db := pr.db
+
+	if len(listURL) > 0 {
+		var listFoto []daos.FotoProduk
+		// foto and toko in trash can still be restored so their file is still used
+		if errDb := db.Unscoped().Where("url IN ? OR url_thumbnail IN ? OR url_medium IN ? OR url_large IN ?", listURL, listURL, listURL, listURL).
+			Find(&listFoto).Error; errDb != nil {
+			errHelper = &helper.ErrorStruct{
+				Err:  errDb,
+				Code: http.StatusInternalServerError,
+			}
+			return listDipakai, errHelper
+		}
+		for _, v := range listFoto {
+			listDipakai = append(listDipakai, v.ListURL()...)
+		}
+		var listURLLog, listURLToko []string
+		if errDb := db.Model(&daos.LogFotoProduk{}).Where("url IN ?", listURL).Pluck("url", &listURLLog).Error; errDb != nil {
+			errHelper = &helper.ErrorStruct{
+				Err:  errDb,
+				Code: http.StatusInternalServerError,
+			}
+			return listDipakai, errHelper
+		}
+		listDipakai = append(listDipakai, listURLLog...)
+		if errDb := db.Unscoped().Model(&daos.Toko{}).Where("url_foto IN ?", listURL).Pluck("url_foto", &listURLToko).Error; errDb != nil {
+			errHelper = &helper.ErrorStruct{
+				Err:  errDb,
+				Code: http.StatusInternalServerError,
+			}
+			return listDipakai, errHelper
+		}
+		listDipakai = append(listDipakai, listURLToko...)
+	}
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return listDipakai, errHelper
+}
+
+// GetFotoProdukByURL foto that has one of listURL as any of its rendition, foto in trash included because its file is kept
+func (pr *ProdukRepositoryImpl) GetFotoProdukByURL(ctx context.Context, listURL []string) (response []daos.FotoProduk, errHelper *helper.ErrorStruct) {
+	// get gorm client
+	db := pr.db
+
+	if len(listURL) > 0 {
+		if errDb := db.Unscoped().Where("url IN ? OR url_thumbnail IN ? OR url_medium IN ? OR url_large IN ?", listURL, listURL, listURL, listURL).
+			Find(&response).Error; errDb != nil {
+			errHelper = &helper.ErrorStruct{
+				Err:  errDb,
+				Code: http.StatusInternalServerError,
+			}
+			return response, errHelper
+		}
+	}
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return response, errHelper
+}
+
+// scopeProdukTampil produk that can be seen and bought by buyer, the same rule as daos.Produk.Tampil
+func scopeProdukTampil(db *gorm.DB) *gorm.DB {
+	return db.Where("produks.status_moderasi = ? AND produks.status_publikasi = ?", daos.ModerasiDisetujui, daos.PublikasiTerbit).
+		Where("produks.jadwal_terbit IS NULL OR produks.jadwal_terbit <= ?", time.Now())
+}
+
+// scopeFilterProduk compose every filter that is set, filter named in lewati are skipped for facet counting
+func scopeFilterProduk(params daos.FilterProduk, lewati ...string) func(db *gorm.DB) *gorm.DB {
+	dilewati := map[string]bool{}
+	for _, v := range lewati {
+		dilewati[v] = true
+	}
+	return func(db *gorm.DB) *gorm.DB {
+		db = scopeProdukTampil(db)
+		if params.ListID != nil {
+			if len(params.ListID) == 0 {
+				return db.Where("1 = 0")
+			}
+			db = db.Where("produks.id IN ?", params.ListID)
+		}
+		if params.CategoryID != 0 && !dilewati["category"] {
+			db = db.Where("produks.category_id = ?", params.CategoryID)
+		}
+		if params.TokoID != 0 {
+			db = db.Where("produks.toko_id = ?", params.TokoID)
+		}
+		if params.MinHarga != 0 && !dilewati["harga"] {
+			db = db.Where("produks.harga_konsumen >= ?", params.MinHarga)
+		}
+		if params.MaxHarga != 0 && !dilewati["harga"] {
+			db = db.Where("produks.harga_konsumen <= ?", params.MaxHarga)
+		}
+		if params.Tersedia {
+			db = db.Where("produks.stok > 0")
+		}
+		// every attribute filter must match, value of one attribute is any of the listed value
+		for _, v := range params.Atribut {
+			atribut := db.Session(&gorm.Session{NewDB: true}).Table("atribut_produks").Select("1").
+				Joins("JOIN atribut_categories ON atribut_categories.id = atribut_produks.atribut_category_id").
+				Where("atribut_produks.produk_id = produks.id AND atribut_categories.kode = ?", v.Kode)
+			if len(v.Nilai) > 0 {
+				atribut = atribut.Where("atribut_produks.nilai_teks IN ?", v.Nilai)
+			}
+			if v.Min != nil {
+				atribut = atribut.Where("atribut_produks.nilai_angka >= ?", *v.Min)
+			}
+			if v.Max != nil {
+				atribut = atribut.Where("atribut_produks.nilai_angka <= ?", *v.Max)
+			}
+			db = db.Where("EXISTS (?)", atribut)
+		}
+		return db
+	}
+}
+
+// scopeSortProduk order produk, relevansi keep the order of ListID from search index.
+// When params.Setelah is set only produk ordered after it are returned, id is the tie breaker of every order.
+func scopeSortProduk(params daos.FilterProduk) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		setelah := params.Setelah
+		switch params.Sort {
+		case daos.SortProdukHargaAsc:
+			if setelah != nil {
+				db = db.Where("produks.harga_konsumen > ? OR (produks.harga_konsumen = ? AND produks.id < ?)", setelah.HargaKonsumen, setelah.HargaKonsumen, setelah.ID)
+			}
+			return db.Order("produks.harga_konsumen ASC").Order("produks.id DESC")
+		case daos.SortProdukHargaDesc:
+			if setelah != nil {
+				db = db.Where("produks.harga_konsumen < ? OR (produks.harga_konsumen = ? AND produks.id < ?)", setelah.HargaKonsumen, setelah.HargaKonsumen, setelah.ID)
+			}
+			return db.Order("produks.harga_konsumen DESC").Order("produks.id DESC")
+		case daos.SortProdukTerlaris:
+			penjualan := db.Session(&gorm.Session{NewDB: true}).Table("detail_trxes").
+				Select("log_produks.produk_id, SUM(detail_trxes.kuantitas) AS terjual").
+				Joins("JOIN log_produks ON log_produks.id = detail_trxes.log_produk_id").
+				Group("log_produks.produk_id")
+			db = db.Select("produks.*, COALESCE(penjualan.terjual, 0) AS terjual").
+				Joins("LEFT JOIN (?) AS penjualan ON penjualan.produk_id = produks.id", penjualan)
+			if setelah != nil {
+				db = db.Where("COALESCE(penjualan.terjual, 0) < ? OR (COALESCE(penjualan.terjual, 0) = ? AND produks.id < ?)", setelah.Terjual, setelah.Terjual, setelah.ID)
+			}
+			return db.Order("COALESCE(penjualan.terjual, 0) DESC").Order("produks.id DESC")
+		case daos.SortProdukRelevansi:
+			if len(params.ListID) > 0 {
+				var listID []interface{}
+				for _, ID := range params.ListID {
+					listID = append(listID, ID)
+				}
+				return db.Clauses(clause.OrderBy{Expression: clause.Expr{
+					SQL:                "FIELD(produks.id" + strings.Repeat(", ?", len(listID)) + ")",
+					Vars:               listID,
+					WithoutParentheses: true,
+				}})
+			}
+		}
+		if setelah != nil {
+			db = db.Where("produks.created_at < ? OR (produks.created_at = ? AND produks.id < ?)", setelah.CreatedAt, setelah.CreatedAt, setelah.ID)
+		}
+		return db.Order("produks.created_at DESC").Order("produks.id DESC")
+	}
+}
+
+func (pr *ProdukRepositoryImpl) GetAllProduk(ctx context.Context, params daos.FilterProduk) (response []daos.Produk, errHelper *helper.ErrorStruct) {
+	// get gorm client
+	db := pr.db
+
+	// get produk records from database with every filter and sort applied
+	if errDb := db.Model(&daos.Produk{}).Scopes(scopeFilterProduk(params), scopeSortProduk(params)).
+		Limit(params.Limit).Offset(params.Offset).
+		Preload("FotoProduk", urutanFotoProduk).Preload("Category").Preload("Toko").Find(&response).Error; errDb != nil {
+		// response another error
+		errHelper = &helper.ErrorStruct{
+			Err:  errDb,
+			Code: http.StatusInternalServerError,
+		}
+		return response, errHelper
+	}
+	// sale price of promo running now
+	if errDb := lengkapiPromoAktif(db, response); errDb != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errDb,
+			Code: http.StatusInternalServerError,
+		}
+		return response, errHelper
+	}
+	// check if record not found
+	if len(response) <= 0 {
+		errHelper = &helper.ErrorStruct{
+			Err:  errors.New("no product found"),
+			Code: http.StatusNotFound,
+		}
+		return response, errHelper
+	}
+
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return response, errHelper
+}
+
+func (pr *ProdukRepositoryImpl) GetFacetProduk(ctx context.Context, params daos.FilterProduk) (response daos.FacetProduk, errHelper *helper.ErrorStruct) {
+	// get gorm client
+	db := pr.db
+
+	// count per category ignoring category filter so other category can still be chosen
+	if errDb := db.Model(&daos.Produk{}).Scopes(scopeFilterProduk(params, "category")).
+		Select("produks.category_id, categories.nama_category, COUNT(*) AS jumlah").
+		Joins("JOIN categories ON categories.id = produks.category_id").
+		Group("produks.category_id, categories.nama_category").
+		Order("jumlah DESC").Scan(&response.Category).Error; errDb != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errDb,
+			Code: http.StatusInternalServerError,
+		}
+		return response, errHelper
+	}
+
+	// count per harga bucket ignoring harga filter
+	bucket := "CASE"
+	var listBatas []interface{}
+	for i, batas := range daos.BatasHargaFacet {
+		bucket += fmt.Sprintf(" WHEN produks.harga_konsumen < ? THEN %d", i)
+		listBatas = append(listBatas, batas)
+	}
+	bucket += fmt.Sprintf(" ELSE %d END", len(daos.BatasHargaFacet))
+	if errDb := db.Model(&daos.Produk{}).Scopes(scopeFilterProduk(params, "harga")).
+		Select(bucket+" AS bucket, COUNT(*) AS jumlah", listBatas...).
+		Group("bucket").Order("bucket").Scan(&response.Harga).Error; errDb != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errDb,
+			Code: http.StatusInternalServerError,
+		}
+		return response, errHelper
+	}
+
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return response, errHelper
+}
+
+func (pr *ProdukRepositoryImpl) UpdateVarian(ctx context.Context, tokoID, produkID uint, listOpsi []daos.OpsiVarian, listSKU []daos.SKU, stok *uint) (errHelper *helper.ErrorStruct) {
+	// get gorm client
+	db := pr.db
+
+	// replace opsi varian and sync sku with transaction
+	errDb := db.Transaction(func(tx *gorm.DB) error {
+		var produk daos.Produk
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("toko_id = ? AND id = ?", tokoID, produkID).First(&produk).Error; err != nil {
+			return err
+		}
+
+		// replace opsi varian
+		if err := tx.Where("produk_id = ?", produkID).Delete(&daos.OpsiVarian{}).Error; err != nil {
+			return err
+		}
+		for i := range listOpsi {
+			listOpsi[i].ProdukID = produkID
+		}
+		if len(listOpsi) > 0 {
+			if err := tx.Create(&listOpsi).Error; err != nil {
+				return err
+			}
+		}
+
+		// sku with id are updated, without id are created and the rest are deleted
+		var listSKUDb []daos.SKU
+		if err := tx.Where("produk_id = ?", produkID).Find(&listSKUDb).Error; err != nil {
+			return err
+		}
+		listSKUTersisa := map[uint]bool{}
+		for _, v := range listSKUDb {
+			listSKUTersisa[v.ID] = true
+		}
+		stokSKU := map[uint]uint{}
+		for _, v := range listSKUDb {
+			stokSKU[v.ID] = v.Stok
+		}
+		var stokTotal uint
+		var listMutasi []daos.MutasiStok
+		for _, v := range listSKU {
+			stokTotal += v.Stok
+			if v.ID == 0 {
+				v.ProdukID = produkID
+				if err := tx.Create(&v).Error; err != nil {
+					return err
+				}
+				// loop variable is reused, so its id is copied before taking the address
+				ID := v.ID
+				listMutasi = append(listMutasi, daos.MutasiStok{SKUID: &ID, Tipe: daos.MutasiStokAwal, Perubahan: int(v.Stok), Saldo: v.Stok})
+				continue
+			}
+			if !listSKUTersisa[v.ID] {
+				return errors.New("sku not found")
+			}
+			delete(listSKUTersisa, v.ID)
+			ID := v.ID
+			listMutasi = append(listMutasi, daos.MutasiStok{SKUID: &ID, Tipe: daos.MutasiStokPenyesuaian, Perubahan: int(v.Stok) - int(stokSKU[v.ID]), Saldo: v.Stok, Alasan: "variant updated"})
+			if err := tx.Model(&daos.SKU{}).Where("id = ?", v.ID).Updates(map[string]interface{}{
+				"kode_sku":       v.KodeSKU,
+				"varian":         v.Varian,
+				"harga_reseller": v.HargaReseller,
+				"harga_konsumen": v.HargaKonsumen,
+				"stok":           v.Stok,
+			}).Error; err != nil {
+				return err
+			}
+		}
+		var listSKUDihapus []uint
+		for ID := range listSKUTersisa {
+			listSKUDihapus = append(listSKUDihapus, ID)
+			skuID := ID
+			listMutasi = append(listMutasi, daos.MutasiStok{SKUID: &skuID, Tipe: daos.MutasiStokPenyesuaian, Perubahan: -int(stokSKU[ID]), Alasan: "sku deleted"})
+		}
+		if len(listSKUDihapus) > 0 {
+			// foto of deleted sku stay as foto of produk
+			if err := tx.Model(&daos.FotoProduk{}).Where("sku_id IN ?", listSKUDihapus).Update("sku_id", nil).Error; err != nil {
+				return err
+			}
+			if err := tx.Where("id IN ?", listSKUDihapus).Delete(&daos.SKU{}).Error; err != nil {
+				return err
+			}
+		}
+
+		// stok of produk with variant is the sum of sku stok, produk without
+		// variant keeps its own stok unless the request sends a new one, and
+		// removing every sku resets it because the old sum belongs to the sku
+		stokProduk := stokTotal
+		if len(listSKU) == 0 {
+			stokTotal = produk.Stok
+			if len(listSKUDb) > 0 {
+				stokTotal = 0
+			}
+			stokProduk = stokTotal
+			if stok != nil {
+				stokProduk = *stok
+			}
+		}
+		kolom := map[string]interface{}{"versi": versiBaru}
+		if len(listSKU) > 0 || stokProduk != produk.Stok {
+			kolom["stok"] = stokProduk
+		}
+		if err := tx.Model(&daos.Produk{}).Where("id = ?", produkID).Updates(kolom).Error; err != nil {
+			return err
+		}
+		for i := range listMutasi {
+			listMutasi[i].SaldoProduk = stokTotal
+		}
+		if len(listSKU) == 0 && stokProduk != stokTotal {
+			listMutasi = append(listMutasi, daos.MutasiStok{
+				Tipe:        daos.MutasiStokPenyesuaian,
+				Perubahan:   int(stokProduk) - int(stokTotal),
+				Saldo:       stokProduk,
+				SaldoProduk: stokProduk,
+				Alasan:      "variant updated",
+			})
+		}
+		for i := range listMutasi {
+			listMutasi[i].ProdukID = produkID
+			listMutasi[i].PelakuID = &tokoID
+		}
+		if err := catatMutasiStok(tx, listMutasi); err != nil {
+			return err
+		}
+		// return nil will commit the whole transaction
+		return nil
+	})
+	// error checking
+	if errDb != nil {
+		// check if error is record not found
+		if errDb == gorm.ErrRecordNotFound || errDb.Error() == "sku not found" {
+			errHelper = &helper.ErrorStruct{
+				Err:  errDb,
+				Code: http.StatusNotFound,
+			}
+			return errHelper
+		}
+		// check if kode_sku are duplicate
+		var mysqlErr *mysql.MySQLError
+		if errors.As(errDb, &mysqlErr) && mysqlErr.Number == 1062 {
+			errHelper = &helper.ErrorStruct{
+				Err:  errDb,
+				Code: http.StatusBadRequest,
+			}
+			return errHelper
+		}
+		// response another error
+		errHelper = &helper.ErrorStruct{
+			Err:  errDb,
+			Code: http.StatusInternalServerError,
+		}
+		return errHelper
+	}
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return errHelper
+}
+
+func (pr *ProdukRepositoryImpl) CreateFotoSKU(ctx context.Context, tokoID, produkID, skuID uint, listFoto []daos.FotoProduk) (errHelper *helper.ErrorStruct) {
+	// get gorm client
+	db := pr.db
+
+	// sku must belong to produk owned by toko, produk is locked so urutan of concurrent upload does not collide
+	errDb := db.Transaction(func(tx *gorm.DB) error {
+		if err := kunciProdukToko(tx, tokoID, produkID); err != nil {
+			return err
+		}
+		var sku daos.SKU
+		if err := tx.Where("id = ? AND produk_id = ?", skuID, produkID).First(&sku).Error; err != nil {
+			return err
+		}
+		for i := range listFoto {
+			listFoto[i].ProdukID = produkID
+			listFoto[i].SKUID = &sku.ID
+		}
+		if err := siapkanFotoBaru(tx, produkID, listFoto); err != nil {
+			return err
+		}
+		if err := tx.Create(&listFoto).Error; err != nil {
+			return err
+		}
+		return naikkanVersi(tx, produkID)
+	})
+	// error checking
+	if errDb != nil {
+		// check if error is record not found
+		if errDb == gorm.ErrRecordNotFound {
+			errHelper = &helper.ErrorStruct{
+				Err:  errors.New("sku not found"),
+				Code: http.StatusNotFound,
+			}
+			return errHelper
+		}
+		// response another error
+		errHelper = &helper.ErrorStruct{
+			Err:  errDb,
+			Code: http.StatusInternalServerError,
+		}
+		return errHelper
+	}
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return errHelper
+}
+
+func (pr *ProdukRepositoryImpl) GetProdukBatch(ctx context.Context, afterID uint, limit int, params daos.FilterBatchProduk) (response []daos.Produk, errHelper *helper.ErrorStruct) {
+	// get gorm client
+	db := pr.db
+
+	// get produk ordered by id, used to walk through every produk
+	query := db.Where("produks.id > ?", afterID)
+	if params.TokoID != 0 {
+		query = query.Where("produks.toko_id = ?", params.TokoID)
+	}
+	if params.CategoryID != 0 {
+		query = query.Where("produks.category_id = ?", params.CategoryID)
+	}
+	if params.BerubahSejak != nil {
+		// renamed toko or category change the indexed text of every produk in it
+		sejak := *params.BerubahSejak
+		query = query.Unscoped().Where("produks.updated_at >= ? OR produks.deleted_at >= ? OR produks.toko_id IN (?) OR produks.category_id IN (?)", sejak, sejak,
+			db.Unscoped().Model(&daos.Toko{}).Select("id").Where("updated_at >= ?", sejak),
+			db.Unscoped().Model(&daos.Category{}).Select("id").Where("updated_at >= ?", sejak))
+	}
+	if errDb := query.Order("produks.id").Limit(limit).Preload("Category").Preload("Toko").Find(&response).Error; errDb != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errDb,
+			Code: http.StatusInternalServerError,
+		}
+		return response, errHelper
+	}
+
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return response, errHelper
+}
+
+// GetProdukEkspor produk of the toko ordered by id with category, toko and foto of the produk itself (not of its sku),
+// tokoID 0 return produk of every toko
+func (pr *ProdukRepositoryImpl) GetProdukEkspor(ctx context.Context, tokoID, afterID uint, limit int) (response []daos.Produk, errHelper *helper.ErrorStruct) {
+	// get gorm client
+	db := pr.db
+
+	query := db.Where("id > ?", afterID)
+	if tokoID > 0 {
+		query = query.Where("toko_id = ?", tokoID)
+	}
+	errDb := query.Order("id").Limit(limit).Preload("Category").Preload("Toko").
+		Preload("FotoProduk", func(db *gorm.DB) *gorm.DB {
+			return urutanFotoProduk(db.Where("sku_id IS NULL"))
+		}).Find(&response).Error
+	if errDb != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errDb,
+			Code: http.StatusInternalServerError,
 		}
 		return response, errHelper
 	}
@@ -0,0 +1,366 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/daos"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/helper"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"net/http"
+	"time"
+)
+
+var (
+	errKuotaPromoHabis = errors.New("flash sale quota is not enough")
+	errBatasPromoUser  = errors.New("flash sale purchase limit per user reached")
+	errPromoSelesai    = errors.New("promo already ended")
+)
+
+type PromoRepository interface {
+	CreatePromo(ctx context.Context, promo daos.Promo) (ID uint, errHelper *helper.ErrorStruct)
+	GetAllPromo(ctx context.Context, tokoID uint, params daos.FilterPromo) (response []daos.Promo, errHelper *helper.ErrorStruct)
+	GetPromoByID(ctx context.Context, ID uint) (response daos.Promo, errHelper *helper.ErrorStruct)
+	BatalkanPromo(ctx context.Context, tokoID, ID uint) (errHelper *helper.ErrorStruct)
+	GetRiwayatHarga(ctx context.Context, produkID uint, params daos.FilterRiwayatHarga) (response []daos.RiwayatHarga, errHelper *helper.ErrorStruct)
+}
+
+type PromoRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewPromoRepository(db *gorm.DB) PromoRepository {
+	return &PromoRepositoryImpl{db: db}
+}
+
+// scopeItemPromoAktif item of promo running at waktu whose quota is not sold out, cheapest first
+func scopeItemPromoAktif(waktu time.Time) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Joins("JOIN promos ON promos.id = item_promos.promo_id AND promos.deleted_at IS NULL").
+			Where("promos.mulai <= ? AND promos.selesai > ?", waktu, waktu).
+			Where("item_promos.kuota = 0 OR item_promos.terjual < item_promos.kuota").
+			Order("item_promos.harga_konsumen, item_promos.id")
+	}
+}
+
+// lengkapiPromoAktif fill PromoAktif of every produk with the cheapest item of promo running now
+func lengkapiPromoAktif(db *gorm.DB, listProduk []daos.Produk) error {
+	if len(listProduk) == 0 {
+		return nil
+	}
+	var listID []uint
+	for _, v := range listProduk {
+		listID = append(listID, v.ID)
+	}
+	var listItem []daos.ItemPromo
+	if err := db.Scopes(scopeItemPromoAktif(time.Now())).Preload("Promo").
+		Where("item_promos.produk_id IN ?", listID).Find(&listItem).Error; err != nil {
+		return err
+	}
+	// list is ordered by price so the first item of a produk is the cheapest
+	listItemByProduk := map[uint]daos.ItemPromo{}
+	for _, v := range listItem {
+		if _, ok := listItemByProduk[v.ProdukID]; !ok {
+			listItemByProduk[v.ProdukID] = v
+		}
+	}
+	for i := range listProduk {
+		if item, ok := listItemByProduk[listProduk[i].ID]; ok {
+			listProduk[i].PromoAktif = &item
+		}
+	}
+	return nil
+}
+
+// pakaiPromo lock the active item promo of produk and count kuantitas bought by user into it,
+// dibeli is kuantitas of each item already bought earlier in the same trx. Nil item means produk has no running promo.
+func pakaiPromo(tx *gorm.DB, produkID, userID, kuantitas uint, dibeli map[uint]uint) (*daos.ItemPromo, error) {
+	var listItem []daos.ItemPromo
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Scopes(scopeItemPromoAktif(time.Now())).
+		Where("item_promos.produk_id = ?", produkID).Limit(1).Find(&listItem).Error; err != nil {
+		return nil, err
+	}
+	if len(listItem) == 0 {
+		return nil, nil
+	}
+	item := listItem[0]
+	if item.Kuota != 0 && item.Terjual+kuantitas > item.Kuota {
+		return nil, errKuotaPromoHabis
+	}
+	if item.BatasPerUser != 0 {
+		var jumlah uint
+		if err := tx.Model(&daos.PembelianPromo{}).Select("COALESCE(SUM(kuantitas), 0)").
+			Where("item_promo_id = ? AND user_id = ?", item.ID, userID).Scan(&jumlah).Error; err != nil {
+			return nil, err
+		}
+		if jumlah+dibeli[item.ID]+kuantitas > item.BatasPerUser {
+			return nil, errBatasPromoUser
+		}
+	}
+	if err := tx.Model(&daos.ItemPromo{}).Where("id = ?", item.ID).
+		UpdateColumn("terjual", gorm.Expr("terjual + ?", kuantitas)).Error; err != nil {
+		return nil, err
+	}
+	dibeli[item.ID] += kuantitas
+	return &item, nil
+}
+
+// catatRiwayatHarga record manual price of produk when it is new or changed
+func catatRiwayatHarga(tx *gorm.DB, produkID, hargaReseller, hargaKonsumen uint) error {
+	var terakhir []daos.RiwayatHarga
+	if err := tx.Where("produk_id = ? AND sumber = ?", produkID, daos.RiwayatHargaManual).
+		Order("berlaku_mulai DESC, id DESC").Limit(1).Find(&terakhir).Error; err != nil {
+		return err
+	}
+	if len(terakhir) > 0 && terakhir[0].HargaReseller == hargaReseller && terakhir[0].HargaKonsumen == hargaKonsumen {
+		return nil
+	}
+	return tx.Create(&daos.RiwayatHarga{
+		ProdukID:      produkID,
+		HargaReseller: hargaReseller,
+		HargaKonsumen: hargaKonsumen,
+		Sumber:        daos.RiwayatHargaManual,
+		BerlakuMulai:  time.Now(),
+	}).Error
+}
+
+// CreatePromo promo with its item, every produk must belong to the toko, be sold lower than its normal price
+// and not be in other promo at the same time
+func (pr *PromoRepositoryImpl) CreatePromo(ctx context.Context, promo daos.Promo) (ID uint, errHelper *helper.ErrorStruct) {
+	// get gorm client
+	db := pr.db
+
+	errTrans := db.Transaction(func(tx *gorm.DB) error {
+		var listID []uint
+		for _, v := range promo.ItemPromo {
+			listID = append(listID, v.ProdukID)
+		}
+		var listProduk []daos.Produk
+		if err := tx.Select("id", "harga_reseller", "harga_konsumen").
+			Where("toko_id = ? AND id IN ?", promo.TokoID, listID).Find(&listProduk).Error; err != nil {
+			return err
+		}
+		listProdukByID := map[uint]daos.Produk{}
+		for _, v := range listProduk {
+			listProdukByID[v.ID] = v
+		}
+		for _, v := range promo.ItemPromo {
+			produk, ok := listProdukByID[v.ProdukID]
+			if !ok {
+				return gorm.ErrRecordNotFound
+			}
+			if v.HargaKonsumen >= produk.HargaKonsumen {
+				errHelper = &helper.ErrorStruct{
+					Err:  fmt.Errorf("harga_konsumen of produk %d must be lower than %d", v.ProdukID, produk.HargaKonsumen),
+					Code: http.StatusBadRequest,
+				}
+				return errHelper.Err
+			}
+		}
+
+		// one produk can only be in one promo at a time
+		var bentrok []daos.ItemPromo
+		if err := tx.Joins("JOIN promos ON promos.id = item_promos.promo_id AND promos.deleted_at IS NULL").
+			Where("item_promos.produk_id IN ?", listID).
+			Where("promos.mulai < ? AND promos.selesai > ?", promo.Selesai, promo.Mulai).
+			Limit(1).Find(&bentrok).Error; err != nil {
+			return err
+		}
+		if len(bentrok) > 0 {
+			errHelper = &helper.ErrorStruct{
+				Err:  fmt.Errorf("produk %d is already in other promo at that time", bentrok[0].ProdukID),
+				Code: http.StatusConflict,
+			}
+			return errHelper.Err
+		}
+
+		if err := tx.Create(&promo).Error; err != nil {
+			return err
+		}
+		var listRiwayat []daos.RiwayatHarga
+		for _, v := range promo.ItemPromo {
+			produk := listProdukByID[v.ProdukID]
+			hargaReseller, hargaKonsumen := v.Harga(produk.HargaReseller, produk.HargaKonsumen)
+			selesai := promo.Selesai
+			listRiwayat = append(listRiwayat, daos.RiwayatHarga{
+				ProdukID:      v.ProdukID,
+				HargaReseller: hargaReseller,
+				HargaKonsumen: hargaKonsumen,
+				Sumber:        promo.Tipe,
+				PromoID:       &promo.ID,
+				BerlakuMulai:  promo.Mulai,
+				BerlakuSampai: &selesai,
+			})
+		}
+		return tx.Create(&listRiwayat).Error
+	})
+	if errTrans != nil {
+		// invalid item already set its own error
+		if errHelper != nil {
+			return ID, errHelper
+		}
+		if errTrans == gorm.ErrRecordNotFound {
+			errHelper = &helper.ErrorStruct{
+				Err:  errors.New("No Data Product"),
+				Code: http.StatusNotFound,
+			}
+			return ID, errHelper
+		}
+		errHelper = &helper.ErrorStruct{
+			Err:  errTrans,
+			Code: http.StatusInternalServerError,
+		}
+		return ID, errHelper
+	}
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return promo.ID, errHelper
+}
+
+// GetAllPromo promo of the toko, tokoID 0 list promo of every toko
+func (pr *PromoRepositoryImpl) GetAllPromo(ctx context.Context, tokoID uint, params daos.FilterPromo) (response []daos.Promo, errHelper *helper.ErrorStruct) {
+	// get gorm client
+	db := pr.db
+
+	query := db.Preload("ItemPromo")
+	if tokoID != 0 {
+		query = query.Where("toko_id = ?", tokoID)
+	}
+	if params.Tipe != "" {
+		query = query.Where("tipe = ?", params.Tipe)
+	}
+	if params.Aktif {
+		sekarang := time.Now()
+		query = query.Where("mulai <= ? AND selesai > ?", sekarang, sekarang)
+	}
+	if errDb := query.Order("mulai DESC, id DESC").Limit(params.Limit).Offset(params.Offset).Find(&response).Error; errDb != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errDb,
+			Code: http.StatusInternalServerError,
+		}
+		return response, errHelper
+	}
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return response, errHelper
+}
+
+func (pr *PromoRepositoryImpl) GetPromoByID(ctx context.Context, ID uint) (response daos.Promo, errHelper *helper.ErrorStruct) {
+	// get gorm client
+	db := pr.db
+
+	if errDb := db.Preload("ItemPromo").First(&response, ID).Error; errDb != nil {
+		if errDb == gorm.ErrRecordNotFound {
+			errHelper = &helper.ErrorStruct{
+				Err:  errors.New("promo not found"),
+				Code: http.StatusNotFound,
+			}
+			return response, errHelper
+		}
+		errHelper = &helper.ErrorStruct{
+			Err:  errDb,
+			Code: http.StatusInternalServerError,
+		}
+		return response, errHelper
+	}
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return response, errHelper
+}
+
+// BatalkanPromo delete promo that has not started, promo already running is ended now so its sale stays in price history
+func (pr *PromoRepositoryImpl) BatalkanPromo(ctx context.Context, tokoID, ID uint) (errHelper *helper.ErrorStruct) {
+	// get gorm client
+	db := pr.db
+
+	errTrans := db.Transaction(func(tx *gorm.DB) error {
+		var promo daos.Promo
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("toko_id = ? AND id = ?", tokoID, ID).First(&promo).Error; err != nil {
+			return err
+		}
+		sekarang := time.Now()
+		if !promo.Selesai.After(sekarang) {
+			return errPromoSelesai
+		}
+		if promo.Mulai.After(sekarang) {
+			if err := tx.Where("promo_id = ?", promo.ID).Delete(&daos.RiwayatHarga{}).Error; err != nil {
+				return err
+			}
+			return tx.Delete(&promo).Error
+		}
+		if err := tx.Model(&promo).Update("selesai", sekarang).Error; err != nil {
+			return err
+		}
+		return tx.Model(&daos.RiwayatHarga{}).Where("promo_id = ?", promo.ID).Update("berlaku_sampai", sekarang).Error
+	})
+	if errTrans != nil {
+		if errTrans == gorm.ErrRecordNotFound {
+			errHelper = &helper.ErrorStruct{
+				Err:  errors.New("promo not found"),
+				Code: http.StatusNotFound,
+			}
+			return errHelper
+		}
+		if errTrans == errPromoSelesai {
+			errHelper = &helper.ErrorStruct{
+				Err:  errTrans,
+				Code: http.StatusBadRequest,
+			}
+			return errHelper
+		}
+		errHelper = &helper.ErrorStruct{
+			Err:  errTrans,
+			Code: http.StatusInternalServerError,
+		}
+		return errHelper
+	}
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return errHelper
+}
+
+// GetRiwayatHarga price history of produk, the newest first
+func (pr *PromoRepositoryImpl) GetRiwayatHarga(ctx context.Context, produkID uint, params daos.FilterRiwayatHarga) (response []daos.RiwayatHarga, errHelper *helper.ErrorStruct) {
+	// get gorm client
+	db := pr.db
+
+	var produk daos.Produk
+	errDb := db.Select("id").First(&produk, produkID).Error
+	if errDb == nil {
+		errDb = db.Where("produk_id = ?", produkID).Order("berlaku_mulai DESC, id DESC").
+			Limit(params.Limit).Offset(params.Offset).Find(&response).Error
+	}
+	if errDb != nil {
+		if errDb == gorm.ErrRecordNotFound {
+			errHelper = &helper.ErrorStruct{
+				Err:  errors.New("No Data Product"),
+				Code: http.StatusNotFound,
+			}
+			return response, errHelper
+		}
+		errHelper = &helper.ErrorStruct{
+			Err:  errDb,
+			Code: http.StatusInternalServerError,
+		}
+		return response, errHelper
+	}
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return response, errHelper
+}
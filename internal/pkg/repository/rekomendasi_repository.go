@@ -0,0 +1,278 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/daos"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/helper"
+	"gorm.io/gorm"
+	"net/http"
+)
+
+// ukuranBatchProdukTerkait number of related produk inserted at once when the table is rebuilt
+const ukuranBatchProdukTerkait = 500
+
+type RekomendasiRepository interface {
+	HitungProdukTerkait(ctx context.Context) (jumlah int, errHelper *helper.ErrorStruct)
+	GetProdukTerkait(ctx context.Context, produkID uint, limit int) (response []daos.Produk, errHelper *helper.ErrorStruct)
+	GetRekomendasiUser(ctx context.Context, userID uint, limit int) (response []daos.Produk, errHelper *helper.ErrorStruct)
+}
+
+type RekomendasiRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewRekomendasiRepository(db *gorm.DB) RekomendasiRepository {
+	return &RekomendasiRepositoryImpl{db: db}
+}
+
+// HitungProdukTerkait rebuild related produk from every trx, only BatasProdukTerkait produk with the highest skor are kept
+func (rr *RekomendasiRepositoryImpl) HitungProdukTerkait(ctx context.Context) (jumlah int, errHelper *helper.ErrorStruct) {
+	// get gorm client
+	db := rr.db.WithContext(ctx)
+
+	errTrans := db.Transaction(func(tx *gorm.DB) error {
+		// every produk of a trx once, then pair it with other produk of the same trx
+		produkTRX := tx.Session(&gorm.Session{NewDB: true}).Table("detail_trxes").
+			Select("DISTINCT detail_trxes.trx_id, log_produks.produk_id").
+			Joins("JOIN log_produks ON log_produks.id = detail_trxes.log_produk_id")
+		rows, err := tx.Table("(?) AS a", produkTRX).
+			Select("a.produk_id, b.produk_id AS terkait_id, COUNT(*) AS skor").
+			Joins("JOIN (?) AS b ON b.trx_id = a.trx_id AND b.produk_id <> a.produk_id", produkTRX).
+			Group("a.produk_id, b.produk_id").
+			Order("a.produk_id, skor DESC, terkait_id").Rows()
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		// rows are ordered by produk then skor, so the first rows of a produk have the highest skor.
+		// Every row is read before writing because the connection is busy until rows are closed.
+		var listSimpan []daos.ProdukTerkait
+		var produkID uint
+		var urutan int
+		for rows.Next() {
+			var terkait daos.ProdukTerkait
+			if err := tx.ScanRows(rows, &terkait); err != nil {
+				return err
+			}
+			if terkait.ProdukID != produkID {
+				produkID, urutan = terkait.ProdukID, 0
+			}
+			urutan++
+			if urutan <= daos.BatasProdukTerkait {
+				listSimpan = append(listSimpan, terkait)
+			}
+		}
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		rows.Close()
+
+		if err := tx.Where("1 = 1").Delete(&daos.ProdukTerkait{}).Error; err != nil {
+			return err
+		}
+		if len(listSimpan) > 0 {
+			if err := tx.CreateInBatches(&listSimpan, ukuranBatchProdukTerkait).Error; err != nil {
+				return err
+			}
+		}
+		jumlah = len(listSimpan)
+		return nil
+	})
+	if errTrans != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errTrans,
+			Code: http.StatusInternalServerError,
+		}
+		return jumlah, errHelper
+	}
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return jumlah, errHelper
+}
+
+// GetProdukTerkait produk frequently bought together with the produk, filled with best seller of the same category
+func (rr *RekomendasiRepositoryImpl) GetProdukTerkait(ctx context.Context, produkID uint, limit int) (response []daos.Produk, errHelper *helper.ErrorStruct) {
+	// get gorm client
+	db := rr.db
+
+	var produk daos.Produk
+	if errDb := db.Select("id", "category_id").First(&produk, produkID).Error; errDb != nil {
+		if errDb == gorm.ErrRecordNotFound {
+			errHelper = &helper.ErrorStruct{
+				Err:  errors.New("No Data Product"),
+				Code: http.StatusNotFound,
+			}
+			return response, errHelper
+		}
+		errHelper = &helper.ErrorStruct{
+			Err:  errDb,
+			Code: http.StatusInternalServerError,
+		}
+		return response, errHelper
+	}
+
+	var listID []uint
+	errDb := db.Model(&daos.ProdukTerkait{}).Where("produk_id = ?", produkID).
+		Order("skor DESC, terkait_id").Pluck("terkait_id", &listID).Error
+	if errDb == nil {
+		response, errDb = lengkapiRekomendasi(db, listID, []uint{produk.CategoryID}, []uint{produkID}, 0, limit)
+	}
+	if errDb != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errDb,
+			Code: http.StatusInternalServerError,
+		}
+		return response, errHelper
+	}
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return response, errHelper
+}
+
+// GetRekomendasiUser produk frequently bought together with what the user bought before, filled with best seller
+// of category the user bought from. User without trx get best seller of every category.
+// Produk already bought and produk of the user's own toko are not recommended.
+func (rr *RekomendasiRepositoryImpl) GetRekomendasiUser(ctx context.Context, userID uint, limit int) (response []daos.Produk, errHelper *helper.ErrorStruct) {
+	// get gorm client
+	db := rr.db
+
+	errDb := func() error {
+		var listDibeli []uint
+		if err := db.Model(&daos.DetailTRX{}).Distinct("log_produks.produk_id").
+			Joins("JOIN trxes ON trxes.id = detail_trxes.trx_id").
+			Joins("JOIN log_produks ON log_produks.id = detail_trxes.log_produk_id").
+			Where("trxes.user_id = ?", userID).Pluck("log_produks.produk_id", &listDibeli).Error; err != nil {
+			return err
+		}
+		if len(listDibeli) == 0 {
+			var err error
+			response, err = lengkapiRekomendasi(db, nil, nil, nil, userID, limit)
+			return err
+		}
+
+		var listCategory []uint
+		if err := db.Unscoped().Model(&daos.Produk{}).Distinct("category_id").
+			Where("id IN ?", listDibeli).Pluck("category_id", &listCategory).Error; err != nil {
+			return err
+		}
+		// related produk of every bought produk, produk related to more of them come first
+		var listID []uint
+		if err := db.Model(&daos.ProdukTerkait{}).Select("terkait_id").
+			Where("produk_id IN ? AND terkait_id NOT IN ?", listDibeli, listDibeli).
+			Group("terkait_id").Order("SUM(skor) DESC, terkait_id").Limit(limit*2).
+			Pluck("terkait_id", &listID).Error; err != nil {
+			return err
+		}
+		var err error
+		response, err = lengkapiRekomendasi(db, listID, listCategory, listDibeli, userID, limit)
+		return err
+	}()
+	if errDb != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errDb,
+			Code: http.StatusInternalServerError,
+		}
+		return response, errHelper
+	}
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return response, errHelper
+}
+
+// lengkapiRekomendasi load produk of listID in its order and fill the rest up to limit with best seller of listCategory,
+// then with best seller of every category. Produk in kecuali, of toko kecualiToko or out of stock are skipped.
+func lengkapiRekomendasi(db *gorm.DB, listID, listCategory, kecuali []uint, kecualiToko uint, limit int) ([]daos.Produk, error) {
+	response, err := muatProdukTersedia(db, listID, kecualiToko)
+	if err != nil {
+		return nil, err
+	}
+	if len(response) > limit {
+		response = response[:limit]
+	}
+
+	// best seller of the category first, then of every category
+	listTahap := [][]uint{listCategory, nil}
+	if len(listCategory) == 0 {
+		listTahap = [][]uint{nil}
+	}
+	for _, category := range listTahap {
+		if len(response) >= limit {
+			break
+		}
+		dilewati := append([]uint{}, kecuali...)
+		for _, v := range response {
+			dilewati = append(dilewati, v.ID)
+		}
+		listTerlaris, err := produkTerlaris(db, category, dilewati, kecualiToko, limit-len(response))
+		if err != nil {
+			return nil, err
+		}
+		listProduk, err := muatProdukTersedia(db, listTerlaris, kecualiToko)
+		if err != nil {
+			return nil, err
+		}
+		response = append(response, listProduk...)
+	}
+
+	if err := lengkapiPromoAktif(db, response); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// produkTerlaris id of produk in stock with the most sold kuantitas
+func produkTerlaris(db *gorm.DB, listCategory, kecuali []uint, kecualiToko uint, limit int) (listID []uint, err error) {
+	query := db.Model(&daos.Produk{}).
+		Joins("JOIN log_produks ON log_produks.produk_id = produks.id").
+		Joins("JOIN detail_trxes ON detail_trxes.log_produk_id = log_produks.id").
+		Where("produks.stok > 0").Scopes(scopeProdukTampil)
+	if len(listCategory) > 0 {
+		query = query.Where("produks.category_id IN ?", listCategory)
+	}
+	if len(kecuali) > 0 {
+		query = query.Where("produks.id NOT IN ?", kecuali)
+	}
+	if kecualiToko != 0 {
+		query = query.Where("produks.toko_id <> ?", kecualiToko)
+	}
+	err = query.Group("produks.id").Order("SUM(detail_trxes.kuantitas) DESC, produks.id DESC").
+		Limit(limit).Pluck("produks.id", &listID).Error
+	return listID, err
+}
+
+// muatProdukTersedia produk of listID that are in stock and shown to buyer, in the order of listID
+func muatProdukTersedia(db *gorm.DB, listID []uint, kecualiToko uint) (response []daos.Produk, err error) {
+	response = []daos.Produk{}
+	if len(listID) == 0 {
+		return response, nil
+	}
+	query := db.Where("id IN ? AND stok > 0", listID).Scopes(scopeProdukTampil)
+	if kecualiToko != 0 {
+		query = query.Where("toko_id <> ?", kecualiToko)
+	}
+	var listProduk []daos.Produk
+	if err := query.Preload("FotoProduk", urutanFotoProduk).Preload("Category").Preload("Toko").Find(&listProduk).Error; err != nil {
+		return nil, err
+	}
+	listProdukByID := map[uint]daos.Produk{}
+	for _, v := range listProduk {
+		listProdukByID[v.ID] = v
+	}
+	for _, ID := range listID {
+		if produk, ok := listProdukByID[ID]; ok {
+			response = append(response, produk)
+		}
+	}
+	return response, nil
+}
@@ -0,0 +1,184 @@
+package repository
+
+import (
+	"context"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/daos"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/helper"
+	"gorm.io/gorm"
+	"net/http"
+	"time"
+)
+
+type SampahRepository interface {
+	PurgeSampah(ctx context.Context, batas time.Time) (listURL []string, jumlah int64, errHelper *helper.ErrorStruct)
+}
+
+type SampahRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewSampahRepository(db *gorm.DB) SampahRepository {
+	return &SampahRepositoryImpl{db: db}
+}
+
+// PurgeSampah permanently delete record that is in trash since before batas, file of deleted foto and toko logo is returned so it can be removed.
+// Record still referred by transaction history or chat can not be deleted and stays in trash.
+func (sr *SampahRepositoryImpl) PurgeSampah(ctx context.Context, batas time.Time) (listURL []string, jumlah int64, errHelper *helper.ErrorStruct) {
+	// get gorm client, trash is only reachable unscoped
+	db := sr.db
+
+	errDb := db.Transaction(func(tx *gorm.DB) error {
+		// produk go first because foto, toko and category can only be deleted once no produk use them
+		var listIDProduk []uint
+		if err := tx.Unscoped().Model(&daos.Produk{}).Where("deleted_at < ?", batas).
+			Where("NOT EXISTS (SELECT 1 FROM log_produks WHERE log_produks.produk_id = produks.id)").
+			Where("NOT EXISTS (SELECT 1 FROM percakapans WHERE percakapans.produk_id = produks.id)").
+			Pluck("id", &listIDProduk).Error; err != nil {
+			return err
+		}
+
+		// foto deleted one by one from produk that is not in trash, and every foto of purged produk
+		var listFoto []daos.FotoProduk
+		query := tx.Unscoped().Where("deleted_at < ? AND produk_id IN (SELECT id FROM produks WHERE deleted_at IS NULL)", batas)
+		if len(listIDProduk) > 0 {
+			query = query.Or("produk_id IN ?", listIDProduk)
+		}
+		if err := query.Find(&listFoto).Error; err != nil {
+			return err
+		}
+		if len(listFoto) > 0 {
+			var listIDFoto []uint
+			for _, v := range listFoto {
+				listIDFoto = append(listIDFoto, v.ID)
+				listURL = append(listURL, v.ListURL()...)
+			}
+			result := tx.Unscoped().Where("id IN ?", listIDFoto).Delete(&daos.FotoProduk{})
+			if result.Error != nil {
+				return result.Error
+			}
+			jumlah += result.RowsAffected
+		}
+		if len(listIDProduk) > 0 {
+			if err := tx.Unscoped().Where("produk_id IN ?", listIDProduk).Delete(&daos.OpsiVarian{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Unscoped().Where("produk_id IN ?", listIDProduk).Delete(&daos.SKU{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Where("produk_id IN ?", listIDProduk).Delete(&daos.AtributProduk{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Where("diskusi_id IN (?)", tx.Model(&daos.DiskusiProduk{}).Select("id").Where("produk_id IN ?", listIDProduk)).
+				Delete(&daos.TanggapanDiskusi{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Where("produk_id IN ?", listIDProduk).Delete(&daos.DiskusiProduk{}).Error; err != nil {
+				return err
+			}
+			// sale of produk is kept in log_produk, so purged produk has no mutasi from trx
+			if err := tx.Where("produk_id IN ?", listIDProduk).Delete(&daos.MutasiStok{}).Error; err != nil {
+				return err
+			}
+			// promo item bought in a trx has log_produk too, so the purged one has no pembelian
+			if err := tx.Where("produk_id IN ?", listIDProduk).Delete(&daos.ItemPromo{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Where("produk_id IN ?", listIDProduk).Delete(&daos.RiwayatHarga{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Where("produk_id IN ? OR terkait_id IN ?", listIDProduk, listIDProduk).Delete(&daos.ProdukTerkait{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Where("produk_id IN ?", listIDProduk).Delete(&daos.StatistikProduk{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Where("produk_id IN ?", listIDProduk).Delete(&daos.StatistikHarian{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Where("produk_id IN ?", listIDProduk).Delete(&daos.RiwayatModerasi{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Where("tipe = ? AND ref_id IN ?", daos.SlugTipeProduk, listIDProduk).Delete(&daos.Slug{}).Error; err != nil {
+				return err
+			}
+			result := tx.Unscoped().Where("id IN ?", listIDProduk).Delete(&daos.Produk{})
+			if result.Error != nil {
+				return result.Error
+			}
+			jumlah += result.RowsAffected
+		}
+
+		// toko without any produk, sale or chat left
+		var listToko []daos.Toko
+		if err := tx.Unscoped().Where("deleted_at < ?", batas).
+			Where("NOT EXISTS (SELECT 1 FROM produks WHERE produks.toko_id = tokos.id)").
+			Where("NOT EXISTS (SELECT 1 FROM log_produks WHERE log_produks.toko_id = tokos.id)").
+			Where("NOT EXISTS (SELECT 1 FROM detail_trxes WHERE detail_trxes.toko_id = tokos.id)").
+			Where("NOT EXISTS (SELECT 1 FROM percakapans WHERE percakapans.toko_id = tokos.id)").
+			Find(&listToko).Error; err != nil {
+			return err
+		}
+		if len(listToko) > 0 {
+			var listIDToko []uint
+			for _, v := range listToko {
+				listIDToko = append(listIDToko, v.ID)
+				if v.UrlFoto != "" {
+					listURL = append(listURL, v.UrlFoto)
+				}
+			}
+			// item of the promo belong to produk of the toko which are already purged
+			if err := tx.Unscoped().Where("toko_id IN ?", listIDToko).Delete(&daos.Promo{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Where("tipe = ? AND ref_id IN ?", daos.SlugTipeToko, listIDToko).Delete(&daos.Slug{}).Error; err != nil {
+				return err
+			}
+			result := tx.Unscoped().Where("id IN ?", listIDToko).Delete(&daos.Toko{})
+			if result.Error != nil {
+				return result.Error
+			}
+			jumlah += result.RowsAffected
+		}
+
+		// category without any produk left
+		result := tx.Unscoped().Where("deleted_at < ?", batas).
+			Where("NOT EXISTS (SELECT 1 FROM produks WHERE produks.category_id = categories.id)").
+			Where("NOT EXISTS (SELECT 1 FROM log_produks WHERE log_produks.category_id = categories.id)").
+			Delete(&daos.Category{})
+		if result.Error != nil {
+			return result.Error
+		}
+		jumlah += result.RowsAffected
+		// attribute schema of category that no longer exist, its produk are already deleted
+		if err := tx.Where("NOT EXISTS (SELECT 1 FROM categories WHERE categories.id = atribut_categories.category_id)").
+			Delete(&daos.AtributCategory{}).Error; err != nil {
+			return err
+		}
+
+		// alamat never used by any trx
+		result = tx.Unscoped().Where("deleted_at < ?", batas).
+			Where("NOT EXISTS (SELECT 1 FROM trxes WHERE trxes.alamat_id = alamats.id)").
+			Delete(&daos.Alamat{})
+		if result.Error != nil {
+			return result.Error
+		}
+		jumlah += result.RowsAffected
+
+		// return nil will commit the whole transaction
+		return nil
+	})
+	// error checking
+	if errDb != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errDb,
+			Code: http.StatusInternalServerError,
+		}
+		return nil, 0, errHelper
+	}
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return listURL, jumlah, errHelper
+}
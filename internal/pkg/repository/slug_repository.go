@@ -0,0 +1,145 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/daos"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/helper"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"net/http"
+)
+
+type SlugRepository interface {
+	LengkapiSlug(ctx context.Context, tipe string, limit int) (jumlah int, errHelper *helper.ErrorStruct)
+}
+
+type SlugRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewSlugRepository(db *gorm.DB) SlugRepository {
+	return &SlugRepositoryImpl{db: db}
+}
+
+// sumberSlug table and name column of record that has slug
+var sumberSlug = map[string]struct {
+	model     interface{}
+	tabel     string
+	kolomNama string
+}{
+	daos.SlugTipeProduk: {&daos.Produk{}, "produks", "nama_produk"},
+	daos.SlugTipeToko:   {&daos.Toko{}, "tokos", "nama_toko"},
+}
+
+// simpanSlug give record unique slug made from nama, number is added when the slug is already used by other record.
+// Rename that give the same slug keep the current slug, so number given on collision does not change.
+// It must be called inside transaction because slug of the same name is locked until commit.
+func simpanSlug(tx *gorm.DB, tipe string, refID uint, nama string) (slug string, err error) {
+	sumber, ada := sumberSlug[tipe]
+	if !ada {
+		return "", errors.New("unknown slug type " + tipe)
+	}
+	dasar := helper.BuatSlug(nama)
+	if dasar == "" {
+		dasar = tipe
+	}
+
+	// every slug of the record and every slug made from the same dasar
+	var listSlug []daos.Slug
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("tipe = ? AND (ref_id = ? OR slug = ? OR slug LIKE ?)", tipe, refID, dasar, dasar+"-%").
+		Find(&listSlug).Error; err != nil {
+		return "", err
+	}
+	pemilik := map[string]uint{}
+	for _, v := range listSlug {
+		pemilik[v.Slug] = v.RefID
+	}
+	var sekarang string
+	if err := tx.Unscoped().Model(sumber.model).Select("slug").Where("id = ?", refID).Scan(&sekarang).Error; err != nil {
+		return "", err
+	}
+	if pemilik[sekarang] == refID && helper.SlugDariDasar(sekarang, dasar) {
+		return sekarang, nil
+	}
+
+	for nomor := 1; ; nomor++ {
+		slug = helper.SlugBernomor(dasar, nomor)
+		ref, dipakai := pemilik[slug]
+		if dipakai && ref != refID {
+			continue
+		}
+		// old slug of the same record is used again when the record is renamed back
+		if !dipakai {
+			if err := tx.Create(&daos.Slug{Tipe: tipe, Slug: slug, RefID: refID}).Error; err != nil {
+				return "", err
+			}
+		}
+		if err := tx.Unscoped().Model(sumber.model).Where("id = ?", refID).UpdateColumn("slug", slug).Error; err != nil {
+			return "", err
+		}
+		return slug, nil
+	}
+}
+
+// cariSlug id of record that has or had the slug
+func cariSlug(db *gorm.DB, tipe, slug string) (refID uint, err error) {
+	var data daos.Slug
+	if err := db.Where("tipe = ? AND slug = ?", tipe, slug).First(&data).Error; err != nil {
+		return 0, err
+	}
+	return data.RefID, nil
+}
+
+// LengkapiSlug give slug to record created before slug table existed, or whose slug failed to be saved.
+// Trashed record get slug too so it keep its url when restored.
+func (sr *SlugRepositoryImpl) LengkapiSlug(ctx context.Context, tipe string, limit int) (jumlah int, errHelper *helper.ErrorStruct) {
+	// get gorm client
+	db := sr.db
+
+	sumber, ada := sumberSlug[tipe]
+	if !ada {
+		errHelper = &helper.ErrorStruct{
+			Err:  errors.New("unknown slug type " + tipe),
+			Code: http.StatusBadRequest,
+		}
+		return 0, errHelper
+	}
+	var listSumber []struct {
+		ID   uint
+		Nama string
+	}
+	tanpaSlug := db.Model(&daos.Slug{}).Select("1").Where("slugs.tipe = ? AND slugs.ref_id = "+sumber.tabel+".id", tipe)
+	if errDb := db.Unscoped().Model(sumber.model).Select("id, "+sumber.kolomNama+" AS nama").
+		Where("NOT EXISTS (?)", tanpaSlug).Order("id").Limit(limit).Scan(&listSumber).Error; errDb != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errDb,
+			Code: http.StatusInternalServerError,
+		}
+		return 0, errHelper
+	}
+
+	// record with the same name get number in order of id
+	for _, v := range listSumber {
+		errDb := db.Transaction(func(tx *gorm.DB) error {
+			_, err := simpanSlug(tx, tipe, v.ID, v.Nama)
+			return err
+		})
+		if errDb != nil {
+			errHelper = &helper.ErrorStruct{
+				Err:  errDb,
+				Code: http.StatusInternalServerError,
+			}
+			return jumlah, errHelper
+		}
+		jumlah++
+	}
+
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return jumlah, errHelper
+}
@@ -0,0 +1,197 @@
+package repository
+
+import (
+	"context"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/daos"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/helper"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"net/http"
+	"time"
+)
+
+// ukuranBatchStatistik number of statistik row written at once
+const ukuranBatchStatistik = 500
+
+type StatistikRepository interface {
+	SimpanTayangan(ctx context.Context, jumlah map[uint]uint) (errHelper *helper.ErrorStruct)
+	HitungTerjual(ctx context.Context, hari int) (errHelper *helper.ErrorStruct)
+	GetProdukTrending(ctx context.Context, params daos.FilterPopuler) (response []daos.Produk, errHelper *helper.ErrorStruct)
+	GetProdukTerlaris(ctx context.Context, params daos.FilterPopuler) (response []daos.Produk, errHelper *helper.ErrorStruct)
+}
+
+type StatistikRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewStatistikRepository(db *gorm.DB) StatistikRepository {
+	return &StatistikRepositoryImpl{db: db}
+}
+
+// awalHari midnight of the day of waktu, statistik harian is grouped by it
+func awalHari(waktu time.Time) time.Time {
+	tahun, bulan, tanggal := waktu.Date()
+	return time.Date(tahun, bulan, tanggal, 0, 0, 0, 0, waktu.Location())
+}
+
+// SimpanTayangan add counted view of every produk id to its total and to statistik of today
+func (sr *StatistikRepositoryImpl) SimpanTayangan(ctx context.Context, jumlah map[uint]uint) (errHelper *helper.ErrorStruct) {
+	// get gorm client
+	db := sr.db.WithContext(ctx)
+
+	hariIni := awalHari(time.Now())
+	var listStatistik []daos.StatistikProduk
+	var listHarian []daos.StatistikHarian
+	for produkID, n := range jumlah {
+		listStatistik = append(listStatistik, daos.StatistikProduk{ProdukID: produkID, Dilihat: n})
+		listHarian = append(listHarian, daos.StatistikHarian{ProdukID: produkID, Tanggal: hariIni, Dilihat: n})
+	}
+	errTrans := db.Transaction(func(tx *gorm.DB) error {
+		if len(listStatistik) == 0 {
+			return nil
+		}
+		if err := tx.Clauses(clause.OnConflict{DoUpdates: clause.Assignments(map[string]interface{}{
+			"dilihat":    gorm.Expr("dilihat + VALUES(dilihat)"),
+			"updated_at": gorm.Expr("VALUES(updated_at)"),
+		})}).CreateInBatches(&listStatistik, ukuranBatchStatistik).Error; err != nil {
+			return err
+		}
+		return tx.Clauses(clause.OnConflict{DoUpdates: clause.Assignments(map[string]interface{}{
+			"dilihat": gorm.Expr("dilihat + VALUES(dilihat)"),
+		})}).CreateInBatches(&listHarian, ukuranBatchStatistik).Error
+	})
+	if errTrans != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errTrans,
+			Code: http.StatusInternalServerError,
+		}
+		return errHelper
+	}
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return errHelper
+}
+
+// HitungTerjual recount total sold kuantitas of every produk and sold kuantitas per day of the last days from trx
+func (sr *StatistikRepositoryImpl) HitungTerjual(ctx context.Context, hari int) (errHelper *helper.ErrorStruct) {
+	// get gorm client
+	db := sr.db.WithContext(ctx)
+
+	errTrans := db.Transaction(func(tx *gorm.DB) error {
+		penjualan := func() *gorm.DB {
+			return tx.Model(&daos.DetailTRX{}).Joins("JOIN log_produks ON log_produks.id = detail_trxes.log_produk_id")
+		}
+
+		var listStatistik []daos.StatistikProduk
+		if err := penjualan().Select("log_produks.produk_id, SUM(detail_trxes.kuantitas) AS terjual").
+			Group("log_produks.produk_id").Scan(&listStatistik).Error; err != nil {
+			return err
+		}
+		var listHarian []daos.StatistikHarian
+		if err := penjualan().Select("log_produks.produk_id, DATE(detail_trxes.created_at) AS tanggal, SUM(detail_trxes.kuantitas) AS terjual").
+			Where("detail_trxes.created_at >= ?", awalHari(time.Now()).AddDate(0, 0, 1-hari)).
+			Group("log_produks.produk_id, DATE(detail_trxes.created_at)").Scan(&listHarian).Error; err != nil {
+			return err
+		}
+
+		if len(listStatistik) > 0 {
+			if err := tx.Clauses(clause.OnConflict{DoUpdates: clause.AssignmentColumns([]string{"terjual", "updated_at"})}).
+				CreateInBatches(&listStatistik, ukuranBatchStatistik).Error; err != nil {
+				return err
+			}
+		}
+		if len(listHarian) > 0 {
+			if err := tx.Clauses(clause.OnConflict{DoUpdates: clause.AssignmentColumns([]string{"terjual"})}).
+				CreateInBatches(&listHarian, ukuranBatchStatistik).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if errTrans != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errTrans,
+			Code: http.StatusInternalServerError,
+		}
+		return errHelper
+	}
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return errHelper
+}
+
+// GetProdukTrending produk in stock with the highest view and weighted sold kuantitas in the last days
+func (sr *StatistikRepositoryImpl) GetProdukTrending(ctx context.Context, params daos.FilterPopuler) (response []daos.Produk, errHelper *helper.ErrorStruct) {
+	// get gorm client
+	db := sr.db
+
+	trending := db.Session(&gorm.Session{NewDB: true}).Model(&daos.StatistikHarian{}).
+		Select("produk_id, SUM(dilihat) AS dilihat, SUM(terjual) AS terjual, SUM(dilihat) + SUM(terjual) * ? AS skor", daos.BobotTerjualTrending).
+		Where("tanggal >= ?", awalHari(time.Now()).AddDate(0, 0, 1-params.Hari)).
+		Group("produk_id")
+	query := db.Model(&daos.Produk{}).Select("produks.id AS produk_id, trending.dilihat, trending.terjual").
+		Joins("JOIN (?) AS trending ON trending.produk_id = produks.id", trending).
+		Order("trending.skor DESC, produks.id DESC")
+	return sr.getProdukPopuler(query, params)
+}
+
+// GetProdukTerlaris produk in stock with the highest total sold kuantitas
+func (sr *StatistikRepositoryImpl) GetProdukTerlaris(ctx context.Context, params daos.FilterPopuler) (response []daos.Produk, errHelper *helper.ErrorStruct) {
+	// get gorm client
+	db := sr.db
+
+	query := db.Model(&daos.Produk{}).Select("produks.id AS produk_id, statistik_produks.dilihat, statistik_produks.terjual").
+		Joins("JOIN statistik_produks ON statistik_produks.produk_id = produks.id").
+		Where("statistik_produks.terjual > 0").
+		Order("statistik_produks.terjual DESC, produks.id DESC")
+	return sr.getProdukPopuler(query, params)
+}
+
+// getProdukPopuler load produk of ordered statistik query with its statistik
+func (sr *StatistikRepositoryImpl) getProdukPopuler(query *gorm.DB, params daos.FilterPopuler) (response []daos.Produk, errHelper *helper.ErrorStruct) {
+	db := sr.db
+
+	query = query.Where("produks.stok > 0").Scopes(scopeProdukTampil)
+	if params.CategoryID != 0 {
+		query = query.Where("produks.category_id = ?", params.CategoryID)
+	}
+	var listStatistik []daos.StatistikProduk
+	errDb := query.Limit(params.Limit).Offset(params.Offset).Scan(&listStatistik).Error
+	if errDb == nil {
+		var listID []uint
+		for _, v := range listStatistik {
+			listID = append(listID, v.ProdukID)
+		}
+		response, errDb = muatProdukTersedia(db, listID, 0)
+	}
+	if errDb == nil {
+		errDb = lengkapiPromoAktif(db, response)
+	}
+	if errDb != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errDb,
+			Code: http.StatusInternalServerError,
+		}
+		return response, errHelper
+	}
+	listStatistikByID := map[uint]daos.StatistikProduk{}
+	for _, v := range listStatistik {
+		listStatistikByID[v.ProdukID] = v
+	}
+	for i := range response {
+		statistik := listStatistikByID[response[i].ID]
+		response[i].Statistik = &statistik
+	}
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return response, errHelper
+}
@@ -0,0 +1,276 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/daos"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/helper"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"net/http"
+)
+
+var (
+	errStokNegatif   = errors.New("stock can not be negative")
+	errSKUDibutuhkan = errors.New("sku_id is required for product with variant")
+	errSKUTidakAda   = errors.New("sku not found")
+)
+
+type StokRepository interface {
+	UbahStok(ctx context.Context, tokoID uint, mutasi daos.MutasiStok, stokBaru *uint) (response daos.MutasiStok, errHelper *helper.ErrorStruct)
+	GetMutasiStok(ctx context.Context, tokoID, produkID uint, params daos.FilterMutasiStok) (response []daos.MutasiStok, errHelper *helper.ErrorStruct)
+	SetBatasStokRendahProduk(ctx context.Context, tokoID, produkID uint, batas *uint) (errHelper *helper.ErrorStruct)
+	SetBatasStokRendahToko(ctx context.Context, tokoID uint, batas *uint) (errHelper *helper.ErrorStruct)
+	GetProdukStokRendah(ctx context.Context, tokoID uint, limit, offset int) (response []daos.Produk, errHelper *helper.ErrorStruct)
+}
+
+type StokRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewStokRepository(db *gorm.DB) StokRepository {
+	return &StokRepositoryImpl{db: db}
+}
+
+// catatMutasiStok save movement made in the same transaction as the stok change, movement without change is skipped
+func catatMutasiStok(tx *gorm.DB, listMutasi []daos.MutasiStok) error {
+	var listDicatat []daos.MutasiStok
+	for _, v := range listMutasi {
+		if v.Perubahan != 0 {
+			listDicatat = append(listDicatat, v)
+		}
+	}
+	if len(listDicatat) == 0 {
+		return nil
+	}
+	return tx.Create(&listDicatat).Error
+}
+
+// UbahStok change stok produk or sku of the toko by mutasi.Perubahan, or to stokBaru when it is given such as after stock count.
+// Sku is required for produk with variant and the stok produk follow the sum of its sku.
+func (sr *StokRepositoryImpl) UbahStok(ctx context.Context, tokoID uint, mutasi daos.MutasiStok, stokBaru *uint) (response daos.MutasiStok, errHelper *helper.ErrorStruct) {
+	// get gorm client
+	db := sr.db
+
+	errDb := db.Transaction(func(tx *gorm.DB) error {
+		var produk daos.Produk
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("toko_id = ? AND id = ?", tokoID, mutasi.ProdukID).First(&produk).Error; err != nil {
+			return err
+		}
+		var jumlahSKU int64
+		if err := tx.Model(&daos.SKU{}).Where("produk_id = ?", produk.ID).Count(&jumlahSKU).Error; err != nil {
+			return err
+		}
+
+		// stok that is changed, sku or produk
+		stok := produk.Stok
+		var sku daos.SKU
+		switch {
+		case mutasi.SKUID != nil:
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ? AND produk_id = ?", *mutasi.SKUID, produk.ID).First(&sku).Error; err != nil {
+				if err == gorm.ErrRecordNotFound {
+					return errSKUTidakAda
+				}
+				return err
+			}
+			stok = sku.Stok
+		case jumlahSKU > 0:
+			return errSKUDibutuhkan
+		}
+		if stokBaru != nil {
+			mutasi.Perubahan = int(*stokBaru) - int(stok)
+		}
+		if int(stok)+mutasi.Perubahan < 0 {
+			return errStokNegatif
+		}
+		mutasi.Saldo = uint(int(stok) + mutasi.Perubahan)
+		mutasi.SaldoProduk = uint(int(produk.Stok) + mutasi.Perubahan)
+
+		if sku.ID != 0 {
+			if err := tx.Model(&daos.SKU{}).Where("id = ?", sku.ID).Update("stok", mutasi.Saldo).Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.Model(&daos.Produk{}).Where("id = ?", produk.ID).Updates(map[string]interface{}{
+			"stok":  mutasi.SaldoProduk,
+			"versi": versiBaru,
+		}).Error; err != nil {
+			return err
+		}
+		// stock count that match the stok is still recorded so the count is in history
+		if err := tx.Create(&mutasi).Error; err != nil {
+			return err
+		}
+		response = mutasi
+		return nil
+	})
+	// error checking
+	if errDb != nil {
+		if errDb == gorm.ErrRecordNotFound {
+			errHelper = &helper.ErrorStruct{
+				Err:  errors.New("No Data Product"),
+				Code: http.StatusNotFound,
+			}
+			return response, errHelper
+		}
+		if errDb == errSKUTidakAda {
+			errHelper = &helper.ErrorStruct{
+				Err:  errDb,
+				Code: http.StatusNotFound,
+			}
+			return response, errHelper
+		}
+		if errDb == errStokNegatif || errDb == errSKUDibutuhkan {
+			errHelper = &helper.ErrorStruct{
+				Err:  errDb,
+				Code: http.StatusBadRequest,
+			}
+			return response, errHelper
+		}
+		errHelper = &helper.ErrorStruct{
+			Err:  errDb,
+			Code: http.StatusInternalServerError,
+		}
+		return response, errHelper
+	}
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return response, errHelper
+}
+
+// GetMutasiStok stock history of produk owned by the toko, newest first. Produk in trash still has its history.
+func (sr *StokRepositoryImpl) GetMutasiStok(ctx context.Context, tokoID, produkID uint, params daos.FilterMutasiStok) (response []daos.MutasiStok, errHelper *helper.ErrorStruct) {
+	// get gorm client
+	db := sr.db
+
+	var produk daos.Produk
+	if errDb := db.Unscoped().Select("id").Where("toko_id = ? AND id = ?", tokoID, produkID).First(&produk).Error; errDb != nil {
+		if errDb == gorm.ErrRecordNotFound {
+			errHelper = &helper.ErrorStruct{
+				Err:  errors.New("No Data Product"),
+				Code: http.StatusNotFound,
+			}
+			return response, errHelper
+		}
+		errHelper = &helper.ErrorStruct{
+			Err:  errDb,
+			Code: http.StatusInternalServerError,
+		}
+		return response, errHelper
+	}
+
+	query := db.Where("produk_id = ?", produkID)
+	if params.SKUID != 0 {
+		query = query.Where("sku_id = ?", params.SKUID)
+	}
+	if params.Tipe != "" {
+		query = query.Where("tipe = ?", params.Tipe)
+	}
+	if errDb := query.Order("id DESC").Limit(params.Limit).Offset(params.Offset).Find(&response).Error; errDb != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errDb,
+			Code: http.StatusInternalServerError,
+		}
+		return response, errHelper
+	}
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return response, errHelper
+}
+
+// SetBatasStokRendahProduk low stock threshold of produk owned by the toko, nil make produk use threshold of the toko
+func (sr *StokRepositoryImpl) SetBatasStokRendahProduk(ctx context.Context, tokoID, produkID uint, batas *uint) (errHelper *helper.ErrorStruct) {
+	// get gorm client
+	db := sr.db
+
+	var produk daos.Produk
+	errDb := db.Select("id").Where("toko_id = ? AND id = ?", tokoID, produkID).First(&produk).Error
+	if errDb == nil {
+		errDb = db.Model(&produk).Updates(map[string]interface{}{
+			"batas_stok_rendah": batas,
+			"versi":             versiBaru,
+		}).Error
+	}
+	if errDb != nil {
+		if errDb == gorm.ErrRecordNotFound {
+			errHelper = &helper.ErrorStruct{
+				Err:  errors.New("No Data Product"),
+				Code: http.StatusNotFound,
+			}
+			return errHelper
+		}
+		errHelper = &helper.ErrorStruct{
+			Err:  errDb,
+			Code: http.StatusInternalServerError,
+		}
+		return errHelper
+	}
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return errHelper
+}
+
+// SetBatasStokRendahToko default low stock threshold of produk in the toko, nil use the default of the app
+func (sr *StokRepositoryImpl) SetBatasStokRendahToko(ctx context.Context, tokoID uint, batas *uint) (errHelper *helper.ErrorStruct) {
+	// get gorm client
+	db := sr.db
+
+	var toko daos.Toko
+	errDb := db.Select("id").Where("id = ?", tokoID).First(&toko).Error
+	if errDb == nil {
+		errDb = db.Model(&toko).Update("batas_stok_rendah", batas).Error
+	}
+	if errDb != nil {
+		if errDb == gorm.ErrRecordNotFound {
+			errHelper = &helper.ErrorStruct{
+				Err:  errors.New("Toko tidak ditemukan"),
+				Code: http.StatusNotFound,
+			}
+			return errHelper
+		}
+		errHelper = &helper.ErrorStruct{
+			Err:  errDb,
+			Code: http.StatusInternalServerError,
+		}
+		return errHelper
+	}
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return errHelper
+}
+
+// GetProdukStokRendah produk of the toko whose stok is at or below its threshold, the emptiest first
+func (sr *StokRepositoryImpl) GetProdukStokRendah(ctx context.Context, tokoID uint, limit, offset int) (response []daos.Produk, errHelper *helper.ErrorStruct) {
+	// get gorm client
+	db := sr.db
+
+	errDb := db.Select("produks.*").Preload("Toko").Joins("JOIN tokos ON tokos.id = produks.toko_id").
+		Where("produks.toko_id = ?", tokoID).
+		Where("produks.stok <= COALESCE(produks.batas_stok_rendah, tokos.batas_stok_rendah, ?)", daos.BatasStokRendahDefault).
+		Order("produks.stok, produks.id").Limit(limit).Offset(offset).Find(&response).Error
+	if errDb != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errDb,
+			Code: http.StatusInternalServerError,
+		}
+		return response, errHelper
+	}
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return response, errHelper
+}
@@ -6,14 +6,19 @@ import (
 	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/daos"
 	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/helper"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"net/http"
+	"time"
 )
 
 type TokoRepository interface {
 	GetTokoByID(ctx context.Context, ID uint) (response daos.Toko, errHelper *helper.ErrorStruct)
+	GetTokoBySlug(ctx context.Context, slug string) (response daos.Toko, errHelper *helper.ErrorStruct)
 	GetTokoByUserID(ctx context.Context, userID uint) (response daos.Toko, errHelper *helper.ErrorStruct)
 	GetAllToko(ctx context.Context, params daos.FilterToko) (response []daos.Toko, errHelper *helper.ErrorStruct)
-	UpdateToko(ctx context.Context, data daos.Toko) (errHelper *helper.ErrorStruct)
+	UpdateToko(ctx context.Context, data daos.Toko) (versi uint, errHelper *helper.ErrorStruct)
+	DeleteToko(ctx context.Context, userID uint) (errHelper *helper.ErrorStruct)
+	RestoreToko(ctx context.Context, userID uint) (errHelper *helper.ErrorStruct)
 }
 
 type TokoRepositoryImpl struct {
@@ -58,6 +63,30 @@ func (tr *TokoRepositoryImpl) GetTokoByID(ctx context.Context, ID uint) (respons
 	return response, errHelper
 }
 
+// GetTokoBySlug toko that has or had the slug, slug of toko in response is its current slug
+func (tr *TokoRepositoryImpl) GetTokoBySlug(ctx context.Context, slug string) (response daos.Toko, errHelper *helper.ErrorStruct) {
+	// get gorm client
+	db := tr.db
+
+	// find toko id from current and old slug
+	ID, errDb := cariSlug(db, daos.SlugTipeToko, slug)
+	if errDb != nil {
+		if errDb == gorm.ErrRecordNotFound {
+			errHelper = &helper.ErrorStruct{
+				Err:  errors.New("Toko tidak ditemukan"),
+				Code: http.StatusNotFound,
+			}
+			return response, errHelper
+		}
+		errHelper = &helper.ErrorStruct{
+			Err:  errDb,
+			Code: http.StatusInternalServerError,
+		}
+		return response, errHelper
+	}
+	return tr.GetTokoByID(ctx, ID)
+}
+
 func (tr *TokoRepositoryImpl) GetTokoByUserID(ctx context.Context, userID uint) (response daos.Toko, errHelper *helper.ErrorStruct) {
 	// get gorm client
 	db := tr.db
@@ -92,62 +121,172 @@ func (tr *TokoRepositoryImpl) GetTokoByUserID(ctx context.Context, userID uint)
 
 func (tr *TokoRepositoryImpl) GetAllToko(ctx context.Context, params daos.FilterToko) (response []daos.Toko, errHelper *helper.ErrorStruct) {
 	// get gorm client
-	db := tr.db
+	db := tr.db.Order("id ASC")
+	if params.Nama != "" {
+		db = db.Where("nama_toko LIKE ?", "%"+params.Nama+"%")
+	}
+	// keyset pagination continue after the last toko of previous page
+	if params.SetelahID != 0 {
+		db = db.Where("id > ?", params.SetelahID)
+	} else {
+		db = db.Offset(params.Offset)
+	}
 
 	// get all toko from database and error information
-	if errDb := db.Limit(params.Limit).Offset(params.Offset).Find(&response).Error; errDb != nil {
-		if len(response) <= 0 {
+	if errDb := db.Limit(params.Limit).Find(&response).Error; errDb != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errDb,
+			Code: http.StatusInternalServerError,
+		}
+		return response, errHelper
+	}
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return response, errHelper
+}
+
+// UpdateToko data.Versi is the versi read by the client, update is rejected when toko is changed after that.
+// Zero data.Versi update any versi.
+func (tr *TokoRepositoryImpl) UpdateToko(ctx context.Context, data daos.Toko) (versi uint, errHelper *helper.ErrorStruct) {
+	// get gorm client
+	db := tr.db
+	var responseDb daos.Toko
+	// update toko data, new name give toko new slug
+	errDb := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("user_id = ?", data.UserID).First(&responseDb).Error; err != nil {
+			return err
+		}
+		if data.Versi != 0 && data.Versi != responseDb.Versi {
+			return errVersiBerbeda
+		}
+		data.Versi = responseDb.Versi + 1
+		if err := tx.Model(&responseDb).Updates(data).Error; err != nil {
+			return err
+		}
+		if data.NamaToko != "" {
+			if _, err := simpanSlug(tx, daos.SlugTipeToko, responseDb.ID, data.NamaToko); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if errDb != nil {
+		// check if error is record not found
+		if errDb == gorm.ErrRecordNotFound {
 			errHelper = &helper.ErrorStruct{
-				Err:  errors.New("no product found"),
+				Err:  errDb,
 				Code: http.StatusNotFound,
 			}
-			return []daos.Toko{}, errHelper
+			return versi, errHelper
+		}
+		if errDb == errVersiBerbeda {
+			errHelper = &helper.ErrorStruct{
+				Err:  errDb,
+				Code: http.StatusPreconditionFailed,
+			}
+			return versi, errHelper
 		}
+		// response another error
 		errHelper = &helper.ErrorStruct{
 			Err:  errDb,
 			Code: http.StatusInternalServerError,
 		}
-		return response, errHelper
+		return versi, errHelper
 	}
-	if params.Nama != "" {
-		if errDb := db.Limit(params.Limit).Offset(params.Offset).Where("nama_toko LIKE ?", "%"+params.Nama+"%").Find(&response).Error; errDb != nil {
-			if len(response) <= 0 {
-				errHelper = &helper.ErrorStruct{
-					Err:  errors.New("no product found"),
-					Code: http.StatusNotFound,
-				}
-				return []daos.Toko{}, errHelper
-			}
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return responseDb.Versi, errHelper
+}
+
+// DeleteToko move toko of user to trash with every produk and foto still on sale, so they are restored together
+func (tr *TokoRepositoryImpl) DeleteToko(ctx context.Context, userID uint) (errHelper *helper.ErrorStruct) {
+	// get gorm client
+	db := tr.db
+	var toko daos.Toko
+
+	// update with transaction
+	errDb := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).First(&toko).Error; err != nil {
+			return err
+		}
+		dihapus := time.Now()
+		listIDProduk := tx.Model(&daos.Produk{}).Select("id").Where("toko_id = ?", toko.ID)
+		if err := tx.Model(&daos.FotoProduk{}).Where("produk_id IN (?)", listIDProduk).Update("deleted_at", dihapus).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&daos.Produk{}).Where("toko_id = ?", toko.ID).Update("deleted_at", dihapus).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&toko).Update("deleted_at", dihapus).Error; err != nil {
+			return err
+		}
+		// return nil will commit the whole transaction
+		return nil
+	})
+	// error checking
+	if errDb != nil {
+		if errDb == gorm.ErrRecordNotFound {
 			errHelper = &helper.ErrorStruct{
-				Err:  errDb,
-				Code: http.StatusInternalServerError,
+				Err:  errors.New("Toko tidak ditemukan"),
+				Code: http.StatusNotFound,
 			}
-			return response, errHelper
+			return errHelper
 		}
+		errHelper = &helper.ErrorStruct{
+			Err:  errDb,
+			Code: http.StatusInternalServerError,
+		}
+		return errHelper
 	}
 	// success response
 	errHelper = &helper.ErrorStruct{
 		Err:  nil,
 		Code: http.StatusOK,
 	}
-	return response, errHelper
+	return errHelper
 }
 
-func (tr *TokoRepositoryImpl) UpdateToko(ctx context.Context, data daos.Toko) (errHelper *helper.ErrorStruct) {
+// RestoreToko bring back toko of user from trash with produk and foto deleted together with it
+func (tr *TokoRepositoryImpl) RestoreToko(ctx context.Context, userID uint) (errHelper *helper.ErrorStruct) {
 	// get gorm client
 	db := tr.db
-	var responseDb daos.Toko
-	// update toko data
-	if errDb := db.Where("user_id = ?", data.UserID).First(&responseDb).Updates(data).Error; errDb != nil {
-		// check if error is record not found
+	var toko daos.Toko
+
+	// update with transaction
+	errDb := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Where("user_id = ? AND deleted_at IS NOT NULL", userID).First(&toko).Error; err != nil {
+			return err
+		}
+		listIDProduk := tx.Unscoped().Model(&daos.Produk{}).Select("id").Where("toko_id = ? AND deleted_at = ?", toko.ID, toko.DeletedAt)
+		if err := tx.Unscoped().Model(&daos.FotoProduk{}).Where("produk_id IN (?) AND deleted_at = ?", listIDProduk, toko.DeletedAt).
+			Update("deleted_at", nil).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Model(&daos.Produk{}).Where("toko_id = ? AND deleted_at = ?", toko.ID, toko.DeletedAt).
+			Update("deleted_at", nil).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Model(&toko).Update("deleted_at", nil).Error; err != nil {
+			return err
+		}
+		// return nil will commit the whole transaction
+		return nil
+	})
+	// error checking
+	if errDb != nil {
 		if errDb == gorm.ErrRecordNotFound {
 			errHelper = &helper.ErrorStruct{
-				Err:  errDb,
+				Err:  errors.New("Toko tidak ada di sampah"),
 				Code: http.StatusNotFound,
 			}
 			return errHelper
 		}
-		// response another error
 		errHelper = &helper.ErrorStruct{
 			Err:  errDb,
 			Code: http.StatusInternalServerError,
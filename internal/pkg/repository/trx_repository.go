@@ -14,9 +14,17 @@ import (
 type TRXRepository interface {
 	GetAllTRX(ctx context.Context, userID uint, params daos.FilterTRX) (trx []daos.TRXResponse, errHelper *helper.ErrorStruct)
 	GetTRXByID(ctx context.Context, userID, ID uint) (trx daos.TRXResponse, errHelper *helper.ErrorStruct)
-	CreateTRX(ctx context.Context, trx daos.TRX, listKuantitasProdukID []daos.ProdukIDKuantitas) (ID uint, errHelper *helper.ErrorStruct)
+	CreateTRX(ctx context.Context, trx daos.TRX, listKuantitasProdukID []daos.ProdukIDKuantitas) (ID uint, listNotifikasiID []uint, errHelper *helper.ErrorStruct)
+	KonfirmasiPembayaran(ctx context.Context, ID uint) (listNotifikasiID []uint, errHelper *helper.ErrorStruct)
+	KirimTRX(ctx context.Context, userID, ID uint, noResi string) (listNotifikasiID []uint, errHelper *helper.ErrorStruct)
 }
 
+var (
+	errTRXSudahDibayar = errors.New("trx is already paid")
+	errTRXBelumDibayar = errors.New("trx is not paid yet")
+	errTRXSudahDikirim = errors.New("trx is already shipped by this toko")
+)
+
 type TRXRepositoryImpl struct {
 	db *gorm.DB
 }
@@ -29,8 +37,15 @@ func (tr *TRXRepositoryImpl) GetAllTRX(ctx context.Context, userID uint, params
 	// get gorm client
 	db := tr.db
 	var trxDB []daos.TRX
-	// get trx by id
-	if errDb := db.Limit(params.Limit).Offset(params.Offset).Preload("DetailTRX").Where("user_id = ?", userID).Find(&trxDB).Error; errDb != nil {
+	// keyset pagination continue after the last trx of previous page
+	query := db.Where("user_id = ?", userID).Order("id ASC")
+	if params.SetelahID != 0 {
+		query = query.Where("id > ?", params.SetelahID)
+	} else {
+		query = query.Offset(params.Offset)
+	}
+	// get trx of user
+	if errDb := query.Limit(params.Limit).Preload("DetailTRX").Find(&trxDB).Error; errDb != nil {
 
 		errHelper = &helper.ErrorStruct{
 			Err:  errDb,
@@ -48,7 +63,7 @@ func (tr *TRXRepositoryImpl) GetAllTRX(ctx context.Context, userID uint, params
 
 	for _, t := range trxDB {
 		var alamat daos.Alamat
-		if errDb := db.First(&alamat, t.AlamatID).Error; errDb != nil {
+		if errDb := db.Scopes(denganSampah).First(&alamat, t.AlamatID).Error; errDb != nil {
 			errHelper = &helper.ErrorStruct{
 				Err:  errDb,
 				Code: http.StatusInternalServerError,
@@ -59,7 +74,7 @@ func (tr *TRXRepositoryImpl) GetAllTRX(ctx context.Context, userID uint, params
 
 		for _, v := range t.DetailTRX {
 			var logProduk daos.LogProduk
-			if err := db.Where("id = ?", v.LogProdukID).Preload("Toko").Preload("Category").Preload("LogFotoProduk").First(&logProduk).Error; err != nil {
+			if err := db.Where("id = ?", v.LogProdukID).Preload("Toko", denganSampah).Preload("Category", denganSampah).Preload("LogFotoProduk").First(&logProduk).Error; err != nil {
 				errHelper = &helper.ErrorStruct{
 					Err:  err,
 					Code: http.StatusInternalServerError,
@@ -73,6 +88,7 @@ func (tr *TRXRepositoryImpl) GetAllTRX(ctx context.Context, userID uint, params
 				Toko:        logProduk.Toko,
 				Kuantitas:   v.Kuantitas,
 				HargaTotal:  v.HargaTotal,
+				NoResi:      v.NoResi,
 				UpdatedAt:   time.Time{},
 				CreatedAt:   time.Time{},
 				LogProduk:   logProduk,
@@ -80,16 +96,17 @@ func (tr *TRXRepositoryImpl) GetAllTRX(ctx context.Context, userID uint, params
 			listDetailTRX = append(listDetailTRX, detailTRXResponse)
 		}
 		transaction := daos.TRXResponse{
-			ID:          t.ID,
-			UserID:      t.UserID,
-			AlamatID:    t.AlamatID,
-			Alamat:      alamat,
-			HargaTotal:  t.HargaTotal,
-			KodeInvoice: t.KodeInvoice,
-			MethodBayar: t.MethodBayar,
-			UpdatedAt:   time.Time{},
-			CreatedAt:   time.Time{},
-			DetailTRX:   listDetailTRX,
+			ID:               t.ID,
+			UserID:           t.UserID,
+			AlamatID:         t.AlamatID,
+			Alamat:           alamat,
+			HargaTotal:       t.HargaTotal,
+			KodeInvoice:      t.KodeInvoice,
+			MethodBayar:      t.MethodBayar,
+			StatusPembayaran: t.StatusPembayaran,
+			UpdatedAt:        time.Time{},
+			CreatedAt:        time.Time{},
+			DetailTRX:        listDetailTRX,
 		}
 		trx = append(trx, transaction)
 	}
@@ -121,7 +138,7 @@ func (tr *TRXRepositoryImpl) GetTRXByID(ctx context.Context, userID, ID uint) (t
 		return trx, errHelper
 	}
 	var alamat daos.Alamat
-	if errDb := db.First(&alamat, trxDB.AlamatID).Error; errDb != nil {
+	if errDb := db.Scopes(denganSampah).First(&alamat, trxDB.AlamatID).Error; errDb != nil {
 		errHelper = &helper.ErrorStruct{
 			Err:  errDb,
 			Code: http.StatusInternalServerError,
@@ -132,7 +149,7 @@ func (tr *TRXRepositoryImpl) GetTRXByID(ctx context.Context, userID, ID uint) (t
 
 	for _, v := range trxDB.DetailTRX {
 		var logProduk daos.LogProduk
-		if err := db.Where("id = ?", v.LogProdukID).Preload("Toko").Preload("Category").Preload("LogFotoProduk").First(&logProduk).Error; err != nil {
+		if err := db.Where("id = ?", v.LogProdukID).Preload("Toko", denganSampah).Preload("Category", denganSampah).Preload("LogFotoProduk").First(&logProduk).Error; err != nil {
 			errHelper = &helper.ErrorStruct{
 				Err:  err,
 				Code: http.StatusInternalServerError,
@@ -146,6 +163,7 @@ func (tr *TRXRepositoryImpl) GetTRXByID(ctx context.Context, userID, ID uint) (t
 			Toko:        logProduk.Toko,
 			Kuantitas:   v.Kuantitas,
 			HargaTotal:  v.HargaTotal,
+			NoResi:      v.NoResi,
 			UpdatedAt:   time.Time{},
 			CreatedAt:   time.Time{},
 			LogProduk:   logProduk,
@@ -153,16 +171,17 @@ func (tr *TRXRepositoryImpl) GetTRXByID(ctx context.Context, userID, ID uint) (t
 		listDetailTRX = append(listDetailTRX, detailTRXResponse)
 	}
 	trx = daos.TRXResponse{
-		ID:          trxDB.ID,
-		UserID:      trxDB.UserID,
-		AlamatID:    trxDB.AlamatID,
-		Alamat:      alamat,
-		HargaTotal:  trxDB.HargaTotal,
-		KodeInvoice: trxDB.KodeInvoice,
-		MethodBayar: trxDB.MethodBayar,
-		UpdatedAt:   time.Time{},
-		CreatedAt:   time.Time{},
-		DetailTRX:   listDetailTRX,
+		ID:               trxDB.ID,
+		UserID:           trxDB.UserID,
+		AlamatID:         trxDB.AlamatID,
+		Alamat:           alamat,
+		HargaTotal:       trxDB.HargaTotal,
+		KodeInvoice:      trxDB.KodeInvoice,
+		MethodBayar:      trxDB.MethodBayar,
+		StatusPembayaran: trxDB.StatusPembayaran,
+		UpdatedAt:        time.Time{},
+		CreatedAt:        time.Time{},
+		DetailTRX:        listDetailTRX,
 	}
 	errHelper = &helper.ErrorStruct{
 		Err:  nil,
@@ -171,43 +190,123 @@ func (tr *TRXRepositoryImpl) GetTRXByID(ctx context.Context, userID, ID uint) (t
 	return trx, errHelper
 }
 
-func (tr *TRXRepositoryImpl) CreateTRX(ctx context.Context, trx daos.TRX, listProdukIDKuantitas []daos.ProdukIDKuantitas) (ID uint, errHelper *helper.ErrorStruct) {
+func (tr *TRXRepositoryImpl) CreateTRX(ctx context.Context, trx daos.TRX, listProdukIDKuantitas []daos.ProdukIDKuantitas) (ID uint, listNotifikasiID []uint, errHelper *helper.ErrorStruct) {
 	// get gorm client
 	db := tr.db
 
 	// start transaction
 	errTrans := db.Transaction(func(tx *gorm.DB) error {
+		// alamat must belong to buyer and not in trash
+		var alamat daos.Alamat
+		if err := tx.Select("id").Where("user_id = ? AND id = ?", trx.UserID, trx.AlamatID).First(&alamat).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return errors.New("alamat not found")
+			}
+			return err
+		}
 
 		var listNewDetailTRX []daos.DetailTRX
 		var hargaTotalTRX uint
+		// seller user_id for every toko in this trx and produk that reach low stock
+		listPenjual := map[uint]uint{}
+		var listStokRendah []daos.Produk
+		var listMutasi []daos.MutasiStok
+		// kuantitas bought from every item promo in this trx
+		listDibeliPromo := map[uint]uint{}
 		for _, v := range listProdukIDKuantitas {
 			produk := daos.Produk{}
 
 			// update stok produk
-			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", v.ProdukID).Preload("FotoProduk").First(&produk).Error; err != nil {
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", v.ProdukID).Preload("FotoProduk", urutanFotoProduk).Preload("Toko").First(&produk).Error; err != nil {
 				return err
 			}
 			if produk.TokoID == trx.UserID {
 				return errors.New("user cannot buy their own items")
 			}
+			if !produk.Tampil(time.Now()) {
+				return errProdukTidakTersedia
+			}
 			if int(produk.Stok)-int(v.Kuantitas) <= 0 {
 				return errors.New("not enough stock")
 			}
-			if err := tx.Model(&daos.Produk{}).Where("id=?", v.ProdukID).Update("stok", produk.Stok-v.Kuantitas).Error; err != nil {
+			// produk with variant must be bought through one of its sku
+			sku := daos.SKU{}
+			hargaReseller, hargaKonsumen := produk.HargaReseller, produk.HargaKonsumen
+			if v.SKUID != 0 {
+				if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ? AND produk_id = ?", v.SKUID, v.ProdukID).First(&sku).Error; err != nil {
+					if err == gorm.ErrRecordNotFound {
+						return errors.New("sku not found")
+					}
+					return err
+				}
+				if int(sku.Stok)-int(v.Kuantitas) <= 0 {
+					return errors.New("not enough stock")
+				}
+				if err := tx.Model(&daos.SKU{}).Where("id = ?", sku.ID).Update("stok", sku.Stok-v.Kuantitas).Error; err != nil {
+					return err
+				}
+				hargaReseller, hargaKonsumen = sku.Harga(produk)
+			} else {
+				var jumlahSKU int64
+				if err := tx.Model(&daos.SKU{}).Where("produk_id = ?", v.ProdukID).Count(&jumlahSKU).Error; err != nil {
+					return err
+				}
+				if jumlahSKU > 0 {
+					return errors.New("sku_id is required for product with variant")
+				}
+			}
+			// sale price of promo running now, flash sale quota and limit per user are counted here
+			itemPromo, err := pakaiPromo(tx, v.ProdukID, trx.UserID, v.Kuantitas, listDibeliPromo)
+			if err != nil {
+				return err
+			}
+			if itemPromo != nil {
+				hargaReseller, hargaKonsumen = itemPromo.Harga(hargaReseller, hargaKonsumen)
+			}
+			if err := tx.Model(&daos.Produk{}).Where("id=?", v.ProdukID).Updates(map[string]interface{}{
+				"stok":  produk.Stok - v.Kuantitas,
+				"versi": versiBaru,
+			}).Error; err != nil {
 				return err
 			}
+			mutasi := daos.MutasiStok{
+				ProdukID:    v.ProdukID,
+				Tipe:        daos.MutasiStokPenjualan,
+				Perubahan:   -int(v.Kuantitas),
+				Saldo:       produk.Stok - v.Kuantitas,
+				SaldoProduk: produk.Stok - v.Kuantitas,
+				PelakuID:    &trx.UserID,
+			}
+			if sku.ID != 0 {
+				mutasi.SKUID = &sku.ID
+				mutasi.Saldo = sku.Stok - v.Kuantitas
+			}
+			listMutasi = append(listMutasi, mutasi)
+			listPenjual[produk.TokoID] = produk.Toko.UserID
+			// check if this trx makes stok cross the low stock threshold
+			if produk.MelewatiBatasStokRendah(produk.Stok, produk.Stok-v.Kuantitas) {
+				produk.Stok = produk.Stok - v.Kuantitas
+				listStokRendah = append(listStokRendah, produk)
+			}
 
 			newLogProduk := daos.LogProduk{
 				ProdukID:      v.ProdukID,
 				NamaProduk:    produk.NamaProduk,
 				Slug:          produk.Slug,
-				HargaReseller: produk.HargaReseller,
-				HargaKonsumen: produk.HargaKonsumen,
+				HargaReseller: hargaReseller,
+				HargaKonsumen: hargaKonsumen,
 				Deskripsi:     produk.Deskripsi,
 				TokoID:        produk.TokoID,
 				CategoryID:    produk.CategoryID,
 			}
 
+			// snapshot chosen variant
+			if sku.ID != 0 {
+				newLogProduk.SKUID = &sku.ID
+				newLogProduk.KodeSKU = sku.KodeSKU
+				newLogProduk.Varian = sku.Varian
+			}
+
 			if err := tx.Create(&newLogProduk).Error; err != nil {
 				return err
 			}
@@ -229,7 +328,7 @@ func (tr *TRXRepositoryImpl) CreateTRX(ctx context.Context, trx daos.TRX, listPr
 				LogProdukID: newLogProduk.ID,
 				TokoID:      newLogProduk.TokoID,
 				Kuantitas:   v.Kuantitas,
-				HargaTotal:  produk.HargaKonsumen * v.Kuantitas,
+				HargaTotal:  hargaKonsumen * v.Kuantitas,
 			}
 			hargaTotalTRX += newDetailTRX.HargaTotal
 			listNewDetailTRX = append(listNewDetailTRX, newDetailTRX)
@@ -252,28 +351,196 @@ func (tr *TRXRepositoryImpl) CreateTRX(ctx context.Context, trx daos.TRX, listPr
 		if err := tx.Create(&listNewDetailTRX).Error; err != nil {
 			return err
 		}
+		for i := range listMutasi {
+			listMutasi[i].TRXID = &newTRX.ID
+		}
+		if err := catatMutasiStok(tx, listMutasi); err != nil {
+			return err
+		}
+		var listPembelianPromo []daos.PembelianPromo
+		for itemPromoID, kuantitas := range listDibeliPromo {
+			listPembelianPromo = append(listPembelianPromo, daos.PembelianPromo{
+				ItemPromoID: itemPromoID,
+				UserID:      trx.UserID,
+				TRXID:       newTRX.ID,
+				Kuantitas:   kuantitas,
+			})
+		}
+		if len(listPembelianPromo) > 0 {
+			if err := tx.Create(&listPembelianPromo).Error; err != nil {
+				return err
+			}
+		}
+
+		// create notifikasi for buyer, sellers and low stock produk
+		listNotifikasi := newTRXNotifikasi(newTRX, listNewDetailTRX, listPenjual, listStokRendah)
+		if err := tx.Create(&listNotifikasi).Error; err != nil {
+			return err
+		}
+		for _, v := range listNotifikasi {
+			listNotifikasiID = append(listNotifikasiID, v.ID)
+		}
 		// return nil will commit the whole transaction
 		return nil
 	})
 	// error checking
 	if errTrans != nil {
-		if errTrans.Error() == "not enough stock" || errTrans.Error() == "user cannot buy their own items" {
+		if errTrans.Error() == "sku not found" || errTrans.Error() == "alamat not found" || errTrans == gorm.ErrRecordNotFound {
+			errHelper = &helper.ErrorStruct{
+				Err:  errTrans,
+				Code: http.StatusNotFound,
+			}
+			return ID, nil, errHelper
+		}
+		if errTrans.Error() == "not enough stock" || errTrans.Error() == "user cannot buy their own items" || errTrans.Error() == "sku_id is required for product with variant" ||
+			errTrans == errKuotaPromoHabis || errTrans == errBatasPromoUser || errTrans == errProdukTidakTersedia {
+			errHelper = &helper.ErrorStruct{
+				Err:  errTrans,
+				Code: http.StatusBadRequest,
+			}
+			return ID, nil, errHelper
+		}
+		errHelper = &helper.ErrorStruct{
+			Err:  errTrans,
+			Code: http.StatusInternalServerError,
+		}
+		return ID, nil, errHelper
+	}
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return ID, listNotifikasiID, errHelper
+}
+
+func (tr *TRXRepositoryImpl) KonfirmasiPembayaran(ctx context.Context, ID uint) (listNotifikasiID []uint, errHelper *helper.ErrorStruct) {
+	// get gorm client
+	db := tr.db
+
+	// trx is locked so payment is confirmed once
+	errTrans := db.Transaction(func(tx *gorm.DB) error {
+		var trx daos.TRX
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", ID).First(&trx).Error; err != nil {
+			return err
+		}
+		if trx.StatusPembayaran == daos.PembayaranLunas {
+			return errTRXSudahDibayar
+		}
+		if err := tx.Model(&daos.TRX{}).Where("id = ?", ID).Updates(map[string]interface{}{
+			"status_pembayaran": daos.PembayaranLunas,
+			"dibayar_pada":      time.Now(),
+		}).Error; err != nil {
+			return err
+		}
+
+		// create notifikasi for buyer
+		notifikasi := newPembayaranNotifikasi(trx)
+		if err := tx.Create(&notifikasi).Error; err != nil {
+			return err
+		}
+		listNotifikasiID = append(listNotifikasiID, notifikasi.ID)
+		return nil
+	})
+	// error checking
+	if errTrans != nil {
+		if errTrans == gorm.ErrRecordNotFound {
+			errHelper = &helper.ErrorStruct{
+				Err:  errors.New("trx not found"),
+				Code: http.StatusNotFound,
+			}
+			return nil, errHelper
+		}
+		if errTrans == errTRXSudahDibayar {
+			errHelper = &helper.ErrorStruct{
+				Err:  errTrans,
+				Code: http.StatusBadRequest,
+			}
+			return nil, errHelper
+		}
+		errHelper = &helper.ErrorStruct{
+			Err:  errTrans,
+			Code: http.StatusInternalServerError,
+		}
+		return nil, errHelper
+	}
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return listNotifikasiID, errHelper
+}
+
+func (tr *TRXRepositoryImpl) KirimTRX(ctx context.Context, userID, ID uint, noResi string) (listNotifikasiID []uint, errHelper *helper.ErrorStruct) {
+	// get gorm client
+	db := tr.db
+
+	// toko of seller ship every detail trx it sold in one package
+	errTrans := db.Transaction(func(tx *gorm.DB) error {
+		var toko daos.Toko
+		if err := tx.Select("id", "nama_toko").Where("user_id = ?", userID).First(&toko).Error; err != nil {
+			return err
+		}
+		var trx daos.TRX
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", ID).First(&trx).Error; err != nil {
+			return err
+		}
+		var listDetailTRX []daos.DetailTRX
+		if err := tx.Where("trx_id = ? AND toko_id = ?", ID, toko.ID).Find(&listDetailTRX).Error; err != nil {
+			return err
+		}
+		// trx without item of the toko is not visible to the toko
+		if len(listDetailTRX) == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		if trx.StatusPembayaran != daos.PembayaranLunas {
+			return errTRXBelumDibayar
+		}
+		if listDetailTRX[0].NoResi != "" {
+			return errTRXSudahDikirim
+		}
+		if err := tx.Model(&daos.DetailTRX{}).Where("trx_id = ? AND toko_id = ?", ID, toko.ID).Updates(map[string]interface{}{
+			"no_resi":      noResi,
+			"dikirim_pada": time.Now(),
+		}).Error; err != nil {
+			return err
+		}
+
+		// create notifikasi for buyer
+		notifikasi := newPengirimanNotifikasi(trx, toko, noResi)
+		if err := tx.Create(&notifikasi).Error; err != nil {
+			return err
+		}
+		listNotifikasiID = append(listNotifikasiID, notifikasi.ID)
+		return nil
+	})
+	// error checking
+	if errTrans != nil {
+		if errTrans == gorm.ErrRecordNotFound {
+			errHelper = &helper.ErrorStruct{
+				Err:  errors.New("trx not found"),
+				Code: http.StatusNotFound,
+			}
+			return nil, errHelper
+		}
+		if errTrans == errTRXBelumDibayar || errTrans == errTRXSudahDikirim {
 			errHelper = &helper.ErrorStruct{
 				Err:  errTrans,
 				Code: http.StatusBadRequest,
 			}
-			return ID, errHelper
+			return nil, errHelper
 		}
 		errHelper = &helper.ErrorStruct{
 			Err:  errTrans,
 			Code: http.StatusInternalServerError,
 		}
-		return ID, errHelper
+		return nil, errHelper
 	}
 	// success response
 	errHelper = &helper.ErrorStruct{
 		Err:  nil,
 		Code: http.StatusOK,
 	}
-	return ID, errHelper
+	return listNotifikasiID, errHelper
 }
@@ -25,7 +25,7 @@ func TestCreateTRX(t *testing.T) {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			response, err := repo.CreateTRX(context.Background(), daos.TRX{
+			response, _, err := repo.CreateTRX(context.Background(), daos.TRX{
 				UserID:      1,
 				AlamatID:    1,
 				HargaTotal:  0,
@@ -8,9 +8,11 @@ import (
 	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/helper"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/daos"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type UserRepository interface {
@@ -18,8 +20,14 @@ type UserRepository interface {
 	Login(ctx context.Context, noTelp string) (response daos.User, errHelper *helper.ErrorStruct)
 	GetMyProfile(ctx context.Context, userID uint) (response daos.User, errHelper *helper.ErrorStruct)
 	UpdateProfile(ctx context.Context, data daos.User) (errHelper *helper.ErrorStruct)
+	GetUserByEmail(ctx context.Context, email string) (response daos.User, errHelper *helper.ErrorStruct)
+	CreateResetKataSandi(ctx context.Context, data daos.ResetKataSandi) (errHelper *helper.ErrorStruct)
+	ResetKataSandi(ctx context.Context, tokenHash, kataSandi string) (errHelper *helper.ErrorStruct)
 }
 
+// errTokenResetTidakValid token is unknown, already used or expired
+var errTokenResetTidakValid = errors.New("token is invalid or expired")
+
 type UserRepositoryImpl struct {
 	db *gorm.DB
 }
@@ -48,6 +56,9 @@ func (ur *UserRepositoryImpl) Register(ctx context.Context, data daos.User) (err
 		if err := tx.Create(&toko).Error; err != nil {
 			return err
 		}
+		if _, err := simpanSlug(tx, daos.SlugTipeToko, toko.ID, toko.NamaToko); err != nil {
+			return err
+		}
 
 		return nil
 	})
@@ -163,3 +174,103 @@ func (ur *UserRepositoryImpl) UpdateProfile(ctx context.Context, data daos.User)
 	}
 	return errHelper
 }
+
+func (ur *UserRepositoryImpl) GetUserByEmail(ctx context.Context, email string) (response daos.User, errHelper *helper.ErrorStruct) {
+	// get gorm client
+	db := ur.db
+
+	// get user data by email
+	if errDb := db.Model(&daos.User{}).First(&response, "email = ?", email).Error; errDb != nil {
+		// check if error is user not found
+		if errDb == gorm.ErrRecordNotFound {
+			errHelper = &helper.ErrorStruct{
+				Err:  errors.New("user not found"),
+				Code: http.StatusNotFound,
+			}
+			return response, errHelper
+		}
+		// response another error
+		errHelper = &helper.ErrorStruct{
+			Err:  errDb,
+			Code: http.StatusInternalServerError,
+		}
+		return response, errHelper
+	}
+
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return response, errHelper
+}
+
+func (ur *UserRepositoryImpl) CreateResetKataSandi(ctx context.Context, data daos.ResetKataSandi) (errHelper *helper.ErrorStruct) {
+	// get gorm client
+	db := ur.db
+
+	// token requested before is replaced, so only the newest email can reset kata sandi
+	errDb := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&daos.ResetKataSandi{}).Where("user_id = ? AND dipakai_pada IS NULL", data.UserID).
+			Update("dipakai_pada", time.Now()).Error; err != nil {
+			return err
+		}
+		return tx.Create(&data).Error
+	})
+	if errDb != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errDb,
+			Code: http.StatusInternalServerError,
+		}
+		return errHelper
+	}
+
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return errHelper
+}
+
+func (ur *UserRepositoryImpl) ResetKataSandi(ctx context.Context, tokenHash, kataSandi string) (errHelper *helper.ErrorStruct) {
+	// get gorm client
+	db := ur.db
+
+	// token is locked so it can only be used once
+	errDb := db.Transaction(func(tx *gorm.DB) error {
+		var reset daos.ResetKataSandi
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("token_hash = ? AND dipakai_pada IS NULL AND berlaku_hingga > ?", tokenHash, time.Now()).First(&reset).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return errTokenResetTidakValid
+			}
+			return err
+		}
+		if err := tx.Model(&daos.User{}).Where("id = ?", reset.UserID).Update("kata_sandi", kataSandi).Error; err != nil {
+			return err
+		}
+		return tx.Model(&reset).Update("dipakai_pada", time.Now()).Error
+	})
+	if errDb != nil {
+		if errDb == errTokenResetTidakValid {
+			errHelper = &helper.ErrorStruct{
+				Err:  errDb,
+				Code: http.StatusBadRequest,
+			}
+			return errHelper
+		}
+		errHelper = &helper.ErrorStruct{
+			Err:  errDb,
+			Code: http.StatusInternalServerError,
+		}
+		return errHelper
+	}
+
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return errHelper
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/pkg/dto"
 	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/pkg/repository"
 	"net/http"
+	"time"
 )
 
 type AlamatUseCase interface {
@@ -15,6 +16,8 @@ type AlamatUseCase interface {
 	GetAlamatByID(ctx context.Context, ID uint) (response dto.Alamat, errHelper *helper.ErrorStruct)
 	UpdateAlamatByID(ctx context.Context, userID, ID uint, data dto.UpdateAlamatRequest) (errHelper *helper.ErrorStruct)
 	DeleteAlamatByID(ctx context.Context, userID, ID uint) (errHelper *helper.ErrorStruct)
+	RestoreAlamatByID(ctx context.Context, userID, ID uint) (errHelper *helper.ErrorStruct)
+	GetSampahAlamat(ctx context.Context, userID uint) (response []dto.Alamat, errHelper *helper.ErrorStruct)
 }
 
 type AlamatUseCaseImpl struct {
@@ -169,3 +172,52 @@ func (au *AlamatUseCaseImpl) DeleteAlamatByID(ctx context.Context, userID, ID ui
 	}
 	return errHelper
 }
+
+func (au *AlamatUseCaseImpl) RestoreAlamatByID(ctx context.Context, userID, ID uint) (errHelper *helper.ErrorStruct) {
+	// call RestoreAlamatByID from alamat repository to bring back alamat record and get error information
+	if errRepo := au.alamatRepository.RestoreAlamatByID(ctx, userID, ID); errRepo.Err != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errRepo.Err,
+			Code: errRepo.Code,
+		}
+		return errHelper
+	}
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return errHelper
+}
+
+func (au *AlamatUseCaseImpl) GetSampahAlamat(ctx context.Context, userID uint) (response []dto.Alamat, errHelper *helper.ErrorStruct) {
+	// call GetSampahAlamat from alamat repository to get deleted alamat and error information
+	responseRepo, errRepo := au.alamatRepository.GetSampahAlamat(ctx, userID)
+	if errRepo.Err != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errRepo.Err,
+			Code: errRepo.Code,
+		}
+		return response, errHelper
+	}
+
+	// mapping from daos alamat to dto alamat
+	response = []dto.Alamat{}
+	for _, v := range responseRepo {
+		response = append(response, dto.Alamat{
+			ID:           v.ID,
+			JudulAlamat:  v.JudulAlamat,
+			NamaPenerima: v.NamaPenerima,
+			NoTelp:       v.NoTelp,
+			DetailAlamat: v.DetailAlamat,
+			DeletedAt:    v.DeletedAt.Time.Format(time.RFC3339),
+		})
+	}
+
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return response, errHelper
+}
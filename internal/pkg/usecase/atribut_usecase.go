@@ -0,0 +1,264 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/daos"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/helper"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/pkg/dto"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/pkg/repository"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// polaKodeAtribut kode is used in query attr.<kode>, so it can not contain dot
+var polaKodeAtribut = regexp.MustCompile(`^[a-z0-9_]+$`)
+
+type AtributUseCase interface {
+	GetAtributCategory(ctx context.Context, categoryID uint) (response []dto.AtributCategoryResponse, errHelper *helper.ErrorStruct)
+	CreateAtributCategory(ctx context.Context, data dto.AtributCategoryRequest) (ID uint, errHelper *helper.ErrorStruct)
+	UpdateAtributCategory(ctx context.Context, data dto.AtributCategoryRequest) (errHelper *helper.ErrorStruct)
+	DeleteAtributCategory(ctx context.Context, categoryID, ID uint) (errHelper *helper.ErrorStruct)
+	SimpanAtributProduk(ctx context.Context, data dto.AtributProdukRequest) (errHelper *helper.ErrorStruct)
+}
+
+type AtributUseCaseImpl struct {
+	atributRepository repository.AtributRepository
+}
+
+func NewAtributUseCase(atributRepository repository.AtributRepository) AtributUseCase {
+	return &AtributUseCaseImpl{atributRepository: atributRepository}
+}
+
+func mapAtributCategory(v daos.AtributCategory) dto.AtributCategoryResponse {
+	return dto.AtributCategoryResponse{
+		ID:         v.ID,
+		CategoryID: v.CategoryID,
+		Kode:       v.Kode,
+		Nama:       v.Nama,
+		Tipe:       v.Tipe,
+		Opsi:       v.ListOpsi(),
+		Satuan:     v.Satuan,
+		Wajib:      v.Wajib,
+		Urutan:     v.Urutan,
+	}
+}
+
+// mapAtributProduk attribute value of produk in the order of the category schema
+func mapAtributProduk(listAtribut []daos.AtributProduk) (response []dto.AtributProdukResponse) {
+	sort.SliceStable(listAtribut, func(i, j int) bool {
+		a, b := listAtribut[i].AtributCategory, listAtribut[j].AtributCategory
+		if a.Urutan != b.Urutan {
+			return a.Urutan < b.Urutan
+		}
+		return a.ID < b.ID
+	})
+	for _, v := range listAtribut {
+		response = append(response, dto.AtributProdukResponse{
+			Kode:   v.AtributCategory.Kode,
+			Nama:   v.AtributCategory.Nama,
+			Tipe:   v.AtributCategory.Tipe,
+			Nilai:  v.Nilai(),
+			Satuan: v.AtributCategory.Satuan,
+		})
+	}
+	return response
+}
+
+// validasiOpsiAtribut only enum attribute has opsi and it must not be empty
+func validasiOpsiAtribut(tipe string, listOpsi []string) error {
+	if tipe == daos.AtributPilihan && len(listOpsi) == 0 {
+		return errors.New("opsi is required for enum attribute")
+	}
+	if tipe != daos.AtributPilihan && len(listOpsi) > 0 {
+		return errors.New("opsi is only for enum attribute")
+	}
+	return nil
+}
+
+func (au *AtributUseCaseImpl) GetAtributCategory(ctx context.Context, categoryID uint) (response []dto.AtributCategoryResponse, errHelper *helper.ErrorStruct) {
+	// call GetAtributCategory from atribut repository
+	responseRepo, errRepo := au.atributRepository.GetAtributCategory(ctx, categoryID)
+	if errRepo.Err != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errRepo.Err,
+			Code: errRepo.Code,
+		}
+		return response, errHelper
+	}
+	response = []dto.AtributCategoryResponse{}
+	for _, v := range responseRepo {
+		response = append(response, mapAtributCategory(v))
+	}
+
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return response, errHelper
+}
+
+func (au *AtributUseCaseImpl) CreateAtributCategory(ctx context.Context, data dto.AtributCategoryRequest) (ID uint, errHelper *helper.ErrorStruct) {
+	// validate user input
+	data.Kode = strings.ToLower(strings.TrimSpace(data.Kode))
+	data.Nama = strings.TrimSpace(data.Nama)
+	err := helper.Validate.Struct(data)
+	if err == nil && !polaKodeAtribut.MatchString(data.Kode) {
+		err = errors.New("kode can only contain lowercase letter, number and underscore")
+	}
+	if err == nil {
+		err = validasiOpsiAtribut(data.Tipe, data.Opsi)
+	}
+	if err != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  err,
+			Code: http.StatusBadRequest,
+		}
+		return ID, errHelper
+	}
+	opsi := ""
+	if len(data.Opsi) > 0 {
+		opsiByte, _ := json.Marshal(data.Opsi)
+		opsi = string(opsiByte)
+	}
+
+	// call CreateAtributCategory from atribut repository
+	ID, errRepo := au.atributRepository.CreateAtributCategory(ctx, daos.AtributCategory{
+		CategoryID: data.CategoryID,
+		Kode:       data.Kode,
+		Nama:       data.Nama,
+		Tipe:       data.Tipe,
+		Opsi:       opsi,
+		Satuan:     data.Satuan,
+		Wajib:      data.Wajib,
+		Urutan:     data.Urutan,
+	})
+	if errRepo.Err != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errRepo.Err,
+			Code: errRepo.Code,
+		}
+		return ID, errHelper
+	}
+
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return ID, errHelper
+}
+
+// UpdateAtributCategory kode and tipe of existing attribute are kept, so they are not validated here
+func (au *AtributUseCaseImpl) UpdateAtributCategory(ctx context.Context, data dto.AtributCategoryRequest) (errHelper *helper.ErrorStruct) {
+	// tipe of the attribute decide whether opsi is allowed
+	listAtribut, errRepo := au.atributRepository.GetAtributCategory(ctx, data.CategoryID)
+	if errRepo.Err != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errRepo.Err,
+			Code: errRepo.Code,
+		}
+		return errHelper
+	}
+	var atribut *daos.AtributCategory
+	for i := range listAtribut {
+		if listAtribut[i].ID == data.ID {
+			atribut = &listAtribut[i]
+		}
+	}
+	if atribut == nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errors.New("attribute not found"),
+			Code: http.StatusNotFound,
+		}
+		return errHelper
+	}
+	data.Kode, data.Tipe = atribut.Kode, atribut.Tipe
+
+	// validate user input
+	data.Nama = strings.TrimSpace(data.Nama)
+	err := helper.Validate.Struct(data)
+	if err == nil {
+		err = validasiOpsiAtribut(data.Tipe, data.Opsi)
+	}
+	if err != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  err,
+			Code: http.StatusBadRequest,
+		}
+		return errHelper
+	}
+	opsi := ""
+	if len(data.Opsi) > 0 {
+		opsiByte, _ := json.Marshal(data.Opsi)
+		opsi = string(opsiByte)
+	}
+
+	// call UpdateAtributCategory from atribut repository
+	errRepo = au.atributRepository.UpdateAtributCategory(ctx, daos.AtributCategory{
+		ID:         data.ID,
+		CategoryID: data.CategoryID,
+		Nama:       data.Nama,
+		Opsi:       opsi,
+		Satuan:     data.Satuan,
+		Wajib:      data.Wajib,
+		Urutan:     data.Urutan,
+	})
+	if errRepo.Err != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errRepo.Err,
+			Code: errRepo.Code,
+		}
+		return errHelper
+	}
+
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return errHelper
+}
+
+func (au *AtributUseCaseImpl) DeleteAtributCategory(ctx context.Context, categoryID, ID uint) (errHelper *helper.ErrorStruct) {
+	// call DeleteAtributCategory from atribut repository
+	errRepo := au.atributRepository.DeleteAtributCategory(ctx, categoryID, ID)
+	if errRepo.Err != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errRepo.Err,
+			Code: errRepo.Code,
+		}
+		return errHelper
+	}
+
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return errHelper
+}
+
+// SimpanAtributProduk value is validated against schema of the produk category by repository
+func (au *AtributUseCaseImpl) SimpanAtributProduk(ctx context.Context, data dto.AtributProdukRequest) (errHelper *helper.ErrorStruct) {
+	// call SimpanAtributProduk from atribut repository
+	errRepo := au.atributRepository.SimpanAtributProduk(ctx, data.TokoID, data.ProdukID, data.Atribut)
+	if errRepo.Err != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errRepo.Err,
+			Code: errRepo.Code,
+		}
+		return errHelper
+	}
+
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return errHelper
+}
@@ -15,15 +15,19 @@ type CategoryUseCase interface {
 	GetCategoryByID(ctx context.Context, ID uint) (response dto.CategoryWithID, errHelper *helper.ErrorStruct)
 	UpdateCategoryByID(ctx context.Context, ID uint, data dto.CreateAndUpdateCategoryRequest) (errHelper *helper.ErrorStruct)
 	DeleteCategoryByID(ctx context.Context, data dto.CategoryIDOnly) (errHelper *helper.ErrorStruct)
+	RestoreCategoryByID(ctx context.Context, ID uint) (errHelper *helper.ErrorStruct)
+	GetSampahCategory(ctx context.Context) (response []dto.CategoryWithID, errHelper *helper.ErrorStruct)
 }
 
 type CategoryUseCaseImpl struct {
 	categoryRepository repository.CategoryRepository
+	produkUseCase      ProdukUseCase
 }
 
-func NewCategoryUseCase(categoryRepository repository.CategoryRepository) CategoryUseCase {
+func NewCategoryUseCase(categoryRepository repository.CategoryRepository, produkUseCase ProdukUseCase) CategoryUseCase {
 	return &CategoryUseCaseImpl{
 		categoryRepository: categoryRepository,
+		produkUseCase:      produkUseCase,
 	}
 }
 
@@ -132,6 +136,9 @@ func (cu *CategoryUseCaseImpl) UpdateCategoryByID(ctx context.Context, ID uint,
 		return errHelper
 	}
 
+	// nama category is searchable text of every produk in the category
+	cu.produkUseCase.IndexProdukCategory(ctx, ID)
+
 	// success response
 	errHelper = &helper.ErrorStruct{
 		Err:  nil,
@@ -158,3 +165,53 @@ func (cu *CategoryUseCaseImpl) DeleteCategoryByID(ctx context.Context, data dto.
 	}
 	return errHelper
 }
+
+func (cu *CategoryUseCaseImpl) RestoreCategoryByID(ctx context.Context, ID uint) (errHelper *helper.ErrorStruct) {
+
+	// call RestoreCategoryByID from category repository to get error from repository
+	if errRepo := cu.categoryRepository.RestoreCategoryByID(ctx, ID); errRepo.Err != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errRepo.Err,
+			Code: errRepo.Code,
+		}
+		return errHelper
+	}
+
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return errHelper
+}
+
+func (cu *CategoryUseCaseImpl) GetSampahCategory(ctx context.Context) (response []dto.CategoryWithID, errHelper *helper.ErrorStruct) {
+
+	// call GetSampahCategory from category repository to get deleted categories and error information
+	responseRepo, errRepo := cu.categoryRepository.GetSampahCategory(ctx)
+	if errRepo.Err != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errRepo.Err,
+			Code: errRepo.Code,
+		}
+		return response, errHelper
+	}
+
+	// mapping from daos category to dto category
+	response = []dto.CategoryWithID{}
+	for _, v := range responseRepo {
+		deletedAt := v.DeletedAt.Time
+		response = append(response, dto.CategoryWithID{
+			ID:           v.ID,
+			NamaCategory: v.NamaCategory,
+			DeletedAt:    &deletedAt,
+		})
+	}
+
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return response, errHelper
+}
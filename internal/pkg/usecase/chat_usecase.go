@@ -0,0 +1,327 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/daos"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/helper"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/infrastructure/storage"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/pkg/dto"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/pkg/repository"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	PeranPembeli = "pembeli"
+	PeranPenjual = "penjual"
+)
+
+type ChatUseCase interface {
+	CreatePercakapan(ctx context.Context, userID uint, data dto.CreatePercakapanRequest) (response dto.PercakapanResponse, pesan *dto.PesanResponse, errHelper *helper.ErrorStruct)
+	GetMyPercakapan(ctx context.Context, userID uint, params dto.FilterPercakapan) (response []dto.PercakapanResponse, errHelper *helper.ErrorStruct)
+	GetPesan(ctx context.Context, userID, percakapanID uint, params dto.FilterPesan) (response []dto.PesanResponse, errHelper *helper.ErrorStruct)
+	KirimPesan(ctx context.Context, userID uint, data dto.CreatePesanRequest) (response dto.PesanResponse, errHelper *helper.ErrorStruct)
+	MarkRead(ctx context.Context, userID, percakapanID uint) (lawanID uint, errHelper *helper.ErrorStruct)
+	CountUnread(ctx context.Context, userID uint) (response dto.ChatUnreadResponse, errHelper *helper.ErrorStruct)
+}
+
+type ChatUseCaseImpl struct {
+	chatRepository repository.ChatRepository
+	blobStorage    storage.BlobStorage
+}
+
+func NewChatUseCase(chatRepository repository.ChatRepository, blobStorage storage.BlobStorage) ChatUseCase {
+	return &ChatUseCaseImpl{chatRepository: chatRepository, blobStorage: blobStorage}
+}
+
+// mapPercakapan mapping percakapan from daos to dto seen from user side
+func mapPercakapan(userID uint, v daos.Percakapan, blobStorage storage.BlobStorage) dto.PercakapanResponse {
+	percakapan := dto.PercakapanResponse{
+		ID:    v.ID,
+		Peran: PeranPembeli,
+		User: dto.UserChat{
+			ID:   v.User.ID,
+			Nama: v.User.Nama,
+		},
+		Toko: dto.GetTokoByIDResponse{
+			ID:       v.Toko.ID,
+			NamaToko: v.Toko.NamaToko,
+			Slug:     v.Toko.Slug,
+			UrlFoto:  blobStorage.URL(v.Toko.UrlFoto),
+		},
+		PesanTerakhir: v.PesanTerakhir,
+		Unread:        v.UnreadUser,
+	}
+	if v.UserID != userID {
+		percakapan.Peran = PeranPenjual
+		percakapan.Unread = v.UnreadToko
+	}
+	if v.ProdukID != nil {
+		percakapan.ProdukID = *v.ProdukID
+	}
+	if v.TRXID != nil {
+		percakapan.TRXID = *v.TRXID
+	}
+	if v.PesanTerakhirAt != nil {
+		percakapan.PesanTerakhirAt = v.PesanTerakhirAt.Format(time.RFC3339)
+	}
+	return percakapan
+}
+
+// mapPesan mapping pesan from daos to dto
+func mapPesan(v daos.Pesan) dto.PesanResponse {
+	return dto.PesanResponse{
+		ID:           v.ID,
+		PercakapanID: v.PercakapanID,
+		PengirimID:   v.PengirimID,
+		DariToko:     v.DariToko,
+		IsiPesan:     v.IsiPesan,
+		IsRead:       v.ReadAt != nil,
+		CreatedAt:    v.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+func (cu *ChatUseCaseImpl) CreatePercakapan(ctx context.Context, userID uint, data dto.CreatePercakapanRequest) (response dto.PercakapanResponse, pesan *dto.PesanResponse, errHelper *helper.ErrorStruct) {
+	// validate user input
+	if errValidate := helper.Validate.Struct(data); errValidate != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errValidate,
+			Code: http.StatusBadRequest,
+		}
+		return response, pesan, errHelper
+	}
+
+	// mapping optional reference
+	percakapan := daos.Percakapan{
+		UserID: userID,
+		TokoID: data.TokoID,
+	}
+	if data.ProdukID != 0 {
+		percakapan.ProdukID = &data.ProdukID
+	}
+	if data.TRXID != 0 {
+		percakapan.TRXID = &data.TRXID
+	}
+
+	// call GetOrCreatePercakapan from chat repository
+	responseRepo, errRepo := cu.chatRepository.GetOrCreatePercakapan(ctx, percakapan)
+	if errRepo.Err != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errRepo.Err,
+			Code: errRepo.Code,
+		}
+		return response, pesan, errHelper
+	}
+	response = mapPercakapan(userID, responseRepo, cu.blobStorage)
+
+	// send first message when provided
+	if strings.TrimSpace(data.IsiPesan) != "" {
+		pesanUseCase, errUseCase := cu.KirimPesan(ctx, userID, dto.CreatePesanRequest{
+			PercakapanID: responseRepo.ID,
+			IsiPesan:     data.IsiPesan,
+		})
+		if errUseCase.Err != nil {
+			return response, pesan, errUseCase
+		}
+		pesan = &pesanUseCase
+		response.PesanTerakhir = pesanUseCase.IsiPesan
+		response.PesanTerakhirAt = pesanUseCase.CreatedAt
+	}
+
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return response, pesan, errHelper
+}
+
+func (cu *ChatUseCaseImpl) GetMyPercakapan(ctx context.Context, userID uint, params dto.FilterPercakapan) (response []dto.PercakapanResponse, errHelper *helper.ErrorStruct) {
+	// setup pagination
+	if params.Limit < 1 {
+		params.Limit = 10
+	}
+	if params.Page < 1 {
+		params.Page = 0
+	} else {
+		params.Page = (params.Page - 1) * params.Limit
+	}
+
+	// call GetMyPercakapan from chat repository
+	responseRepo, errRepo := cu.chatRepository.GetMyPercakapan(ctx, userID, daos.FilterPercakapan{
+		Limit:  params.Limit,
+		Offset: params.Page,
+	})
+	if errRepo.Err != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errRepo.Err,
+			Code: errRepo.Code,
+		}
+		return response, errHelper
+	}
+	for _, v := range responseRepo {
+		response = append(response, mapPercakapan(userID, v, cu.blobStorage))
+	}
+
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return response, errHelper
+}
+
+func (cu *ChatUseCaseImpl) GetPesan(ctx context.Context, userID, percakapanID uint, params dto.FilterPesan) (response []dto.PesanResponse, errHelper *helper.ErrorStruct) {
+	// setup pagination
+	if params.Limit < 1 || params.Limit > 100 {
+		params.Limit = 20
+	}
+
+	// check if user is participant of percakapan
+	if _, errRepo := cu.chatRepository.GetPercakapanByID(ctx, userID, percakapanID); errRepo.Err != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errRepo.Err,
+			Code: errRepo.Code,
+		}
+		return response, errHelper
+	}
+
+	// call GetPesan from chat repository
+	responseRepo, errRepo := cu.chatRepository.GetPesan(ctx, percakapanID, daos.FilterPesan{
+		Limit:    params.Limit,
+		BeforeID: params.BeforeID,
+	})
+	if errRepo.Err != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errRepo.Err,
+			Code: errRepo.Code,
+		}
+		return response, errHelper
+	}
+	response = []dto.PesanResponse{}
+	for _, v := range responseRepo {
+		response = append(response, mapPesan(v))
+	}
+
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return response, errHelper
+}
+
+func (cu *ChatUseCaseImpl) KirimPesan(ctx context.Context, userID uint, data dto.CreatePesanRequest) (response dto.PesanResponse, errHelper *helper.ErrorStruct) {
+	// validate user input
+	data.IsiPesan = strings.TrimSpace(data.IsiPesan)
+	if errValidate := helper.Validate.Struct(data); errValidate != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errValidate,
+			Code: http.StatusBadRequest,
+		}
+		return response, errHelper
+	}
+	if len([]rune(data.IsiPesan)) > 2000 {
+		errHelper = &helper.ErrorStruct{
+			Err:  errors.New("isi_pesan maximum 2000 characters"),
+			Code: http.StatusBadRequest,
+		}
+		return response, errHelper
+	}
+
+	// check if user is participant of percakapan
+	percakapan, errRepo := cu.chatRepository.GetPercakapanByID(ctx, userID, data.PercakapanID)
+	if errRepo.Err != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errRepo.Err,
+			Code: errRepo.Code,
+		}
+		return response, errHelper
+	}
+
+	// message sent by owner of toko when sender is not the buyer
+	dariToko := percakapan.UserID != userID
+	penerimaID := percakapan.Toko.UserID
+	if dariToko {
+		penerimaID = percakapan.UserID
+	}
+
+	// call CreatePesan from chat repository
+	responseRepo, errRepo := cu.chatRepository.CreatePesan(ctx, daos.Pesan{
+		PercakapanID: percakapan.ID,
+		PengirimID:   userID,
+		DariToko:     dariToko,
+		IsiPesan:     data.IsiPesan,
+	})
+	if errRepo.Err != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errRepo.Err,
+			Code: errRepo.Code,
+		}
+		return response, errHelper
+	}
+	response = mapPesan(responseRepo)
+	response.PenerimaID = penerimaID
+
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return response, errHelper
+}
+
+func (cu *ChatUseCaseImpl) MarkRead(ctx context.Context, userID, percakapanID uint) (lawanID uint, errHelper *helper.ErrorStruct) {
+	// check if user is participant of percakapan
+	percakapan, errRepo := cu.chatRepository.GetPercakapanByID(ctx, userID, percakapanID)
+	if errRepo.Err != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errRepo.Err,
+			Code: errRepo.Code,
+		}
+		return lawanID, errHelper
+	}
+	dariToko := percakapan.UserID != userID
+	lawanID = percakapan.Toko.UserID
+	if dariToko {
+		lawanID = percakapan.UserID
+	}
+
+	// call MarkRead from chat repository
+	if errRepo = cu.chatRepository.MarkRead(ctx, percakapan.ID, dariToko); errRepo.Err != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errRepo.Err,
+			Code: errRepo.Code,
+		}
+		return lawanID, errHelper
+	}
+
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return lawanID, errHelper
+}
+
+func (cu *ChatUseCaseImpl) CountUnread(ctx context.Context, userID uint) (response dto.ChatUnreadResponse, errHelper *helper.ErrorStruct) {
+	// call CountUnread from chat repository
+	total, errRepo := cu.chatRepository.CountUnread(ctx, userID)
+	if errRepo.Err != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errRepo.Err,
+			Code: errRepo.Code,
+		}
+		return response, errHelper
+	}
+	response.Total = total
+
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return response, errHelper
+}
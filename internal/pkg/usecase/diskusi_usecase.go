@@ -0,0 +1,362 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/daos"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/helper"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/pkg/dto"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/pkg/repository"
+	"net/http"
+	"strings"
+	"time"
+)
+
+type DiskusiUseCase interface {
+	GetDiskusiProduk(ctx context.Context, produkID, penggunaID uint, params dto.FilterDiskusi) (response []dto.PertanyaanResponse, errHelper *helper.ErrorStruct)
+	CreatePertanyaan(ctx context.Context, data dto.DiskusiRequest) (response dto.PertanyaanResponse, errHelper *helper.ErrorStruct)
+	CreateJawaban(ctx context.Context, data dto.DiskusiRequest) (response dto.JawabanResponse, errHelper *helper.ErrorStruct)
+	VoteMembantu(ctx context.Context, userID, ID uint) (errHelper *helper.ErrorStruct)
+	HapusVoteMembantu(ctx context.Context, userID, ID uint) (errHelper *helper.ErrorStruct)
+	LaporkanDiskusi(ctx context.Context, userID, ID uint, jawaban bool) (errHelper *helper.ErrorStruct)
+	GetAntrianModerasi(ctx context.Context, params dto.FilterModerasiDiskusi) (response []dto.DiskusiModerasiResponse, errHelper *helper.ErrorStruct)
+	ModerasiDiskusi(ctx context.Context, data dto.ModerasiDiskusiRequest) (errHelper *helper.ErrorStruct)
+}
+
+type DiskusiUseCaseImpl struct {
+	diskusiRepository repository.DiskusiRepository
+}
+
+func NewDiskusiUseCase(diskusiRepository repository.DiskusiRepository) DiskusiUseCase {
+	return &DiskusiUseCaseImpl{diskusiRepository: diskusiRepository}
+}
+
+// mapJawaban mapping answer from daos to dto, listMembantu is answer voted helpful by logged in user
+func mapJawaban(v daos.DiskusiProduk, listMembantu map[uint]bool) dto.JawabanResponse {
+	jawaban := dto.JawabanResponse{
+		ID: v.ID,
+		User: dto.UserChat{
+			ID:   v.User.ID,
+			Nama: v.User.Nama,
+		},
+		DariToko:       v.DariToko,
+		Isi:            v.Isi,
+		JumlahMembantu: v.JumlahMembantu,
+		Membantu:       listMembantu[v.ID],
+		CreatedAt:      v.CreatedAt.Format(time.RFC3339),
+	}
+	if v.PertanyaanID != nil {
+		jawaban.PertanyaanID = *v.PertanyaanID
+	}
+	return jawaban
+}
+
+// mapPertanyaan mapping question with its answer from daos to dto
+func mapPertanyaan(v daos.DiskusiProduk, listMembantu map[uint]bool) dto.PertanyaanResponse {
+	pertanyaan := dto.PertanyaanResponse{
+		ID:       v.ID,
+		ProdukID: v.ProdukID,
+		User: dto.UserChat{
+			ID:   v.User.ID,
+			Nama: v.User.Nama,
+		},
+		Isi:           v.Isi,
+		JumlahJawaban: v.JumlahJawaban,
+		Jawaban:       []dto.JawabanResponse{},
+		CreatedAt:     v.CreatedAt.Format(time.RFC3339),
+	}
+	for _, jawaban := range v.Jawaban {
+		pertanyaan.Jawaban = append(pertanyaan.Jawaban, mapJawaban(jawaban, listMembantu))
+	}
+	return pertanyaan
+}
+
+// GetDiskusiProduk penggunaID is 0 when user is not logged in, so no answer is marked as voted
+func (du *DiskusiUseCaseImpl) GetDiskusiProduk(ctx context.Context, produkID, penggunaID uint, params dto.FilterDiskusi) (response []dto.PertanyaanResponse, errHelper *helper.ErrorStruct) {
+	// setup pagination
+	if params.Limit < 1 {
+		params.Limit = 10
+	}
+	if params.Page < 1 {
+		params.Page = 0
+	} else {
+		params.Page = (params.Page - 1) * params.Limit
+	}
+
+	// call GetDiskusiProduk from diskusi repository
+	responseRepo, errRepo := du.diskusiRepository.GetDiskusiProduk(ctx, produkID, penggunaID, daos.FilterDiskusi{
+		Limit:  params.Limit,
+		Offset: params.Page,
+	})
+	if errRepo.Err != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errRepo.Err,
+			Code: errRepo.Code,
+		}
+		return response, errHelper
+	}
+
+	// answer already voted helpful by logged in user
+	listMembantu := map[uint]bool{}
+	if penggunaID != 0 {
+		var listJawabanID []uint
+		for _, v := range responseRepo {
+			for _, jawaban := range v.Jawaban {
+				listJawabanID = append(listJawabanID, jawaban.ID)
+			}
+		}
+		listVote, errRepo := du.diskusiRepository.GetTanggapanUser(ctx, penggunaID, listJawabanID, daos.TanggapanMembantu)
+		if errRepo.Err != nil {
+			errHelper = &helper.ErrorStruct{
+				Err:  errRepo.Err,
+				Code: errRepo.Code,
+			}
+			return response, errHelper
+		}
+		for _, v := range listVote {
+			listMembantu[v] = true
+		}
+	}
+	response = []dto.PertanyaanResponse{}
+	for _, v := range responseRepo {
+		response = append(response, mapPertanyaan(v, listMembantu))
+	}
+
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return response, errHelper
+}
+
+func (du *DiskusiUseCaseImpl) CreatePertanyaan(ctx context.Context, data dto.DiskusiRequest) (response dto.PertanyaanResponse, errHelper *helper.ErrorStruct) {
+	// validate user input
+	data.Isi = strings.TrimSpace(data.Isi)
+	if errValidate := helper.Validate.Struct(data); errValidate != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errValidate,
+			Code: http.StatusBadRequest,
+		}
+		return response, errHelper
+	}
+
+	// call CreatePertanyaan from diskusi repository
+	responseRepo, errRepo := du.diskusiRepository.CreatePertanyaan(ctx, daos.DiskusiProduk{
+		ProdukID:       data.ProdukID,
+		UserID:         data.UserID,
+		Isi:            data.Isi,
+		StatusModerasi: daos.ModerasiDisetujui,
+	})
+	if errRepo.Err != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errRepo.Err,
+			Code: errRepo.Code,
+		}
+		return response, errHelper
+	}
+	response = mapPertanyaan(responseRepo, nil)
+
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return response, errHelper
+}
+
+// CreateJawaban answer can be given by owner of the produk or other user
+func (du *DiskusiUseCaseImpl) CreateJawaban(ctx context.Context, data dto.DiskusiRequest) (response dto.JawabanResponse, errHelper *helper.ErrorStruct) {
+	// validate user input
+	data.Isi = strings.TrimSpace(data.Isi)
+	if errValidate := helper.Validate.Struct(data); errValidate != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errValidate,
+			Code: http.StatusBadRequest,
+		}
+		return response, errHelper
+	}
+
+	// call CreateJawaban from diskusi repository
+	responseRepo, errRepo := du.diskusiRepository.CreateJawaban(ctx, daos.DiskusiProduk{
+		PertanyaanID:   &data.PertanyaanID,
+		UserID:         data.UserID,
+		Isi:            data.Isi,
+		StatusModerasi: daos.ModerasiDisetujui,
+	})
+	if errRepo.Err != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errRepo.Err,
+			Code: errRepo.Code,
+		}
+		return response, errHelper
+	}
+	response = mapJawaban(responseRepo, nil)
+
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return response, errHelper
+}
+
+// VoteMembantu only answer can be voted helpful
+func (du *DiskusiUseCaseImpl) VoteMembantu(ctx context.Context, userID, ID uint) (errHelper *helper.ErrorStruct) {
+	// call BeriTanggapan from diskusi repository
+	errRepo := du.diskusiRepository.BeriTanggapan(ctx, userID, ID, daos.TanggapanMembantu, true)
+	if errRepo.Err != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errRepo.Err,
+			Code: errRepo.Code,
+		}
+		return errHelper
+	}
+
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return errHelper
+}
+
+func (du *DiskusiUseCaseImpl) HapusVoteMembantu(ctx context.Context, userID, ID uint) (errHelper *helper.ErrorStruct) {
+	// call HapusTanggapan from diskusi repository
+	errRepo := du.diskusiRepository.HapusTanggapan(ctx, userID, ID, daos.TanggapanMembantu)
+	if errRepo.Err != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errRepo.Err,
+			Code: errRepo.Code,
+		}
+		return errHelper
+	}
+
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return errHelper
+}
+
+// LaporkanDiskusi report question or answer to admin, it stays shown until admin reject it
+func (du *DiskusiUseCaseImpl) LaporkanDiskusi(ctx context.Context, userID, ID uint, jawaban bool) (errHelper *helper.ErrorStruct) {
+	// call BeriTanggapan from diskusi repository
+	errRepo := du.diskusiRepository.BeriTanggapan(ctx, userID, ID, daos.TanggapanLaporan, jawaban)
+	if errRepo.Err != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errRepo.Err,
+			Code: errRepo.Code,
+		}
+		return errHelper
+	}
+
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return errHelper
+}
+
+// GetAntrianModerasi reported question and answer by default, the most reported first
+func (du *DiskusiUseCaseImpl) GetAntrianModerasi(ctx context.Context, params dto.FilterModerasiDiskusi) (response []dto.DiskusiModerasiResponse, errHelper *helper.ErrorStruct) {
+	// validate filter
+	if errValidate := helper.Validate.Struct(params); errValidate != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errValidate,
+			Code: http.StatusBadRequest,
+		}
+		return response, errHelper
+	}
+	filter := daos.FilterModerasiDiskusi{Status: params.Status}
+	if params.Status == "" {
+		filter.Status = daos.ModerasiDisetujui
+		filter.Dilaporkan = true
+	}
+
+	// setup pagination
+	if params.Limit < 1 {
+		params.Limit = 10
+	}
+	if params.Page < 1 {
+		params.Page = 0
+	} else {
+		params.Page = (params.Page - 1) * params.Limit
+	}
+	filter.Limit, filter.Offset = params.Limit, params.Page
+
+	// call GetAntrianModerasiDiskusi from diskusi repository
+	responseRepo, errRepo := du.diskusiRepository.GetAntrianModerasiDiskusi(ctx, filter)
+	if errRepo.Err != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errRepo.Err,
+			Code: errRepo.Code,
+		}
+		return response, errHelper
+	}
+	response = []dto.DiskusiModerasiResponse{}
+	for _, v := range responseRepo {
+		response = append(response, dto.DiskusiModerasiResponse{
+			ID:           v.ID,
+			ProdukID:     v.ProdukID,
+			PertanyaanID: v.PertanyaanID,
+			User: dto.UserChat{
+				ID:   v.User.ID,
+				Nama: v.User.Nama,
+			},
+			Isi:            v.Isi,
+			StatusModerasi: v.StatusModerasi,
+			AlasanModerasi: v.AlasanModerasi,
+			JumlahLaporan:  v.JumlahLaporan,
+			CreatedAt:      v.CreatedAt.Format(time.RFC3339),
+		})
+	}
+
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return response, errHelper
+}
+
+// ModerasiDiskusi approve or reject question or answer, alasan of approved one is ignored
+func (du *DiskusiUseCaseImpl) ModerasiDiskusi(ctx context.Context, data dto.ModerasiDiskusiRequest) (errHelper *helper.ErrorStruct) {
+	// validate user input
+	data.Alasan = strings.TrimSpace(data.Alasan)
+	var err error
+	if errValidate := helper.Validate.Struct(data); errValidate != nil {
+		err = errValidate
+	} else if data.Status == daos.ModerasiDitolak && data.Alasan == "" {
+		err = errors.New("alasan is required to reject question or answer")
+	}
+	if err != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  err,
+			Code: http.StatusBadRequest,
+		}
+		return errHelper
+	}
+	if data.Status == daos.ModerasiDisetujui {
+		data.Alasan = ""
+	}
+
+	// call ModerasiDiskusi from diskusi repository
+	errRepo := du.diskusiRepository.ModerasiDiskusi(ctx, data.DiskusiID, data.Status, data.Alasan)
+	if errRepo.Err != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errRepo.Err,
+			Code: errRepo.Code,
+		}
+		return errHelper
+	}
+
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return errHelper
+}
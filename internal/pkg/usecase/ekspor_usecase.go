@@ -0,0 +1,192 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/daos"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/helper"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/infrastructure/storage"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/infrastructure/tabel"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/pkg/dto"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/pkg/repository"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	KolomEksporTokoID   = "toko_id"
+	KolomEksporNamaToko = "nama_toko"
+
+	// batasEkspor produk read from database at once while the file is streamed
+	batasEkspor = 200
+)
+
+type EksporUseCase interface {
+	EksporProduk(ctx context.Context, tokoID uint, params dto.FilterEkspor) (response dto.FileEkspor, errHelper *helper.ErrorStruct)
+	EksporSemuaProduk(ctx context.Context, params dto.FilterEkspor) (response dto.FileEkspor, errHelper *helper.ErrorStruct)
+}
+
+type EksporUseCaseImpl struct {
+	produkRepository repository.ProdukRepository
+	blobStorage      storage.BlobStorage
+}
+
+// NewEksporUseCase file is written in the same column as import file, so exported file can be edited and imported back
+func NewEksporUseCase(produkRepository repository.ProdukRepository, blobStorage storage.BlobStorage) EksporUseCase {
+	return &EksporUseCaseImpl{
+		produkRepository: produkRepository,
+		blobStorage:      blobStorage,
+	}
+}
+
+func (eu *EksporUseCaseImpl) EksporProduk(ctx context.Context, tokoID uint, params dto.FilterEkspor) (response dto.FileEkspor, errHelper *helper.ErrorStruct) {
+	return eu.ekspor(tokoID, params, fmt.Sprintf("produk-toko-%d", tokoID))
+}
+
+// EksporSemuaProduk whole catalog for admin, toko of every produk is written in extra column
+func (eu *EksporUseCaseImpl) EksporSemuaProduk(ctx context.Context, params dto.FilterEkspor) (response dto.FileEkspor, errHelper *helper.ErrorStruct) {
+	return eu.ekspor(0, params, "produk")
+}
+
+func (eu *EksporUseCaseImpl) ekspor(tokoID uint, params dto.FilterEkspor, nama string) (response dto.FileEkspor, errHelper *helper.ErrorStruct) {
+	// csv is the default format
+	format := strings.ToLower(strings.TrimSpace(params.Format))
+	if format == "" {
+		format = tabel.FormatCSV
+	}
+	if format != tabel.FormatCSV && format != tabel.FormatXLSX && format != tabel.FormatJSON {
+		errHelper = &helper.ErrorStruct{
+			Err:  tabel.ErrFormatTidakDidukung,
+			Code: http.StatusBadRequest,
+		}
+		return response, errHelper
+	}
+
+	response = dto.FileEkspor{
+		NamaFile:    fmt.Sprintf("%s-%s.%s", nama, time.Now().Format("20060102-150405"), format),
+		ContentType: tabel.ContentType(format),
+		Tulis: func(w io.Writer) error {
+			// request context is finished once the handler return, the file is streamed after that
+			if format == tabel.FormatJSON {
+				return eu.tulisJSON(context.Background(), tokoID, w)
+			}
+			return eu.tulisTabel(context.Background(), tokoID, format, w)
+		},
+	}
+
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return response, errHelper
+}
+
+// setiapProdukEkspor call fungsi for every produk, produk is read page by page ordered by id
+func (eu *EksporUseCaseImpl) setiapProdukEkspor(ctx context.Context, tokoID uint, fungsi func(produk dto.EksporProduk) error) error {
+	var setelah uint
+	for {
+		listProduk, errRepo := eu.produkRepository.GetProdukEkspor(ctx, tokoID, setelah, batasEkspor)
+		if errRepo.Err != nil {
+			return errRepo.Err
+		}
+		for _, v := range listProduk {
+			if err := fungsi(eu.mapEkspor(v, tokoID == 0)); err != nil {
+				return err
+			}
+		}
+		if len(listProduk) < batasEkspor {
+			return nil
+		}
+		setelah = listProduk[len(listProduk)-1].ID
+	}
+}
+
+func (eu *EksporUseCaseImpl) mapEkspor(v daos.Produk, denganToko bool) dto.EksporProduk {
+	produk := dto.EksporProduk{
+		ID:            v.ID,
+		NamaProduk:    v.NamaProduk,
+		Category:      v.Category.NamaCategory,
+		HargaReseller: v.HargaReseller,
+		HargaKonsumen: v.HargaKonsumen,
+		Stok:          v.Stok,
+		Deskripsi:     v.Deskripsi,
+		Foto:          []string{},
+		Versi:         v.Versi,
+	}
+	for _, f := range v.FotoProduk {
+		produk.Foto = append(produk.Foto, eu.blobStorage.URL(f.URL))
+	}
+	if denganToko {
+		produk.TokoID = v.TokoID
+		produk.NamaToko = v.Toko.NamaToko
+	}
+	return produk
+}
+
+func (eu *EksporUseCaseImpl) tulisTabel(ctx context.Context, tokoID uint, format string, w io.Writer) error {
+	penulis, err := tabel.NewPenulis(format, w)
+	if err != nil {
+		return err
+	}
+	header := append([]string{}, ListKolomImpor...)
+	if tokoID == 0 {
+		header = append(header, KolomEksporTokoID, KolomEksporNamaToko)
+	}
+	if err := penulis.Tulis(header); err != nil {
+		return err
+	}
+
+	err = eu.setiapProdukEkspor(ctx, tokoID, func(produk dto.EksporProduk) error {
+		// the order is the same as ListKolomImpor
+		sel := []string{
+			strconv.Itoa(int(produk.ID)),
+			produk.NamaProduk,
+			produk.Category,
+			strconv.Itoa(int(produk.HargaReseller)),
+			strconv.Itoa(int(produk.HargaKonsumen)),
+			strconv.Itoa(int(produk.Stok)),
+			produk.Deskripsi,
+			strings.Join(produk.Foto, "|"),
+			strconv.Itoa(int(produk.Versi)),
+		}
+		if tokoID == 0 {
+			sel = append(sel, strconv.Itoa(int(produk.TokoID)), produk.NamaToko)
+		}
+		return penulis.Tulis(sel)
+	})
+	if err != nil {
+		return err
+	}
+	return penulis.Tutup()
+}
+
+func (eu *EksporUseCaseImpl) tulisJSON(ctx context.Context, tokoID uint, w io.Writer) error {
+	if _, err := io.WriteString(w, "["); err != nil {
+		return err
+	}
+	pertama := true
+	err := eu.setiapProdukEkspor(ctx, tokoID, func(produk dto.EksporProduk) error {
+		if !pertama {
+			if _, err := io.WriteString(w, ","); err != nil {
+				return err
+			}
+		}
+		pertama = false
+		data, err := json.Marshal(produk)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(data)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, "]")
+	return err
+}
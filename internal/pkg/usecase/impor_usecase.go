@@ -0,0 +1,732 @@
+package usecase
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/daos"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/helper"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/infrastructure/gambar"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/infrastructure/jadwal"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/infrastructure/storage"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/infrastructure/tabel"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/pkg/dto"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/pkg/repository"
+	"math"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	KolomImporID            = "id"
+	KolomImporNama          = "nama_produk"
+	KolomImporCategory      = "category"
+	KolomImporHargaReseller = "harga_reseller"
+	KolomImporHargaKonsumen = "harga_konsumen"
+	KolomImporStok          = "stok"
+	KolomImporDeskripsi     = "deskripsi"
+	KolomImporFoto          = "foto"
+	KolomImporVersi         = "versi"
+
+	maxBarisImpor   = 5000
+	maxFotoImpor    = 10
+	maxPanjangNama  = 255
+	batasProgres    = 25
+	jedaProgres     = 5 * time.Second
+	prefixFotoImpor = "produk"
+)
+
+// ListKolomImpor column of import file in the order written by export, every column except id, foto and versi is required.
+// Row with id update that produk of the toko instead of creating new one, so exported file can be edited and imported back.
+// Versi written by export make the row fail when the produk is changed after the export.
+var ListKolomImpor = []string{KolomImporID, KolomImporNama, KolomImporCategory, KolomImporHargaReseller, KolomImporHargaKonsumen, KolomImporStok, KolomImporDeskripsi, KolomImporFoto, KolomImporVersi}
+
+// aliasKolomImpor other accepted header name, header is compared in lower case with space replaced by underscore
+var aliasKolomImpor = map[string]string{
+	"produk_id":     KolomImporID,
+	"nama":          KolomImporNama,
+	"name":          KolomImporNama,
+	"category_id":   KolomImporCategory,
+	"nama_category": KolomImporCategory,
+	"kategori":      KolomImporCategory,
+	"harga":         KolomImporHargaKonsumen,
+	"price":         KolomImporHargaKonsumen,
+	"stock":         KolomImporStok,
+	"description":   KolomImporDeskripsi,
+	"photos":        KolomImporFoto,
+	"images":        KolomImporFoto,
+	"image":         KolomImporFoto,
+	"gambar":        KolomImporFoto,
+}
+
+type ImporUseCase interface {
+	ImporProduk(ctx context.Context, data dto.ImporProdukRequest) (response dto.ImporProdukResponse, errHelper *helper.ErrorStruct)
+	GetImporByID(ctx context.Context, tokoID, ID uint) (response dto.ImporProdukResponse, errHelper *helper.ErrorStruct)
+	GetAllImpor(ctx context.Context, tokoID uint, params dto.FilterImpor) (response []dto.ImporProdukResponse, errHelper *helper.ErrorStruct)
+	GetBarisImporGagal(ctx context.Context, tokoID, ID uint, params dto.FilterImpor) (response []dto.BarisImporGagalResponse, errHelper *helper.ErrorStruct)
+	GetLaporanImpor(ctx context.Context, tokoID, ID uint) (namaFile string, data []byte, errHelper *helper.ErrorStruct)
+	HentikanImporTerputus(ctx context.Context) (errHelper *helper.ErrorStruct)
+}
+
+type ImporUseCaseImpl struct {
+	imporRepository    repository.ImporRepository
+	categoryRepository repository.CategoryRepository
+	produkUseCase      ProdukUseCase
+	produkRepository   repository.ProdukRepository
+	gambarPipeline     *gambar.Pipeline
+	blobStorage        storage.BlobStorage
+	penjadwal          *jadwal.Penjadwal
+}
+
+// NewImporUseCase every row is saved through produkUseCase so it is indexed like produk uploaded one by one,
+// the job run in background through penjadwal
+func NewImporUseCase(imporRepository repository.ImporRepository, categoryRepository repository.CategoryRepository, produkUseCase ProdukUseCase, produkRepository repository.ProdukRepository, gambarPipeline *gambar.Pipeline, blobStorage storage.BlobStorage, penjadwal *jadwal.Penjadwal) ImporUseCase {
+	return &ImporUseCaseImpl{
+		imporRepository:    imporRepository,
+		categoryRepository: categoryRepository,
+		produkUseCase:      produkUseCase,
+		produkRepository:   produkRepository,
+		gambarPipeline:     gambarPipeline,
+		blobStorage:        blobStorage,
+		penjadwal:          penjadwal,
+	}
+}
+
+// jobImpor data needed by background job, read once when the file is uploaded
+type jobImpor struct {
+	impor     daos.ImporProduk
+	kolom     map[string]int
+	listBaris []tabel.Baris
+	arsip     map[string]*zip.File
+}
+
+func (iu *ImporUseCaseImpl) ImporProduk(ctx context.Context, data dto.ImporProdukRequest) (response dto.ImporProdukResponse, errHelper *helper.ErrorStruct) {
+	// read and check the whole file first so wrong file is refused right away
+	format, err := tabel.FormatDariNama(data.NamaFile)
+	if err == nil {
+		var listBaris []tabel.Baris
+		listBaris, err = tabel.Baca(format, data.Data)
+		if err == nil && len(listBaris) < 2 {
+			err = errors.New("file has no data row")
+		}
+		if err == nil && len(listBaris)-1 > maxBarisImpor {
+			err = fmt.Errorf("file has %d data row, maximum is %d", len(listBaris)-1, maxBarisImpor)
+		}
+		var job jobImpor
+		if err == nil {
+			job.listBaris = listBaris[1:]
+			job.kolom, err = kolomImpor(listBaris[0])
+		}
+		if err == nil && len(data.Zip) > 0 {
+			job.arsip, err = bukaArsipFoto(data.Zip)
+		}
+		if err == nil {
+			return iu.mulaiImpor(ctx, data, format, listBaris[0], job)
+		}
+	}
+	errHelper = &helper.ErrorStruct{
+		Err:  err,
+		Code: http.StatusBadRequest,
+	}
+	return response, errHelper
+}
+
+func (iu *ImporUseCaseImpl) mulaiImpor(ctx context.Context, data dto.ImporProdukRequest, format string, header tabel.Baris, job jobImpor) (response dto.ImporProdukResponse, errHelper *helper.ErrorStruct) {
+	// only one running import per toko, so the same file uploaded twice does not create produk twice
+	listImpor, errRepo := iu.imporRepository.GetAllImpor(ctx, data.TokoID, 1, 0)
+	if errRepo.Err != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errRepo.Err,
+			Code: errRepo.Code,
+		}
+		return response, errHelper
+	}
+	if len(listImpor) > 0 && (listImpor[0].Status == daos.StatusImporMenunggu || listImpor[0].Status == daos.StatusImporDiproses) {
+		errHelper = &helper.ErrorStruct{
+			Err:  errors.New("another import is still running"),
+			Code: http.StatusConflict,
+		}
+		return response, errHelper
+	}
+
+	headerByte, _ := json.Marshal(header.Sel)
+	job.impor = daos.ImporProduk{
+		TokoID:     data.TokoID,
+		NamaFile:   path.Base(data.NamaFile),
+		Format:     format,
+		Status:     daos.StatusImporMenunggu,
+		Header:     string(headerByte),
+		TotalBaris: len(job.listBaris),
+	}
+	ID, errRepo := iu.imporRepository.CreateImpor(ctx, job.impor)
+	if errRepo.Err != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errRepo.Err,
+			Code: errRepo.Code,
+		}
+		return response, errHelper
+	}
+	job.impor.ID = ID
+	job.impor.CreatedAt = time.Now()
+
+	// request context end with the request, job use context of penjadwal instead
+	if !iu.penjadwal.Sekali(fmt.Sprintf("impor produk %d", ID), func(ctx context.Context) {
+		iu.prosesImpor(ctx, job)
+	}) {
+		job.impor.Status = daos.StatusImporGagal
+		job.impor.Pesan = "server is shutting down"
+		iu.imporRepository.SelesaikanImpor(ctx, job.impor)
+		errHelper = &helper.ErrorStruct{
+			Err:  errors.New(job.impor.Pesan),
+			Code: http.StatusServiceUnavailable,
+		}
+		return response, errHelper
+	}
+
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return mapImpor(job.impor), errHelper
+}
+
+// kolomImpor position of every known column in header, unknown column is ignored
+func kolomImpor(header tabel.Baris) (kolom map[string]int, err error) {
+	kolom = map[string]int{}
+	for i, v := range header.Sel {
+		nama := strings.ReplaceAll(strings.ToLower(strings.TrimSpace(v)), " ", "_")
+		if nama == "" {
+			continue
+		}
+		if alias, ada := aliasKolomImpor[nama]; ada {
+			nama = alias
+		}
+		if _, ada := kolom[nama]; ada {
+			return nil, fmt.Errorf("column %s is written more than once", nama)
+		}
+		kolom[nama] = i
+	}
+
+	var listHilang []string
+	for _, v := range ListKolomImpor {
+		if _, ada := kolom[v]; !ada && v != KolomImporID && v != KolomImporFoto && v != KolomImporVersi {
+			listHilang = append(listHilang, v)
+		}
+	}
+	if len(listHilang) > 0 {
+		return nil, fmt.Errorf("column %s is required", strings.Join(listHilang, ", "))
+	}
+	return kolom, nil
+}
+
+// bukaArsipFoto index foto in zip by lower case file name, folder inside zip is ignored
+func bukaArsipFoto(data []byte) (arsip map[string]*zip.File, err error) {
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, errors.New("zip file of foto is corrupted")
+	}
+	arsip = map[string]*zip.File{}
+	for _, f := range reader.File {
+		if f.FileInfo().IsDir() || strings.HasPrefix(f.Name, "__MACOSX/") {
+			continue
+		}
+		arsip[strings.ToLower(path.Base(f.Name))] = f
+	}
+	return arsip, nil
+}
+
+// prosesImpor save every row as produk, rejected row is recorded with its reason.
+// Progress is saved periodically so it can be followed while the job is running.
+func (iu *ImporUseCaseImpl) prosesImpor(ctx context.Context, job jobImpor) {
+	impor := job.impor
+	selesai := func(status, pesan string) {
+		impor.Status = status
+		impor.Pesan = pesan
+		if errRepo := iu.imporRepository.SelesaikanImpor(context.Background(), impor); errRepo.Err != nil {
+			helper.Logger("impor_usecase", helper.LoggerLevelError, fmt.Sprintf("failed to finish impor %d : %s", impor.ID, errRepo.Err.Error()))
+		}
+	}
+
+	listCategory, errRepo := iu.categoryRepository.GetAllCategory(ctx)
+	if errRepo.Err != nil {
+		selesai(daos.StatusImporGagal, "failed to read category")
+		return
+	}
+	category := map[string]uint{}
+	for _, v := range listCategory {
+		category[strings.ToLower(v.NamaCategory)] = v.ID
+		category[strconv.Itoa(int(v.ID))] = v.ID
+	}
+
+	var listBarisGagal []daos.BarisImporGagal
+	terakhirDisimpan := time.Now()
+	for i, baris := range job.listBaris {
+		if ctx.Err() != nil {
+			// rows already saved stay, the message tell where to continue
+			iu.imporRepository.UpdateProgresImpor(context.Background(), impor, listBarisGagal)
+			selesai(daos.StatusImporGagal, fmt.Sprintf("import is interrupted at row %d, row after it is not imported", baris.NomorBaris))
+			return
+		}
+
+		if err := iu.imporBaris(ctx, impor.TokoID, job, category, baris); err != nil {
+			dataByte, _ := json.Marshal(baris.Sel)
+			listBarisGagal = append(listBarisGagal, daos.BarisImporGagal{
+				ImporProdukID: impor.ID,
+				NomorBaris:    baris.NomorBaris,
+				Data:          string(dataByte),
+				Pesan:         err.Error(),
+			})
+			impor.Gagal++
+		} else {
+			impor.Berhasil++
+		}
+		impor.BarisDiproses = i + 1
+
+		if len(listBarisGagal) >= batasProgres || i%batasProgres == batasProgres-1 || time.Since(terakhirDisimpan) >= jedaProgres {
+			if errRepo := iu.imporRepository.UpdateProgresImpor(context.Background(), impor, listBarisGagal); errRepo.Err != nil {
+				helper.Logger("impor_usecase", helper.LoggerLevelWarn, fmt.Sprintf("failed to save progress of impor %d : %s", impor.ID, errRepo.Err.Error()))
+				// job was stopped because it looked abandoned
+				if errRepo.Code == http.StatusConflict {
+					return
+				}
+				continue
+			}
+			listBarisGagal = nil
+			terakhirDisimpan = time.Now()
+		}
+	}
+
+	if errRepo := iu.imporRepository.UpdateProgresImpor(context.Background(), impor, listBarisGagal); errRepo.Err != nil {
+		helper.Logger("impor_usecase", helper.LoggerLevelWarn, fmt.Sprintf("failed to save progress of impor %d : %s", impor.ID, errRepo.Err.Error()))
+		if errRepo.Code == http.StatusConflict {
+			return
+		}
+	}
+	selesai(daos.StatusImporSelesai, "")
+}
+
+// imporBaris validate one row then save it as produk, every problem of the row is returned together
+func (iu *ImporUseCaseImpl) imporBaris(ctx context.Context, tokoID uint, job jobImpor, category map[string]uint, baris tabel.Baris) error {
+	ambil := func(nama string) string {
+		i, ada := job.kolom[nama]
+		if !ada {
+			return ""
+		}
+		return baris.Ambil(i)
+	}
+	var listPesan []string
+
+	data := dto.UploadProdukRequest{
+		TokoID:     tokoID,
+		NamaProduk: ambil(KolomImporNama),
+		Deskripsi:  ambil(KolomImporDeskripsi),
+	}
+	if data.NamaProduk == "" {
+		listPesan = append(listPesan, KolomImporNama+" is required")
+	} else if utf8.RuneCountInString(data.NamaProduk) > maxPanjangNama {
+		listPesan = append(listPesan, fmt.Sprintf("%s is longer than %d character", KolomImporNama, maxPanjangNama))
+	}
+	if data.Deskripsi == "" {
+		listPesan = append(listPesan, KolomImporDeskripsi+" is required")
+	}
+	var produkID uint
+	if nilai := ambil(KolomImporID); nilai != "" {
+		var err error
+		if produkID, err = bacaAngkaImpor(nilai); err != nil || produkID == 0 {
+			listPesan = append(listPesan, KolomImporID+" must be id of produk in your toko")
+		}
+	}
+	var versi uint
+	if nilai := ambil(KolomImporVersi); nilai != "" && produkID > 0 {
+		var err error
+		if versi, err = bacaAngkaImpor(nilai); err != nil || versi == 0 {
+			listPesan = append(listPesan, KolomImporVersi+" must be versi of the produk written by export")
+		}
+	}
+	if nilai := ambil(KolomImporCategory); nilai == "" {
+		listPesan = append(listPesan, KolomImporCategory+" is required")
+	} else if ID, ada := category[strings.ToLower(nilai)]; ada {
+		data.CategoryID = ID
+	} else {
+		listPesan = append(listPesan, fmt.Sprintf("category %q not found", nilai))
+	}
+	for _, v := range []struct {
+		nama  string
+		nilai *uint
+	}{
+		{KolomImporHargaReseller, &data.HargaReseller},
+		{KolomImporHargaKonsumen, &data.HargaKonsumen},
+		{KolomImporStok, &data.Stok},
+	} {
+		angka, err := bacaAngkaImpor(ambil(v.nama))
+		if err != nil {
+			listPesan = append(listPesan, fmt.Sprintf("%s %s", v.nama, err.Error()))
+			continue
+		}
+		*v.nilai = angka
+	}
+
+	listFoto := strings.FieldsFunc(ambil(KolomImporFoto), func(r rune) bool {
+		return r == '|' || r == '\n'
+	})
+	if len(listFoto) > maxFotoImpor {
+		listPesan = append(listPesan, fmt.Sprintf("%s has %d foto, maximum is %d", KolomImporFoto, len(listFoto), maxFotoImpor))
+	}
+	// foto is only processed for valid row so rejected row does not leave file behind
+	if len(listPesan) > 0 {
+		return errors.New(strings.Join(listPesan, "; "))
+	}
+	// file without versi is checked against the produk as it is read now, so change made while foto is processed is not overwritten
+	if produkID > 0 && versi == 0 {
+		produk, errRepo := iu.produkRepository.GetProdukByID(ctx, produkID)
+		if errRepo.Code == http.StatusNotFound || (errRepo.Err == nil && produk.TokoID != tokoID) {
+			return fmt.Errorf("produk %d not found in your toko", produkID)
+		}
+		if errRepo.Err != nil {
+			return errRepo.Err
+		}
+		versi = produk.Versi
+	}
+
+	for _, v := range listFoto {
+		referensi := strings.TrimSpace(v)
+		// foto already stored by this api is reused, foto already owned by the updated produk is skipped
+		foto, ada, err := iu.fotoTersimpan(ctx, referensi, produkID)
+		if err != nil {
+			return err
+		}
+		if ada && produkID > 0 && fotoMilikProduk(foto, produkID) {
+			continue
+		}
+		photo := dto.Photos{URL: foto.URL, URLThumbnail: foto.URLThumbnail, URLMedium: foto.URLMedium, URLLarge: foto.URLLarge}
+		if !ada {
+			if photo, err = iu.simpanFotoImpor(ctx, referensi, job.arsip); err != nil {
+				return fmt.Errorf("foto %s: %s", referensi, err.Error())
+			}
+		}
+		data.Photos = append(data.Photos, photo)
+	}
+
+	if produkID > 0 {
+		_, errUseCase := iu.produkUseCase.UpdateProdukByID(ctx, dto.UpdateProdukRequest{
+			ID:            produkID,
+			NamaProduk:    data.NamaProduk,
+			CategoryID:    data.CategoryID,
+			TokoID:        data.TokoID,
+			HargaReseller: data.HargaReseller,
+			HargaKonsumen: data.HargaKonsumen,
+			Stok:          data.Stok,
+			Deskripsi:     data.Deskripsi,
+			Photos:        data.Photos,
+			Versi:         versi,
+		})
+		if errUseCase.Code == http.StatusNotFound {
+			return fmt.Errorf("produk %d not found in your toko", produkID)
+		}
+		if errUseCase.Code == http.StatusPreconditionFailed {
+			return fmt.Errorf("produk %d has been changed since %s %d, export it again", produkID, KolomImporVersi, versi)
+		}
+		return errUseCase.Err
+	}
+	if _, errUseCase := iu.produkUseCase.UploadProduk(ctx, data); errUseCase.Err != nil {
+		return errUseCase.Err
+	}
+	return nil
+}
+
+// fotoMilikProduk foto shown in gallery of the produk
+func fotoMilikProduk(foto daos.FotoProduk, produkID uint) bool {
+	return foto.ProdukID == produkID && foto.SKUID == nil && !foto.DeletedAt.Valid
+}
+
+// fotoTersimpan foto produk whose file is referred by key or by url of this api, such as foto url written by export.
+// Foto of produkID is preferred because the same file can be used by several produk.
+func (iu *ImporUseCaseImpl) fotoTersimpan(ctx context.Context, referensi string, produkID uint) (foto daos.FotoProduk, ada bool, err error) {
+	listKandidat := []string{referensi}
+	if alamat, errParse := url.Parse(referensi); errParse == nil {
+		// key is the last three segment of media url, query such as signature is dropped
+		listSegmen := strings.Split(strings.Trim(alamat.Path, "/"), "/")
+		if len(listSegmen) >= 3 {
+			kunci := strings.Join(listSegmen[len(listSegmen)-3:], "/")
+			if storage.AdalahKunci(kunci) && kunci != referensi {
+				listKandidat = append(listKandidat, kunci)
+			}
+		}
+	}
+
+	listFoto, errRepo := iu.produkRepository.GetFotoProdukByURL(ctx, listKandidat)
+	if errRepo.Err != nil {
+		return foto, false, errRepo.Err
+	}
+	if len(listFoto) == 0 {
+		return foto, false, nil
+	}
+	for _, v := range listFoto {
+		if fotoMilikProduk(v, produkID) {
+			return v, true, nil
+		}
+	}
+	return listFoto[0], true, nil
+}
+
+// bacaAngkaImpor whole number >= 0, spreadsheet may write it as decimal or scientific notation
+// and text cell may use thousand separator
+func bacaAngkaImpor(nilai string) (uint, error) {
+	if nilai == "" {
+		return 0, errors.New("is required")
+	}
+	if angka, err := strconv.ParseUint(nilai, 10, 32); err == nil {
+		return uint(angka), nil
+	}
+	if angka, err := strconv.ParseFloat(nilai, 64); err == nil {
+		if angka < 0 || angka != math.Trunc(angka) || angka > math.MaxUint32 {
+			return 0, errors.New("must be whole number >= 0")
+		}
+		return uint(angka), nil
+	}
+	tanpaPemisah := strings.NewReplacer("Rp", "", " ", "", ".", "", ",", "").Replace(nilai)
+	if angka, err := strconv.ParseUint(tanpaPemisah, 10, 32); err == nil {
+		return uint(angka), nil
+	}
+	return 0, errors.New("must be whole number >= 0")
+}
+
+// simpanFotoImpor process foto from url or zip then save every rendition to blob storage
+func (iu *ImporUseCaseImpl) simpanFotoImpor(ctx context.Context, referensi string, arsip map[string]*zip.File) (photo dto.Photos, err error) {
+	var listRendisi []gambar.Rendisi
+	if strings.HasPrefix(strings.ToLower(referensi), "http://") || strings.HasPrefix(strings.ToLower(referensi), "https://") {
+		listRendisi, err = iu.gambarPipeline.Unduh(ctx, referensi)
+	} else {
+		f, ada := arsip[strings.ToLower(path.Base(referensi))]
+		if !ada {
+			return photo, errors.New("not found in zip file, use http or https url or upload zip of foto")
+		}
+		src, errOpen := f.Open()
+		if errOpen != nil {
+			return photo, errOpen
+		}
+		listRendisi, err = iu.gambarPipeline.Proses(src)
+		src.Close()
+	}
+	if err != nil {
+		return photo, err
+	}
+
+	// same key scheme as foto uploaded through form
+	for _, v := range listRendisi {
+		kunci := storage.KunciKonten(prefixFotoImpor, v.Data, gambar.Ekstensi)
+		if err := iu.blobStorage.Simpan(ctx, kunci, v.Data, gambar.ContentType); err != nil {
+			return photo, err
+		}
+		switch v.Nama {
+		case gambar.RendisiThumbnail:
+			photo.URLThumbnail = kunci
+		case gambar.RendisiMedium:
+			photo.URLMedium = kunci
+		case gambar.RendisiLarge:
+			photo.URLLarge = kunci
+			photo.URL = kunci
+		}
+	}
+	return photo, nil
+}
+
+func mapImpor(v daos.ImporProduk) dto.ImporProdukResponse {
+	impor := dto.ImporProdukResponse{
+		ID:            v.ID,
+		NamaFile:      v.NamaFile,
+		Format:        v.Format,
+		Status:        v.Status,
+		TotalBaris:    v.TotalBaris,
+		BarisDiproses: v.BarisDiproses,
+		Berhasil:      v.Berhasil,
+		Gagal:         v.Gagal,
+		Pesan:         v.Pesan,
+		CreatedAt:     v.CreatedAt.Format(time.RFC3339),
+	}
+	if v.TotalBaris > 0 {
+		impor.Progres = v.BarisDiproses * 100 / v.TotalBaris
+	}
+	if v.SelesaiAt != nil {
+		impor.SelesaiAt = v.SelesaiAt.Format(time.RFC3339)
+	}
+	return impor
+}
+
+func (iu *ImporUseCaseImpl) GetImporByID(ctx context.Context, tokoID, ID uint) (response dto.ImporProdukResponse, errHelper *helper.ErrorStruct) {
+	// call GetImporByID from impor repository
+	responseRepo, errRepo := iu.imporRepository.GetImporByID(ctx, tokoID, ID)
+	if errRepo.Err != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errRepo.Err,
+			Code: errRepo.Code,
+		}
+		return response, errHelper
+	}
+
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return mapImpor(responseRepo), errHelper
+}
+
+func (iu *ImporUseCaseImpl) GetAllImpor(ctx context.Context, tokoID uint, params dto.FilterImpor) (response []dto.ImporProdukResponse, errHelper *helper.ErrorStruct) {
+	// setup pagination
+	if params.Limit < 1 {
+		params.Limit = 10
+	}
+	if params.Page < 1 {
+		params.Page = 0
+	} else {
+		params.Page = (params.Page - 1) * params.Limit
+	}
+
+	// call GetAllImpor from impor repository
+	responseRepo, errRepo := iu.imporRepository.GetAllImpor(ctx, tokoID, params.Limit, params.Page)
+	if errRepo.Err != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errRepo.Err,
+			Code: errRepo.Code,
+		}
+		return response, errHelper
+	}
+	for _, v := range responseRepo {
+		response = append(response, mapImpor(v))
+	}
+
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return response, errHelper
+}
+
+func (iu *ImporUseCaseImpl) GetBarisImporGagal(ctx context.Context, tokoID, ID uint, params dto.FilterImpor) (response []dto.BarisImporGagalResponse, errHelper *helper.ErrorStruct) {
+	// setup pagination
+	if params.Limit < 1 {
+		params.Limit = 10
+	}
+	if params.Page < 1 {
+		params.Page = 0
+	} else {
+		params.Page = (params.Page - 1) * params.Limit
+	}
+
+	// make sure the impor belong to the toko
+	if _, errRepo := iu.imporRepository.GetImporByID(ctx, tokoID, ID); errRepo.Err != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errRepo.Err,
+			Code: errRepo.Code,
+		}
+		return response, errHelper
+	}
+	responseRepo, errRepo := iu.imporRepository.GetBarisImporGagal(ctx, ID, params.Limit, params.Page)
+	if errRepo.Err != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errRepo.Err,
+			Code: errRepo.Code,
+		}
+		return response, errHelper
+	}
+	for _, v := range responseRepo {
+		baris := dto.BarisImporGagalResponse{
+			NomorBaris: v.NomorBaris,
+			Pesan:      v.Pesan,
+		}
+		json.Unmarshal([]byte(v.Data), &baris.Data)
+		response = append(response, baris)
+	}
+
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return response, errHelper
+}
+
+// GetLaporanImpor csv of every rejected row with the original header plus error column,
+// the row can be fixed and the file imported again because unknown column is ignored
+func (iu *ImporUseCaseImpl) GetLaporanImpor(ctx context.Context, tokoID, ID uint) (namaFile string, data []byte, errHelper *helper.ErrorStruct) {
+	impor, errRepo := iu.imporRepository.GetImporByID(ctx, tokoID, ID)
+	if errRepo.Err != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errRepo.Err,
+			Code: errRepo.Code,
+		}
+		return namaFile, data, errHelper
+	}
+	listBarisGagal, errRepo := iu.imporRepository.GetBarisImporGagal(ctx, ID, 0, 0)
+	if errRepo.Err != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errRepo.Err,
+			Code: errRepo.Code,
+		}
+		return namaFile, data, errHelper
+	}
+
+	var header []string
+	json.Unmarshal([]byte(impor.Header), &header)
+	var buffer bytes.Buffer
+	writer := csv.NewWriter(&buffer)
+	writer.Write(append(header, "error"))
+	for _, v := range listBarisGagal {
+		var sel []string
+		json.Unmarshal([]byte(v.Data), &sel)
+		// keep the error in its own column even when the row is shorter than header
+		for len(sel) < len(header) {
+			sel = append(sel, "")
+		}
+		writer.Write(append(sel, v.Pesan))
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  err,
+			Code: http.StatusInternalServerError,
+		}
+		return namaFile, data, errHelper
+	}
+
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return fmt.Sprintf("laporan-impor-%d.csv", impor.ID), buffer.Bytes(), errHelper
+}
+
+// HentikanImporTerputus mark job without progress for 30 minutes as failed, its worker stopped without finishing it
+func (iu *ImporUseCaseImpl) HentikanImporTerputus(ctx context.Context) (errHelper *helper.ErrorStruct) {
+	jumlah, errRepo := iu.imporRepository.HentikanImporTerputus(ctx, time.Now().Add(-30*time.Minute))
+	if errRepo.Err != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errRepo.Err,
+			Code: errRepo.Code,
+		}
+		return errHelper
+	}
+	if jumlah > 0 {
+		helper.Logger("impor_usecase", helper.LoggerLevelInfo, fmt.Sprintf("%d interrupted impor is stopped", jumlah))
+	}
+
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return errHelper
+}
@@ -0,0 +1,181 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/daos"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/helper"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/infrastructure/storage"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/pkg/dto"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/pkg/repository"
+	"net/http"
+	"strings"
+	"time"
+)
+
+type ModerasiUseCase interface {
+	GetAntrianModerasi(ctx context.Context, params dto.FilterModerasi) (response []dto.GetProduk, errHelper *helper.ErrorStruct)
+	ModerasiProduk(ctx context.Context, data dto.ModerasiRequest) (errHelper *helper.ErrorStruct)
+	GetRiwayatModerasi(ctx context.Context, produkID uint) (response []dto.RiwayatModerasiResponse, errHelper *helper.ErrorStruct)
+	SetTokoTerpercaya(ctx context.Context, tokoID uint, data dto.TokoTerpercayaRequest) (errHelper *helper.ErrorStruct)
+}
+
+type ModerasiUseCaseImpl struct {
+	moderasiRepository repository.ModerasiRepository
+	blobStorage        storage.BlobStorage
+}
+
+func NewModerasiUseCase(moderasiRepository repository.ModerasiRepository, blobStorage storage.BlobStorage) ModerasiUseCase {
+	return &ModerasiUseCaseImpl{
+		moderasiRepository: moderasiRepository,
+		blobStorage:        blobStorage,
+	}
+}
+
+// GetAntrianModerasi produk waiting for review by default, the longest waiting first
+func (mu *ModerasiUseCaseImpl) GetAntrianModerasi(ctx context.Context, params dto.FilterModerasi) (response []dto.GetProduk, errHelper *helper.ErrorStruct) {
+	// validate filter
+	if errValidate := helper.Validate.Struct(params); errValidate != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errValidate,
+			Code: http.StatusBadRequest,
+		}
+		return response, errHelper
+	}
+	if params.Status == "" {
+		params.Status = daos.ModerasiMenunggu
+	}
+
+	// setup pagination
+	if params.Limit < 1 {
+		params.Limit = 10
+	}
+	if params.Page < 1 {
+		params.Page = 0
+	} else {
+		params.Page = (params.Page - 1) * params.Limit
+	}
+
+	// call GetAntrianModerasi from moderasi repository
+	responseRepo, errRepo := mu.moderasiRepository.GetAntrianModerasi(ctx, daos.FilterModerasi{
+		Limit:  params.Limit,
+		Offset: params.Page,
+		Status: params.Status,
+		TokoID: params.TokoID,
+	})
+	if errRepo.Err != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errRepo.Err,
+			Code: errRepo.Code,
+		}
+		return response, errHelper
+	}
+	response = []dto.GetProduk{}
+	for _, v := range responseRepo {
+		response = append(response, mapProduk(v, mu.blobStorage))
+	}
+
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return response, errHelper
+}
+
+// ModerasiProduk approve or reject produk, alasan of approved produk is ignored
+func (mu *ModerasiUseCaseImpl) ModerasiProduk(ctx context.Context, data dto.ModerasiRequest) (errHelper *helper.ErrorStruct) {
+	// validate user input
+	data.Alasan = strings.TrimSpace(data.Alasan)
+	var err error
+	if errValidate := helper.Validate.Struct(data); errValidate != nil {
+		err = errValidate
+	} else if data.Status == daos.ModerasiDitolak && data.Alasan == "" {
+		err = errors.New("alasan is required to reject product")
+	}
+	if err != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  err,
+			Code: http.StatusBadRequest,
+		}
+		return errHelper
+	}
+	if data.Status == daos.ModerasiDisetujui {
+		data.Alasan = ""
+	}
+
+	// call ModerasiProduk from moderasi repository
+	errRepo := mu.moderasiRepository.ModerasiProduk(ctx, data.AdminID, data.ProdukID, data.Status, data.Alasan)
+	if errRepo.Err != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errRepo.Err,
+			Code: errRepo.Code,
+		}
+		return errHelper
+	}
+
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return errHelper
+}
+
+func (mu *ModerasiUseCaseImpl) GetRiwayatModerasi(ctx context.Context, produkID uint) (response []dto.RiwayatModerasiResponse, errHelper *helper.ErrorStruct) {
+	// call GetRiwayatModerasi from moderasi repository
+	responseRepo, errRepo := mu.moderasiRepository.GetRiwayatModerasi(ctx, produkID)
+	if errRepo.Err != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errRepo.Err,
+			Code: errRepo.Code,
+		}
+		return response, errHelper
+	}
+	response = []dto.RiwayatModerasiResponse{}
+	for _, v := range responseRepo {
+		response = append(response, dto.RiwayatModerasiResponse{
+			ID:        v.ID,
+			ProdukID:  v.ProdukID,
+			Status:    v.Status,
+			Alasan:    v.Alasan,
+			AdminID:   v.AdminID,
+			CreatedAt: v.CreatedAt.Format(time.RFC3339),
+		})
+	}
+
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return response, errHelper
+}
+
+func (mu *ModerasiUseCaseImpl) SetTokoTerpercaya(ctx context.Context, tokoID uint, data dto.TokoTerpercayaRequest) (errHelper *helper.ErrorStruct) {
+	// validate user input
+	if errValidate := helper.Validate.Struct(data); errValidate != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errValidate,
+			Code: http.StatusBadRequest,
+		}
+		return errHelper
+	}
+
+	// call SetTokoTerpercaya from moderasi repository
+	errRepo := mu.moderasiRepository.SetTokoTerpercaya(ctx, tokoID, *data.Terpercaya)
+	if errRepo.Err != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errRepo.Err,
+			Code: errRepo.Code,
+		}
+		return errHelper
+	}
+
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return errHelper
+}
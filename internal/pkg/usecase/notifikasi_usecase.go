@@ -0,0 +1,144 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/daos"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/helper"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/pkg/dto"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/pkg/repository"
+	"net/http"
+	"time"
+)
+
+type NotifikasiUseCase interface {
+	GetMyNotifikasi(ctx context.Context, userID uint, params dto.FilterNotifikasi) (response []dto.NotifikasiResponse, errHelper *helper.ErrorStruct)
+	CountUnread(ctx context.Context, userID uint) (response dto.NotifikasiUnreadResponse, errHelper *helper.ErrorStruct)
+	MarkRead(ctx context.Context, userID, ID uint) (errHelper *helper.ErrorStruct)
+	MarkAllRead(ctx context.Context, userID uint) (errHelper *helper.ErrorStruct)
+}
+
+type NotifikasiUseCaseImpl struct {
+	notifikasiRepository repository.NotifikasiRepository
+}
+
+func NewNotifikasiUseCase(notifikasiRepository repository.NotifikasiRepository) NotifikasiUseCase {
+	return &NotifikasiUseCaseImpl{notifikasiRepository: notifikasiRepository}
+}
+
+func (nu *NotifikasiUseCaseImpl) GetMyNotifikasi(ctx context.Context, userID uint, params dto.FilterNotifikasi) (response []dto.NotifikasiResponse, errHelper *helper.ErrorStruct) {
+	// setup pagination
+	if params.Limit < 1 {
+		params.Limit = 10
+	}
+	if params.Page < 1 {
+		params.Page = 0
+	} else {
+		params.Page = (params.Page - 1) * params.Limit
+	}
+
+	// call GetMyNotifikasi from notifikasi repository
+	responseRepo, errRepo := nu.notifikasiRepository.GetMyNotifikasi(ctx, userID, daos.FilterNotifikasi{
+		Limit:      params.Limit,
+		Offset:     params.Page,
+		Tipe:       params.Tipe,
+		UnreadOnly: params.Unread,
+	})
+	if errRepo.Err != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errRepo.Err,
+			Code: errRepo.Code,
+		}
+		return response, errHelper
+	}
+
+	// mapping notifikasi from daos to dto
+	for _, v := range responseRepo {
+		notifikasi := dto.NotifikasiResponse{
+			ID:        v.ID,
+			Tipe:      v.Tipe,
+			Judul:     v.Judul,
+			Pesan:     v.Pesan,
+			RefTipe:   v.RefTipe,
+			RefID:     v.RefID,
+			IsRead:    v.IsRead,
+			CreatedAt: v.CreatedAt.Format(time.RFC3339),
+		}
+		if v.Payload != "" {
+			notifikasi.Payload = json.RawMessage(v.Payload)
+		}
+		if v.ReadAt != nil {
+			notifikasi.ReadAt = v.ReadAt.Format(time.RFC3339)
+		}
+		response = append(response, notifikasi)
+	}
+
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return response, errHelper
+}
+
+func (nu *NotifikasiUseCaseImpl) CountUnread(ctx context.Context, userID uint) (response dto.NotifikasiUnreadResponse, errHelper *helper.ErrorStruct) {
+	// call CountUnread from notifikasi repository
+	responseRepo, errRepo := nu.notifikasiRepository.CountUnread(ctx, userID)
+	if errRepo.Err != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errRepo.Err,
+			Code: errRepo.Code,
+		}
+		return response, errHelper
+	}
+
+	// sum unread counter per tipe
+	response.PerTipe = map[string]uint{}
+	for _, v := range responseRepo {
+		response.PerTipe[v.Tipe] = v.Jumlah
+		response.Total += v.Jumlah
+	}
+
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return response, errHelper
+}
+
+func (nu *NotifikasiUseCaseImpl) MarkRead(ctx context.Context, userID, ID uint) (errHelper *helper.ErrorStruct) {
+	// call MarkRead from notifikasi repository
+	if errRepo := nu.notifikasiRepository.MarkRead(ctx, userID, ID); errRepo.Err != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errRepo.Err,
+			Code: errRepo.Code,
+		}
+		return errHelper
+	}
+
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return errHelper
+}
+
+func (nu *NotifikasiUseCaseImpl) MarkAllRead(ctx context.Context, userID uint) (errHelper *helper.ErrorStruct) {
+	// call MarkAllRead from notifikasi repository
+	if errRepo := nu.notifikasiRepository.MarkAllRead(ctx, userID); errRepo.Err != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errRepo.Err,
+			Code: errRepo.Code,
+		}
+		return errHelper
+	}
+
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return errHelper
+}
@@ -2,29 +2,88 @@ package usecase
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/daos"
 	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/helper"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/infrastructure/search"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/infrastructure/storage"
 	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/pkg/dto"
 	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/pkg/repository"
 	"net/http"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 )
 
 type ProdukUseCase interface {
 	UploadProduk(ctx context.Context, data dto.UploadProdukRequest) (ID uint, errHelper *helper.ErrorStruct)
-	GetProdukByID(ctx context.Context, ID uint) (response dto.GetProduk, errHelper *helper.ErrorStruct)
-	UpdateProdukByID(ctx context.Context, data dto.UpdateProdukRequest) (errHelper *helper.ErrorStruct)
+	GetProdukByID(ctx context.Context, ID, penggunaID uint) (response dto.GetProduk, errHelper *helper.ErrorStruct)
+	GetProdukBySlug(ctx context.Context, slug string, penggunaID uint) (response dto.GetProduk, errHelper *helper.ErrorStruct)
+	UpdateProdukByID(ctx context.Context, data dto.UpdateProdukRequest) (versi uint, errHelper *helper.ErrorStruct)
 	DeleteProdukByID(ctx context.Context, tokoID, ID uint) (errHelper *helper.ErrorStruct)
-	GetAllProduk(ctx context.Context, params dto.FilterProduk) (response []dto.GetProduk, errHelper *helper.ErrorStruct)
+	RestoreProdukByID(ctx context.Context, tokoID, ID uint) (errHelper *helper.ErrorStruct)
+	GetSampahProduk(ctx context.Context, tokoID uint, params dto.FilterSampah) (response []dto.GetProduk, errHelper *helper.ErrorStruct)
+	GetProdukToko(ctx context.Context, tokoID uint, params dto.FilterProdukToko) (response []dto.GetProduk, errHelper *helper.ErrorStruct)
+	UbahPublikasi(ctx context.Context, data dto.PublikasiRequest) (errHelper *helper.ErrorStruct)
+	GetAllProduk(ctx context.Context, params dto.FilterProduk) (response []dto.GetProduk, facet dto.FacetProduk, nextCursor string, errHelper *helper.ErrorStruct)
+	UpdateVarian(ctx context.Context, data dto.UpdateVarianRequest) (errHelper *helper.ErrorStruct)
+	CreateFotoSKU(ctx context.Context, tokoID, produkID, skuID uint, photos []dto.Photos) (errHelper *helper.ErrorStruct)
+	GetFotoProduk(ctx context.Context, produkID uint) (response []dto.FotoProdukGetProduk, errHelper *helper.ErrorStruct)
+	DeleteFotoProduk(ctx context.Context, tokoID, produkID, ID uint) (errHelper *helper.ErrorStruct)
+	UrutkanFotoProduk(ctx context.Context, data dto.UrutanFotoRequest) (errHelper *helper.ErrorStruct)
+	SetFotoUtama(ctx context.Context, tokoID, produkID, ID uint) (errHelper *helper.ErrorStruct)
+	RestoreFotoProduk(ctx context.Context, tokoID, produkID, ID uint) (errHelper *helper.ErrorStruct)
+	GetSampahFotoProduk(ctx context.Context, tokoID, produkID uint) (response []dto.FotoProdukGetProduk, errHelper *helper.ErrorStruct)
+	IndexAllProduk(ctx context.Context) (errHelper *helper.ErrorStruct)
+	SinkronIndex(ctx context.Context) (errHelper *helper.ErrorStruct)
+	IndexProdukToko(ctx context.Context, tokoID uint)
+	IndexProdukCategory(ctx context.Context, categoryID uint)
 }
 
 type ProdukUseCaseImpl struct {
 	produkRepository repository.ProdukRepository
+	searchIndex      search.Index
+	blobStorage      storage.BlobStorage
+	// indexSejak time of the last index build or sync, produk changed after it is indexed again by SinkronIndex
+	indexMu    sync.Mutex
+	indexSejak time.Time
 }
 
-func NewProdukUseCase(produkRepository repository.ProdukRepository) ProdukUseCase {
-	return &ProdukUseCaseImpl{produkRepository: produkRepository}
+func NewProdukUseCase(produkRepository repository.ProdukRepository, searchIndex search.Index, blobStorage storage.BlobStorage) ProdukUseCase {
+	return &ProdukUseCaseImpl{
+		produkRepository: produkRepository,
+		searchIndex:      searchIndex,
+		blobStorage:      blobStorage,
+	}
+}
+
+// maxKandidatCari the most relevant search hit passed to database as candidate, so the id list of a short query
+// stays far below the placeholder limit of MySQL and every page binds the same bounded list
+const maxKandidatCari = 1000
+
+const (
+	// IntervalSinkronIndex how often every instance index produk changed by other instance
+	IntervalSinkronIndex  = time.Minute
+	toleransiSinkronIndex = time.Minute
+)
+
+// statusPublikasi publication state that can be given by toko
+var statusPublikasi = map[string]bool{daos.PublikasiDraf: true, daos.PublikasiTerbit: true, daos.PublikasiArsip: true}
+
+// validasiPublikasi publication can only be scheduled for published produk and in the future
+func validasiPublikasi(status string, jadwalTerbit *time.Time) error {
+	switch {
+	case !statusPublikasi[status]:
+		return fmt.Errorf("status_publikasi must be %s, %s or %s", daos.PublikasiDraf, daos.PublikasiTerbit, daos.PublikasiArsip)
+	case jadwalTerbit != nil && status != daos.PublikasiTerbit:
+		return errors.New("jadwal_terbit is only for published product")
+	case jadwalTerbit != nil && !jadwalTerbit.After(time.Now()):
+		return errors.New("jadwal_terbit must be in the future")
+	}
+	return nil
 }
 
 func (pu *ProdukUseCaseImpl) UploadProduk(ctx context.Context, data dto.UploadProdukRequest) (ID uint, errHelper *helper.ErrorStruct) {
@@ -36,31 +95,43 @@ func (pu *ProdukUseCaseImpl) UploadProduk(ctx context.Context, data dto.UploadPr
 		}
 		return ID, errHelper
 	}
+	if data.StatusPublikasi == "" {
+		data.StatusPublikasi = daos.PublikasiTerbit
+	}
+	if err := validasiPublikasi(data.StatusPublikasi, data.JadwalTerbit); err != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  err,
+			Code: http.StatusBadRequest,
+		}
+		return ID, errHelper
+	}
 
 	// mapping foto url
 	var listPhotos []daos.FotoProduk
-	for _, v := range data.Photos {
+	for i, v := range data.Photos {
 		listPhotos = append(listPhotos, daos.FotoProduk{
-			URL: v.URL,
+			URL:          v.URL,
+			URLThumbnail: v.URLThumbnail,
+			URLMedium:    v.URLMedium,
+			URLLarge:     v.URLLarge,
+			// keep upload order, the first foto is the primary foto
+			Urutan: i,
+			Utama:  i == 0,
 		})
 	}
 
-	// format slug
-	slug := strings.ToLower(data.NamaProduk)
-	listSlug := strings.Split(slug, " ")
-	slug = strings.Join(listSlug, "-")
-
-	// Call UploadProduk function from produk repository to create new record in database and get ID new inserted record and error information
+	// Call UploadProduk function from produk repository to create new record with unique slug in database and get ID new inserted record and error information
 	IDUseCase, errUseCase := pu.produkRepository.UploadProduk(ctx, daos.Produk{
-		CategoryID:    data.CategoryID,
-		TokoID:        data.TokoID,
-		NamaProduk:    data.NamaProduk,
-		Slug:          slug,
-		HargaReseller: data.HargaReseller,
-		HargaKonsumen: data.HargaKonsumen,
-		Stok:          data.Stok,
-		Deskripsi:     data.Deskripsi,
-		FotoProduk:    listPhotos,
+		CategoryID:      data.CategoryID,
+		TokoID:          data.TokoID,
+		NamaProduk:      data.NamaProduk,
+		HargaReseller:   data.HargaReseller,
+		HargaKonsumen:   data.HargaKonsumen,
+		Stok:            data.Stok,
+		Deskripsi:       data.Deskripsi,
+		FotoProduk:      listPhotos,
+		StatusPublikasi: data.StatusPublikasi,
+		JadwalTerbit:    data.JadwalTerbit,
 	})
 	// error checking UploadProduk useCase
 	if errUseCase.Err != nil {
@@ -71,6 +142,8 @@ func (pu *ProdukUseCaseImpl) UploadProduk(ctx context.Context, data dto.UploadPr
 		return ID, errUseCase
 	}
 
+	pu.indexProduk(ctx, IDUseCase)
+
 	// success response
 	errHelper = &helper.ErrorStruct{
 		Err:  nil,
@@ -80,7 +153,8 @@ func (pu *ProdukUseCaseImpl) UploadProduk(ctx context.Context, data dto.UploadPr
 
 }
 
-func (pu *ProdukUseCaseImpl) GetProdukByID(ctx context.Context, ID uint) (response dto.GetProduk, errHelper *helper.ErrorStruct) {
+// GetProdukByID penggunaID is the logged in user or 0, produk that is not approved or published is only shown to its toko
+func (pu *ProdukUseCaseImpl) GetProdukByID(ctx context.Context, ID, penggunaID uint) (response dto.GetProduk, errHelper *helper.ErrorStruct) {
 	// call GetProdukByID from user repository
 	responseRepo, errRepo := pu.produkRepository.GetProdukByID(ctx, ID)
 	if errRepo.Err != nil {
@@ -90,71 +164,147 @@ func (pu *ProdukUseCaseImpl) GetProdukByID(ctx context.Context, ID uint) (respon
 		}
 		return response, errHelper
 	}
+	if !produkTerlihat(responseRepo, penggunaID) {
+		errHelper = &helper.ErrorStruct{
+			Err:  errors.New("No Data Product"),
+			Code: http.StatusNotFound,
+		}
+		return response, errHelper
+	}
 
 	// success response
+	response = pu.mapGetProduk(responseRepo)
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return response, errHelper
+}
 
-	// mapping foto produk from daos to dto
-	var listFoto []dto.FotoProdukGetProduk
-	for _, v := range responseRepo.FotoProduk {
-		foto := dto.FotoProdukGetProduk{
-			ID:       v.ID,
-			ProdukID: v.ProdukID,
-			URL:      v.URL,
+// GetProdukBySlug produk by its current or old slug, Slug in response is the current one so caller can redirect old slug
+func (pu *ProdukUseCaseImpl) GetProdukBySlug(ctx context.Context, slug string, penggunaID uint) (response dto.GetProduk, errHelper *helper.ErrorStruct) {
+	// call GetProdukBySlug from produk repository
+	responseRepo, errRepo := pu.produkRepository.GetProdukBySlug(ctx, strings.ToLower(slug))
+	if errRepo.Err != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errRepo.Err,
+			Code: errRepo.Code,
 		}
-		listFoto = append(listFoto, foto)
+		return response, errHelper
 	}
+	if !produkTerlihat(responseRepo, penggunaID) {
+		errHelper = &helper.ErrorStruct{
+			Err:  errors.New("No Data Product"),
+			Code: http.StatusNotFound,
+		}
+		return response, errHelper
+	}
+
+	// success response
+	response = pu.mapGetProduk(responseRepo)
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return response, errHelper
+}
+
+// produkTerlihat buyer only see approved and published produk, toko (tokoID is the same as userID) see every produk it owns
+func produkTerlihat(produk daos.Produk, penggunaID uint) bool {
+	return produk.Tampil(time.Now()) || (penggunaID != 0 && produk.TokoID == penggunaID)
+}
+
+// mapGetProduk mapping produk detail from daos to dto
+func (pu *ProdukUseCaseImpl) mapGetProduk(responseRepo daos.Produk) dto.GetProduk {
+	// mapping foto produk from daos to dto
+	listFoto := mapListFotoProduk(responseRepo.FotoProduk, pu.blobStorage)
 	// mapping toko from daos to dto
 	toko := dto.GetTokoByIDResponse{
 		ID:       responseRepo.Toko.ID,
 		NamaToko: responseRepo.Toko.NamaToko,
-		UrlFoto:  responseRepo.Toko.UrlFoto,
+		Slug:     responseRepo.Toko.Slug,
+		UrlFoto:  pu.blobStorage.URL(responseRepo.Toko.UrlFoto),
 	}
 	// mapping category from daos to dto
 	category := dto.CategoryWithID{
 		ID:           responseRepo.Category.ID,
 		NamaCategory: responseRepo.Category.NamaCategory,
 	}
-	// mapping response from db to local struct
-	response = dto.GetProduk{
-		ID:            responseRepo.ID,
-		NamaProduk:    responseRepo.NamaProduk,
-		Slug:          responseRepo.Slug,
-		HargaReseller: responseRepo.HargaReseller,
-		HargaKonsumen: responseRepo.HargaKonsumen,
-		Stok:          responseRepo.Stok,
-		Deskripsi:     responseRepo.Deskripsi,
-		Toko:          toko,
-		Category:      category,
-		FotoProduk:    listFoto,
+	// mapping opsi varian and sku from daos to dto
+	var listOpsi []dto.OpsiVarianResponse
+	for _, v := range responseRepo.OpsiVarian {
+		opsi := dto.OpsiVarianResponse{
+			ID:   v.ID,
+			Nama: v.Nama,
+		}
+		_ = json.Unmarshal([]byte(v.Nilai), &opsi.Nilai)
+		listOpsi = append(listOpsi, opsi)
 	}
-	errHelper = &helper.ErrorStruct{
-		Err:  nil,
-		Code: http.StatusOK,
+	var listSKU []dto.SKUResponse
+	for _, v := range responseRepo.SKU {
+		hargaReseller, hargaKonsumen := v.Harga(responseRepo)
+		sku := dto.SKUResponse{
+			ID:            v.ID,
+			KodeSKU:       v.KodeSKU,
+			HargaReseller: hargaReseller,
+			HargaKonsumen: hargaKonsumen,
+			Stok:          v.Stok,
+			FotoProduk:    []dto.FotoProdukGetProduk{},
+		}
+		if responseRepo.PromoAktif != nil {
+			sku.HargaResellerNormal, sku.HargaKonsumenNormal = hargaReseller, hargaKonsumen
+			sku.HargaReseller, sku.HargaKonsumen = responseRepo.PromoAktif.Harga(hargaReseller, hargaKonsumen)
+		}
+		_ = json.Unmarshal([]byte(v.Varian), &sku.Varian)
+		for _, f := range v.FotoProduk {
+			sku.FotoProduk = append(sku.FotoProduk, mapFotoProduk(f, pu.blobStorage))
+		}
+		listSKU = append(listSKU, sku)
 	}
-	return response, errHelper
+	// mapping response from db to local struct
+	response := dto.GetProduk{
+		ID:              responseRepo.ID,
+		NamaProduk:      responseRepo.NamaProduk,
+		Slug:            responseRepo.Slug,
+		HargaReseller:   responseRepo.HargaReseller,
+		HargaKonsumen:   responseRepo.HargaKonsumen,
+		Stok:            responseRepo.Stok,
+		Deskripsi:       responseRepo.Deskripsi,
+		Toko:            toko,
+		Category:        category,
+		FotoProduk:      listFoto,
+		OpsiVarian:      listOpsi,
+		SKU:             listSKU,
+		Atribut:         mapAtributProduk(responseRepo.Atribut),
+		StatusModerasi:  responseRepo.StatusModerasi,
+		AlasanModerasi:  responseRepo.AlasanModerasi,
+		StatusPublikasi: responseRepo.StatusPublikasi,
+		JadwalTerbit:    responseRepo.JadwalTerbit,
+		Versi:           responseRepo.Versi,
+	}
+	terapkanPromo(&response, responseRepo.PromoAktif)
+	return response
 }
 
-func (pu *ProdukUseCaseImpl) UpdateProdukByID(ctx context.Context, data dto.UpdateProdukRequest) (errHelper *helper.ErrorStruct) {
-	// edit slug
-	slug := strings.ToLower(data.NamaProduk)
-	listSlug := strings.Split(slug, " ")
-	slug = strings.Join(listSlug, "-")
-
+// UpdateProdukByID versi is the new versi of produk after it is updated
+func (pu *ProdukUseCaseImpl) UpdateProdukByID(ctx context.Context, data dto.UpdateProdukRequest) (versi uint, errHelper *helper.ErrorStruct) {
 	// mapping foto data from dto to daos
 	var listFoto []daos.FotoProduk
 	for _, v := range data.Photos {
 		foto := daos.FotoProduk{
-			ProdukID: data.ID,
-			URL:      v.URL,
+			ProdukID:     data.ID,
+			URL:          v.URL,
+			URLThumbnail: v.URLThumbnail,
+			URLMedium:    v.URLMedium,
+			URLLarge:     v.URLLarge,
 		}
 		listFoto = append(listFoto, foto)
 	}
 
 	// call GetProdukByID from user repository
-	errRepo := pu.produkRepository.UpdateProdukByID(ctx, daos.Produk{
+	versi, errRepo := pu.produkRepository.UpdateProdukByID(ctx, daos.Produk{
 		ID:            data.ID,
 		NamaProduk:    data.NamaProduk,
-		Slug:          slug,
 		HargaReseller: data.HargaReseller,
 		HargaKonsumen: data.HargaKonsumen,
 		Stok:          data.Stok,
@@ -162,6 +312,7 @@ func (pu *ProdukUseCaseImpl) UpdateProdukByID(ctx context.Context, data dto.Upda
 		TokoID:        data.TokoID,
 		CategoryID:    data.CategoryID,
 		FotoProduk:    listFoto,
+		Versi:         data.Versi,
 	})
 	// error checking
 	if errRepo.Err != nil {
@@ -169,14 +320,16 @@ func (pu *ProdukUseCaseImpl) UpdateProdukByID(ctx context.Context, data dto.Upda
 			Err:  errRepo.Err,
 			Code: errRepo.Code,
 		}
-		return errHelper
+		return versi, errHelper
 	}
+	pu.indexProduk(ctx, data.ID)
+
 	// success response
 	errHelper = &helper.ErrorStruct{
 		Err:  nil,
 		Code: http.StatusOK,
 	}
-	return errHelper
+	return versi, errHelper
 }
 
 func (pu *ProdukUseCaseImpl) DeleteProdukByID(ctx context.Context, tokoID, ID uint) (errHelper *helper.ErrorStruct) {
@@ -190,6 +343,8 @@ func (pu *ProdukUseCaseImpl) DeleteProdukByID(ctx context.Context, tokoID, ID ui
 		}
 		return errHelper
 	}
+	pu.searchIndex.Hapus(ID)
+
 	// success response
 	errHelper = &helper.ErrorStruct{
 		Err:  nil,
@@ -198,7 +353,28 @@ func (pu *ProdukUseCaseImpl) DeleteProdukByID(ctx context.Context, tokoID, ID ui
 	return errHelper
 }
 
-func (pu *ProdukUseCaseImpl) GetAllProduk(ctx context.Context, params dto.FilterProduk) (response []dto.GetProduk, errHelper *helper.ErrorStruct) {
+func (pu *ProdukUseCaseImpl) RestoreProdukByID(ctx context.Context, tokoID, ID uint) (errHelper *helper.ErrorStruct) {
+	// call RestoreProdukByID form ProdukRepository to get error information
+	errRepo := pu.produkRepository.RestoreProdukByID(ctx, tokoID, ID)
+	// error checking
+	if errRepo.Err != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errRepo.Err,
+			Code: errRepo.Code,
+		}
+		return errHelper
+	}
+	pu.indexProduk(ctx, ID)
+
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return errHelper
+}
+
+func (pu *ProdukUseCaseImpl) GetSampahProduk(ctx context.Context, tokoID uint, params dto.FilterSampah) (response []dto.GetProduk, errHelper *helper.ErrorStruct) {
 	// setup pagination
 	if params.Limit < 1 {
 		params.Limit = 10
@@ -209,55 +385,651 @@ func (pu *ProdukUseCaseImpl) GetAllProduk(ctx context.Context, params dto.Filter
 		params.Page = (params.Page - 1) * params.Limit
 	}
 
-	// call GetAllProduk from produk repository to get all produk
-	responseRepo, errRepo := pu.produkRepository.GetAllProduk(ctx, daos.FilterProduk{
+	// call GetSampahProduk from produk repository
+	responseRepo, errRepo := pu.produkRepository.GetSampahProduk(ctx, tokoID, daos.FilterProduk{
+		Limit:  params.Limit,
+		Offset: params.Page,
+	})
+	if errRepo.Err != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errRepo.Err,
+			Code: errRepo.Code,
+		}
+		return response, errHelper
+	}
+	// success response
+	response = []dto.GetProduk{}
+	for _, v := range responseRepo {
+		produk := mapProduk(v, pu.blobStorage)
+		produk.DeletedAt = &v.DeletedAt.Time
+		response = append(response, produk)
+	}
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return response, errHelper
+}
+
+// GetProdukToko every produk of the toko including draft, archived, scheduled and not approved produk
+func (pu *ProdukUseCaseImpl) GetProdukToko(ctx context.Context, tokoID uint, params dto.FilterProdukToko) (response []dto.GetProduk, errHelper *helper.ErrorStruct) {
+	// validate filter
+	if errValidate := helper.Validate.Struct(params); errValidate != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errValidate,
+			Code: http.StatusBadRequest,
+		}
+		return response, errHelper
+	}
+
+	// setup pagination
+	if params.Limit < 1 {
+		params.Limit = 10
+	}
+	if params.Page < 1 {
+		params.Page = 0
+	} else {
+		params.Page = (params.Page - 1) * params.Limit
+	}
+
+	// call GetProdukToko from produk repository
+	responseRepo, errRepo := pu.produkRepository.GetProdukToko(ctx, tokoID, daos.FilterProdukToko{
+		Limit:           params.Limit,
+		Offset:          params.Page,
+		NamaProduk:      params.NamaProduk,
+		StatusPublikasi: params.StatusPublikasi,
+		StatusModerasi:  params.StatusModerasi,
+	})
+	if errRepo.Err != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errRepo.Err,
+			Code: errRepo.Code,
+		}
+		return response, errHelper
+	}
+	response = []dto.GetProduk{}
+	for _, v := range responseRepo {
+		response = append(response, mapProduk(v, pu.blobStorage))
+	}
+
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return response, errHelper
+}
+
+// UbahPublikasi publish, schedule, unpublish to draft or archive produk of the toko
+func (pu *ProdukUseCaseImpl) UbahPublikasi(ctx context.Context, data dto.PublikasiRequest) (errHelper *helper.ErrorStruct) {
+	// validate user input
+	err := helper.Validate.Struct(data)
+	if err == nil {
+		err = validasiPublikasi(data.StatusPublikasi, data.JadwalTerbit)
+	}
+	if err != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  err,
+			Code: http.StatusBadRequest,
+		}
+		return errHelper
+	}
+
+	// call UbahPublikasi from produk repository
+	errRepo := pu.produkRepository.UbahPublikasi(ctx, data.TokoID, data.ProdukID, data.StatusPublikasi, data.JadwalTerbit)
+	if errRepo.Err != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errRepo.Err,
+			Code: errRepo.Code,
+		}
+		return errHelper
+	}
+
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return errHelper
+}
+
+func (pu *ProdukUseCaseImpl) GetAllProduk(ctx context.Context, params dto.FilterProduk) (response []dto.GetProduk, facet dto.FacetProduk, nextCursor string, errHelper *helper.ErrorStruct) {
+	// validate filter
+	if errValidate := helper.Validate.Struct(params); errValidate != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errValidate,
+			Code: http.StatusBadRequest,
+		}
+		return response, facet, nextCursor, errHelper
+	}
+
+	// setup pagination
+	if params.Limit < 1 {
+		params.Limit = 10
+	}
+	if params.Page < 1 {
+		params.Page = 0
+	} else {
+		params.Page = (params.Page - 1) * params.Limit
+	}
+
+	filter := daos.FilterProduk{
 		Limit:      params.Limit,
 		Offset:     params.Page,
-		NamaProduk: params.NamaProduk,
 		CategoryID: params.CategoryID,
 		TokoID:     params.TokoID,
 		MaxHarga:   params.MaxHarga,
 		MinHarga:   params.MinHarga,
-	})
+		Tersedia:   params.Tersedia,
+		Sort:       params.Sort,
+	}
+	for _, v := range params.Atribut {
+		filter.Atribut = append(filter.Atribut, daos.FilterAtribut{
+			Kode:  v.Kode,
+			Nilai: v.Nilai,
+			Min:   v.Min,
+			Max:   v.Max,
+		})
+	}
+
+	// nama produk is searched in full text index, every other filter is applied by database
+	query := strings.TrimSpace(params.NamaProduk)
+	if query != "" {
+		// filter skipped by facet (category and harga) is applied by database, so only toko is filtered by the index
+		listHasil, _ := pu.searchIndex.Cari(query, search.Filter{TokoID: params.TokoID}, maxKandidatCari, 0)
+		if len(listHasil) == 0 {
+			errHelper = &helper.ErrorStruct{
+				Err:  errors.New("no product found"),
+				Code: http.StatusNotFound,
+			}
+			return response, facet, nextCursor, errHelper
+		}
+		filter.ListID = []uint{}
+		for _, v := range listHasil {
+			filter.ListID = append(filter.ListID, v.ID)
+		}
+		if filter.Sort == "" {
+			filter.Sort = daos.SortProdukRelevansi
+		}
+	} else if filter.Sort == "" || filter.Sort == daos.SortProdukRelevansi {
+		filter.Sort = daos.SortProdukTerbaru
+	}
+
+	// one more produk is requested to know whether next page exist
+	halaman := filter
+	halaman.Limit = params.Limit + 1
+	if params.Cursor != "" {
+		// keyset pagination continue after cursor, page is ignored
+		setelah, listID, errCursor := setelahCursorProduk(params.Cursor, filter)
+		if errCursor != nil {
+			errHelper = &helper.ErrorStruct{
+				Err:  errCursor,
+				Code: http.StatusBadRequest,
+			}
+			return response, facet, nextCursor, errHelper
+		}
+		halaman.Offset = 0
+		halaman.Setelah = setelah
+		halaman.ListID = listID
+	}
+
+	// call GetAllProduk from produk repository to get all produk
+	responseRepo, errRepo := pu.produkRepository.GetAllProduk(ctx, halaman)
 	// error checking
 	if errRepo.Err != nil {
 		errHelper = &helper.ErrorStruct{
 			Err:  errRepo.Err,
 			Code: errRepo.Code,
 		}
-		return response, errHelper
+		return response, facet, nextCursor, errHelper
+	}
+	if len(responseRepo) > params.Limit {
+		responseRepo = responseRepo[:params.Limit]
+		nextCursor = cursorProduk(filter.Sort, responseRepo[len(responseRepo)-1])
+	}
+	listSorotan := map[uint]search.Sorotan{}
+	if query != "" {
+		var listID []uint
+		for _, v := range responseRepo {
+			listID = append(listID, v.ID)
+		}
+		listSorotan = pu.searchIndex.Sorot(query, listID)
 	}
 	for _, v := range responseRepo {
-		var listFoto []dto.FotoProdukGetProduk
+		produk := mapProduk(v, pu.blobStorage)
+		if sorotan, ok := listSorotan[v.ID]; ok {
+			produk.Highlight = &dto.HighlightProduk{
+				NamaProduk: sorotan.NamaProduk,
+				Deskripsi:  sorotan.Deskripsi,
+			}
+		}
+		response = append(response, produk)
+	}
 
-		for _, f := range v.FotoProduk {
-			foto := dto.FotoProdukGetProduk{
-				ID:       f.ID,
-				ProdukID: f.ProdukID,
-				URL:      f.URL,
+	// call GetFacetProduk from produk repository to count produk per category and price range
+	facetRepo, errRepo := pu.produkRepository.GetFacetProduk(ctx, filter)
+	if errRepo.Err != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errRepo.Err,
+			Code: errRepo.Code,
+		}
+		return response, facet, nextCursor, errHelper
+	}
+	facet = mapFacetProduk(facetRepo)
+
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return response, facet, nextCursor, errHelper
+}
+
+// cursorProduk encode sort key of the last produk of a page
+func cursorProduk(sort string, produk daos.Produk) string {
+	cursor := helper.Cursor{Sort: sort, ID: produk.ID}
+	switch sort {
+	case daos.SortProdukHargaAsc, daos.SortProdukHargaDesc:
+		cursor.Nilai = strconv.FormatUint(uint64(produk.HargaKonsumen), 10)
+	case daos.SortProdukTerlaris:
+		cursor.Nilai = strconv.FormatUint(uint64(produk.Terjual), 10)
+	case daos.SortProdukTerbaru:
+		cursor.Nilai = produk.CreatedAt.Format(time.RFC3339Nano)
+	}
+	return helper.EncodeCursor(cursor)
+}
+
+// setelahCursorProduk decode cursor into the last produk of previous page,
+// relevansi order continue from the position of that produk in search result instead
+func setelahCursorProduk(text string, filter daos.FilterProduk) (setelah *daos.Produk, listID []uint, err error) {
+	cursor, err := helper.DecodeCursor(text, filter.Sort)
+	if err != nil {
+		return nil, nil, err
+	}
+	setelah = &daos.Produk{ID: cursor.ID}
+	switch filter.Sort {
+	case daos.SortProdukHargaAsc, daos.SortProdukHargaDesc, daos.SortProdukTerlaris:
+		nilai, errParse := strconv.ParseUint(cursor.Nilai, 10, 64)
+		if errParse != nil {
+			return nil, nil, helper.ErrCursorInvalid
+		}
+		setelah.HargaKonsumen = uint(nilai)
+		setelah.Terjual = uint(nilai)
+	case daos.SortProdukTerbaru:
+		if setelah.CreatedAt, err = time.Parse(time.RFC3339Nano, cursor.Nilai); err != nil {
+			return nil, nil, helper.ErrCursorInvalid
+		}
+	case daos.SortProdukRelevansi:
+		for i, ID := range filter.ListID {
+			if ID == cursor.ID {
+				return nil, filter.ListID[i+1:], nil
+			}
+		}
+		return nil, nil, helper.ErrCursorInvalid
+	}
+	return setelah, filter.ListID, nil
+}
+
+// mapFacetProduk mapping facet from daos to dto, price bucket index is converted to its range
+func mapFacetProduk(facetRepo daos.FacetProduk) (facet dto.FacetProduk) {
+	facet.Category = []dto.FacetCategory{}
+	facet.Harga = []dto.FacetHarga{}
+	for _, v := range facetRepo.Category {
+		facet.Category = append(facet.Category, dto.FacetCategory{
+			ID:           v.CategoryID,
+			NamaCategory: v.NamaCategory,
+			Jumlah:       v.Jumlah,
+		})
+	}
+	for _, v := range facetRepo.Harga {
+		harga := dto.FacetHarga{Jumlah: v.Jumlah}
+		if v.Bucket > 0 {
+			harga.Min = daos.BatasHargaFacet[v.Bucket-1]
+		}
+		if v.Bucket < len(daos.BatasHargaFacet) {
+			harga.Max = daos.BatasHargaFacet[v.Bucket]
+		}
+		facet.Harga = append(facet.Harga, harga)
+	}
+	return facet
+}
+
+// mapFotoProduk mapping foto produk and its rendition from daos to dto, stored key is turned into public url
+func mapFotoProduk(f daos.FotoProduk, blobStorage storage.BlobStorage) dto.FotoProdukGetProduk {
+	foto := dto.FotoProdukGetProduk{
+		ID:           f.ID,
+		ProdukID:     f.ProdukID,
+		URL:          blobStorage.URL(f.URL),
+		URLThumbnail: blobStorage.URL(f.URLThumbnail),
+		URLMedium:    blobStorage.URL(f.URLMedium),
+		URLLarge:     blobStorage.URL(f.URLLarge),
+		Urutan:       f.Urutan,
+		Utama:        f.Utama,
+	}
+	if f.SKUID != nil {
+		foto.SKUID = *f.SKUID
+	}
+	return foto
+}
+
+// mapListFotoProduk mapping ordered foto of produk, produk uploaded before primary foto existed show its first foto as primary
+func mapListFotoProduk(listFotoRepo []daos.FotoProduk, blobStorage storage.BlobStorage) (listFoto []dto.FotoProdukGetProduk) {
+	adaUtama := false
+	for _, f := range listFotoRepo {
+		listFoto = append(listFoto, mapFotoProduk(f, blobStorage))
+		adaUtama = adaUtama || f.Utama
+	}
+	if !adaUtama && len(listFoto) > 0 {
+		listFoto[0].Utama = true
+	}
+	return listFoto
+}
+
+// mapProduk mapping produk list item from daos to dto
+func mapProduk(v daos.Produk, blobStorage storage.BlobStorage) dto.GetProduk {
+	listFoto := mapListFotoProduk(v.FotoProduk, blobStorage)
+	response := dto.GetProduk{
+		ID:            v.ID,
+		NamaProduk:    v.NamaProduk,
+		Slug:          v.Slug,
+		HargaReseller: v.HargaReseller,
+		HargaKonsumen: v.HargaKonsumen,
+		Stok:          v.Stok,
+		Deskripsi:     v.Deskripsi,
+		Toko: dto.GetTokoByIDResponse{
+			ID:       v.Toko.ID,
+			NamaToko: v.Toko.NamaToko,
+			Slug:     v.Toko.Slug,
+			UrlFoto:  blobStorage.URL(v.Toko.UrlFoto),
+		},
+		Category: dto.CategoryWithID{
+			ID:           v.Category.ID,
+			NamaCategory: v.Category.NamaCategory,
+		},
+		FotoProduk:      listFoto,
+		StatusModerasi:  v.StatusModerasi,
+		AlasanModerasi:  v.AlasanModerasi,
+		StatusPublikasi: v.StatusPublikasi,
+		JadwalTerbit:    v.JadwalTerbit,
+		Versi:           v.Versi,
+	}
+	terapkanPromo(&response, v.PromoAktif)
+	return response
+}
+
+// terapkanPromo replace harga of produk with sale price of running promo and keep the normal price in promo
+func terapkanPromo(produk *dto.GetProduk, item *daos.ItemPromo) {
+	if item == nil {
+		return
+	}
+	promo := dto.PromoProdukResponse{
+		ID:                  item.PromoID,
+		Tipe:                item.Promo.Tipe,
+		Nama:                item.Promo.Nama,
+		Selesai:             item.Promo.Selesai.Format(time.RFC3339),
+		HargaResellerNormal: produk.HargaReseller,
+		HargaKonsumenNormal: produk.HargaKonsumen,
+		BatasPerUser:        item.BatasPerUser,
+	}
+	if item.Kuota != 0 {
+		sisa := item.Kuota - item.Terjual
+		promo.Sisa = &sisa
+	}
+	produk.HargaReseller, produk.HargaKonsumen = item.Harga(produk.HargaReseller, produk.HargaKonsumen)
+	produk.Promo = &promo
+}
+
+// dokumenProduk mapping produk from daos to search dokumen, category and toko must be preloaded
+func dokumenProduk(v daos.Produk) search.Dokumen {
+	return search.Dokumen{
+		ID:            v.ID,
+		NamaProduk:    v.NamaProduk,
+		Deskripsi:     v.Deskripsi,
+		NamaCategory:  v.Category.NamaCategory,
+		NamaToko:      v.Toko.NamaToko,
+		CategoryID:    v.CategoryID,
+		TokoID:        v.TokoID,
+		HargaKonsumen: v.HargaKonsumen,
+	}
+}
+
+// indexProduk refresh produk in search index after it is created or updated, failure only logged
+func (pu *ProdukUseCaseImpl) indexProduk(ctx context.Context, ID uint) {
+	produk, errRepo := pu.produkRepository.GetProdukByID(ctx, ID)
+	if errRepo.Err != nil {
+		helper.Logger("produk_usecase", helper.LoggerLevelWarn, fmt.Sprintf("failed to index produk %d : %s", ID, errRepo.Err.Error()))
+		return
+	}
+	pu.searchIndex.Simpan(dokumenProduk(produk))
+}
+
+// indexBatch walk through produk matching params in batch, deleted produk is removed from the index
+func (pu *ProdukUseCaseImpl) indexBatch(ctx context.Context, params daos.FilterBatchProduk) (errHelper *helper.ErrorStruct) {
+	var afterID uint
+	for {
+		responseRepo, errRepo := pu.produkRepository.GetProdukBatch(ctx, afterID, 500, params)
+		if errRepo.Err != nil {
+			errHelper = &helper.ErrorStruct{
+				Err:  errRepo.Err,
+				Code: errRepo.Code,
 			}
-			listFoto = append(listFoto, foto)
+			return errHelper
 		}
-		produk := dto.GetProduk{
+		for _, v := range responseRepo {
+			if v.DeletedAt.Valid {
+				pu.searchIndex.Hapus(v.ID)
+			} else {
+				pu.searchIndex.Simpan(dokumenProduk(v))
+			}
+			afterID = v.ID
+		}
+		if len(responseRepo) < 500 {
+			break
+		}
+	}
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return errHelper
+}
+
+func (pu *ProdukUseCaseImpl) IndexAllProduk(ctx context.Context) (errHelper *helper.ErrorStruct) {
+	pu.indexMu.Lock()
+	defer pu.indexMu.Unlock()
+
+	// walk through every produk, change made while walking is picked up by the next sync
+	mulai := time.Now()
+	if errHelper = pu.indexBatch(ctx, daos.FilterBatchProduk{}); errHelper.Err != nil {
+		return errHelper
+	}
+	pu.indexSejak = mulai
+	return errHelper
+}
+
+// SinkronIndex index produk changed since the last build or sync, by this instance or another one.
+// The previous sync is overlapped so clock difference between instance and database does not skip a change.
+func (pu *ProdukUseCaseImpl) SinkronIndex(ctx context.Context) (errHelper *helper.ErrorStruct) {
+	pu.indexMu.Lock()
+	if pu.indexSejak.IsZero() {
+		// the index was never built
+		pu.indexMu.Unlock()
+		return pu.IndexAllProduk(ctx)
+	}
+	defer pu.indexMu.Unlock()
+
+	mulai := time.Now()
+	sejak := pu.indexSejak.Add(-toleransiSinkronIndex)
+	if errHelper = pu.indexBatch(ctx, daos.FilterBatchProduk{BerubahSejak: &sejak}); errHelper.Err != nil {
+		return errHelper
+	}
+	pu.indexSejak = mulai
+	return errHelper
+}
+
+// IndexProdukToko index every produk of the toko again after nama toko is changed, failure only logged
+func (pu *ProdukUseCaseImpl) IndexProdukToko(ctx context.Context, tokoID uint) {
+	if errHelper := pu.indexBatch(ctx, daos.FilterBatchProduk{TokoID: tokoID}); errHelper.Err != nil {
+		helper.Logger("produk_usecase", helper.LoggerLevelWarn, fmt.Sprintf("failed to index produk of toko %d : %s", tokoID, errHelper.Err.Error()))
+	}
+}
+
+// IndexProdukCategory index every produk of the category again after nama category is changed, failure only logged
+func (pu *ProdukUseCaseImpl) IndexProdukCategory(ctx context.Context, categoryID uint) {
+	if errHelper := pu.indexBatch(ctx, daos.FilterBatchProduk{CategoryID: categoryID}); errHelper.Err != nil {
+		helper.Logger("produk_usecase", helper.LoggerLevelWarn, fmt.Sprintf("failed to index produk of category %d : %s", categoryID, errHelper.Err.Error()))
+	}
+}
+
+// validateVarian check every sku use exactly one known nilai for every opsi varian and no combination is repeated
+func validateVarian(data dto.UpdateVarianRequest) error {
+	listNilai := map[string]map[string]bool{}
+	for _, v := range data.OpsiVarian {
+		if _, ok := listNilai[v.Nama]; ok {
+			return fmt.Errorf("opsi varian %s is duplicated", v.Nama)
+		}
+		listNilai[v.Nama] = map[string]bool{}
+		for _, n := range v.Nilai {
+			listNilai[v.Nama][n] = true
+		}
+	}
+	if len(data.SKU) > 0 && len(data.OpsiVarian) == 0 {
+		return errors.New("sku require at least one opsi varian")
+	}
+	listKombinasi := map[string]bool{}
+	listKodeSKU := map[string]bool{}
+	for _, sku := range data.SKU {
+		if listKodeSKU[sku.KodeSKU] {
+			return fmt.Errorf("kode_sku %s is duplicated", sku.KodeSKU)
+		}
+		listKodeSKU[sku.KodeSKU] = true
+		if len(sku.Varian) != len(data.OpsiVarian) {
+			return fmt.Errorf("sku %s must choose one nilai for every opsi varian", sku.KodeSKU)
+		}
+		var kombinasi strings.Builder
+		for _, opsi := range data.OpsiVarian {
+			nilai, ok := sku.Varian[opsi.Nama]
+			if !ok || !listNilai[opsi.Nama][nilai] {
+				return fmt.Errorf("sku %s has invalid nilai for opsi varian %s", sku.KodeSKU, opsi.Nama)
+			}
+			kombinasi.WriteString(nilai + "\x00")
+		}
+		if listKombinasi[kombinasi.String()] {
+			return fmt.Errorf("sku %s has the same varian as another sku", sku.KodeSKU)
+		}
+		listKombinasi[kombinasi.String()] = true
+	}
+	return nil
+}
+
+func (pu *ProdukUseCaseImpl) UpdateVarian(ctx context.Context, data dto.UpdateVarianRequest) (errHelper *helper.ErrorStruct) {
+	// validate user input
+	if errValidate := helper.Validate.Struct(data); errValidate != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errValidate,
+			Code: http.StatusBadRequest,
+		}
+		return errHelper
+	}
+	if errValidate := validateVarian(data); errValidate != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errValidate,
+			Code: http.StatusBadRequest,
+		}
+		return errHelper
+	}
+
+	// mapping opsi varian and sku from dto to daos
+	var listOpsi []daos.OpsiVarian
+	for i, v := range data.OpsiVarian {
+		nilai, _ := json.Marshal(v.Nilai)
+		listOpsi = append(listOpsi, daos.OpsiVarian{
+			Nama:   v.Nama,
+			Nilai:  string(nilai),
+			Urutan: uint(i),
+		})
+	}
+	var listSKU []daos.SKU
+	for _, v := range data.SKU {
+		varian, _ := json.Marshal(v.Varian)
+		listSKU = append(listSKU, daos.SKU{
 			ID:            v.ID,
-			NamaProduk:    v.NamaProduk,
-			Slug:          v.Slug,
+			KodeSKU:       v.KodeSKU,
+			Varian:        string(varian),
 			HargaReseller: v.HargaReseller,
 			HargaKonsumen: v.HargaKonsumen,
 			Stok:          v.Stok,
-			Deskripsi:     v.Deskripsi,
-			Toko: dto.GetTokoByIDResponse{
-				ID:       v.Toko.ID,
-				NamaToko: v.Toko.NamaToko,
-				UrlFoto:  v.Toko.UrlFoto,
-			},
-			Category: dto.CategoryWithID{
-				ID:           v.Category.ID,
-				NamaCategory: v.Category.NamaCategory,
-			},
-			FotoProduk: listFoto,
+		})
+	}
+
+	// call UpdateVarian from produk repository
+	errRepo := pu.produkRepository.UpdateVarian(ctx, data.TokoID, data.ProdukID, listOpsi, listSKU, data.Stok)
+	if errRepo.Err != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errRepo.Err,
+			Code: errRepo.Code,
 		}
-		response = append(response, produk)
+		return errHelper
+	}
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return errHelper
+}
+
+func (pu *ProdukUseCaseImpl) CreateFotoSKU(ctx context.Context, tokoID, produkID, skuID uint, photos []dto.Photos) (errHelper *helper.ErrorStruct) {
+	if len(photos) == 0 {
+		errHelper = &helper.ErrorStruct{
+			Err:  errors.New("photos are required"),
+			Code: http.StatusBadRequest,
+		}
+		return errHelper
+	}
+
+	// mapping foto url
+	var listFoto []daos.FotoProduk
+	for _, v := range photos {
+		listFoto = append(listFoto, daos.FotoProduk{
+			URL:          v.URL,
+			URLThumbnail: v.URLThumbnail,
+			URLMedium:    v.URLMedium,
+			URLLarge:     v.URLLarge,
+		})
+	}
+
+	// call CreateFotoSKU from produk repository
+	errRepo := pu.produkRepository.CreateFotoSKU(ctx, tokoID, produkID, skuID, listFoto)
+	if errRepo.Err != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errRepo.Err,
+			Code: errRepo.Code,
+		}
+		return errHelper
+	}
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return errHelper
+}
+
+func (pu *ProdukUseCaseImpl) GetFotoProduk(ctx context.Context, produkID uint) (response []dto.FotoProdukGetProduk, errHelper *helper.ErrorStruct) {
+	// call GetFotoProduk from produk repository
+	responseRepo, errRepo := pu.produkRepository.GetFotoProduk(ctx, produkID)
+	if errRepo.Err != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errRepo.Err,
+			Code: errRepo.Code,
+		}
+		return response, errHelper
+	}
+	// success response
+	response = mapListFotoProduk(responseRepo, pu.blobStorage)
+	if response == nil {
+		response = []dto.FotoProdukGetProduk{}
 	}
 	errHelper = &helper.ErrorStruct{
 		Err:  nil,
@@ -265,3 +1037,108 @@ func (pu *ProdukUseCaseImpl) GetAllProduk(ctx context.Context, params dto.Filter
 	}
 	return response, errHelper
 }
+
+func (pu *ProdukUseCaseImpl) DeleteFotoProduk(ctx context.Context, tokoID, produkID, ID uint) (errHelper *helper.ErrorStruct) {
+	// call DeleteFotoProduk from produk repository
+	errRepo := pu.produkRepository.DeleteFotoProduk(ctx, tokoID, produkID, ID)
+	if errRepo.Err != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errRepo.Err,
+			Code: errRepo.Code,
+		}
+		return errHelper
+	}
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return errHelper
+}
+
+func (pu *ProdukUseCaseImpl) RestoreFotoProduk(ctx context.Context, tokoID, produkID, ID uint) (errHelper *helper.ErrorStruct) {
+	// call RestoreFotoProduk from produk repository
+	errRepo := pu.produkRepository.RestoreFotoProduk(ctx, tokoID, produkID, ID)
+	if errRepo.Err != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errRepo.Err,
+			Code: errRepo.Code,
+		}
+		return errHelper
+	}
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return errHelper
+}
+
+func (pu *ProdukUseCaseImpl) GetSampahFotoProduk(ctx context.Context, tokoID, produkID uint) (response []dto.FotoProdukGetProduk, errHelper *helper.ErrorStruct) {
+	// call GetSampahFotoProduk from produk repository
+	responseRepo, errRepo := pu.produkRepository.GetSampahFotoProduk(ctx, tokoID, produkID)
+	if errRepo.Err != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errRepo.Err,
+			Code: errRepo.Code,
+		}
+		return response, errHelper
+	}
+	// success response
+	response = []dto.FotoProdukGetProduk{}
+	for _, v := range responseRepo {
+		foto := mapFotoProduk(v, pu.blobStorage)
+		foto.DeletedAt = &v.DeletedAt.Time
+		response = append(response, foto)
+	}
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return response, errHelper
+}
+
+func (pu *ProdukUseCaseImpl) UrutkanFotoProduk(ctx context.Context, data dto.UrutanFotoRequest) (errHelper *helper.ErrorStruct) {
+	// validate user input
+	if errValidate := helper.Validate.Struct(data); errValidate != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errValidate,
+			Code: http.StatusBadRequest,
+		}
+		return errHelper
+	}
+
+	// call UrutkanFotoProduk from produk repository
+	errRepo := pu.produkRepository.UrutkanFotoProduk(ctx, data.TokoID, data.ProdukID, data.ListID)
+	if errRepo.Err != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errRepo.Err,
+			Code: errRepo.Code,
+		}
+		return errHelper
+	}
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return errHelper
+}
+
+func (pu *ProdukUseCaseImpl) SetFotoUtama(ctx context.Context, tokoID, produkID, ID uint) (errHelper *helper.ErrorStruct) {
+	// call SetFotoUtama from produk repository
+	errRepo := pu.produkRepository.SetFotoUtama(ctx, tokoID, produkID, ID)
+	if errRepo.Err != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errRepo.Err,
+			Code: errRepo.Code,
+		}
+		return errHelper
+	}
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return errHelper
+}
@@ -0,0 +1,275 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/daos"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/helper"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/pkg/dto"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/pkg/repository"
+	"net/http"
+	"strings"
+	"time"
+)
+
+type PromoUseCase interface {
+	CreatePromo(ctx context.Context, data dto.CreatePromoRequest) (ID uint, errHelper *helper.ErrorStruct)
+	GetMyPromo(ctx context.Context, tokoID uint, params dto.FilterPromo) (response []dto.PromoResponse, errHelper *helper.ErrorStruct)
+	GetPromoAktif(ctx context.Context, params dto.FilterPromo) (response []dto.PromoResponse, errHelper *helper.ErrorStruct)
+	GetPromoByID(ctx context.Context, ID uint) (response dto.PromoResponse, errHelper *helper.ErrorStruct)
+	BatalkanPromo(ctx context.Context, tokoID, ID uint) (errHelper *helper.ErrorStruct)
+	GetRiwayatHarga(ctx context.Context, produkID uint, params dto.FilterRiwayatHarga) (response []dto.RiwayatHargaResponse, errHelper *helper.ErrorStruct)
+}
+
+type PromoUseCaseImpl struct {
+	promoRepository repository.PromoRepository
+}
+
+func NewPromoUseCase(promoRepository repository.PromoRepository) PromoUseCase {
+	return &PromoUseCaseImpl{promoRepository: promoRepository}
+}
+
+// statusPromo whether promo has not started, is running or already ended at waktu
+func statusPromo(promo daos.Promo, waktu time.Time) string {
+	if promo.Mulai.After(waktu) {
+		return dto.StatusPromoTerjadwal
+	}
+	if promo.Selesai.After(waktu) {
+		return dto.StatusPromoAktif
+	}
+	return dto.StatusPromoSelesai
+}
+
+func mapPromo(v daos.Promo, waktu time.Time) dto.PromoResponse {
+	promo := dto.PromoResponse{
+		ID:        v.ID,
+		TokoID:    v.TokoID,
+		Tipe:      v.Tipe,
+		Nama:      v.Nama,
+		Mulai:     v.Mulai.Format(time.RFC3339),
+		Selesai:   v.Selesai.Format(time.RFC3339),
+		Status:    statusPromo(v, waktu),
+		ItemPromo: []dto.ItemPromoResponse{},
+	}
+	for _, i := range v.ItemPromo {
+		promo.ItemPromo = append(promo.ItemPromo, dto.ItemPromoResponse{
+			ID:            i.ID,
+			ProdukID:      i.ProdukID,
+			HargaReseller: i.HargaReseller,
+			HargaKonsumen: i.HargaKonsumen,
+			Kuota:         i.Kuota,
+			Terjual:       i.Terjual,
+			BatasPerUser:  i.BatasPerUser,
+		})
+	}
+	return promo
+}
+
+// CreatePromo flash sale must give quota to every produk, scheduled sale has no quota or limit per user
+func (pu *PromoUseCaseImpl) CreatePromo(ctx context.Context, data dto.CreatePromoRequest) (ID uint, errHelper *helper.ErrorStruct) {
+	// validate user input
+	data.Nama = strings.TrimSpace(data.Nama)
+	var err error
+	if errValidate := helper.Validate.Struct(data); errValidate != nil {
+		err = errValidate
+	} else if !data.Selesai.After(time.Now()) {
+		err = errors.New("selesai must be in the future")
+	}
+	listProdukID := map[uint]bool{}
+	for _, v := range data.ItemPromo {
+		if err != nil {
+			break
+		}
+		switch {
+		case listProdukID[v.ProdukID]:
+			err = fmt.Errorf("produk %d is listed more than once", v.ProdukID)
+		case data.Tipe == daos.PromoTipeFlashSale && v.Kuota == 0:
+			err = fmt.Errorf("kuota of produk %d is required for flash sale", v.ProdukID)
+		case data.Tipe == daos.PromoTipeSale && (v.Kuota != 0 || v.BatasPerUser != 0):
+			err = errors.New("kuota and batas_per_user are only for flash sale")
+		}
+		listProdukID[v.ProdukID] = true
+	}
+	if err != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  err,
+			Code: http.StatusBadRequest,
+		}
+		return ID, errHelper
+	}
+
+	promo := daos.Promo{
+		TokoID:  data.TokoID,
+		Tipe:    data.Tipe,
+		Nama:    data.Nama,
+		Mulai:   data.Mulai,
+		Selesai: data.Selesai,
+	}
+	for _, v := range data.ItemPromo {
+		promo.ItemPromo = append(promo.ItemPromo, daos.ItemPromo{
+			ProdukID:      v.ProdukID,
+			HargaReseller: v.HargaReseller,
+			HargaKonsumen: v.HargaKonsumen,
+			Kuota:         v.Kuota,
+			BatasPerUser:  v.BatasPerUser,
+		})
+	}
+
+	// call CreatePromo from promo repository
+	ID, errRepo := pu.promoRepository.CreatePromo(ctx, promo)
+	if errRepo.Err != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errRepo.Err,
+			Code: errRepo.Code,
+		}
+		return ID, errHelper
+	}
+
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return ID, errHelper
+}
+
+func (pu *PromoUseCaseImpl) GetMyPromo(ctx context.Context, tokoID uint, params dto.FilterPromo) (response []dto.PromoResponse, errHelper *helper.ErrorStruct) {
+	return pu.getAllPromo(ctx, tokoID, params, false)
+}
+
+// GetPromoAktif promo of every toko running now
+func (pu *PromoUseCaseImpl) GetPromoAktif(ctx context.Context, params dto.FilterPromo) (response []dto.PromoResponse, errHelper *helper.ErrorStruct) {
+	return pu.getAllPromo(ctx, 0, params, true)
+}
+
+func (pu *PromoUseCaseImpl) getAllPromo(ctx context.Context, tokoID uint, params dto.FilterPromo, aktif bool) (response []dto.PromoResponse, errHelper *helper.ErrorStruct) {
+	// validate filter
+	if errValidate := helper.Validate.Struct(params); errValidate != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errValidate,
+			Code: http.StatusBadRequest,
+		}
+		return response, errHelper
+	}
+
+	// setup pagination
+	if params.Limit < 1 {
+		params.Limit = 10
+	}
+	if params.Page < 1 {
+		params.Page = 0
+	} else {
+		params.Page = (params.Page - 1) * params.Limit
+	}
+
+	// call GetAllPromo from promo repository
+	responseRepo, errRepo := pu.promoRepository.GetAllPromo(ctx, tokoID, daos.FilterPromo{
+		Limit:  params.Limit,
+		Offset: params.Page,
+		Tipe:   params.Tipe,
+		Aktif:  aktif,
+	})
+	if errRepo.Err != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errRepo.Err,
+			Code: errRepo.Code,
+		}
+		return response, errHelper
+	}
+	sekarang := time.Now()
+	response = []dto.PromoResponse{}
+	for _, v := range responseRepo {
+		response = append(response, mapPromo(v, sekarang))
+	}
+
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return response, errHelper
+}
+
+func (pu *PromoUseCaseImpl) GetPromoByID(ctx context.Context, ID uint) (response dto.PromoResponse, errHelper *helper.ErrorStruct) {
+	// call GetPromoByID from promo repository
+	responseRepo, errRepo := pu.promoRepository.GetPromoByID(ctx, ID)
+	if errRepo.Err != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errRepo.Err,
+			Code: errRepo.Code,
+		}
+		return response, errHelper
+	}
+
+	// success response
+	response = mapPromo(responseRepo, time.Now())
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return response, errHelper
+}
+
+func (pu *PromoUseCaseImpl) BatalkanPromo(ctx context.Context, tokoID, ID uint) (errHelper *helper.ErrorStruct) {
+	// call BatalkanPromo from promo repository
+	errRepo := pu.promoRepository.BatalkanPromo(ctx, tokoID, ID)
+	if errRepo.Err != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errRepo.Err,
+			Code: errRepo.Code,
+		}
+		return errHelper
+	}
+
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return errHelper
+}
+
+func (pu *PromoUseCaseImpl) GetRiwayatHarga(ctx context.Context, produkID uint, params dto.FilterRiwayatHarga) (response []dto.RiwayatHargaResponse, errHelper *helper.ErrorStruct) {
+	// setup pagination
+	if params.Limit < 1 {
+		params.Limit = 10
+	}
+	if params.Page < 1 {
+		params.Page = 0
+	} else {
+		params.Page = (params.Page - 1) * params.Limit
+	}
+
+	// call GetRiwayatHarga from promo repository
+	responseRepo, errRepo := pu.promoRepository.GetRiwayatHarga(ctx, produkID, daos.FilterRiwayatHarga{Limit: params.Limit, Offset: params.Page})
+	if errRepo.Err != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errRepo.Err,
+			Code: errRepo.Code,
+		}
+		return response, errHelper
+	}
+	response = []dto.RiwayatHargaResponse{}
+	for _, v := range responseRepo {
+		riwayat := dto.RiwayatHargaResponse{
+			ID:            v.ID,
+			HargaReseller: v.HargaReseller,
+			HargaKonsumen: v.HargaKonsumen,
+			Sumber:        v.Sumber,
+			PromoID:       v.PromoID,
+			BerlakuMulai:  v.BerlakuMulai.Format(time.RFC3339),
+		}
+		if v.BerlakuSampai != nil {
+			riwayat.BerlakuSampai = v.BerlakuSampai.Format(time.RFC3339)
+		}
+		response = append(response, riwayat)
+	}
+
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return response, errHelper
+}
@@ -0,0 +1,109 @@
+package usecase
+
+import (
+	"context"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/daos"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/helper"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/infrastructure/storage"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/pkg/dto"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/pkg/repository"
+	"net/http"
+)
+
+// maxLimitRekomendasi related produk table keep at most daos.BatasProdukTerkait produk for each produk
+const maxLimitRekomendasi = daos.BatasProdukTerkait
+
+type RekomendasiUseCase interface {
+	HitungProdukTerkait(ctx context.Context) (jumlah int, errHelper *helper.ErrorStruct)
+	GetProdukTerkait(ctx context.Context, produkID uint, params dto.FilterRekomendasi) (response []dto.GetProduk, errHelper *helper.ErrorStruct)
+	GetRekomendasiUser(ctx context.Context, userID uint, params dto.FilterRekomendasi) (response []dto.GetProduk, errHelper *helper.ErrorStruct)
+}
+
+type RekomendasiUseCaseImpl struct {
+	rekomendasiRepository repository.RekomendasiRepository
+	blobStorage           storage.BlobStorage
+}
+
+func NewRekomendasiUseCase(rekomendasiRepository repository.RekomendasiRepository, blobStorage storage.BlobStorage) RekomendasiUseCase {
+	return &RekomendasiUseCaseImpl{
+		rekomendasiRepository: rekomendasiRepository,
+		blobStorage:           blobStorage,
+	}
+}
+
+// limitRekomendasi default 10 and at most maxLimitRekomendasi
+func limitRekomendasi(limit int) int {
+	if limit < 1 {
+		return 10
+	}
+	if limit > maxLimitRekomendasi {
+		return maxLimitRekomendasi
+	}
+	return limit
+}
+
+// HitungProdukTerkait rebuild frequently bought together produk from trx history
+func (ru *RekomendasiUseCaseImpl) HitungProdukTerkait(ctx context.Context) (jumlah int, errHelper *helper.ErrorStruct) {
+	// call HitungProdukTerkait from rekomendasi repository
+	jumlah, errRepo := ru.rekomendasiRepository.HitungProdukTerkait(ctx)
+	if errRepo.Err != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errRepo.Err,
+			Code: errRepo.Code,
+		}
+		return jumlah, errHelper
+	}
+
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return jumlah, errHelper
+}
+
+func (ru *RekomendasiUseCaseImpl) GetProdukTerkait(ctx context.Context, produkID uint, params dto.FilterRekomendasi) (response []dto.GetProduk, errHelper *helper.ErrorStruct) {
+	// call GetProdukTerkait from rekomendasi repository
+	responseRepo, errRepo := ru.rekomendasiRepository.GetProdukTerkait(ctx, produkID, limitRekomendasi(params.Limit))
+	if errRepo.Err != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errRepo.Err,
+			Code: errRepo.Code,
+		}
+		return response, errHelper
+	}
+	response = []dto.GetProduk{}
+	for _, v := range responseRepo {
+		response = append(response, mapProduk(v, ru.blobStorage))
+	}
+
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return response, errHelper
+}
+
+func (ru *RekomendasiUseCaseImpl) GetRekomendasiUser(ctx context.Context, userID uint, params dto.FilterRekomendasi) (response []dto.GetProduk, errHelper *helper.ErrorStruct) {
+	// call GetRekomendasiUser from rekomendasi repository
+	responseRepo, errRepo := ru.rekomendasiRepository.GetRekomendasiUser(ctx, userID, limitRekomendasi(params.Limit))
+	if errRepo.Err != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errRepo.Err,
+			Code: errRepo.Code,
+		}
+		return response, errHelper
+	}
+	response = []dto.GetProduk{}
+	for _, v := range responseRepo {
+		response = append(response, mapProduk(v, ru.blobStorage))
+	}
+
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return response, errHelper
+}
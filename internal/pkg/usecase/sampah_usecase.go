@@ -0,0 +1,89 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/helper"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/infrastructure/storage"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/pkg/repository"
+	"net/http"
+	"time"
+)
+
+type SampahUseCase interface {
+	PurgeSampah(ctx context.Context) (errHelper *helper.ErrorStruct)
+}
+
+type SampahUseCaseImpl struct {
+	sampahRepository repository.SampahRepository
+	produkRepository repository.ProdukRepository
+	blobStorage      storage.BlobStorage
+	retensi          time.Duration
+}
+
+// NewSampahUseCase record stays in trash for retensi before it is deleted permanently
+func NewSampahUseCase(sampahRepository repository.SampahRepository, produkRepository repository.ProdukRepository, blobStorage storage.BlobStorage, retensi time.Duration) SampahUseCase {
+	return &SampahUseCaseImpl{
+		sampahRepository: sampahRepository,
+		produkRepository: produkRepository,
+		blobStorage:      blobStorage,
+		retensi:          retensi,
+	}
+}
+
+func (su *SampahUseCaseImpl) PurgeSampah(ctx context.Context) (errHelper *helper.ErrorStruct) {
+	// call PurgeSampah from sampah repository to delete record older than retention
+	listURL, jumlah, errRepo := su.sampahRepository.PurgeSampah(ctx, time.Now().Add(-su.retensi))
+	if errRepo.Err != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errRepo.Err,
+			Code: errRepo.Code,
+		}
+		return errHelper
+	}
+	if jumlah > 0 {
+		helper.Logger("sampah_usecase", helper.LoggerLevelInfo, fmt.Sprintf("%d record purged from trash", jumlah))
+	}
+	su.hapusFile(ctx, listURL)
+
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return errHelper
+}
+
+// hapusFile remove file of purged foto and toko logo from blob storage, file still used somewhere else is kept.
+// Failure is only logged because the record is already deleted.
+func (su *SampahUseCaseImpl) hapusFile(ctx context.Context, listURL []string) {
+	var listKunci []string
+	for _, URL := range listURL {
+		// file uploaded before blob storage existed is not managed by it
+		if storage.AdalahKunci(URL) {
+			listKunci = append(listKunci, URL)
+		}
+	}
+	if len(listKunci) == 0 {
+		return
+	}
+	listDipakai, errRepo := su.produkRepository.GetURLFotoDipakai(ctx, listKunci)
+	if errRepo.Err != nil {
+		helper.Logger("sampah_usecase", helper.LoggerLevelWarn, fmt.Sprintf("failed to check file usage : %s", errRepo.Err.Error()))
+		return
+	}
+	dipakai := map[string]bool{}
+	for _, v := range listDipakai {
+		dipakai[v] = true
+	}
+	for _, kunci := range listKunci {
+		if dipakai[kunci] {
+			continue
+		}
+		// same file can be shared by several purged record
+		dipakai[kunci] = true
+		if err := su.blobStorage.Hapus(ctx, kunci); err != nil {
+			helper.Logger("sampah_usecase", helper.LoggerLevelWarn, fmt.Sprintf("failed to delete file %s : %s", kunci, err.Error()))
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package usecase
+
+import (
+	"context"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/daos"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/helper"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/pkg/repository"
+	"net/http"
+)
+
+// batasLengkapiSlug record given slug in one query
+const batasLengkapiSlug = 200
+
+type SlugUseCase interface {
+	LengkapiSlug(ctx context.Context) (jumlah int, errHelper *helper.ErrorStruct)
+}
+
+type SlugUseCaseImpl struct {
+	slugRepository repository.SlugRepository
+}
+
+func NewSlugUseCase(slugRepository repository.SlugRepository) SlugUseCase {
+	return &SlugUseCaseImpl{slugRepository: slugRepository}
+}
+
+// LengkapiSlug give slug to every toko and produk that does not have one yet, such as record created before slug was unique
+func (su *SlugUseCaseImpl) LengkapiSlug(ctx context.Context) (jumlah int, errHelper *helper.ErrorStruct) {
+	for _, tipe := range []string{daos.SlugTipeToko, daos.SlugTipeProduk} {
+		for {
+			if ctx.Err() != nil {
+				errHelper = &helper.ErrorStruct{
+					Err:  ctx.Err(),
+					Code: http.StatusInternalServerError,
+				}
+				return jumlah, errHelper
+			}
+			// call LengkapiSlug from slug repository until no record is left
+			jumlahRepo, errRepo := su.slugRepository.LengkapiSlug(ctx, tipe, batasLengkapiSlug)
+			jumlah += jumlahRepo
+			if errRepo.Err != nil {
+				errHelper = &helper.ErrorStruct{
+					Err:  errRepo.Err,
+					Code: errRepo.Code,
+				}
+				return jumlah, errHelper
+			}
+			if jumlahRepo < batasLengkapiSlug {
+				break
+			}
+		}
+	}
+
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return jumlah, errHelper
+}
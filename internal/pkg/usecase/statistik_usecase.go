@@ -0,0 +1,152 @@
+package usecase
+
+import (
+	"context"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/daos"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/helper"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/infrastructure/storage"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/infrastructure/tayangan"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/pkg/dto"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/pkg/repository"
+	"net/http"
+)
+
+// maxHariTrending statistik harian of sold kuantitas is recounted for this many days
+const maxHariTrending = 30
+
+type StatistikUseCase interface {
+	SimpanTayangan(ctx context.Context) (errHelper *helper.ErrorStruct)
+	HitungTerjual(ctx context.Context) (errHelper *helper.ErrorStruct)
+	GetProdukTrending(ctx context.Context, params dto.FilterPopuler) (response []dto.GetProduk, errHelper *helper.ErrorStruct)
+	GetProdukTerlaris(ctx context.Context, params dto.FilterPopuler) (response []dto.GetProduk, errHelper *helper.ErrorStruct)
+}
+
+type StatistikUseCaseImpl struct {
+	statistikRepository repository.StatistikRepository
+	pencatatTayangan    *tayangan.Pencatat
+	blobStorage         storage.BlobStorage
+}
+
+func NewStatistikUseCase(statistikRepository repository.StatistikRepository, pencatatTayangan *tayangan.Pencatat, blobStorage storage.BlobStorage) StatistikUseCase {
+	return &StatistikUseCaseImpl{
+		statistikRepository: statistikRepository,
+		pencatatTayangan:    pencatatTayangan,
+		blobStorage:         blobStorage,
+	}
+}
+
+// SimpanTayangan write view counted in memory to database, view is kept for the next call when it fails
+func (su *StatistikUseCaseImpl) SimpanTayangan(ctx context.Context) (errHelper *helper.ErrorStruct) {
+	jumlah := su.pencatatTayangan.Ambil()
+	if len(jumlah) == 0 {
+		errHelper = &helper.ErrorStruct{
+			Err:  nil,
+			Code: http.StatusOK,
+		}
+		return errHelper
+	}
+
+	// call SimpanTayangan from statistik repository
+	errRepo := su.statistikRepository.SimpanTayangan(ctx, jumlah)
+	if errRepo.Err != nil {
+		su.pencatatTayangan.Kembalikan(jumlah)
+		errHelper = &helper.ErrorStruct{
+			Err:  errRepo.Err,
+			Code: errRepo.Code,
+		}
+		return errHelper
+	}
+
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return errHelper
+}
+
+// HitungTerjual recount sold kuantitas from trx history
+func (su *StatistikUseCaseImpl) HitungTerjual(ctx context.Context) (errHelper *helper.ErrorStruct) {
+	// call HitungTerjual from statistik repository
+	errRepo := su.statistikRepository.HitungTerjual(ctx, maxHariTrending)
+	if errRepo.Err != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errRepo.Err,
+			Code: errRepo.Code,
+		}
+		return errHelper
+	}
+
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return errHelper
+}
+
+func (su *StatistikUseCaseImpl) GetProdukTrending(ctx context.Context, params dto.FilterPopuler) (response []dto.GetProduk, errHelper *helper.ErrorStruct) {
+	return su.getProdukPopuler(ctx, params, su.statistikRepository.GetProdukTrending)
+}
+
+func (su *StatistikUseCaseImpl) GetProdukTerlaris(ctx context.Context, params dto.FilterPopuler) (response []dto.GetProduk, errHelper *helper.ErrorStruct) {
+	return su.getProdukPopuler(ctx, params, su.statistikRepository.GetProdukTerlaris)
+}
+
+// getProdukPopuler trending and best seller have the same filter and response
+func (su *StatistikUseCaseImpl) getProdukPopuler(ctx context.Context, params dto.FilterPopuler, getProduk func(ctx context.Context, params daos.FilterPopuler) ([]daos.Produk, *helper.ErrorStruct)) (response []dto.GetProduk, errHelper *helper.ErrorStruct) {
+	// validate filter
+	if errValidate := helper.Validate.Struct(params); errValidate != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errValidate,
+			Code: http.StatusBadRequest,
+		}
+		return response, errHelper
+	}
+
+	// setup pagination
+	if params.Limit < 1 {
+		params.Limit = 10
+	}
+	if params.Page < 1 {
+		params.Page = 0
+	} else {
+		params.Page = (params.Page - 1) * params.Limit
+	}
+	if params.Hari == 0 {
+		params.Hari = daos.HariTrendingDefault
+	}
+
+	// call statistik repository
+	responseRepo, errRepo := getProduk(ctx, daos.FilterPopuler{
+		Limit:      params.Limit,
+		Offset:     params.Page,
+		CategoryID: params.CategoryID,
+		Hari:       params.Hari,
+	})
+	if errRepo.Err != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errRepo.Err,
+			Code: errRepo.Code,
+		}
+		return response, errHelper
+	}
+	response = []dto.GetProduk{}
+	for _, v := range responseRepo {
+		produk := mapProduk(v, su.blobStorage)
+		if v.Statistik != nil {
+			produk.Statistik = &dto.StatistikProduk{
+				Dilihat: v.Statistik.Dilihat,
+				Terjual: v.Statistik.Terjual,
+			}
+		}
+		response = append(response, produk)
+	}
+
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return response, errHelper
+}
@@ -0,0 +1,241 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/daos"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/helper"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/pkg/dto"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/pkg/repository"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// maxPanjangAlasan alasan is stored in varchar(255)
+const maxPanjangAlasan = 255
+
+// tipePenyesuaianStok movement that can be recorded through adjust endpoint, cancellation and return put stok back
+var tipePenyesuaianStok = map[string]bool{daos.MutasiStokPenyesuaian: true, daos.MutasiStokPembatalan: true, daos.MutasiStokRetur: true}
+
+type StokUseCase interface {
+	SesuaikanStok(ctx context.Context, data dto.UbahStokRequest) (response dto.MutasiStokResponse, errHelper *helper.ErrorStruct)
+	RestockStok(ctx context.Context, data dto.UbahStokRequest) (response dto.MutasiStokResponse, errHelper *helper.ErrorStruct)
+	GetMutasiStok(ctx context.Context, tokoID, produkID uint, params dto.FilterMutasiStok) (response []dto.MutasiStokResponse, errHelper *helper.ErrorStruct)
+	SetBatasStokRendah(ctx context.Context, data dto.BatasStokRendahRequest) (errHelper *helper.ErrorStruct)
+	GetProdukStokRendah(ctx context.Context, tokoID uint, params dto.FilterStokRendah) (response []dto.StokRendahResponse, errHelper *helper.ErrorStruct)
+}
+
+type StokUseCaseImpl struct {
+	stokRepository repository.StokRepository
+}
+
+func NewStokUseCase(stokRepository repository.StokRepository) StokUseCase {
+	return &StokUseCaseImpl{stokRepository: stokRepository}
+}
+
+func mapMutasiStok(v daos.MutasiStok) dto.MutasiStokResponse {
+	return dto.MutasiStokResponse{
+		ID:          v.ID,
+		ProdukID:    v.ProdukID,
+		SKUID:       v.SKUID,
+		Tipe:        v.Tipe,
+		Perubahan:   v.Perubahan,
+		Saldo:       v.Saldo,
+		SaldoProduk: v.SaldoProduk,
+		PelakuID:    v.PelakuID,
+		Alasan:      v.Alasan,
+		TRXID:       v.TRXID,
+		CreatedAt:   v.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+// SesuaikanStok manual adjustment such as after stock count, cancellation or return handled outside checkout.
+// Alasan is required for adjustment so discrepancy can be explained later.
+func (su *StokUseCaseImpl) SesuaikanStok(ctx context.Context, data dto.UbahStokRequest) (response dto.MutasiStokResponse, errHelper *helper.ErrorStruct) {
+	// validate user input
+	data.Alasan = strings.TrimSpace(data.Alasan)
+	if data.Tipe == "" {
+		data.Tipe = daos.MutasiStokPenyesuaian
+	}
+	var err error
+	switch {
+	case !tipePenyesuaianStok[data.Tipe]:
+		err = fmt.Errorf("tipe must be %s, %s or %s", daos.MutasiStokPenyesuaian, daos.MutasiStokPembatalan, daos.MutasiStokRetur)
+	case data.Stok != nil && data.Tipe != daos.MutasiStokPenyesuaian:
+		err = errors.New("stok can only be set by adjustment")
+	case data.Stok != nil && data.Jumlah != 0:
+		err = errors.New("only one of jumlah and stok can be sent")
+	case data.Stok == nil && data.Jumlah == 0:
+		err = errors.New("jumlah or stok is required")
+	case data.Tipe != daos.MutasiStokPenyesuaian && data.Jumlah < 0:
+		err = fmt.Errorf("jumlah of %s must be > 0", data.Tipe)
+	case data.Tipe == daos.MutasiStokPenyesuaian && data.Alasan == "":
+		err = errors.New("alasan is required for adjustment")
+	}
+	if err != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  err,
+			Code: http.StatusBadRequest,
+		}
+		return response, errHelper
+	}
+	return su.ubahStok(ctx, data)
+}
+
+// RestockStok add received stok
+func (su *StokUseCaseImpl) RestockStok(ctx context.Context, data dto.UbahStokRequest) (response dto.MutasiStokResponse, errHelper *helper.ErrorStruct) {
+	// validate user input
+	data.Alasan = strings.TrimSpace(data.Alasan)
+	if data.Jumlah <= 0 || data.Stok != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errors.New("jumlah must be > 0"),
+			Code: http.StatusBadRequest,
+		}
+		return response, errHelper
+	}
+	data.Tipe = daos.MutasiStokRestock
+	return su.ubahStok(ctx, data)
+}
+
+func (su *StokUseCaseImpl) ubahStok(ctx context.Context, data dto.UbahStokRequest) (response dto.MutasiStokResponse, errHelper *helper.ErrorStruct) {
+	if utf8.RuneCountInString(data.Alasan) > maxPanjangAlasan {
+		errHelper = &helper.ErrorStruct{
+			Err:  fmt.Errorf("alasan is longer than %d character", maxPanjangAlasan),
+			Code: http.StatusBadRequest,
+		}
+		return response, errHelper
+	}
+	mutasi := daos.MutasiStok{
+		ProdukID:  data.ProdukID,
+		Tipe:      data.Tipe,
+		Perubahan: data.Jumlah,
+		PelakuID:  &data.TokoID,
+		Alasan:    data.Alasan,
+	}
+	if data.SKUID != 0 {
+		mutasi.SKUID = &data.SKUID
+	}
+
+	// call UbahStok from stok repository
+	responseRepo, errRepo := su.stokRepository.UbahStok(ctx, data.TokoID, mutasi, data.Stok)
+	if errRepo.Err != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errRepo.Err,
+			Code: errRepo.Code,
+		}
+		return response, errHelper
+	}
+
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return mapMutasiStok(responseRepo), errHelper
+}
+
+func (su *StokUseCaseImpl) GetMutasiStok(ctx context.Context, tokoID, produkID uint, params dto.FilterMutasiStok) (response []dto.MutasiStokResponse, errHelper *helper.ErrorStruct) {
+	// setup pagination
+	if params.Limit < 1 {
+		params.Limit = 10
+	}
+	if params.Page < 1 {
+		params.Page = 0
+	} else {
+		params.Page = (params.Page - 1) * params.Limit
+	}
+
+	// call GetMutasiStok from stok repository
+	responseRepo, errRepo := su.stokRepository.GetMutasiStok(ctx, tokoID, produkID, daos.FilterMutasiStok{
+		Limit:  params.Limit,
+		Offset: params.Page,
+		SKUID:  params.SKUID,
+		Tipe:   params.Tipe,
+	})
+	if errRepo.Err != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errRepo.Err,
+			Code: errRepo.Code,
+		}
+		return response, errHelper
+	}
+	response = []dto.MutasiStokResponse{}
+	for _, v := range responseRepo {
+		response = append(response, mapMutasiStok(v))
+	}
+
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return response, errHelper
+}
+
+// SetBatasStokRendah threshold of one produk when ProdukID is set, otherwise default threshold of the toko
+func (su *StokUseCaseImpl) SetBatasStokRendah(ctx context.Context, data dto.BatasStokRendahRequest) (errHelper *helper.ErrorStruct) {
+	// call stok repository
+	var errRepo *helper.ErrorStruct
+	if data.ProdukID != 0 {
+		errRepo = su.stokRepository.SetBatasStokRendahProduk(ctx, data.TokoID, data.ProdukID, data.BatasStokRendah)
+	} else {
+		errRepo = su.stokRepository.SetBatasStokRendahToko(ctx, data.TokoID, data.BatasStokRendah)
+	}
+	if errRepo.Err != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errRepo.Err,
+			Code: errRepo.Code,
+		}
+		return errHelper
+	}
+
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return errHelper
+}
+
+func (su *StokUseCaseImpl) GetProdukStokRendah(ctx context.Context, tokoID uint, params dto.FilterStokRendah) (response []dto.StokRendahResponse, errHelper *helper.ErrorStruct) {
+	// setup pagination
+	if params.Limit < 1 {
+		params.Limit = 10
+	}
+	if params.Page < 1 {
+		params.Page = 0
+	} else {
+		params.Page = (params.Page - 1) * params.Limit
+	}
+
+	// call GetProdukStokRendah from stok repository
+	responseRepo, errRepo := su.stokRepository.GetProdukStokRendah(ctx, tokoID, params.Limit, params.Page)
+	if errRepo.Err != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errRepo.Err,
+			Code: errRepo.Code,
+		}
+		return response, errHelper
+	}
+	response = []dto.StokRendahResponse{}
+	for _, v := range responseRepo {
+		response = append(response, dto.StokRendahResponse{
+			ID:                    v.ID,
+			NamaProduk:            v.NamaProduk,
+			Slug:                  v.Slug,
+			Stok:                  v.Stok,
+			BatasStokRendah:       v.BatasStokRendahEfektif(),
+			BatasStokRendahProduk: v.BatasStokRendah,
+		})
+	}
+
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return response, errHelper
+}
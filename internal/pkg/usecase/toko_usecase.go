@@ -5,24 +5,31 @@ import (
 	"errors"
 	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/daos"
 	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/helper"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/infrastructure/storage"
 	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/pkg/dto"
 	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/pkg/repository"
 	"net/http"
+	"strings"
 )
 
 type TokoUseCase interface {
 	GetTokoByID(ctx context.Context, ID uint) (response dto.GetTokoByIDResponse, errHelper *helper.ErrorStruct)
+	GetTokoBySlug(ctx context.Context, slug string) (response dto.GetTokoByIDResponse, errHelper *helper.ErrorStruct)
 	GetTokoByUserID(ctx context.Context, userID uint) (response dto.GetTokoByUserIDResponse, errHelper *helper.ErrorStruct)
-	GetAllToko(ctx context.Context, params dto.TokoFilter) (response []dto.GetAllTokoResponse, errHelper *helper.ErrorStruct)
-	UpdateToko(ctx context.Context, userID uint, data dto.UpdateTokoRequest) (errHelper *helper.ErrorStruct)
+	GetAllToko(ctx context.Context, params dto.TokoFilter) (response []dto.GetAllTokoResponse, nextCursor string, errHelper *helper.ErrorStruct)
+	UpdateToko(ctx context.Context, userID uint, data dto.UpdateTokoRequest) (versi uint, errHelper *helper.ErrorStruct)
+	DeleteToko(ctx context.Context, userID uint) (errHelper *helper.ErrorStruct)
+	RestoreToko(ctx context.Context, userID uint) (errHelper *helper.ErrorStruct)
 }
 
 type TokoUseCaseImpl struct {
 	tokoRepository repository.TokoRepository
+	blobStorage    storage.BlobStorage
+	produkUseCase  ProdukUseCase
 }
 
-func NewTokoUseCase(tokoRepository repository.TokoRepository) TokoUseCase {
-	return &TokoUseCaseImpl{tokoRepository: tokoRepository}
+func NewTokoUseCase(tokoRepository repository.TokoRepository, blobStorage storage.BlobStorage, produkUseCase ProdukUseCase) TokoUseCase {
+	return &TokoUseCaseImpl{tokoRepository: tokoRepository, blobStorage: blobStorage, produkUseCase: produkUseCase}
 }
 
 func (tu *TokoUseCaseImpl) GetTokoByID(ctx context.Context, ID uint) (response dto.GetTokoByIDResponse, errHelper *helper.ErrorStruct) {
@@ -43,7 +50,9 @@ func (tu *TokoUseCaseImpl) GetTokoByID(ctx context.Context, ID uint) (response d
 	response = dto.GetTokoByIDResponse{
 		ID:       responseRepo.ID,
 		NamaToko: responseRepo.NamaToko,
-		UrlFoto:  responseRepo.UrlFoto,
+		Slug:     responseRepo.Slug,
+		UrlFoto:  tu.blobStorage.URL(responseRepo.UrlFoto),
+		Versi:    responseRepo.Versi,
 	}
 	errHelper = &helper.ErrorStruct{
 		Err:  errRepo.Err,
@@ -52,6 +61,35 @@ func (tu *TokoUseCaseImpl) GetTokoByID(ctx context.Context, ID uint) (response d
 	return response, errHelper
 }
 
+// GetTokoBySlug toko by its current or old slug, Slug in response is the current one so caller can redirect old slug
+func (tu *TokoUseCaseImpl) GetTokoBySlug(ctx context.Context, slug string) (response dto.GetTokoByIDResponse, errHelper *helper.ErrorStruct) {
+	// call GetTokoBySlug function from toko repository
+	responseRepo, errRepo := tu.tokoRepository.GetTokoBySlug(ctx, strings.ToLower(slug))
+	// error checking
+	if errRepo.Err != nil {
+		errHelper = &helper.ErrorStruct{
+			Code: errRepo.Code,
+			Err:  errRepo.Err,
+		}
+		return response, errHelper
+	}
+
+	// success response
+	// mapping response from repository
+	response = dto.GetTokoByIDResponse{
+		ID:       responseRepo.ID,
+		NamaToko: responseRepo.NamaToko,
+		Slug:     responseRepo.Slug,
+		UrlFoto:  tu.blobStorage.URL(responseRepo.UrlFoto),
+		Versi:    responseRepo.Versi,
+	}
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return response, errHelper
+}
+
 func (tu *TokoUseCaseImpl) GetTokoByUserID(ctx context.Context, userID uint) (response dto.GetTokoByUserIDResponse, errHelper *helper.ErrorStruct) {
 
 	// call GetTokoByID function from toko repository
@@ -70,8 +108,10 @@ func (tu *TokoUseCaseImpl) GetTokoByUserID(ctx context.Context, userID uint) (re
 	response = dto.GetTokoByUserIDResponse{
 		ID:       responseRepo.ID,
 		NamaToko: responseRepo.NamaToko,
-		UrlFoto:  responseRepo.UrlFoto,
+		Slug:     responseRepo.Slug,
+		UrlFoto:  tu.blobStorage.URL(responseRepo.UrlFoto),
 		UserID:   responseRepo.UserID,
+		Versi:    responseRepo.Versi,
 	}
 	errHelper = &helper.ErrorStruct{
 		Err:  errRepo.Err,
@@ -80,7 +120,7 @@ func (tu *TokoUseCaseImpl) GetTokoByUserID(ctx context.Context, userID uint) (re
 	return response, errHelper
 }
 
-func (tu *TokoUseCaseImpl) GetAllToko(ctx context.Context, params dto.TokoFilter) (response []dto.GetAllTokoResponse, errHelper *helper.ErrorStruct) {
+func (tu *TokoUseCaseImpl) GetAllToko(ctx context.Context, params dto.TokoFilter) (response []dto.GetAllTokoResponse, nextCursor string, errHelper *helper.ErrorStruct) {
 	// setup pagination
 	if params.Limit < 1 {
 		params.Limit = 10
@@ -90,19 +130,37 @@ func (tu *TokoUseCaseImpl) GetAllToko(ctx context.Context, params dto.TokoFilter
 	} else {
 		params.Page = (params.Page - 1) * params.Limit
 	}
-	// call GetAllToko function from toko repository to get all toko and error information
-	responseRepo, errRepo := tu.tokoRepository.GetAllToko(ctx, daos.FilterToko{
-		Limit:  params.Limit,
+	// keyset pagination continue after cursor, page is ignored
+	filter := daos.FilterToko{
+		Limit:  params.Limit + 1,
 		Offset: params.Page,
 		Nama:   params.Nama,
-	})
+	}
+	if params.Cursor != "" {
+		cursor, errCursor := helper.DecodeCursor(params.Cursor, "")
+		if errCursor != nil {
+			errHelper = &helper.ErrorStruct{
+				Err:  errCursor,
+				Code: http.StatusBadRequest,
+			}
+			return response, nextCursor, errHelper
+		}
+		filter.SetelahID = cursor.ID
+	}
+	// call GetAllToko function from toko repository to get all toko and error information
+	responseRepo, errRepo := tu.tokoRepository.GetAllToko(ctx, filter)
 	// error checking
 	if errRepo.Err != nil {
 		errHelper = &helper.ErrorStruct{
 			Err:  errRepo.Err,
 			Code: errRepo.Code,
 		}
-		return response, errHelper
+		return response, nextCursor, errHelper
+	}
+	// one more toko is requested to know whether next page exist
+	if len(responseRepo) > params.Limit {
+		responseRepo = responseRepo[:params.Limit]
+		nextCursor = helper.EncodeCursor(helper.Cursor{ID: responseRepo[len(responseRepo)-1].ID})
 	}
 	// check if length of toko data from repository > 0
 	if len(responseRepo) > 0 {
@@ -110,7 +168,8 @@ func (tu *TokoUseCaseImpl) GetAllToko(ctx context.Context, params dto.TokoFilter
 			response = append(response, dto.GetAllTokoResponse{
 				ID:       v.ID,
 				NamaToko: v.NamaToko,
-				UrlFoto:  v.UrlFoto,
+				Slug:     v.Slug,
+				UrlFoto:  tu.blobStorage.URL(v.UrlFoto),
 			})
 		}
 	} else {
@@ -119,24 +178,69 @@ func (tu *TokoUseCaseImpl) GetAllToko(ctx context.Context, params dto.TokoFilter
 			Err:  errors.New("belum ada toko"),
 			Code: http.StatusNotFound,
 		}
-		return response, errHelper
+		return response, nextCursor, errHelper
 	}
 	// success response
 	errHelper = &helper.ErrorStruct{
 		Err:  nil,
 		Code: http.StatusOK,
 	}
-	return response, errHelper
+	return response, nextCursor, errHelper
 }
 
-func (tu *TokoUseCaseImpl) UpdateToko(ctx context.Context, userID uint, data dto.UpdateTokoRequest) (errHelper *helper.ErrorStruct) {
+// UpdateToko versi is the new versi of toko after it is updated
+func (tu *TokoUseCaseImpl) UpdateToko(ctx context.Context, userID uint, data dto.UpdateTokoRequest) (versi uint, errHelper *helper.ErrorStruct) {
 	// call UpdateToko function from toko repository to update data and get err information
-	if errRepo := tu.tokoRepository.UpdateToko(ctx, daos.Toko{
+	versi, errRepo := tu.tokoRepository.UpdateToko(ctx, daos.Toko{
 
 		UserID:   userID,
 		NamaToko: data.NamaToko,
 		UrlFoto:  data.Photo,
-	}); errRepo.Err != nil {
+		Versi:    data.Versi,
+	})
+	if errRepo.Err != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errRepo.Err,
+			Code: errRepo.Code,
+		}
+		return versi, errHelper
+	}
+
+	// nama toko is searchable text of every produk in the toko
+	if data.NamaToko != "" {
+		if toko, errRepo := tu.tokoRepository.GetTokoByUserID(ctx, userID); errRepo.Err == nil {
+			tu.produkUseCase.IndexProdukToko(ctx, toko.ID)
+		}
+	}
+
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return versi, errHelper
+}
+
+func (tu *TokoUseCaseImpl) DeleteToko(ctx context.Context, userID uint) (errHelper *helper.ErrorStruct) {
+	// call DeleteToko function from toko repository to move toko with its produk to trash
+	if errRepo := tu.tokoRepository.DeleteToko(ctx, userID); errRepo.Err != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errRepo.Err,
+			Code: errRepo.Code,
+		}
+		return errHelper
+	}
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return errHelper
+}
+
+func (tu *TokoUseCaseImpl) RestoreToko(ctx context.Context, userID uint) (errHelper *helper.ErrorStruct) {
+	// call RestoreToko function from toko repository to bring back toko with its produk
+	if errRepo := tu.tokoRepository.RestoreToko(ctx, userID); errRepo.Err != nil {
 		errHelper = &helper.ErrorStruct{
 			Err:  errRepo.Err,
 			Code: errRepo.Code,
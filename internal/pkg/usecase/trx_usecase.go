@@ -2,31 +2,85 @@ package usecase
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/daos"
 	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/helper"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/infrastructure/messaging"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/infrastructure/storage"
 	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/pkg/dto"
 	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/pkg/repository"
 	"math/rand"
 	"net/http"
 	"sort"
+	"strings"
 )
 
 type TRXUseCase interface {
-	GetAllTRX(ctx context.Context, userID uint, params dto.FilterTRX) (trx []dto.TRXGetResponse, errHelper *helper.ErrorStruct)
+	GetAllTRX(ctx context.Context, userID uint, params dto.FilterTRX) (trx []dto.TRXGetResponse, nextCursor string, errHelper *helper.ErrorStruct)
 	GetTRXByID(ctx context.Context, userID, ID uint) (trx dto.TRXGetResponse, errHelper *helper.ErrorStruct)
 	CreateTRX(ctx context.Context, trx dto.TRX) (ID uint, errHelper *helper.ErrorStruct)
+	KonfirmasiPembayaran(ctx context.Context, ID uint) (errHelper *helper.ErrorStruct)
+	KirimTRX(ctx context.Context, userID, ID uint, data dto.PengirimanTRX) (errHelper *helper.ErrorStruct)
 }
 
 type TRXUseCaseImpl struct {
-	trxRepository repository.TRXRepository
+	trxRepository        repository.TRXRepository
+	notifikasiRepository repository.NotifikasiRepository
+	messenger            messaging.Messenger
+	blobStorage          storage.BlobStorage
 }
 
-func NewTRXUseCase(trxRepository repository.TRXRepository) TRXUseCase {
-	return &TRXUseCaseImpl{trxRepository: trxRepository}
+func NewTRXUseCase(trxRepository repository.TRXRepository, notifikasiRepository repository.NotifikasiRepository, messenger messaging.Messenger, blobStorage storage.BlobStorage) TRXUseCase {
+	return &TRXUseCaseImpl{
+		trxRepository:        trxRepository,
+		notifikasiRepository: notifikasiRepository,
+		messenger:            messenger,
+		blobStorage:          blobStorage,
+	}
+}
+
+// emailNotifikasi email template used for every notifikasi tipe produced by trx and the payload it is rendered with
+var emailNotifikasi = map[string]struct {
+	template string
+	payload  func() interface{}
+}{
+	daos.NotifikasiTipePesananDibuat: {messaging.TemplatePesananDibuat, func() interface{} { return &daos.PayloadPesananDibuat{} }},
+	daos.NotifikasiTipePesananMasuk:  {messaging.TemplatePesananMasuk, func() interface{} { return &daos.PayloadPesananMasuk{} }},
+	daos.NotifikasiTipePembayaran:    {messaging.TemplatePembayaran, func() interface{} { return &daos.PayloadPembayaran{} }},
+	daos.NotifikasiTipePengiriman:    {messaging.TemplatePengiriman, func() interface{} { return &daos.PayloadPengiriman{} }},
+	daos.NotifikasiTipeStokRendah:    {messaging.TemplateStokRendah, func() interface{} { return &daos.PayloadStokRendah{} }},
+}
+
+// kirimEmailNotifikasi send email for every notifikasi created by trx, failure only logged
+func (trxu *TRXUseCaseImpl) kirimEmailNotifikasi(ctx context.Context, listNotifikasiID []uint) {
+	if len(listNotifikasiID) == 0 {
+		return
+	}
+	listNotifikasi, errRepo := trxu.notifikasiRepository.GetNotifikasiByID(ctx, listNotifikasiID)
+	if errRepo.Err != nil {
+		helper.Logger("trx_usecase", helper.LoggerLevelWarn, fmt.Sprintf("failed to get notifikasi %v : %s", listNotifikasiID, errRepo.Err.Error()))
+		return
+	}
+	for _, v := range listNotifikasi {
+		email, ok := emailNotifikasi[v.Tipe]
+		if !ok {
+			continue
+		}
+		// payload is decoded to the same struct it is built from
+		payload := email.payload()
+		if err := json.Unmarshal([]byte(v.Payload), payload); err != nil {
+			helper.Logger("trx_usecase", helper.LoggerLevelWarn, fmt.Sprintf("failed to decode payload notifikasi %d : %s", v.ID, err.Error()))
+			continue
+		}
+		data := messaging.DataNotifikasi{Nama: v.User.Nama, Payload: payload}
+		if err := trxu.messenger.KirimEmail(v.User.Email, email.template, "", data); err != nil {
+			helper.Logger("trx_usecase", helper.LoggerLevelWarn, fmt.Sprintf("failed to queue email notifikasi %d : %s", v.ID, err.Error()))
+		}
+	}
 }
 
-func (trxu *TRXUseCaseImpl) GetAllTRX(ctx context.Context, userID uint, params dto.FilterTRX) (trx []dto.TRXGetResponse, errHelper *helper.ErrorStruct) {
+func (trxu *TRXUseCaseImpl) GetAllTRX(ctx context.Context, userID uint, params dto.FilterTRX) (trx []dto.TRXGetResponse, nextCursor string, errHelper *helper.ErrorStruct) {
 	// setup pagination
 	if params.Limit < 1 {
 		params.Limit = 10
@@ -37,17 +91,36 @@ func (trxu *TRXUseCaseImpl) GetAllTRX(ctx context.Context, userID uint, params d
 		params.Page = (params.Page - 1) * params.Limit
 	}
 
-	// call GetTRXByID from trx repository
-	trxRepo, errRepo := trxu.trxRepository.GetAllTRX(ctx, userID, daos.FilterTRX{
-		Limit:  params.Limit,
+	// keyset pagination continue after cursor, page is ignored
+	filter := daos.FilterTRX{
+		Limit:  params.Limit + 1,
 		Offset: params.Page,
-	})
+	}
+	if params.Cursor != "" {
+		cursor, errCursor := helper.DecodeCursor(params.Cursor, "")
+		if errCursor != nil {
+			errHelper = &helper.ErrorStruct{
+				Err:  errCursor,
+				Code: http.StatusBadRequest,
+			}
+			return trx, nextCursor, errHelper
+		}
+		filter.SetelahID = cursor.ID
+	}
+
+	// call GetAllTRX from trx repository
+	trxRepo, errRepo := trxu.trxRepository.GetAllTRX(ctx, userID, filter)
 	if errRepo.Err != nil {
 		errHelper = &helper.ErrorStruct{
 			Err:  errRepo.Err,
 			Code: errRepo.Code,
 		}
-		return trx, errHelper
+		return trx, nextCursor, errHelper
+	}
+	// one more trx is requested to know whether next page exist
+	if len(trxRepo) > params.Limit {
+		trxRepo = trxRepo[:params.Limit]
+		nextCursor = helper.EncodeCursor(helper.Cursor{ID: trxRepo[len(trxRepo)-1].ID})
 	}
 
 	for _, t := range trxRepo {
@@ -58,7 +131,7 @@ func (trxu *TRXUseCaseImpl) GetAllTRX(ctx context.Context, userID uint, params d
 				foto := dto.LogFotoProdukResponse{
 					ID:          f.ID,
 					LogProdukID: f.LogProdukID,
-					URL:         f.URL,
+					URL:         trxu.blobStorage.URL(f.URL),
 				}
 				listFoto = append(listFoto, foto)
 			}
@@ -73,7 +146,7 @@ func (trxu *TRXUseCaseImpl) GetAllTRX(ctx context.Context, userID uint, params d
 
 				Toko: dto.TokoTRX{
 					NamaToko: v.LogProduk.Toko.NamaToko,
-					UrlFoto:  v.LogProduk.Toko.UrlFoto,
+					UrlFoto:  trxu.blobStorage.URL(v.LogProduk.Toko.UrlFoto),
 				},
 				Category: dto.CategoryWithID{
 					ID:           v.LogProduk.Category.ID,
@@ -81,15 +154,23 @@ func (trxu *TRXUseCaseImpl) GetAllTRX(ctx context.Context, userID uint, params d
 				},
 				Photos: listFoto,
 			}
+			// snapshot of chosen variant
+			if v.LogProduk.SKUID != nil {
+				logProduk.SKUID = *v.LogProduk.SKUID
+				logProduk.KodeSKU = v.LogProduk.KodeSKU
+				_ = json.Unmarshal([]byte(v.LogProduk.Varian), &logProduk.Varian)
+			}
 			detailTRX := dto.DetailTRXGetResponse{
 				Product: logProduk,
 				Toko: dto.GetTokoByIDResponse{
 					ID:       v.Toko.ID,
 					NamaToko: v.Toko.NamaToko,
-					UrlFoto:  v.Toko.UrlFoto,
+					Slug:     v.Toko.Slug,
+					UrlFoto:  trxu.blobStorage.URL(v.Toko.UrlFoto),
 				},
 				Kuantitas:  v.Kuantitas,
 				HargaTotal: v.HargaTotal,
+				NoResi:     v.NoResi,
 			}
 			listDetailTRX = append(listDetailTRX, detailTRX)
 		}
@@ -101,12 +182,13 @@ func (trxu *TRXUseCaseImpl) GetAllTRX(ctx context.Context, userID uint, params d
 			DetailAlamat: t.Alamat.DetailAlamat,
 		}
 		transaction := dto.TRXGetResponse{
-			ID:          t.ID,
-			HargaTotal:  t.HargaTotal,
-			KodeInvoice: t.KodeInvoice,
-			MethodBayar: t.MethodBayar,
-			Alamat:      alamat,
-			DetailTRX:   listDetailTRX,
+			ID:               t.ID,
+			HargaTotal:       t.HargaTotal,
+			KodeInvoice:      t.KodeInvoice,
+			MethodBayar:      t.MethodBayar,
+			StatusPembayaran: t.StatusPembayaran,
+			Alamat:           alamat,
+			DetailTRX:        listDetailTRX,
 		}
 		trx = append(trx, transaction)
 	}
@@ -115,7 +197,7 @@ func (trxu *TRXUseCaseImpl) GetAllTRX(ctx context.Context, userID uint, params d
 		Err:  nil,
 		Code: http.StatusOK,
 	}
-	return trx, errHelper
+	return trx, nextCursor, errHelper
 }
 
 func (trxu *TRXUseCaseImpl) GetTRXByID(ctx context.Context, userID, ID uint) (trx dto.TRXGetResponse, errHelper *helper.ErrorStruct) {
@@ -136,7 +218,7 @@ func (trxu *TRXUseCaseImpl) GetTRXByID(ctx context.Context, userID, ID uint) (tr
 			foto := dto.LogFotoProdukResponse{
 				ID:          f.ID,
 				LogProdukID: f.LogProdukID,
-				URL:         f.URL,
+				URL:         trxu.blobStorage.URL(f.URL),
 			}
 			listFoto = append(listFoto, foto)
 		}
@@ -151,7 +233,7 @@ func (trxu *TRXUseCaseImpl) GetTRXByID(ctx context.Context, userID, ID uint) (tr
 
 			Toko: dto.TokoTRX{
 				NamaToko: v.LogProduk.Toko.NamaToko,
-				UrlFoto:  v.LogProduk.Toko.UrlFoto,
+				UrlFoto:  trxu.blobStorage.URL(v.LogProduk.Toko.UrlFoto),
 			},
 			Category: dto.CategoryWithID{
 				ID:           v.LogProduk.Category.ID,
@@ -159,15 +241,23 @@ func (trxu *TRXUseCaseImpl) GetTRXByID(ctx context.Context, userID, ID uint) (tr
 			},
 			Photos: listFoto,
 		}
+		// snapshot of chosen variant
+		if v.LogProduk.SKUID != nil {
+			logProduk.SKUID = *v.LogProduk.SKUID
+			logProduk.KodeSKU = v.LogProduk.KodeSKU
+			_ = json.Unmarshal([]byte(v.LogProduk.Varian), &logProduk.Varian)
+		}
 		detailTRX := dto.DetailTRXGetResponse{
 			Product: logProduk,
 			Toko: dto.GetTokoByIDResponse{
 				ID:       v.Toko.ID,
 				NamaToko: v.Toko.NamaToko,
-				UrlFoto:  v.Toko.UrlFoto,
+				Slug:     v.Toko.Slug,
+				UrlFoto:  trxu.blobStorage.URL(v.Toko.UrlFoto),
 			},
 			Kuantitas:  v.Kuantitas,
 			HargaTotal: v.HargaTotal,
+			NoResi:     v.NoResi,
 		}
 		listDetailTRX = append(listDetailTRX, detailTRX)
 	}
@@ -179,12 +269,13 @@ func (trxu *TRXUseCaseImpl) GetTRXByID(ctx context.Context, userID, ID uint) (tr
 		DetailAlamat: trxRepo.Alamat.DetailAlamat,
 	}
 	trx = dto.TRXGetResponse{
-		ID:          trxRepo.ID,
-		HargaTotal:  trxRepo.HargaTotal,
-		KodeInvoice: trxRepo.KodeInvoice,
-		MethodBayar: trxRepo.MethodBayar,
-		Alamat:      alamat,
-		DetailTRX:   listDetailTRX,
+		ID:               trxRepo.ID,
+		HargaTotal:       trxRepo.HargaTotal,
+		KodeInvoice:      trxRepo.KodeInvoice,
+		MethodBayar:      trxRepo.MethodBayar,
+		StatusPembayaran: trxRepo.StatusPembayaran,
+		Alamat:           alamat,
+		DetailTRX:        listDetailTRX,
 	}
 	// success response
 	errHelper = &helper.ErrorStruct{
@@ -206,6 +297,9 @@ func (trxu *TRXUseCaseImpl) CreateTRX(ctx context.Context, trx dto.TRX) (ID uint
 
 	// sort detail
 	sort.SliceStable(trx.DetailTRX, func(i, j int) bool {
+		if trx.DetailTRX[i].ProductID == trx.DetailTRX[j].ProductID {
+			return trx.DetailTRX[i].SKUID < trx.DetailTRX[j].SKUID
+		}
 		return trx.DetailTRX[i].ProductID < trx.DetailTRX[j].ProductID
 	})
 	// create kode invoice
@@ -216,12 +310,13 @@ func (trxu *TRXUseCaseImpl) CreateTRX(ctx context.Context, trx dto.TRX) (ID uint
 	for _, v := range trx.DetailTRX {
 		produkIDKuantitas := daos.ProdukIDKuantitas{
 			ProdukID:  v.ProductID,
+			SKUID:     v.SKUID,
 			Kuantitas: v.Kuantitas,
 		}
 		listProdukIDKuantitas = append(listProdukIDKuantitas, produkIDKuantitas)
 	}
 	// call CreateTRX from trx repository
-	IDRepo, errRepo := trxu.trxRepository.CreateTRX(ctx, daos.TRX{
+	IDRepo, listNotifikasiID, errRepo := trxu.trxRepository.CreateTRX(ctx, daos.TRX{
 
 		UserID:      trx.UserID,
 		AlamatID:    trx.AlamatID,
@@ -236,6 +331,9 @@ func (trxu *TRXUseCaseImpl) CreateTRX(ctx context.Context, trx dto.TRX) (ID uint
 		}
 		return ID, errHelper
 	}
+	// send email for buyer, sellers and low stock alert
+	trxu.kirimEmailNotifikasi(ctx, listNotifikasiID)
+
 	// success response
 	errHelper = &helper.ErrorStruct{
 		Err:  nil,
@@ -243,3 +341,54 @@ func (trxu *TRXUseCaseImpl) CreateTRX(ctx context.Context, trx dto.TRX) (ID uint
 	}
 	return IDRepo, errHelper
 }
+
+func (trxu *TRXUseCaseImpl) KonfirmasiPembayaran(ctx context.Context, ID uint) (errHelper *helper.ErrorStruct) {
+	// call KonfirmasiPembayaran from trx repository
+	listNotifikasiID, errRepo := trxu.trxRepository.KonfirmasiPembayaran(ctx, ID)
+	if errRepo.Err != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errRepo.Err,
+			Code: errRepo.Code,
+		}
+		return errHelper
+	}
+	// send email for buyer
+	trxu.kirimEmailNotifikasi(ctx, listNotifikasiID)
+
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return errHelper
+}
+
+func (trxu *TRXUseCaseImpl) KirimTRX(ctx context.Context, userID, ID uint, data dto.PengirimanTRX) (errHelper *helper.ErrorStruct) {
+	// validate user input
+	if errValidate := helper.Validate.Struct(data); errValidate != nil {
+		errHelper = &helper.ErrorStruct{
+			Code: http.StatusBadRequest,
+			Err:  errValidate,
+		}
+		return errHelper
+	}
+
+	// call KirimTRX from trx repository
+	listNotifikasiID, errRepo := trxu.trxRepository.KirimTRX(ctx, userID, ID, strings.TrimSpace(data.NoResi))
+	if errRepo.Err != nil {
+		errHelper = &helper.ErrorStruct{
+			Err:  errRepo.Err,
+			Code: errRepo.Code,
+		}
+		return errHelper
+	}
+	// send email for buyer
+	trxu.kirimEmailNotifikasi(ctx, listNotifikasiID)
+
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return errHelper
+}
@@ -2,11 +2,18 @@ package usecase
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
+	"fmt"
 	"net/http"
+	"net/url"
+	"time"
 
 	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/daos"
 	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/helper"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/infrastructure/messaging"
 	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/pkg/dto"
 	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/pkg/repository"
 	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/utils"
@@ -18,18 +25,34 @@ type UserUseCase interface {
 	Login(ctx context.Context, data dto.UserLogin) (response dto.UserResponse, errHelper *helper.ErrorStruct)
 	GetMyProfile(ctx context.Context, userID uint) (response dto.UserResponse, errHelper *helper.ErrorStruct)
 	UpdateProfile(ctx context.Context, ID uint, data dto.UserRegisterAndUpdate) (errHelper *helper.ErrorStruct)
+	MintaResetKataSandi(ctx context.Context, data dto.MintaResetKataSandi) (errHelper *helper.ErrorStruct)
+	ResetKataSandi(ctx context.Context, data dto.ResetKataSandi) (errHelper *helper.ErrorStruct)
 }
 
+// masaBerlakuResetKataSandi how long token sent by reset kata sandi email can be used
+const masaBerlakuResetKataSandi = 30 * time.Minute
+
 type UserUseCaseImpl struct {
 	userRepository repository.UserRepository
+	messenger      messaging.Messenger
+	// urlResetSandi page receiving reset kata sandi token as query parameter
+	urlResetSandi string
 }
 
-func NewUserUseCase(userRepository repository.UserRepository) UserUseCase {
+func NewUserUseCase(userRepository repository.UserRepository, messenger messaging.Messenger, urlResetSandi string) UserUseCase {
 	return &UserUseCaseImpl{
 		userRepository: userRepository,
+		messenger:      messenger,
+		urlResetSandi:  urlResetSandi,
 	}
 }
 
+// hashTokenReset only hash of reset token is saved, so leaked database can not be used to reset kata sandi
+func hashTokenReset(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
+
 func (uc *UserUseCaseImpl) Register(ctx context.Context, data dto.UserRegisterAndUpdate) (errHelper *helper.ErrorStruct) {
 	// validate user input
 	if errValidate := helper.Validate.Struct(data); errValidate != nil {
@@ -61,6 +84,18 @@ func (uc *UserUseCaseImpl) Register(ctx context.Context, data dto.UserRegisterAn
 		return errHelper
 	}
 
+	// send welcome message, delivery failure must not fail the registration
+	dataMessage := map[string]interface{}{
+		"Nama":     data.Nama,
+		"NamaToko": fmt.Sprintf("Toko %s", data.Nama),
+	}
+	if err := uc.messenger.KirimEmail(data.Email, messaging.TemplateRegistrasi, "", dataMessage); err != nil {
+		helper.Logger("user_usecase", helper.LoggerLevelWarn, fmt.Sprintf("failed to queue registration email : %s", err.Error()))
+	}
+	if err := uc.messenger.KirimSMS(data.Notelp, messaging.TemplateRegistrasi, "", dataMessage); err != nil {
+		helper.Logger("user_usecase", helper.LoggerLevelWarn, fmt.Sprintf("failed to queue registration sms : %s", err.Error()))
+	}
+
 	// success response
 	errHelper = &helper.ErrorStruct{
 		Err:  nil,
@@ -192,3 +227,105 @@ func (uc *UserUseCaseImpl) UpdateProfile(ctx context.Context, ID uint, data dto.
 	}
 	return errHelper
 }
+
+func (uc *UserUseCaseImpl) MintaResetKataSandi(ctx context.Context, data dto.MintaResetKataSandi) (errHelper *helper.ErrorStruct) {
+	// validate user input
+	if errValidate := helper.Validate.Struct(data); errValidate != nil {
+		errHelper = &helper.ErrorStruct{
+			Code: http.StatusBadRequest,
+			Err:  errValidate,
+		}
+		return errHelper
+	}
+
+	// unknown email get the same response, so registered email can not be guessed
+	user, errRepo := uc.userRepository.GetUserByEmail(ctx, data.Email)
+	if errRepo.Err != nil {
+		if errRepo.Code == http.StatusNotFound {
+			errHelper = &helper.ErrorStruct{
+				Err:  nil,
+				Code: http.StatusOK,
+			}
+			return errHelper
+		}
+		errHelper = &helper.ErrorStruct{
+			Code: errRepo.Code,
+			Err:  errRepo.Err,
+		}
+		return errHelper
+	}
+
+	// create random token, only its hash is saved
+	tokenByte := make([]byte, 32)
+	if _, err := rand.Read(tokenByte); err != nil {
+		errHelper = &helper.ErrorStruct{
+			Code: http.StatusInternalServerError,
+			Err:  err,
+		}
+		return errHelper
+	}
+	token := hex.EncodeToString(tokenByte)
+	berlakuHingga := time.Now().Add(masaBerlakuResetKataSandi)
+	if errRepo := uc.userRepository.CreateResetKataSandi(ctx, daos.ResetKataSandi{
+		UserID:        user.ID,
+		TokenHash:     hashTokenReset(token),
+		BerlakuHingga: berlakuHingga,
+	}); errRepo.Err != nil {
+		errHelper = &helper.ErrorStruct{
+			Code: errRepo.Code,
+			Err:  errRepo.Err,
+		}
+		return errHelper
+	}
+
+	// send token, delivery failure only logged and user can request again
+	dataMessage := messaging.DataResetKataSandi{
+		Nama:          user.Nama,
+		Tautan:        fmt.Sprintf("%s?token=%s", uc.urlResetSandi, url.QueryEscape(token)),
+		Kode:          token,
+		BerlakuHingga: berlakuHingga.Format("2006-01-02 15:04 MST"),
+	}
+	if err := uc.messenger.KirimEmail(user.Email, messaging.TemplateResetKataSandi, "", dataMessage); err != nil {
+		helper.Logger("user_usecase", helper.LoggerLevelWarn, fmt.Sprintf("failed to queue reset kata sandi email : %s", err.Error()))
+	}
+	if err := uc.messenger.KirimSMS(user.Notelp, messaging.TemplateResetKataSandi, "", dataMessage); err != nil {
+		helper.Logger("user_usecase", helper.LoggerLevelWarn, fmt.Sprintf("failed to queue reset kata sandi sms : %s", err.Error()))
+	}
+
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return errHelper
+}
+
+func (uc *UserUseCaseImpl) ResetKataSandi(ctx context.Context, data dto.ResetKataSandi) (errHelper *helper.ErrorStruct) {
+	// validate user input
+	if errValidate := helper.Validate.Struct(data); errValidate != nil {
+		errHelper = &helper.ErrorStruct{
+			Code: http.StatusBadRequest,
+			Err:  errValidate,
+		}
+		return errHelper
+	}
+
+	// hash password before insert to database
+	passwordByte, _ := bcrypt.GenerateFromPassword([]byte(data.KataSandi), bcrypt.DefaultCost)
+
+	// call ResetKataSandi from user repository, token is checked and used in the same transaction
+	if errRepo := uc.userRepository.ResetKataSandi(ctx, hashTokenReset(data.Token), string(passwordByte)); errRepo.Err != nil {
+		errHelper = &helper.ErrorStruct{
+			Code: errRepo.Code,
+			Err:  errRepo.Err,
+		}
+		return errHelper
+	}
+
+	// success response
+	errHelper = &helper.ErrorStruct{
+		Err:  nil,
+		Code: http.StatusOK,
+	}
+	return errHelper
+}
@@ -1,19 +1,24 @@
 package handler
 
 import (
+	"context"
+	"fmt"
 	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/websocket/v2"
+	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/helper"
 	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/infrastructure/container"
 	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/pkg/controller"
 	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/pkg/repository"
 	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/pkg/usecase"
 	"github.com/syahrilmaulayahya/tugas_akhir_rakamin/internal/utils/apicall"
+	"time"
 )
 
 // AuthRoute group user endpoint
 func AuthRoute(r fiber.Router, containerConf *container.Container) {
 
 	repo := repository.NewUserRepository(containerConf.Mysqldb)
-	userUseCase := usecase.NewUserUseCase(repo)
+	userUseCase := usecase.NewUserUseCase(repo, containerConf.Messaging, containerConf.Apps.URLResetSandi)
 	middleware := usecase.NewMiddleware(usecase.Config{SharedKey: containerConf.Apps.SecretJwt})
 	userController := controller.NewUserController(userUseCase, middleware)
 
@@ -21,22 +26,31 @@ func AuthRoute(r fiber.Router, containerConf *container.Container) {
 	AuthAPI := r.Group("/auth")
 	AuthAPI.Post("/register", userController.Register)
 	AuthAPI.Post("/login", userController.Login)
+	AuthAPI.Post("/reset-password/request", userController.MintaResetKataSandi)
+	AuthAPI.Post("/reset-password", userController.ResetKataSandi)
 
 }
 
 func TokoRoute(r fiber.Router, containerConf *container.Container) {
 	repo := repository.NewTokoRepository(containerConf.Mysqldb)
-	tokoUseCase := usecase.NewTokoUseCase(repo)
-	tokoController := controller.NewTokoController(tokoUseCase)
+	produkUseCase := usecase.NewProdukUseCase(repository.NewProdukRepository(containerConf.Mysqldb), containerConf.Search, containerConf.Storage)
+	tokoUseCase := usecase.NewTokoUseCase(repo, containerConf.Storage, produkUseCase)
+	tokoController := controller.NewTokoController(tokoUseCase, containerConf.Gambar, containerConf.Storage)
+	moderasiUseCase := usecase.NewModerasiUseCase(repository.NewModerasiRepository(containerConf.Mysqldb), containerConf.Storage)
+	moderasiController := controller.NewModerasiController(moderasiUseCase)
 	middleware := usecase.NewMiddleware(usecase.Config{SharedKey: containerConf.Apps.SecretJwt})
 	auth := controller.NewAuthImpl(middleware)
 
 	// toko endpoint
 	tokoAPI := r.Group("/toko")
 	tokoAPI.Get("/my", auth.CheckJwtUser, tokoController.GetMyToko)
+	tokoAPI.Delete("/my", auth.CheckJwtUser, tokoController.DeleteToko)
+	tokoAPI.Put("/my/restore", auth.CheckJwtUser, tokoController.RestoreToko)
+	tokoAPI.Get("/slug/:slug", tokoController.GetTokoBySlug)
 	tokoAPI.Get("/:id_toko", auth.CheckJwtUser, tokoController.GetTokoByID)
 	tokoAPI.Get("", auth.CheckJwtUser, tokoController.GetAllToko)
 	tokoAPI.Put("/:id_toko", auth.CheckJwtUser, tokoController.UpdateToko)
+	tokoAPI.Put("/:id_toko/trusted", auth.CheckJwtAdmin, moderasiController.SetTokoTerpercaya)
 
 }
 
@@ -49,16 +63,24 @@ func CategoryRoute(r fiber.Router, containerConf *container.Container) {
 
 	// setup category service
 	repo := repository.NewCategoryRepository(containerConf.Mysqldb)
-	categoryUseCase := usecase.NewCategoryUseCase(repo)
+	produkUseCase := usecase.NewProdukUseCase(repository.NewProdukRepository(containerConf.Mysqldb), containerConf.Search, containerConf.Storage)
+	categoryUseCase := usecase.NewCategoryUseCase(repo, produkUseCase)
 	categoryController := controller.NewCategoryController(categoryUseCase)
+	atributController := controller.NewAtributController(usecase.NewAtributUseCase(repository.NewAtributRepository(containerConf.Mysqldb)))
 
 	// category endpoint
 	categoryAPI := r.Group("/category")
 	categoryAPI.Post("", auth.CheckJwtAdmin, categoryController.CreateCategory)
 	categoryAPI.Get("", categoryController.GetAllCategory)
+	categoryAPI.Get("/trash", auth.CheckJwtAdmin, categoryController.GetSampahCategory)
 	categoryAPI.Get("/:id", auth.CheckJwt, categoryController.GetCategoryByID)
 	categoryAPI.Put("/:id", auth.CheckJwtAdmin, categoryController.UpdateCategoryByID)
 	categoryAPI.Delete("/:id", auth.CheckJwtAdmin, categoryController.DeleteCategoryByID)
+	categoryAPI.Put("/:id/restore", auth.CheckJwtAdmin, categoryController.RestoreCategoryByID)
+	categoryAPI.Get("/:id/attributes", atributController.GetAtributCategory)
+	categoryAPI.Post("/:id/attributes", auth.CheckJwtAdmin, atributController.CreateAtributCategory)
+	categoryAPI.Put("/:id/attributes/:attr_id", auth.CheckJwtAdmin, atributController.UpdateAtributCategory)
+	categoryAPI.Delete("/:id/attributes/:attr_id", auth.CheckJwtAdmin, atributController.DeleteAtributCategory)
 }
 
 func UserRoute(r fiber.Router, containerConf *container.Container) {
@@ -69,7 +91,7 @@ func UserRoute(r fiber.Router, containerConf *container.Container) {
 
 	// setup user service
 	userRepo := repository.NewUserRepository(containerConf.Mysqldb)
-	userUseCase := usecase.NewUserUseCase(userRepo)
+	userUseCase := usecase.NewUserUseCase(userRepo, containerConf.Messaging, containerConf.Apps.URLResetSandi)
 	userController := controller.NewUserController(userUseCase, middleware)
 
 	// setup alamat service
@@ -83,9 +105,11 @@ func UserRoute(r fiber.Router, containerConf *container.Container) {
 	userAPI.Put("", auth.CheckJwtUser, userController.UpdateProfile)
 	userAPI.Post("/alamat", auth.CheckJwtUser, alamatController.CreateAlamat)
 	userAPI.Get("/alamat", auth.CheckJwtUser, alamatController.GetMyAlamat)
+	userAPI.Get("/alamat/trash", auth.CheckJwtUser, alamatController.GetSampahAlamat)
 	userAPI.Get("/alamat/:id", auth.CheckJwtUser, alamatController.GetAlamatByID)
 	userAPI.Put("/alamat/:id", auth.CheckJwtUser, alamatController.UpdateAlamatByID)
 	userAPI.Delete("/alamat/:id", auth.CheckJwtUser, alamatController.DeleteAlamatByID)
+	userAPI.Put("/alamat/:id/restore", auth.CheckJwtUser, alamatController.RestoreAlamatByID)
 
 }
 
@@ -109,30 +133,222 @@ func ProdukRoute(r fiber.Router, containerConf *container.Container) {
 	auth := controller.NewAuthImpl(middleware)
 
 	produkRepo := repository.NewProdukRepository(containerConf.Mysqldb)
-	produkUseCase := usecase.NewProdukUseCase(produkRepo)
-	produkController := controller.NewProdukController(produkUseCase)
+	produkUseCase := usecase.NewProdukUseCase(produkRepo, containerConf.Search, containerConf.Storage)
+	produkController := controller.NewProdukController(produkUseCase, containerConf.Gambar, containerConf.Storage, containerConf.Tayangan)
+
+	// fill search index with produk already in database, then index produk changed by other instance
+	if errUseCase := produkUseCase.IndexAllProduk(context.Background()); errUseCase.Err != nil {
+		helper.Logger("handler.go", helper.LoggerLevelError, fmt.Sprintf("failed to build search index : %s", errUseCase.Err.Error()))
+	}
+	containerConf.Jadwal.TambahLokal("sinkron index produk", usecase.IntervalSinkronIndex, func(ctx context.Context) {
+		if errUseCase := produkUseCase.SinkronIndex(ctx); errUseCase.Err != nil {
+			helper.Logger("handler.go", helper.LoggerLevelError, fmt.Sprintf("failed to sync search index : %s", errUseCase.Err.Error()))
+		}
+	})
+
+	// give slug to toko and produk created before slug was unique, also retry slug that failed to be saved
+	slugUseCase := usecase.NewSlugUseCase(repository.NewSlugRepository(containerConf.Mysqldb))
+	containerConf.Jadwal.Tambah("lengkapi slug", time.Hour, func(ctx context.Context) {
+		if _, errUseCase := slugUseCase.LengkapiSlug(ctx); errUseCase.Err != nil {
+			helper.Logger("handler.go", helper.LoggerLevelError, fmt.Sprintf("failed to fill slug : %s", errUseCase.Err.Error()))
+		}
+	})
+
+	imporRepo := repository.NewImporRepository(containerConf.Mysqldb)
+	categoryRepo := repository.NewCategoryRepository(containerConf.Mysqldb)
+	imporUseCase := usecase.NewImporUseCase(imporRepo, categoryRepo, produkUseCase, produkRepo, containerConf.Gambar, containerConf.Storage, containerConf.Jadwal)
+	imporController := controller.NewImporController(imporUseCase)
+	eksporUseCase := usecase.NewEksporUseCase(produkRepo, containerConf.Storage)
+	eksporController := controller.NewEksporController(eksporUseCase)
+	stokUseCase := usecase.NewStokUseCase(repository.NewStokRepository(containerConf.Mysqldb))
+	stokController := controller.NewStokController(stokUseCase)
+	promoController := controller.NewPromoController(usecase.NewPromoUseCase(repository.NewPromoRepository(containerConf.Mysqldb)))
+	rekomendasiUseCase := usecase.NewRekomendasiUseCase(repository.NewRekomendasiRepository(containerConf.Mysqldb), containerConf.Storage)
+	rekomendasiController := controller.NewRekomendasiController(rekomendasiUseCase)
+	moderasiUseCase := usecase.NewModerasiUseCase(repository.NewModerasiRepository(containerConf.Mysqldb), containerConf.Storage)
+	moderasiController := controller.NewModerasiController(moderasiUseCase)
+	atributController := controller.NewAtributController(usecase.NewAtributUseCase(repository.NewAtributRepository(containerConf.Mysqldb)))
+	diskusiController := controller.NewDiskusiController(usecase.NewDiskusiUseCase(repository.NewDiskusiRepository(containerConf.Mysqldb)))
+
+	// impor left running by stopped instance is marked as failed
+	containerConf.Jadwal.Tambah("hentikan impor terputus", 10*time.Minute, func(ctx context.Context) {
+		if errUseCase := imporUseCase.HentikanImporTerputus(ctx); errUseCase.Err != nil {
+			helper.Logger("handler.go", helper.LoggerLevelError, fmt.Sprintf("failed to stop interrupted import : %s", errUseCase.Err.Error()))
+		}
+	})
+
+	statistikUseCase := usecase.NewStatistikUseCase(repository.NewStatistikRepository(containerConf.Mysqldb), containerConf.Tayangan, containerConf.Storage)
+	statistikController := controller.NewStatistikController(statistikUseCase)
+
+	// view is counted in memory of every instance, sold kuantitas is recounted from trx history
+	containerConf.Jadwal.TambahLokal("simpan tayangan produk", containerConf.Tayangan.Interval, func(ctx context.Context) {
+		if errUseCase := statistikUseCase.SimpanTayangan(ctx); errUseCase.Err != nil {
+			helper.Logger("handler.go", helper.LoggerLevelError, fmt.Sprintf("failed to save product view : %s", errUseCase.Err.Error()))
+		}
+	})
+	containerConf.Jadwal.Tambah("hitung produk terjual", 10*time.Minute, func(ctx context.Context) {
+		if errUseCase := statistikUseCase.HitungTerjual(ctx); errUseCase.Err != nil {
+			helper.Logger("handler.go", helper.LoggerLevelError, fmt.Sprintf("failed to count sold product : %s", errUseCase.Err.Error()))
+		}
+	})
+
+	// frequently bought together produk is rebuilt from trx history
+	containerConf.Jadwal.Tambah("hitung produk terkait", 6*time.Hour, func(ctx context.Context) {
+		if _, errUseCase := rekomendasiUseCase.HitungProdukTerkait(ctx); errUseCase.Err != nil {
+			helper.Logger("handler.go", helper.LoggerLevelError, fmt.Sprintf("failed to compute related product : %s", errUseCase.Err.Error()))
+		}
+	})
 
 	produkAPI := r.Group("/product")
 	produkAPI.Post("", auth.CheckJwtUser, produkController.UploadProduk)
-	produkAPI.Get("/:id", produkController.GetProdukByID)
+	produkAPI.Post("/import", auth.CheckJwtUser, imporController.ImporProduk)
+	produkAPI.Get("/import", auth.CheckJwtUser, imporController.GetAllImpor)
+	produkAPI.Get("/import/:id", auth.CheckJwtUser, imporController.GetImporByID)
+	produkAPI.Get("/import/:id/errors", auth.CheckJwtUser, imporController.GetBarisImporGagal)
+	produkAPI.Get("/import/:id/report", auth.CheckJwtUser, imporController.GetLaporanImpor)
+	produkAPI.Get("/export", auth.CheckJwtUser, eksporController.EksporProduk)
+	produkAPI.Get("/export/all", auth.CheckJwtAdmin, eksporController.EksporSemuaProduk)
+	produkAPI.Get("/trash", auth.CheckJwtUser, produkController.GetSampahProduk)
+	produkAPI.Get("/my", auth.CheckJwtUser, produkController.GetProdukToko)
+	produkAPI.Get("/recommendations", auth.CheckJwtUser, rekomendasiController.GetRekomendasiUser)
+	produkAPI.Get("/trending", statistikController.GetProdukTrending)
+	produkAPI.Get("/best-seller", statistikController.GetProdukTerlaris)
+	produkAPI.Get("/stock/low", auth.CheckJwtUser, stokController.GetProdukStokRendah)
+	produkAPI.Put("/stock/threshold", auth.CheckJwtUser, stokController.SetBatasStokRendahToko)
+	produkAPI.Get("/moderation", auth.CheckJwtAdmin, moderasiController.GetAntrianModerasi)
+	produkAPI.Put("/moderation/:id", auth.CheckJwtAdmin, moderasiController.ModerasiProduk)
+	produkAPI.Get("/moderation/:id/history", auth.CheckJwtAdmin, moderasiController.GetRiwayatModerasi)
+	produkAPI.Get("/questions/moderation", auth.CheckJwtAdmin, diskusiController.GetAntrianModerasi)
+	produkAPI.Put("/questions/moderation/:id", auth.CheckJwtAdmin, diskusiController.ModerasiDiskusi)
+	produkAPI.Post("/questions/:id/answers", auth.CheckJwtUser, diskusiController.CreateJawaban)
+	produkAPI.Post("/questions/:id/report", auth.CheckJwtUser, diskusiController.LaporkanPertanyaan)
+	produkAPI.Post("/answers/:id/helpful", auth.CheckJwtUser, diskusiController.VoteMembantu)
+	produkAPI.Delete("/answers/:id/helpful", auth.CheckJwtUser, diskusiController.HapusVoteMembantu)
+	produkAPI.Post("/answers/:id/report", auth.CheckJwtUser, diskusiController.LaporkanJawaban)
+	produkAPI.Get("/slug/:slug", auth.CheckJwtOptional, produkController.GetProdukBySlug)
+	produkAPI.Get("/:id", auth.CheckJwtOptional, produkController.GetProdukByID)
 	produkAPI.Put("/:id", auth.CheckJwtUser, produkController.UpdateProdukByID)
 	produkAPI.Delete("/:id", auth.CheckJwtUser, produkController.DeleteProdukByID)
+	produkAPI.Put("/:id/restore", auth.CheckJwtUser, produkController.RestoreProdukByID)
+	produkAPI.Put("/:id/variant", auth.CheckJwtUser, produkController.UpdateVarian)
+	produkAPI.Put("/:id/publication", auth.CheckJwtUser, produkController.UbahPublikasi)
+	produkAPI.Put("/:id/attributes", auth.CheckJwtUser, atributController.SimpanAtributProduk)
+	produkAPI.Get("/:id/stock/history", auth.CheckJwtUser, stokController.GetMutasiStok)
+	produkAPI.Post("/:id/stock/adjust", auth.CheckJwtUser, stokController.SesuaikanStok)
+	produkAPI.Post("/:id/stock/restock", auth.CheckJwtUser, stokController.RestockStok)
+	produkAPI.Put("/:id/stock/threshold", auth.CheckJwtUser, stokController.SetBatasStokRendahProduk)
+	produkAPI.Get("/:id/price/history", promoController.GetRiwayatHarga)
+	produkAPI.Get("/:id/related", rekomendasiController.GetProdukTerkait)
+	produkAPI.Get("/:id/questions", auth.CheckJwtOptional, diskusiController.GetDiskusiProduk)
+	produkAPI.Post("/:id/questions", auth.CheckJwtUser, diskusiController.CreatePertanyaan)
+	produkAPI.Post("/:id/sku/:sku_id/photos", auth.CheckJwtUser, produkController.UploadFotoSKU)
+	produkAPI.Get("/:id/photos", produkController.GetFotoProduk)
+	produkAPI.Get("/:id/photos/trash", auth.CheckJwtUser, produkController.GetSampahFotoProduk)
+	produkAPI.Put("/:id/photos/order", auth.CheckJwtUser, produkController.UrutkanFotoProduk)
+	produkAPI.Put("/:id/photos/:photo_id/primary", auth.CheckJwtUser, produkController.SetFotoUtama)
+	produkAPI.Delete("/:id/photos/:photo_id", auth.CheckJwtUser, produkController.DeleteFotoProduk)
+	produkAPI.Put("/:id/photos/:photo_id/restore", auth.CheckJwtUser, produkController.RestoreFotoProduk)
 	produkAPI.Get("", produkController.GetAllProduk)
 
 }
 
+// PromoRoute group scheduled sale and flash sale endpoint
+func PromoRoute(r fiber.Router, containerConf *container.Container) {
+	// setup middleware service
+	middleware := usecase.NewMiddleware(usecase.Config{SharedKey: containerConf.Apps.SecretJwt})
+	auth := controller.NewAuthImpl(middleware)
+
+	promoRepo := repository.NewPromoRepository(containerConf.Mysqldb)
+	promoUseCase := usecase.NewPromoUseCase(promoRepo)
+	promoController := controller.NewPromoController(promoUseCase)
+
+	promoAPI := r.Group("/promo")
+	promoAPI.Post("", auth.CheckJwtUser, promoController.CreatePromo)
+	promoAPI.Get("", promoController.GetPromoAktif)
+	promoAPI.Get("/my", auth.CheckJwtUser, promoController.GetMyPromo)
+	promoAPI.Get("/:id", promoController.GetPromoByID)
+	promoAPI.Delete("/:id", auth.CheckJwtUser, promoController.BatalkanPromo)
+
+}
+
 func TRXRoute(r fiber.Router, containerConf *container.Container) {
 	// setup middleware service
 	middleware := usecase.NewMiddleware(usecase.Config{SharedKey: containerConf.Apps.SecretJwt})
 	auth := controller.NewAuthImpl(middleware)
 
 	trxRepo := repository.NewTRXRepository(containerConf.Mysqldb)
-	trxUseCase := usecase.NewTRXUseCase(trxRepo)
+	notifikasiRepo := repository.NewNotifikasiRepository(containerConf.Mysqldb)
+	trxUseCase := usecase.NewTRXUseCase(trxRepo, notifikasiRepo, containerConf.Messaging, containerConf.Storage)
 	trxController := controller.NewTRXController(trxUseCase)
 
 	trxAPI := r.Group("/trx")
 	trxAPI.Post("", auth.CheckJwtUser, trxController.CreateTRX)
 	trxAPI.Get("/", auth.CheckJwtUser, trxController.GetALlTRX)
 	trxAPI.Get("/:id", auth.CheckJwtUser, trxController.GetTRXByID)
+	trxAPI.Put("/:id/payment", auth.CheckJwtAdmin, trxController.KonfirmasiPembayaran)
+	trxAPI.Put("/:id/shipment", auth.CheckJwtUser, trxController.KirimTRX)
 
 }
+
+func NotifikasiRoute(r fiber.Router, containerConf *container.Container) {
+	// setup middleware service
+	middleware := usecase.NewMiddleware(usecase.Config{SharedKey: containerConf.Apps.SecretJwt})
+	auth := controller.NewAuthImpl(middleware)
+
+	notifikasiRepo := repository.NewNotifikasiRepository(containerConf.Mysqldb)
+	notifikasiUseCase := usecase.NewNotifikasiUseCase(notifikasiRepo)
+	notifikasiController := controller.NewNotifikasiController(notifikasiUseCase)
+
+	notifikasiAPI := r.Group("/notification")
+	notifikasiAPI.Get("", auth.CheckJwtUser, notifikasiController.GetMyNotifikasi)
+	notifikasiAPI.Get("/unread", auth.CheckJwtUser, notifikasiController.CountUnread)
+	notifikasiAPI.Put("/read", auth.CheckJwtUser, notifikasiController.MarkAllRead)
+	notifikasiAPI.Put("/:id/read", auth.CheckJwtUser, notifikasiController.MarkRead)
+
+}
+
+func ChatRoute(r fiber.Router, containerConf *container.Container) {
+	// setup middleware service
+	middleware := usecase.NewMiddleware(usecase.Config{SharedKey: containerConf.Apps.SecretJwt})
+	auth := controller.NewAuthImpl(middleware)
+
+	chatRepo := repository.NewChatRepository(containerConf.Mysqldb)
+	chatUseCase := usecase.NewChatUseCase(chatRepo, containerConf.Storage)
+	chatController := controller.NewChatController(chatUseCase, controller.NewChatHub())
+
+	chatAPI := r.Group("/chat")
+	chatAPI.Post("", auth.CheckJwtUser, chatController.CreatePercakapan)
+	chatAPI.Get("", auth.CheckJwtUser, chatController.GetMyPercakapan)
+	chatAPI.Get("/unread", auth.CheckJwtUser, chatController.CountUnread)
+	chatAPI.Get("/ws", chatController.UpgradeWebSocket, auth.CheckJwtUser, websocket.New(chatController.WebSocket))
+	chatAPI.Get("/:id/messages", auth.CheckJwtUser, chatController.GetPesan)
+	chatAPI.Post("/:id/messages", auth.CheckJwtUser, chatController.KirimPesan)
+	chatAPI.Put("/:id/read", auth.CheckJwtUser, chatController.MarkRead)
+
+}
+
+func MediaRoute(r fiber.Router, containerConf *container.Container) {
+	mediaController := controller.NewMediaController(containerConf.Storage)
+
+	mediaAPI := r.Group("/media")
+	mediaAPI.Get("/*", mediaController.GetMedia)
+
+}
+
+// SampahJob schedule permanent deletion of record that stays in trash longer than the retention
+func SampahJob(containerConf *container.Container) {
+	retensi := containerConf.Apps.RetensiSampah
+	if retensi < 1 {
+		retensi = 30
+	}
+
+	sampahRepo := repository.NewSampahRepository(containerConf.Mysqldb)
+	produkRepo := repository.NewProdukRepository(containerConf.Mysqldb)
+	sampahUseCase := usecase.NewSampahUseCase(sampahRepo, produkRepo, containerConf.Storage, time.Duration(retensi)*24*time.Hour)
+
+	containerConf.Jadwal.Tambah("purge sampah", time.Hour, func(ctx context.Context) {
+		if errUseCase := sampahUseCase.PurgeSampah(ctx); errUseCase.Err != nil {
+			helper.Logger("handler.go", helper.LoggerLevelError, fmt.Sprintf("failed to purge trash : %s", errUseCase.Err.Error()))
+		}
+	})
+}
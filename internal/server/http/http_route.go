@@ -17,5 +17,12 @@ func RouteInit(r *fiber.App, containerConf *container.Container) {
 	handler.CategoryRoute(api, containerConf)
 	handler.ProvinceCityRoute(api, containerConf)
 	handler.ProdukRoute(api, containerConf)
+	handler.PromoRoute(api, containerConf)
 	handler.TRXRoute(api, containerConf)
+	handler.NotifikasiRoute(api, containerConf)
+	handler.ChatRoute(api, containerConf)
+	handler.MediaRoute(api, containerConf)
+
+	// background job
+	handler.SampahJob(containerConf)
 }